@@ -0,0 +1,167 @@
+package simpleforce
+
+import (
+	"fmt"
+	"time"
+)
+
+// Key prefixes of the records which are related to activities with WhoId, all others are related with WhatId.
+const (
+	contactKeyPrefix = "003"
+	leadKeyPrefix    = "00Q"
+)
+
+// TaskStatusCompleted is the standard closed status of tasks, used by LogCall and CompleteTasks.
+const TaskStatusCompleted = "Completed"
+
+// dateLayout is the layout of date-only fields, e.g. ActivityDate.
+const dateLayout = "2006-01-02"
+
+// Task is a to-do item or a logged activity, created with CreateTask or LogCall.
+type Task struct {
+	Subject     string
+	Description string
+	Status      string    // default status of the org if empty
+	Priority    string    // default priority of the org if empty
+	DueDate     time.Time // ActivityDate, only the date is used
+	// RelatedTo are the IDs of up to one contact or lead, set as WhoId, and one other record, e.g. an account or an
+	// opportunity, set as WhatId. Salesforce does not allow a WhatId along with a lead.
+	RelatedTo []string
+	OwnerID   string                 // the logged in user if empty
+	Fields    map[string]interface{} // any other fields
+}
+
+// CalendarEvent is an Event on the calendar of its owner, created with CreateEvent.
+type CalendarEvent struct {
+	Subject     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time // Start if zero
+	AllDay      bool      // only the dates of Start and End are used
+	RelatedTo   []string  // as for Task
+	OwnerID     string    // the logged in user if empty
+	Fields      map[string]interface{}
+}
+
+// CreateTask creates task and returns its ID. A *BatchError is returned if salesforce rejects it.
+func (client *Client) CreateTask(task Task) (string, error) {
+	obj := client.SObject("Task")
+	setOptional(obj, "Subject", task.Subject)
+	setOptional(obj, "Description", task.Description)
+	setOptional(obj, "Status", task.Status)
+	setOptional(obj, "Priority", task.Priority)
+	if !task.DueDate.IsZero() {
+		obj.Set("ActivityDate", task.DueDate.Format(dateLayout))
+	}
+	return client.createActivity(obj, task.RelatedTo, task.OwnerID, task.Fields)
+}
+
+// LogCall records a call of the given duration which took place today as a completed task with the Call subtype, and
+// returns its ID. The Status and DueDate of call default to completed and today.
+func (client *Client) LogCall(call Task, duration time.Duration) (string, error) {
+	if call.Status == "" {
+		call.Status = TaskStatusCompleted
+	}
+	if call.DueDate.IsZero() {
+		call.DueDate = time.Now()
+	}
+	fields := map[string]interface{}{
+		"TaskSubtype":           "Call",
+		"CallDurationInSeconds": int(duration / time.Second),
+	}
+	for field, value := range call.Fields {
+		fields[field] = value
+	}
+	call.Fields = fields
+	return client.CreateTask(call)
+}
+
+// CreateEvent creates event and returns its ID. A *BatchError is returned if salesforce rejects it.
+func (client *Client) CreateEvent(event CalendarEvent) (string, error) {
+	if event.Start.IsZero() {
+		return "", fmt.Errorf("event %q has no start", event.Subject)
+	}
+	if event.End.IsZero() {
+		event.End = event.Start
+	}
+
+	obj := client.SObject("Event")
+	setOptional(obj, "Subject", event.Subject)
+	setOptional(obj, "Description", event.Description)
+	setOptional(obj, "Location", event.Location)
+	if event.AllDay {
+		obj.Set("IsAllDayEvent", true)
+		obj.Set("ActivityDate", event.Start.Format(dateLayout))
+		obj.Set("DurationInMinutes", int(event.End.Sub(event.Start)/time.Minute))
+	} else {
+		obj.Set("StartDateTime", event.Start.UTC().Format(time.RFC3339))
+		obj.Set("EndDateTime", event.End.UTC().Format(time.RFC3339))
+	}
+	return client.createActivity(obj, event.RelatedTo, event.OwnerID, event.Fields)
+}
+
+// CompleteTasks sets the status of the tasks ids to TaskStatusCompleted, in batches like UpdateAll.
+func (client *Client) CompleteTasks(ids []string, opts ...BatchOption) ([]SaveResult, error) {
+	records := make([]*SObject, len(ids))
+	for idx, id := range ids {
+		records[idx] = client.SObject("Task").Set("Id", id).Set("Status", TaskStatusCompleted)
+	}
+	return client.UpdateAll(records, opts...)
+}
+
+// createActivity relates the task or event obj to the records relatedTo, sets its owner and fields, and creates it.
+func (client *Client) createActivity(obj *SObject, relatedTo []string, ownerID string, fields map[string]interface{}) (
+	string, error) {
+	whoID, whatID, err := activityRelations(relatedTo)
+	if err != nil {
+		return "", err
+	}
+	setOptional(obj, "WhoId", whoID)
+	setOptional(obj, "WhatId", whatID)
+	if ownerID == "" {
+		ownerID = client.user.id
+	}
+	setOptional(obj, "OwnerId", ownerID)
+	for field, value := range fields {
+		obj.Set(field, value)
+	}
+
+	_, err = client.CreateAll([]*SObject{obj})
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
+
+// activityRelations splits the IDs an activity is related to into its WhoId, a contact or lead, and its WhatId.
+func activityRelations(ids []string) (whoID, whatID string, err error) {
+	for _, id := range ids {
+		if len(id) < 3 {
+			return "", "", fmt.Errorf("invalid ID %q", id)
+		}
+		switch id[:3] {
+		case contactKeyPrefix, leadKeyPrefix:
+			if whoID != "" {
+				return "", "", fmt.Errorf("activity can be related to a single contact or lead, got %s and %s", whoID, id)
+			}
+			whoID = id
+		default:
+			if whatID != "" {
+				return "", "", fmt.Errorf("activity can be related to a single other record, got %s and %s", whatID, id)
+			}
+			whatID = id
+		}
+	}
+	if whatID != "" && whoID != "" && whoID[:3] == leadKeyPrefix {
+		return "", "", fmt.Errorf("activity related to lead %s cannot be related to %s", whoID, whatID)
+	}
+	return whoID, whatID, nil
+}
+
+// setOptional sets field of obj to value unless it is empty.
+func setOptional(obj *SObject, field, value string) {
+	if value != "" {
+		obj.Set(field, value)
+	}
+}
@@ -0,0 +1,111 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newActivityClient returns a client whose stub server records the records sent to the collections resource.
+func newActivityClient(t *testing.T, sent *[]map[string]interface{}) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		*sent = append(*sent, body.Records...)
+		var results []map[string]interface{}
+		for range body.Records {
+			results = append(results, map[string]interface{}{"id": "00TNEW", "success": true, "errors": []string{}})
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+	client.user.id = "005ME"
+	return client
+}
+
+func TestClient_CreateTask(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newActivityClient(t, &sent)
+
+	id, err := client.CreateTask(Task{
+		Subject:   "Follow up",
+		DueDate:   time.Date(2022, 5, 1, 23, 0, 0, 0, time.UTC),
+		RelatedTo: []string{"006OPP", "003CONTACT"},
+		Fields:    map[string]interface{}{"Type": "Email"},
+	})
+	if err != nil || id != "00TNEW" {
+		t.Fatalf("unexpected result %s, %v", id, err)
+	}
+	task := sent[0]
+	if task["WhoId"] != "003CONTACT" || task["WhatId"] != "006OPP" || task["OwnerId"] != "005ME" ||
+		task["ActivityDate"] != "2022-05-01" || task["Type"] != "Email" || task["Status"] != nil {
+		t.Errorf("unexpected task %v", task)
+	}
+
+	for _, related := range [][]string{{"00QLEAD", "001ACCOUNT"}, {"003A", "003B"}, {"001A", "006B"}, {"x"}} {
+		if _, err = client.CreateTask(Task{Subject: "Invalid", RelatedTo: related}); err == nil {
+			t.Errorf("expected an error for %v", related)
+		}
+	}
+	if len(sent) != 1 {
+		t.Errorf("expected invalid tasks not to be sent, got %v", sent)
+	}
+}
+
+func TestClient_LogCall(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newActivityClient(t, &sent)
+
+	_, err := client.LogCall(Task{Subject: "Call", RelatedTo: []string{"00QLEAD"}, OwnerID: "005OTHER"}, 90*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := sent[0]
+	if call["TaskSubtype"] != "Call" || call["CallDurationInSeconds"] != 90.0 || call["Status"] != TaskStatusCompleted ||
+		call["WhoId"] != "00QLEAD" || call["OwnerId"] != "005OTHER" || call["ActivityDate"] != time.Now().Format("2006-01-02") {
+		t.Errorf("unexpected call %v", call)
+	}
+}
+
+func TestClient_CreateEvent(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newActivityClient(t, &sent)
+
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	_, err := client.CreateEvent(CalendarEvent{Subject: "Demo", Start: start, End: start.Add(time.Hour),
+		RelatedTo: []string{"001ACCOUNT"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateEvent(CalendarEvent{Subject: "Offsite", Start: start, End: start.Add(24 * time.Hour), AllDay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateEvent(CalendarEvent{Subject: "Unscheduled"}); err == nil {
+		t.Error("expected an error without start")
+	}
+
+	if event := sent[0]; event["StartDateTime"] != "2022-05-01T08:00:00Z" || event["EndDateTime"] != "2022-05-01T09:00:00Z" ||
+		event["WhatId"] != "001ACCOUNT" || event["WhoId"] != nil {
+		t.Errorf("unexpected event %v", event)
+	}
+	if event := sent[1]; event["IsAllDayEvent"] != true || event["ActivityDate"] != "2022-05-01" ||
+		event["DurationInMinutes"] != 1440.0 || event["StartDateTime"] != nil {
+		t.Errorf("unexpected event %v", event)
+	}
+}
+
+func TestClient_CompleteTasks(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newActivityClient(t, &sent)
+
+	results, err := client.CompleteTasks([]string{"00TA", "00TB"})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("unexpected results %v, %v", results, err)
+	}
+	if len(sent) != 2 || sent[1]["Id"] != "00TB" || sent[1]["Status"] != TaskStatusCompleted {
+		t.Errorf("unexpected records %v", sent)
+	}
+}
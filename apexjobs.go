@@ -0,0 +1,184 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultApexJobPollInterval is the first interval used by WaitApexJob when no positive interval is given. The interval
+// doubles after every poll up to MaxApexJobPollInterval.
+const DefaultApexJobPollInterval = 2 * time.Second
+
+// MaxApexJobPollInterval caps the interval between the polls of WaitApexJob.
+const MaxApexJobPollInterval = time.Minute
+
+// ErrApexJobFailed is returned by WaitApexJob when an asynchronous Apex job fails or is aborted.
+var ErrApexJobFailed = errors.New("apex job failed")
+
+// AsyncApexJob statuses.
+const (
+	ApexJobHolding    = "Holding"
+	ApexJobQueued     = "Queued"
+	ApexJobPreparing  = "Preparing"
+	ApexJobProcessing = "Processing"
+	ApexJobCompleted  = "Completed"
+	ApexJobAborted    = "Aborted"
+	ApexJobFailed     = "Failed"
+)
+
+// apexJobFields are the fields of AsyncApexJob selected by ApexJobStatus and QueryApexJobs.
+const apexJobFields = "Id, JobType, Status, ExtendedStatus, ApexClass.Name, MethodName, JobItemsProcessed, " +
+	"TotalJobItems, NumberOfErrors, CreatedDate, CompletedDate"
+
+// ApexJob describes an asynchronous Apex job, e.g. a Batch Apex or Queueable job. CreatedDate and CompletedDate can be
+// parsed with ParseDateTime.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_asyncapexjob.htm
+type ApexJob struct {
+	ID                string
+	JobType           string
+	Status            string
+	ExtendedStatus    string
+	ApexClassName     string
+	MethodName        string
+	JobItemsProcessed int
+	TotalJobItems     int
+	NumberOfErrors    int
+	CreatedDate       string
+	CompletedDate     string
+}
+
+// Done tells whether the job has completed, failed or been aborted.
+func (job *ApexJob) Done() bool {
+	switch job.Status {
+	case ApexJobCompleted, ApexJobAborted, ApexJobFailed:
+		return true
+	}
+	return false
+}
+
+// ApexJobStatus returns the asynchronous Apex job id, e.g. returned by Database.executeBatch or System.enqueueJob, with
+// its current status.
+func (client *Client) ApexJobStatus(id string) (*ApexJob, error) {
+	jobs, err := client.queryApexJobs("SELECT " + apexJobFields + " FROM AsyncApexJob WHERE Id = " + QuoteSOQL(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("apex job %s not found", id)
+	}
+	return &jobs[0], nil
+}
+
+// QueryApexJobs returns the asynchronous Apex jobs of the class className with one of the given statuses, or any status
+// if none are given, newest first. It can be used to check whether a job is already running before starting another.
+func (client *Client) QueryApexJobs(className string, statuses ...string) ([]ApexJob, error) {
+	soql := "SELECT " + apexJobFields + " FROM AsyncApexJob WHERE ApexClass.Name = " + QuoteSOQL(className)
+	if len(statuses) > 0 {
+		quoted := make([]string, len(statuses))
+		for idx, status := range statuses {
+			quoted[idx] = QuoteSOQL(status)
+		}
+		soql += " AND Status IN (" + strings.Join(quoted, ", ") + ")"
+	}
+	soql += " ORDER BY CreatedDate DESC"
+	return client.queryApexJobs(soql)
+}
+
+// queryApexJobs runs soql, selecting apexJobFields from AsyncApexJob, and returns all results.
+func (client *Client) queryApexJobs(soql string) ([]ApexJob, error) {
+	var jobs []ApexJob
+	err := client.queryPages("query", soql, func(records []byte) error {
+		var page []struct {
+			ID             string `json:"Id"`
+			JobType        string `json:"JobType"`
+			Status         string `json:"Status"`
+			ExtendedStatus string `json:"ExtendedStatus"`
+			ApexClass      struct {
+				Name string `json:"Name"`
+			} `json:"ApexClass"`
+			MethodName        string `json:"MethodName"`
+			JobItemsProcessed int    `json:"JobItemsProcessed"`
+			TotalJobItems     int    `json:"TotalJobItems"`
+			NumberOfErrors    int    `json:"NumberOfErrors"`
+			CreatedDate       string `json:"CreatedDate"`
+			CompletedDate     string `json:"CompletedDate"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		if err != nil {
+			return err
+		}
+		for _, record := range page {
+			jobs = append(jobs, ApexJob{
+				ID:                record.ID,
+				JobType:           record.JobType,
+				Status:            record.Status,
+				ExtendedStatus:    record.ExtendedStatus,
+				ApexClassName:     record.ApexClass.Name,
+				MethodName:        record.MethodName,
+				JobItemsProcessed: record.JobItemsProcessed,
+				TotalJobItems:     record.TotalJobItems,
+				NumberOfErrors:    record.NumberOfErrors,
+				CreatedDate:       record.CreatedDate,
+				CompletedDate:     record.CompletedDate,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// AbortApexJob aborts the asynchronous Apex job id. The REST API has no resource for this, so System.abortJob is run
+// as anonymous Apex, which requires the "Author Apex" permission.
+func (client *Client) AbortApexJob(id string) error {
+	// Apex string literals use the same escaping as SOQL.
+	result, err := client.ExecuteAnonymous("System.abortJob(" + QuoteSOQL(id) + ");")
+	if err != nil {
+		return err
+	}
+	if !result.Compiled {
+		return fmt.Errorf("abort apex job %s: %v", id, result.CompileProblem)
+	}
+	if !result.Success {
+		return fmt.Errorf("abort apex job %s: %v", id, result.ExceptionMessage)
+	}
+	return nil
+}
+
+// WaitApexJob polls the asynchronous Apex job id until it is done or ctx is canceled. The interval between polls starts
+// at interval, DefaultApexJobPollInterval if not positive, and doubles after every poll up to MaxApexJobPollInterval.
+// If the job fails or is aborted, the error matches ErrApexJobFailed with errors.Is. A completed Batch Apex job may
+// still have failed batches, which are counted in NumberOfErrors.
+func (client *Client) WaitApexJob(ctx context.Context, id string, interval time.Duration) (*ApexJob, error) {
+	if interval <= 0 {
+		interval = DefaultApexJobPollInterval
+	}
+	for {
+		job, err := client.ApexJobStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		switch job.Status {
+		case ApexJobCompleted:
+			return job, nil
+		case ApexJobFailed, ApexJobAborted:
+			return job, errors.Wrapf(ErrApexJobFailed, "%s: %s", job.Status, job.ExtendedStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > MaxApexJobPollInterval {
+			interval = MaxApexJobPollInterval
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_WaitApexJob(t *testing.T) {
+	polls := map[string]int{}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if !strings.HasPrefix(q, "SELECT "+apexJobFields+" FROM AsyncApexJob WHERE Id = ") {
+			t.Errorf("unexpected query %s", q)
+		}
+		id := strings.Trim(strings.TrimPrefix(q, "SELECT "+apexJobFields+" FROM AsyncApexJob WHERE Id = "), "'")
+		polls[id]++
+		status, extended := "Processing", ""
+		switch {
+		case id == "707OK" && polls[id] > 2:
+			status = "Completed"
+		case id == "707FAIL":
+			status, extended = "Failed", "First error: Apex CPU time limit exceeded"
+		case id == "707MISSING":
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+			return
+		}
+		fmt.Fprintf(w, `{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "AsyncApexJob"},
+			"Id": %q, "JobType": "BatchApex", "Status": %q, "ExtendedStatus": %q, "ApexClass": {"Name": "CleanupBatch"},
+			"MethodName": null, "JobItemsProcessed": %d, "TotalJobItems": 3, "NumberOfErrors": 0,
+			"CreatedDate": "2022-03-01T10:00:00.000+0000", "CompletedDate": null}]}`, id, status, extended, polls[id])
+	})
+
+	job, err := client.WaitApexJob(context.Background(), "707OK", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Done() || job.ApexClassName != "CleanupBatch" || job.JobItemsProcessed != 3 || polls["707OK"] != 3 {
+		t.Errorf("unexpected job %+v after %d polls", job, polls["707OK"])
+	}
+
+	job, err = client.WaitApexJob(context.Background(), "707FAIL", time.Millisecond)
+	if !errors.Is(err, ErrApexJobFailed) || !strings.Contains(err.Error(), "CPU time") || job.Status != ApexJobFailed {
+		t.Errorf("expected a failed job, got %+v, %v", job, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	job, err = client.WaitApexJob(ctx, "707SLOW", time.Millisecond)
+	if err != context.DeadlineExceeded || job == nil || job.Done() {
+		t.Errorf("expected the wait to time out, got %+v, %v", job, err)
+	}
+	if polls["707SLOW"] > 6 {
+		t.Errorf("expected polls to back off, got %d", polls["707SLOW"])
+	}
+
+	if _, err = client.ApexJobStatus("707MISSING"); err == nil {
+		t.Error("expected an error for a missing job")
+	}
+}
+
+func TestClient_QueryApexJobs(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		want := "SELECT " + apexJobFields + " FROM AsyncApexJob WHERE ApexClass.Name = 'CleanupBatch' " +
+			"AND Status IN ('Queued', 'Processing') ORDER BY CreatedDate DESC"
+		if q := r.URL.Query().Get("q"); q != want {
+			t.Errorf("unexpected query %s", q)
+		}
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "707A", "Status": "Queued",
+			"ApexClass": {"Name": "CleanupBatch"}}]}`))
+	})
+
+	jobs, err := client.QueryApexJobs("CleanupBatch", ApexJobQueued, ApexJobProcessing)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "707A" || jobs[0].Done() {
+		t.Errorf("unexpected jobs %+v, %v", jobs, err)
+	}
+}
+
+func TestClient_AbortApexJob(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := r.URL.Query().Get("anonymousBody")
+		if body == "System.abortJob('707A');" {
+			w.Write([]byte(`{"line": -1, "column": -1, "compiled": true, "success": true}`))
+			return
+		}
+		w.Write([]byte(`{"line": 1, "column": 1, "compiled": true, "success": false,
+			"exceptionMessage": "System.StringException: Invalid id: 707BAD"}`))
+	})
+
+	if err := client.AbortApexJob("707A"); err != nil {
+		t.Error(err)
+	}
+	if err := client.AbortApexJob("707BAD"); err == nil || !strings.Contains(err.Error(), "Invalid id") {
+		t.Errorf("expected the exception, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package simpleforce
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	restResourcePattern = regexp.MustCompile(`(?i)@RestResource\s*\(\s*urlMapping\s*=\s*'([^']*)'\s*\)`)
+	httpMethodPattern   = regexp.MustCompile(`(?i)@Http(Get|Post|Put|Patch|Delete)\b`)
+)
+
+// ApexRESTEndpoint is an Apex class exposed as a REST resource with the @RestResource annotation. Path is the URL
+// mapping under /services/apexrest, including the namespace of the class, relative to the instance as expected by
+// ApexREST, e.g. "services/apexrest/acme/invoices/*". Methods lists the HTTP methods handled by the class.
+type ApexRESTEndpoint struct {
+	ClassID         string
+	ClassName       string
+	NamespacePrefix string
+	URLMapping      string
+	Path            string
+	Methods         []string
+}
+
+// ApexRESTEndpoints lists the Apex REST resources of the org by scanning the source of the active Apex classes with the
+// Tooling API. Classes of managed packages, whose source is hidden, are not included.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.apexcode.meta/apexcode/apex_rest_resource.htm
+func (client *Client) ApexRESTEndpoints() ([]ApexRESTEndpoint, error) {
+	var endpoints []ApexRESTEndpoint
+	soql := "SELECT Id, Name, NamespacePrefix, Body FROM ApexClass WHERE Status = 'Active' ORDER BY Name"
+	err := client.toolingQueryPages(soql, func(records []byte) error {
+		var classes []struct {
+			ID              string `json:"Id"`
+			Name            string `json:"Name"`
+			NamespacePrefix string `json:"NamespacePrefix"`
+			Body            string `json:"Body"`
+		}
+		err := client.unmarshalJSON(records, &classes)
+		if err != nil {
+			return err
+		}
+		for _, class := range classes {
+			match := restResourcePattern.FindStringSubmatch(class.Body)
+			if match == nil {
+				continue
+			}
+			path := "services/apexrest/"
+			if class.NamespacePrefix != "" {
+				path += class.NamespacePrefix + "/"
+			}
+			endpoints = append(endpoints, ApexRESTEndpoint{
+				ClassID:         class.ID,
+				ClassName:       class.Name,
+				NamespacePrefix: class.NamespacePrefix,
+				URLMapping:      match[1],
+				Path:            path + strings.TrimPrefix(match[1], "/"),
+				Methods:         apexHTTPMethods(class.Body),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// apexHTTPMethods returns the sorted HTTP methods annotated in the source of an Apex class.
+func apexHTTPMethods(body string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, match := range httpMethodPattern.FindAllStringSubmatch(body, -1) {
+		method := strings.ToUpper(match[1])
+		if !seen[method] {
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
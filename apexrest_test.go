@@ -0,0 +1,43 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_ApexRESTEndpoints(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"done": true,
+			"records": []map[string]interface{}{
+				{"Id": "01pA", "Name": "InvoiceResource", "NamespacePrefix": "acme", "Body": `
+@RestResource(urlMapping = '/invoices/*')
+global with sharing class InvoiceResource {
+    @HttpGet global static Invoice__c show() { return null; }
+    @HttpPost global static Id create() { return null; }
+    @httpget global static void ignored() {}
+}`},
+				{"Id": "01pB", "Name": "Helper", "Body": "public class Helper {}"},
+				{"Id": "01pC", "Name": "Hidden", "NamespacePrefix": "pkg", "Body": "(hidden)"},
+			},
+		})
+	})
+
+	endpoints, err := client.ApexRESTEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ApexRESTEndpoint{{
+		ClassID:         "01pA",
+		ClassName:       "InvoiceResource",
+		NamespacePrefix: "acme",
+		URLMapping:      "/invoices/*",
+		Path:            "services/apexrest/acme/invoices/*",
+		Methods:         []string{"GET", "POST"},
+	}}
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+}
@@ -0,0 +1,160 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxAuditBodySize is the largest request or response body inspected for record IDs.
+const maxAuditBodySize = 1 << 20
+
+// AuditRecord describes an outbound call of a client to salesforce. Object and RecordIDs are derived from the URL and
+// from the request and response bodies of DML calls, e.g. the IDs of created records, and may be empty. StatusCode is
+// 0 and Err is set if no response was received.
+type AuditRecord struct {
+	Time       time.Time
+	Method     string
+	URL        string
+	Object     string
+	RecordIDs  []string
+	Duration   time.Duration
+	StatusCode int
+	Err        error
+}
+
+// SetAuditHook registers hook to be called after every call the client makes to salesforce, e.g. to keep an audit log
+// of what an integration did to CRM data. The hook is called synchronously and must not block for long. Session IDs
+// are never part of an AuditRecord. A nil hook disables auditing.
+func (client *Client) SetAuditHook(hook func(AuditRecord)) {
+	client.auditHook = hook
+}
+
+// send sends req with the HTTP client of the client, queued and throttled if enabled, with the read session it is routed
+// to and to the host overriding its path if any, and reports the call to the audit hook. It fails once the client is
+// closed.
+func (client *Client) send(req *http.Request) (*http.Response, error) {
+	reader := client.routeRead(req)
+	client.overrideHost(req)
+	resp, err := client.tracked(req, func(req *http.Request) (*http.Response, error) {
+		return client.queued(req, client.sendAudited)
+	})
+	if reader != nil && resp != nil {
+		client.trackRead(reader, req, resp)
+	}
+	return resp, err
+}
+
+// sendAudited sends req, reporting the call to the audit hook if set.
+func (client *Client) sendAudited(req *http.Request) (*http.Response, error) {
+	if client.auditHook == nil {
+		return client.roundTrip(req)
+	}
+
+	record := AuditRecord{Time: time.Now(), Method: req.Method, URL: req.URL.String()}
+	record.Object, record.RecordIDs = auditTarget(req.URL)
+	if req.Method != http.MethodGet && req.GetBody != nil && req.ContentLength <= maxAuditBodySize {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			record.RecordIDs = appendIDs(record.RecordIDs, data)
+		}
+	}
+
+	resp, err := client.roundTrip(req)
+	record.Duration = time.Since(record.Time)
+	record.Err = err
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+		if req.Method != http.MethodGet && resp.ContentLength <= maxAuditBodySize {
+			// Buffer the response to pick up the IDs of created records, and hand a copy to the caller.
+			data, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+			if readErr == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+				record.RecordIDs = appendIDs(record.RecordIDs, data)
+			}
+		}
+	}
+
+	client.auditHook(record)
+	return resp, err
+}
+
+// auditTarget derives the object and record IDs of a call from its URL.
+func auditTarget(u *url.URL) (object string, ids []string) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for idx, part := range parts {
+		switch {
+		case part == "sobjects" && idx+1 < len(parts):
+			object = parts[idx+1]
+			// sobjects/Type/ID, but not sobjects/Type/describe or sobjects/Type/ExtField/Value.
+			if idx+2 == len(parts)-1 && parts[idx+2] != "describe" {
+				ids = append(ids, parts[idx+2])
+			}
+			return object, appendUnique(ids, strings.Split(u.Query().Get("ids"), ",")...)
+		case part == "query" && u.Query().Get("q") != "":
+			soql := u.Query().Get("q")
+			if fromIdx := indexTopLevelKeyword(soql, "FROM"); fromIdx >= 0 {
+				if fields := strings.Fields(soql[fromIdx+len("FROM"):]); len(fields) > 0 {
+					object = fields[0]
+				}
+			}
+			return object, nil
+		}
+	}
+	return "", nil
+}
+
+// appendIDs appends the record IDs found in a JSON request or response body: the "id" or "Id" of an object, of the
+// elements of an array, or of the elements of its "records".
+func appendIDs(ids []string, data []byte) []string {
+	var body interface{}
+	if json.Unmarshal(data, &body) != nil {
+		return ids
+	}
+
+	var items []interface{}
+	switch value := body.(type) {
+	case []interface{}:
+		items = value
+	case map[string]interface{}:
+		if records, ok := value["records"].([]interface{}); ok {
+			items = records
+		} else {
+			items = []interface{}{value}
+		}
+	}
+	for _, item := range items {
+		fields, _ := item.(map[string]interface{})
+		for _, key := range []string{sobjectIDKey, "id"} {
+			if id, ok := fields[key].(string); ok {
+				ids = appendUnique(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// appendUnique appends the non-empty values which are not yet in ids.
+func appendUnique(ids []string, values ...string) []string {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		found := false
+		for _, id := range ids {
+			if id == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ids = append(ids, value)
+		}
+	}
+	return ids
+}
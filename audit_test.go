@@ -0,0 +1,68 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_SetAuditHook(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/sobjects/Case/"):
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "500A", "success": true, "errors": []}`))
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/composite/sobjects"):
+			w.Write([]byte(`[{"id": "500A", "success": true}, {"id": "500B", "success": true}]`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`[{"message": "not found", "errorCode": "NOT_FOUND"}]`))
+		}
+	})
+
+	var records []AuditRecord
+	client.SetAuditHook(func(record AuditRecord) {
+		records = append(records, record)
+	})
+
+	obj := client.SObject("Case").Set("Subject", "audit").Create()
+	if obj == nil || obj.ID() != "500A" {
+		t.Fatal("create failed")
+	}
+	update := []*SObject{client.SObject("Case").Set("Subject", "a"), client.SObject("Case").Set("Subject", "b")}
+	update[0].setID("500A")
+	update[1].setID("500B")
+	if _, err := client.UpdateAll(update); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	client.Query("SELECT Id FROM Case WHERE Subject = 'audit'")
+	client.SObject("Case").Get("500Z")
+
+	expected := []struct {
+		method, object, ids string
+		status              int
+	}{
+		{http.MethodPost, "Case", "500A", http.StatusCreated},
+		{http.MethodPatch, "", "500A,500B", http.StatusOK},
+		{http.MethodDelete, "Case", "500A", http.StatusNoContent},
+		{http.MethodGet, "Case", "", http.StatusOK},
+		{http.MethodGet, "Case", "500Z", http.StatusNotFound},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("unexpected audit records %+v", records)
+	}
+	for idx, record := range records {
+		e := expected[idx]
+		if record.Method != e.method || record.Object != e.object || strings.Join(record.RecordIDs, ",") != e.ids ||
+			record.StatusCode != e.status || record.Err != nil || record.Time.IsZero() {
+			t.Errorf("%d: unexpected audit record %+v", idx, record)
+		}
+	}
+}
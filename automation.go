@@ -0,0 +1,163 @@
+package simpleforce
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// ErrFlowNotFound is returned when a flow cannot be resolved by its developer name.
+var ErrFlowNotFound = errors.New("flow not found")
+
+// ErrValidationRuleNotFound is returned when a validation rule cannot be resolved by its object and name.
+var ErrValidationRuleNotFound = errors.New("validation rule not found")
+
+// DeactivateFlow deactivates the flow developerName through the Tooling API and returns the version which was active,
+// 0 if the flow was already inactive, so that it can be restored with ActivateFlow. ErrFlowNotFound is returned if the
+// flow does not exist. Together with SetValidationRuleActive, it switches automation off during data migrations; Apex
+// triggers cannot be deactivated in production orgs without a deployment and are best guarded by a custom setting.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_flowdefinition.htm
+func (client *Client) DeactivateFlow(developerName string) (int, error) {
+	id, active, _, err := client.flowDefinition(developerName)
+	if err != nil {
+		return 0, err
+	}
+	if active == 0 {
+		return 0, nil
+	}
+	return active, client.setFlowVersion(id, 0)
+}
+
+// ActivateFlow activates the version version of the flow developerName, the latest version if 0, through the Tooling
+// API. ErrFlowNotFound is returned if the flow does not exist.
+func (client *Client) ActivateFlow(developerName string, version int) error {
+	id, active, latest, err := client.flowDefinition(developerName)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		version = latest
+	}
+	if version == active {
+		return nil
+	}
+	return client.setFlowVersion(id, version)
+}
+
+// flowDefinition returns the ID and the active and latest version numbers of the flow developerName.
+func (client *Client) flowDefinition(developerName string) (id string, active, latest int, err error) {
+	soql := "SELECT Id, ActiveVersion.VersionNumber, LatestVersion.VersionNumber FROM FlowDefinition " +
+		"WHERE DeveloperName = " + QuoteSOQL(developerName)
+	var definitions []struct {
+		ID            string `json:"Id"`
+		ActiveVersion *struct {
+			VersionNumber int `json:"VersionNumber"`
+		} `json:"ActiveVersion"`
+		LatestVersion *struct {
+			VersionNumber int `json:"VersionNumber"`
+		} `json:"LatestVersion"`
+	}
+	err = client.toolingQueryPages(soql, func(records []byte) error {
+		return client.unmarshalJSON(records, &definitions)
+	})
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if len(definitions) == 0 {
+		return "", 0, 0, errors.Wrap(ErrFlowNotFound, developerName)
+	}
+	definition := definitions[0]
+	if definition.ActiveVersion != nil {
+		active = definition.ActiveVersion.VersionNumber
+	}
+	if definition.LatestVersion != nil {
+		latest = definition.LatestVersion.VersionNumber
+	}
+	return definition.ID, active, latest, nil
+}
+
+// setFlowVersion makes the version version of the flow definition id active, deactivating the flow if 0.
+func (client *Client) setFlowVersion(id string, version int) error {
+	return client.patchToolingMetadata("FlowDefinition", id, map[string]interface{}{"activeVersionNumber": version})
+}
+
+// ValidationRules returns whether each validation rule of objectType, e.g. "Account", is active, keyed by the rule
+// names. Record the result before deactivating rules to restore them afterwards.
+func (client *Client) ValidationRules(objectType string) (map[string]bool, error) {
+	soql := "SELECT ValidationName, Active FROM ValidationRule WHERE EntityDefinition.QualifiedApiName = " +
+		QuoteSOQL(objectType)
+	rules := make(map[string]bool)
+	err := client.toolingQueryPages(soql, func(records []byte) error {
+		var page []struct {
+			ValidationName string `json:"ValidationName"`
+			Active         bool   `json:"Active"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		for _, rule := range page {
+			rules[rule.ValidationName] = rule.Active
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetValidationRuleActive activates or deactivates the validation rule name of objectType through the Tooling API and
+// returns whether it was active before. ErrValidationRuleNotFound is returned if the rule does not exist.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_validationrule.htm
+func (client *Client) SetValidationRuleActive(objectType, name string, active bool) (bool, error) {
+	soql := "SELECT Id, Active FROM ValidationRule WHERE EntityDefinition.QualifiedApiName = " + QuoteSOQL(objectType) +
+		" AND ValidationName = " + QuoteSOQL(name)
+	result, err := client.toolingQuery(soql)
+	if err != nil {
+		return false, err
+	}
+	if len(result.Records) == 0 {
+		return false, errors.Wrapf(ErrValidationRuleNotFound, "%s.%s", objectType, name)
+	}
+	id := result.Records[0].ID()
+	wasActive, _ := result.Records[0].InterfaceField("Active").(bool)
+	if wasActive == active {
+		return wasActive, nil
+	}
+
+	// Metadata can only be queried for a single record, and is replaced as a whole on update.
+	var metadata map[string]interface{}
+	err = client.toolingQueryPages("SELECT Metadata FROM ValidationRule WHERE Id = "+QuoteSOQL(id), func(records []byte) error {
+		var page []struct {
+			Metadata map[string]interface{} `json:"Metadata"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		if len(page) > 0 {
+			metadata = page[0].Metadata
+		}
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if metadata == nil {
+		return false, errors.Wrapf(ErrValidationRuleNotFound, "%s.%s", objectType, name)
+	}
+	metadata["active"] = active
+	return wasActive, client.patchToolingMetadata("ValidationRule", id, metadata)
+}
+
+// patchToolingMetadata updates the Metadata of the Tooling API record id of objectType.
+func (client *Client) patchToolingMetadata(objectType, id string, metadata map[string]interface{}) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	reqData, err := client.marshalJSON(map[string]interface{}{"Metadata": metadata})
+	if err != nil {
+		return err
+	}
+	u := client.makeURL("tooling/sobjects/" + objectType + "/" + url.PathEscape(id))
+	_, err = client.httpRequest(http.MethodPatch, u, bytes.NewReader(reqData))
+	return err
+}
@@ -0,0 +1,100 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_DeactivateFlow(t *testing.T) {
+	var patched []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/services/data/v54.0/tooling/sobjects/FlowDefinition/300A":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			data, _ := json.Marshal(body)
+			patched = append(patched, string(data))
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(r.URL.Query().Get("q"), "DeveloperName = 'Welcome'"):
+			active := `{"attributes": {"type": "Flow"}, "VersionNumber": 3}`
+			if len(patched) == 1 {
+				active = "null"
+			}
+			w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "FlowDefinition"}, "Id": "300A",
+				"ActiveVersion": ` + active + `, "LatestVersion": {"attributes": {"type": "Flow"}, "VersionNumber": 4}}]}`))
+		case strings.Contains(r.URL.Query().Get("q"), "FlowDefinition"):
+			w.Write([]byte(`{"done": true, "records": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	version, err := client.DeactivateFlow("Welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 3 {
+		t.Errorf("expected version 3, got %d", version)
+	}
+	if err = client.ActivateFlow("Welcome", version); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`{"Metadata":{"activeVersionNumber":0}}`, `{"Metadata":{"activeVersionNumber":3}}`}
+	if strings.Join(patched, " ") != strings.Join(expected, " ") {
+		t.Errorf("unexpected updates %v", patched)
+	}
+	if _, err = client.DeactivateFlow("Missing"); !errors.Is(err, ErrFlowNotFound) {
+		t.Errorf("expected ErrFlowNotFound, got %v", err)
+	}
+}
+
+func TestClient_SetValidationRuleActive(t *testing.T) {
+	var patched map[string]interface{}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/services/data/v54.0/tooling/sobjects/ValidationRule/03dA":
+			json.NewDecoder(r.Body).Decode(&patched)
+			w.WriteHeader(http.StatusNoContent)
+		case q == "SELECT Id, Active FROM ValidationRule WHERE EntityDefinition.QualifiedApiName = 'Account' AND "+
+			"ValidationName = 'Require_Phone'":
+			w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "ValidationRule"}, "Id": "03dA",
+				"Active": true}]}`))
+		case q == "SELECT Metadata FROM ValidationRule WHERE Id = '03dA'":
+			w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "ValidationRule"}, "Metadata": {
+				"active": true, "errorConditionFormula": "ISBLANK(Phone)", "errorMessage": "Phone is required"}}]}`))
+		case strings.Contains(q, "SELECT Id, Active FROM ValidationRule"):
+			w.Write([]byte(`{"done": true, "records": []}`))
+		case strings.HasPrefix(q, "SELECT ValidationName, Active FROM ValidationRule"):
+			w.Write([]byte(`{"done": true, "records": [
+				{"attributes": {"type": "ValidationRule"}, "ValidationName": "Require_Phone", "Active": true},
+				{"attributes": {"type": "ValidationRule"}, "ValidationName": "Legacy", "Active": false}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	rules, err := client.ValidationRules("Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || !rules["Require_Phone"] || rules["Legacy"] {
+		t.Errorf("unexpected rules %v", rules)
+	}
+
+	wasActive, err := client.SetValidationRuleActive("Account", "Require_Phone", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, _ := patched["Metadata"].(map[string]interface{})
+	if !wasActive || metadata["active"] != false || metadata["errorConditionFormula"] != "ISBLANK(Phone)" {
+		t.Errorf("unexpected update %v", patched)
+	}
+	if _, err = client.SetValidationRuleActive("Account", "Missing", false); !errors.Is(err, ErrValidationRuleNotFound) {
+		t.Errorf("expected ErrValidationRuleNotFound, got %v", err)
+	}
+}
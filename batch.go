@@ -0,0 +1,484 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// BatchOption is a functional option for the batched DML helpers, e.g. DeleteByQuery.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	batchSize int
+	allOrNone bool
+	dryRun    bool
+	validate  bool
+	security  FieldSecurity
+	truncate  bool
+	truncated func(Truncation)
+	progress  func(processed, total int)
+	bulkFrom  int
+}
+
+// WithBatchSize sets the number of records sent per collection request. Values outside of 1..200 are ignored.
+func WithBatchSize(size int) BatchOption {
+	return func(opts *batchOptions) {
+		if size > 0 && size <= maxCollectionSize {
+			opts.batchSize = size
+		}
+	}
+}
+
+// WithAllOrNone rolls back every record of a collection request if any record in it fails.
+func WithAllOrNone(allOrNone bool) BatchOption {
+	return func(opts *batchOptions) {
+		opts.allOrNone = allOrNone
+	}
+}
+
+// WithProgress registers a callback invoked after each collection request with the number of records processed so
+// far and the total number of records known to need processing.
+func WithProgress(progress func(processed, total int)) BatchOption {
+	return func(opts *batchOptions) {
+		opts.progress = progress
+	}
+}
+
+// WithDryRun makes helpers which support it, e.g. UpdateByQuery, report what would be changed without sending any DML
+// request to salesforce. CreateAll, UpdateAll and UpsertAll only validate the records as with WithValidation.
+func WithDryRun(dryRun bool) BatchOption {
+	return func(opts *batchOptions) {
+		opts.dryRun = dryRun
+	}
+}
+
+// WithValidation makes CreateAll, UpdateAll and UpsertAll check the records against the describe metadata of their
+// objects, which is cached by the client, before sending them. Records with unknown or read-only fields, missing
+// required fields or too long strings are not sent and fail with the SaveErrors salesforce would report, e.g.
+// errcode.StringTooLong, so a payload can be checked without round trips or partial saves.
+func WithValidation(validate bool) BatchOption {
+	return func(opts *batchOptions) {
+		opts.validate = validate
+	}
+}
+
+// WithFieldSecurity makes CreateAll, UpdateAll and UpsertAll strip or reject fields the running user cannot write
+// according to the cached describe metadata, instead of having salesforce fail the records with INVALID_FIELD or
+// INVALID_FIELD_FOR_INSERT_UPDATE errors which do not tell why a field cannot be written.
+func WithFieldSecurity(mode FieldSecurity) BatchOption {
+	return func(opts *batchOptions) {
+		opts.security = mode
+	}
+}
+
+// WithFieldTruncation makes CreateAll, UpdateAll and UpsertAll cut string values which are longer than their fields
+// allow to the field length before sending them, instead of having salesforce fail the records with STRING_TOO_LONG.
+// This emulates the AllowFieldTruncationHeader of the SOAP API, which the REST API does not support. The records passed
+// in are left unchanged. onTruncate, which may be nil, is called for every truncated value, e.g. to log a warning.
+func WithFieldTruncation(onTruncate func(Truncation)) BatchOption {
+	return func(opts *batchOptions) {
+		opts.truncate = true
+		opts.truncated = onTruncate
+	}
+}
+
+// WithBulkThreshold makes CreateAll, UpdateAll, UpsertAll and DeleteAll save threshold or more records with Bulk API
+// 2.0 ingest jobs, one per object, instead of collection requests, which saves API requests for large slices. The
+// helpers block until the jobs are complete and return the results the same way. The progress callback is only called
+// once all records are processed. Bulk jobs are not used with WithAllOrNone, which they do not support, or if threshold
+// is not positive, the default.
+func WithBulkThreshold(threshold int) BatchOption {
+	return func(opts *batchOptions) {
+		opts.bulkFrom = threshold
+	}
+}
+
+func newBatchOptions(opts []BatchOption) *batchOptions {
+	options := &batchOptions{batchSize: maxCollectionSize}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// BatchResult summarizes a batched DML operation driven by a query. Changes is the number of records which are (or,
+// in dry-run mode, would be) modified by helpers that skip records already up to date.
+type BatchResult struct {
+	Matched   int
+	Changes   int
+	Succeeded int
+	Failed    int
+}
+
+// saveChunks sends count records in chunks through send, which is given the bounds of each chunk. The returned
+// results are aligned with the input. Once a request fails as a whole, the records of that chunk and of all following
+// chunks get a failed SaveResult, a RecordError wrapping the request error, and no further request is sent; the
+// request error is returned too. offset is added to the indexes of RecordErrors and done is called after each chunk.
+func saveChunks(
+	count, offset int,
+	idOf func(int) string,
+	send func(start, end int) ([]SaveResult, error),
+	options *batchOptions,
+	done func(end int),
+) ([]SaveResult, []*RecordError, error) {
+	results := make([]SaveResult, 0, count)
+	var failures []*RecordError
+	var reqErr error
+
+	for start := 0; start < count; start += options.batchSize {
+		end := start + options.batchSize
+		if end > count {
+			end = count
+		}
+
+		var saveResults []SaveResult
+		if reqErr == nil {
+			saveResults, reqErr = send(start, end)
+			if reqErr == nil && len(saveResults) != end-start {
+				reqErr = fmt.Errorf("expected %d results, got %d", end-start, len(saveResults))
+			}
+			if reqErr != nil {
+				log.Println(logPrefix, "batch request failed,", reqErr)
+			}
+		}
+		if reqErr != nil {
+			saveResults = make([]SaveResult, end-start)
+		}
+
+		for idx, saveResult := range saveResults {
+			results = append(results, saveResult)
+			if saveResult.Success {
+				continue
+			}
+			recordErr := &RecordError{Index: offset + start + idx, ID: saveResult.ID, Errors: saveResult.Errors}
+			if recordErr.ID == "" && idOf != nil {
+				recordErr.ID = idOf(start + idx)
+			}
+			if len(saveResult.Errors) == 0 {
+				recordErr.Err = reqErr
+			}
+			failures = append(failures, recordErr)
+		}
+
+		if reqErr == nil && done != nil {
+			done(end)
+		}
+	}
+
+	return results, failures, reqErr
+}
+
+// DeleteByQuery deletes all records matched by the SOQL query soql, which must select the Id field. Matching IDs are
+// paged through and deleted in collection requests of up to 200 records. If any record fails, a *BatchError is
+// returned whose RecordError indexes count records in query order. If a query or request fails as a whole, the
+// operation stops and the error is returned (within the *BatchError in case of a request). The BatchResult always
+// reflects the work done.
+func (client *Client) DeleteByQuery(soql string, opts ...BatchOption) (*BatchResult, error) {
+	if err := checkBigObjectWrite(queryObjectType(soql), "delete"); err != nil {
+		return &BatchResult{}, err
+	}
+	options := newBatchOptions(opts)
+	result := &BatchResult{}
+	var failures []*RecordError
+
+	q := soql
+	offset := 0
+	for {
+		queryResult, err := client.Query(q)
+		if err != nil {
+			return result, err
+		}
+		result.Matched = queryResult.TotalSize
+
+		ids := make([]string, 0, len(queryResult.Records))
+		for idx := range queryResult.Records {
+			ids = append(ids, queryResult.Records[idx].ID())
+		}
+
+		results, pageFailures, reqErr := saveChunks(len(ids), offset,
+			func(idx int) string { return ids[idx] },
+			func(start, end int) ([]SaveResult, error) {
+				return client.deleteCollection(ids[start:end], options.allOrNone)
+			},
+			options,
+			func(end int) {
+				if options.progress != nil {
+					options.progress(offset+end, result.Matched)
+				}
+			},
+		)
+		result.tally(results)
+		failures = append(failures, pageFailures...)
+		offset += len(ids)
+
+		if reqErr != nil || queryResult.Done || queryResult.NextRecordsURL == "" {
+			break
+		}
+		q = queryResult.NextRecordsURL
+	}
+
+	return result, newBatchError(failures)
+}
+
+// UpdateByQuery applies the field patch fields to all records matched by the SOQL query soql, which must select the Id
+// field. Records which already hold the patched values are skipped if the patched fields are selected by the query.
+// Updates are sent in collection requests of up to 200 records as the query results are paged through. With
+// WithDryRun, no update is sent and the Changes of the returned BatchResult reports how many records would change.
+// Errors are reported the same way as DeleteByQuery, except that RecordError indexes only count changed records.
+func (client *Client) UpdateByQuery(soql string, fields map[string]interface{}, opts ...BatchOption) (*BatchResult, error) {
+	if err := checkBigObjectWrite(queryObjectType(soql), "update"); err != nil {
+		return &BatchResult{}, err
+	}
+	options := newBatchOptions(opts)
+	result := &BatchResult{}
+	var failures []*RecordError
+
+	q := soql
+	offset := 0
+	for {
+		queryResult, err := client.Query(q)
+		if err != nil {
+			return result, err
+		}
+		result.Matched = queryResult.TotalSize
+
+		var pending []*SObject
+		for idx := range queryResult.Records {
+			record := &queryResult.Records[idx]
+			if !needsPatch(record, fields) {
+				continue
+			}
+			result.Changes++
+			if options.dryRun {
+				continue
+			}
+
+			patch := client.SObject(record.Type())
+			patch.setID(record.ID())
+			for key, val := range fields {
+				patch.Set(key, val)
+			}
+			pending = append(pending, patch)
+		}
+
+		results, pageFailures, reqErr := saveChunks(len(pending), offset,
+			func(idx int) string { return pending[idx].ID() },
+			func(start, end int) ([]SaveResult, error) {
+				return client.updateCollection(pending[start:end], options.allOrNone)
+			},
+			options,
+			func(end int) {
+				if options.progress != nil {
+					options.progress(offset+end, result.Changes)
+				}
+			},
+		)
+		result.tally(results)
+		failures = append(failures, pageFailures...)
+		offset += len(pending)
+
+		if reqErr != nil || queryResult.Done || queryResult.NextRecordsURL == "" {
+			break
+		}
+		q = queryResult.NextRecordsURL
+	}
+
+	return result, newBatchError(failures)
+}
+
+// tally accumulates the outcome of saveResults into the BatchResult.
+func (result *BatchResult) tally(saveResults []SaveResult) {
+	for _, saveResult := range saveResults {
+		if saveResult.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+}
+
+// needsPatch reports whether applying fields to record would modify it. Fields not present on record are assumed to
+// differ.
+func needsPatch(record *SObject, fields map[string]interface{}) bool {
+	for key, val := range fields {
+		current, ok := (*record)[key]
+		if !ok {
+			return true
+		}
+		// Compare JSON representations so that e.g. int patch values match float64 values decoded from responses.
+		currentData, err1 := json.Marshal(current)
+		valData, err2 := json.Marshal(val)
+		if err1 != nil || err2 != nil || !bytes.Equal(currentData, valData) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAll creates any number of records, chunking them into collection requests of up to 200 records. The returned
+// SaveResults are aligned with records, and the IDs of successfully created records are set in place. If any record
+// fails, a *BatchError is returned as well. If a request fails as a whole, the remaining records are not sent and are
+// reported as failed with the request error. Large slices can be saved with Bulk API 2.0 jobs, see WithBulkThreshold.
+func (client *Client) CreateAll(records []*SObject, opts ...BatchOption) ([]SaveResult, error) {
+	options := newBatchOptions(opts)
+	save := client.bulkEscalation(len(records), options, BulkInsert, "", "", client.createCollection)
+	results, err := client.saveAll("create", "", records, save, options)
+	for idx, saveResult := range results {
+		if saveResult.Success && saveResult.ID != "" {
+			records[idx].setID(saveResult.ID)
+		}
+	}
+	return results, err
+}
+
+// UpdateAll updates any number of records, which must have their IDs set, chunking them into collection requests of
+// up to 200 records. Results are returned the same way as CreateAll.
+func (client *Client) UpdateAll(records []*SObject, opts ...BatchOption) ([]SaveResult, error) {
+	for _, record := range records {
+		if err := checkBigObjectWrite(record.Type(), "update"); err != nil {
+			return nil, err
+		}
+	}
+	options := newBatchOptions(opts)
+	save := client.bulkEscalation(len(records), options, BulkUpdate, "", "", client.updateCollection)
+	return client.saveAll("update", "", records, save, options)
+}
+
+// UpsertAll upserts any number of records of objectType by the external ID field externalIDField, which every record
+// must have set, chunking them into collection requests of up to 200 records. The Created flag of each SaveResult tells
+// whether the record was inserted. Results and IDs are returned the same way as CreateAll.
+func (client *Client) UpsertAll(
+	objectType, externalIDField string,
+	records []*SObject,
+	opts ...BatchOption,
+) ([]SaveResult, error) {
+	upsert := func(chunk []*SObject, allOrNone bool) ([]SaveResult, error) {
+		return client.upsertCollection(objectType, externalIDField, chunk, allOrNone)
+	}
+	options := newBatchOptions(opts)
+	save := client.bulkEscalation(len(records), options, BulkUpsert, objectType, externalIDField, upsert)
+	results, err := client.saveAll("upsert", objectType, records, save, options)
+	for idx, saveResult := range results {
+		if saveResult.Success && saveResult.ID != "" {
+			records[idx].setID(saveResult.ID)
+		}
+	}
+	return results, err
+}
+
+// DeleteAll deletes any number of records by ID, chunking them into collection requests of up to 200 records. Results
+// are returned the same way as CreateAll.
+func (client *Client) DeleteAll(ids []string, opts ...BatchOption) ([]SaveResult, error) {
+	options := newBatchOptions(opts)
+	records := make([]*SObject, len(ids))
+	for idx, id := range ids {
+		records[idx] = &SObject{sobjectIDKey: id}
+	}
+	deleteRecords := func(chunk []*SObject, allOrNone bool) ([]SaveResult, error) {
+		chunkIDs := make([]string, len(chunk))
+		for idx, record := range chunk {
+			chunkIDs[idx] = record.ID()
+		}
+		return client.deleteCollection(chunkIDs, allOrNone)
+	}
+	save := client.bulkEscalation(len(ids), options, BulkDelete, "", "", deleteRecords)
+	results, failures, _ := saveChunks(len(ids), 0,
+		func(idx int) string { return ids[idx] },
+		func(start, end int) ([]SaveResult, error) {
+			return save(records[start:end], options.allOrNone)
+		},
+		options,
+		func(end int) {
+			if options.progress != nil {
+				options.progress(end, len(ids))
+			}
+		},
+	)
+	return results, newBatchError(failures)
+}
+
+// bulkEscalation returns save, or a function saving records with Bulk API 2.0 ingest jobs of operation if count reaches
+// the bulk threshold of options, see WithBulkThreshold. In the latter case, options are changed to pass all records at
+// once. Deletes are saved by the IDs of the records, whose types are told by the key prefixes.
+func (client *Client) bulkEscalation(
+	count int,
+	options *batchOptions,
+	operation, objectType, externalIDField string,
+	save func([]*SObject, bool) ([]SaveResult, error),
+) func([]*SObject, bool) ([]SaveResult, error) {
+	if options.bulkFrom <= 0 || count < options.bulkFrom || options.allOrNone {
+		return save
+	}
+	options.batchSize = count
+	return func(records []*SObject, _ bool) ([]SaveResult, error) {
+		if operation == BulkDelete {
+			return client.bulkDelete(records)
+		}
+		return client.bulkSave(operation, objectType, externalIDField, records)
+	}
+}
+
+// saveAll chunks records into collection requests sent with save. Depending on the options, restricted fields are first
+// stripped or rejected, long strings truncated and the records validated for operation, see validateRecords.
+func (client *Client) saveAll(
+	operation, objectType string,
+	records []*SObject,
+	save func([]*SObject, bool) ([]SaveResult, error),
+	options *batchOptions,
+) ([]SaveResult, error) {
+	invalid := map[int][]SaveError{}
+	if options.security != FieldSecurityOff {
+		restricted, err := client.restrictedFields(operation, objectType, records)
+		if err != nil {
+			return nil, err
+		}
+		if options.security == FieldSecurityStrip {
+			records = stripFields(records, restricted)
+		} else {
+			invalid = restricted
+		}
+	}
+	if options.truncate {
+		truncated, err := client.truncateRecords(objectType, records, options.truncated)
+		if err != nil {
+			return nil, err
+		}
+		records = truncated
+	}
+	if options.validate || options.dryRun {
+		validationErrs, err := client.validateRecords(operation, objectType, records)
+		if err != nil {
+			return nil, err
+		}
+		for idx, errs := range validationErrs {
+			for _, saveErr := range errs {
+				if options.security == FieldSecurityError &&
+					(saveErr.StatusCode == errcode.InvalidField || saveErr.StatusCode == errcode.InvalidFieldForInsertUpdate) {
+					// Already reported per field with the reason.
+					continue
+				}
+				invalid[idx] = append(invalid[idx], saveErr)
+			}
+		}
+	}
+	if len(invalid) > 0 || options.dryRun {
+		return client.saveValidated(records, invalid, save, options)
+	}
+	results, failures, _ := saveChunks(len(records), 0,
+		func(idx int) string { return records[idx].ID() },
+		func(start, end int) ([]SaveResult, error) {
+			return save(records[start:end], options.allOrNone)
+		},
+		options,
+		func(end int) {
+			if options.progress != nil {
+				options.progress(end, len(records))
+			}
+		},
+	)
+	return results, newBatchError(failures)
+}
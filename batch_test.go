@@ -0,0 +1,255 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestClient_DeleteByQuery(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalSize":      3,
+				"done":           false,
+				"nextRecordsUrl": "/services/data/v" + DefaultAPIVersion + "/query/01g-2000",
+				"records":        []map[string]interface{}{{"Id": "001A"}, {"Id": "001B"}},
+			})
+		case strings.HasSuffix(r.URL.Path, "/query/01g-2000"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalSize": 3,
+				"done":      true,
+				"records":   []map[string]interface{}{{"Id": "001C"}},
+			})
+		case strings.HasSuffix(r.URL.Path, "/composite/sobjects") && r.Method == http.MethodDelete:
+			var results []SaveResult
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				deleted = append(deleted, id)
+				if id == "001B" {
+					results = append(results, SaveResult{Errors: []SaveError{{StatusCode: "ENTITY_IS_DELETED", Message: "deleted"}}})
+				} else {
+					results = append(results, SaveResult{ID: id, Success: true})
+				}
+			}
+			json.NewEncoder(w).Encode(results)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	var progress []int
+	result, err := client.DeleteByQuery("SELECT Id FROM Account", WithBatchSize(1), WithProgress(func(processed, total int) {
+		progress = append(progress, processed)
+	}))
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Records) != 1 || batchErr.Records[0].Index != 1 || batchErr.Records[0].ID != "001B" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if strings.Join(deleted, ",") != "001A,001B,001C" {
+		t.Errorf("unexpected deletes %v", deleted)
+	}
+	if result.Matched != 3 || result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(progress) != 3 || progress[2] != 3 {
+		t.Errorf("unexpected progress %v", progress)
+	}
+}
+
+func TestClient_UpdateByQuery(t *testing.T) {
+	var updates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalSize": 3,
+				"done":      true,
+				"records": []map[string]interface{}{
+					{"attributes": map[string]string{"type": "Account"}, "Id": "001A", "Rating": "Hot", "NumberOfEmployees": 10},
+					{"attributes": map[string]string{"type": "Account"}, "Id": "001B", "Rating": "Cold", "NumberOfEmployees": 10},
+					{"attributes": map[string]string{"type": "Account"}, "Id": "001C", "Rating": "Hot", "NumberOfEmployees": 5},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/composite/sobjects") && r.Method == http.MethodPatch:
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			var results []SaveResult
+			for _, record := range body.Records {
+				updates++
+				if record["Rating"] != "Hot" || record["attributes"].(map[string]interface{})["type"] != "Account" {
+					t.Errorf("unexpected record %v", record)
+				}
+				results = append(results, SaveResult{ID: record["Id"].(string), Success: true})
+			}
+			json.NewEncoder(w).Encode(results)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	fields := map[string]interface{}{"Rating": "Hot", "NumberOfEmployees": 10}
+
+	result, err := client.UpdateByQuery("SELECT Id, Rating, NumberOfEmployees FROM Account", fields, WithDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates != 0 || result.Matched != 3 || result.Changes != 2 {
+		t.Errorf("unexpected dry-run result %+v", result)
+	}
+
+	result, err = client.UpdateByQuery("SELECT Id, Rating, NumberOfEmployees FROM Account", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates != 2 || result.Changes != 2 || result.Succeeded != 2 || result.Failed != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_CreateAll(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/composite/sobjects") || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		requests++
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Records) > maxCollectionSize {
+			t.Errorf("too many records in one request: %d", len(body.Records))
+		}
+		var results []SaveResult
+		for _, record := range body.Records {
+			results = append(results, SaveResult{ID: "ID-" + record["Name"].(string), Success: true})
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	var records []*SObject
+	for i := 0; i < 450; i++ {
+		records = append(records, client.SObject("Account").Set("Name", strconv.Itoa(i)))
+	}
+	results, err := client.CreateAll(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 || len(results) != len(records) {
+		t.Fatalf("unexpected requests %d, results %d", requests, len(results))
+	}
+	for i, record := range records {
+		if record.ID() != "ID-"+strconv.Itoa(i) || results[i].ID != record.ID() {
+			t.Errorf("unexpected ID %s at %d", record.ID(), i)
+		}
+	}
+}
+
+func TestClient_DeleteAllRequestFailure(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`[{"message":"bad request","errorCode":"MALFORMED_QUERY"}]`))
+			return
+		}
+		var results []SaveResult
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			results = append(results, SaveResult{ID: id, Success: true})
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	ids := []string{"001A", "001B", "001C", "001D", "001E"}
+	results, err := client.DeleteAll(ids, WithBatchSize(2))
+	if requests != 2 || len(results) != len(ids) {
+		t.Fatalf("unexpected requests %d, results %d", requests, len(results))
+	}
+	if !results[0].Success || !results[1].Success || results[2].Success || results[4].Success {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	indexes := batchErr.Indexes()
+	if len(indexes) != 3 || indexes[0] != 2 || indexes[2] != 4 || batchErr.Records[2].ID != "001E" {
+		t.Errorf("unexpected failures %v", indexes)
+	}
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) || sfErr.ErrorCode != "MALFORMED_QUERY" {
+		t.Errorf("request error not unwrapped from %v", err)
+	}
+}
+
+func TestClient_UpsertAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v"+DefaultAPIVersion+"/composite/sobjects/Account/External_Id__c" ||
+			r.Method != http.MethodPatch {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var results []SaveResult
+		for _, record := range body.Records {
+			if _, ok := record["Id"]; ok || record["attributes"].(map[string]interface{})["type"] != "Account" {
+				t.Errorf("unexpected record %v", record)
+			}
+			switch record["External_Id__c"] {
+			case "A-1":
+				results = append(results, SaveResult{ID: "001A", Success: true, Created: true})
+			case "A-2":
+				results = append(results, SaveResult{ID: "001B", Success: true})
+			default:
+				results = append(results, SaveResult{Errors: []SaveError{{StatusCode: "MISSING_ARGUMENT", Message: "missing"}}})
+			}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	records := []*SObject{
+		client.SObject("Account").Set("External_Id__c", "A-1").Set("Name", "New"),
+		client.SObject("Account").Set("External_Id__c", "A-2").Set("Name", "Existing"),
+		client.SObject("Account").Set("Name", "No external ID"),
+	}
+	records[1].setID("001B")
+	results, err := client.UpsertAll("Account", "External_Id__c", records)
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Records) != 1 || batchErr.Records[0].Index != 2 {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !results[0].Created || results[1].Created || records[0].ID() != "001A" || records[2].ID() != "" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
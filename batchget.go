@@ -0,0 +1,118 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxRetrieveIDs is the maximum number of IDs of a single sObject Collections retrieve request.
+	maxRetrieveIDs = 2000
+
+	// maxConcurrentRetrieves limits the number of retrieve requests BatchGet sends at once.
+	maxConcurrentRetrieves = 4
+)
+
+// BatchGet retrieves the records of objectType with the given IDs, returning them keyed by the IDs as passed in. The
+// IDs are retrieved with sObject Collections requests of up to 2000 IDs, sent concurrently, which is much faster
+// than querying or getting the records one by one. Only fields are retrieved, or all fields of the object if fields is
+// empty. IDs which do not exist or are not visible to the user are missing from the result. If a request fails, the
+// records retrieved by the others are returned along with the first error.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_sobjects_collections_retrieve.htm
+func (client *Client) BatchGet(ids []string, objectType string, fields []string) (map[string]*SObject, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	if len(fields) == 0 {
+		var err error
+		fields, err = client.QueryFields(objectType, FieldsAll)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var unique []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	// Do not use makeURL here as the requests are sent from several goroutines.
+	u := fmt.Sprintf("%s/services/data/v%s/composite/sobjects/%s",
+		client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"), url.PathEscape(objectType))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	result := make(map[string]*SObject, len(unique))
+	sem := make(chan struct{}, maxConcurrentRetrieves)
+	for start := 0; start < len(unique); start += maxRetrieveIDs {
+		end := start + maxRetrieveIDs
+		if end > len(unique) {
+			end = len(unique)
+		}
+		chunk := unique[start:end]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			records, err := client.retrieveCollection(u, chunk, fields)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for idx, record := range records {
+				if record != nil {
+					result[chunk[idx]] = record
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return result, firstErr
+}
+
+// retrieveCollection retrieves the records with ids from the sObject Collections resource at u. The returned records
+// are aligned with ids, nil for records which were not found.
+func (client *Client) retrieveCollection(u string, ids, fields []string) ([]*SObject, error) {
+	reqData, err := client.marshalJSON(map[string][]string{"ids": ids, "fields": fields})
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.httpRequest(http.MethodPost, u, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []map[string]interface{}
+	err = client.unmarshalJSON(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != len(ids) {
+		return nil, fmt.Errorf("expected %d records, got %d", len(ids), len(raw))
+	}
+	records := make([]*SObject, len(raw))
+	for idx, fields := range raw {
+		if fields != nil {
+			obj := SObject(fields)
+			obj.setClient(client)
+			records[idx] = &obj
+		}
+	}
+	return records, nil
+}
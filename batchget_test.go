@@ -0,0 +1,78 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_BatchGet(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			w.Write([]byte(`{"name": "Account", "fields": [{"name": "Id"}, {"name": "Name"}]}`))
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/v54.0/composite/sobjects/Account" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			IDs    []string `json:"ids"`
+			Fields []string `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Join(body.Fields, ",") != "Id,Name" {
+			t.Errorf("unexpected fields %v", body.Fields)
+		}
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		records := make([]interface{}, 0, len(body.IDs))
+		for _, id := range body.IDs {
+			if strings.HasPrefix(id, "missing") {
+				records = append(records, nil)
+				continue
+			}
+			records = append(records, map[string]interface{}{
+				"attributes": map[string]string{"type": "Account"}, "Id": id + "AAA", "Name": "Account " + id,
+			})
+		}
+		json.NewEncoder(w).Encode(records)
+	})
+
+	ids := []string{"001000000000001", "missing", "001000000000001"}
+	for i := 0; i < 2500; i++ {
+		ids = append(ids, "001"+strings.Repeat("0", 8)+string(rune('A'+i/1000))+string(rune('A'+i/100%10))+
+			string(rune('A'+i/10%10))+string(rune('A'+i%10)))
+	}
+	records, err := client.BatchGet(ids, "Account", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2501 || requests != 2 {
+		t.Errorf("expected 2501 records from 2 requests, got %d from %d", len(records), requests)
+	}
+	record := records["001000000000001"]
+	if record == nil || record.ID() != "001000000000001AAA" || record.StringField("Name") != "Account 001000000000001" ||
+		record.client() != client {
+		t.Errorf("unexpected record %v", record)
+	}
+	if _, ok := records["missing"]; ok {
+		t.Error("expected missing records to be left out")
+	}
+}
+
+func TestClient_BatchGetFailure(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`[{"message": "No such column 'Bogus' on entity 'Account'", "errorCode": "INVALID_FIELD"}]`))
+	})
+	records, err := client.BatchGet([]string{"001A"}, "Account", []string{"Bogus"})
+	if sfErr, ok := err.(SalesforceError); !ok || sfErr.ErrorCode != "INVALID_FIELD" || len(records) != 0 {
+		t.Errorf("unexpected result %v, %v", records, err)
+	}
+}
@@ -0,0 +1,201 @@
+package simpleforce
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrBigObjectUnsupported is returned when an operation which big objects do not support, e.g. an update or a
+	// delete, is attempted on one.
+	ErrBigObjectUnsupported = errors.New("operation not supported on big objects")
+
+	// ErrInvalidBigObjectQuery is returned by ValidateBigObjectQuery for queries which do not respect the index.
+	ErrInvalidBigObjectQuery = errors.New("invalid big object query")
+
+	// ErrAsyncSOQLUnavailable is returned when the org does not offer Async SOQL, which has been retired.
+	ErrAsyncSOQLUnavailable = errors.New("async SOQL is not available")
+)
+
+// bigObjectConditionRegexp splits a condition of a WHERE clause into the field and the operator.
+var bigObjectConditionRegexp = regexp.MustCompile(`(?i)^([\w.]+)\s*(=|!=|<>|<=|>=|<|>|\s(?:NOT\s+)?IN\b|\sLIKE\b|\s(?:IN|EX)CLUDES\b)`)
+
+// IsBigObject reports whether objectType is a big object, e.g. "Customer_Interaction__b". Big object records are
+// inserted, or overwritten if a record with the same index exists, with Create or CreateAll; they cannot be updated
+// or deleted through the REST API, and queries must filter on their index, see ValidateBigObjectQuery.
+func IsBigObject(objectType string) bool {
+	return strings.HasSuffix(strings.ToLower(objectType), "__b")
+}
+
+// checkBigObjectWrite returns ErrBigObjectUnsupported if operation, e.g. "update", is attempted on a big object.
+func checkBigObjectWrite(objectType, operation string) error {
+	if IsBigObject(objectType) {
+		return errors.Wrapf(ErrBigObjectUnsupported, "%s of %s", operation, objectType)
+	}
+	return nil
+}
+
+// queryObjectType returns the object type a query selects from, or an empty string if it cannot be determined.
+func queryObjectType(soql string) string {
+	fromIdx := indexTopLevelKeyword(soql, "FROM")
+	if fromIdx < 0 {
+		return ""
+	}
+	fields := strings.Fields(soql[fromIdx+len("FROM"):])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// ValidateBigObjectQuery checks that soql filters on the fields of the index of a big object, given in index order,
+// the way salesforce requires: the filtered fields must be a leading part of the index without gaps, combined with
+// AND, and all but the last one compared with "=". The last one may also use "<", ">", "<=", ">=" or IN. The error
+// matches ErrInvalidBigObjectQuery and tells which rule is violated.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.bigobjects.meta/bigobjects/big_object_querying.htm
+func ValidateBigObjectQuery(soql string, index []string) error {
+	whereIdx := indexTopLevelKeyword(soql, "WHERE")
+	if whereIdx < 0 {
+		return nil
+	}
+	where := soql[whereIdx+len("WHERE"):]
+	for _, keyword := range []string{"ORDER", "GROUP", "LIMIT", "OFFSET"} {
+		if idx := indexTopLevelKeyword(where, keyword); idx >= 0 {
+			where = where[:idx]
+		}
+	}
+	if indexTopLevelKeyword(where, "OR") >= 0 {
+		return errors.Wrap(ErrInvalidBigObjectQuery, "OR is not supported")
+	}
+
+	var conditions []string
+	for {
+		idx := indexTopLevelKeyword(where, "AND")
+		if idx < 0 {
+			conditions = append(conditions, strings.TrimSpace(where))
+			break
+		}
+		conditions = append(conditions, strings.TrimSpace(where[:idx]))
+		where = where[idx+len("AND"):]
+	}
+
+	operators := make(map[string]string, len(conditions))
+	for _, condition := range conditions {
+		match := bigObjectConditionRegexp.FindStringSubmatch(condition)
+		if match == nil {
+			return errors.Wrapf(ErrInvalidBigObjectQuery, "unsupported condition %q", condition)
+		}
+		operators[strings.ToLower(match[1])] = strings.ToUpper(strings.Join(strings.Fields(match[2]), " "))
+	}
+
+	filtered := 0
+	for _, field := range index {
+		if _, ok := operators[strings.ToLower(field)]; !ok {
+			break
+		}
+		filtered++
+	}
+	if filtered < len(operators) {
+		for field := range operators {
+			if !containsFold(index[:filtered], field) {
+				return errors.Wrapf(ErrInvalidBigObjectQuery, "%s is not a leading field of the index %s", field,
+					strings.Join(index, ", "))
+			}
+		}
+	}
+	for idx, field := range index[:filtered] {
+		operator := operators[strings.ToLower(field)]
+		switch {
+		case operator == "=":
+		case idx == filtered-1 && (operator == "<" || operator == ">" || operator == "<=" || operator == ">=" ||
+			operator == "IN"):
+		default:
+			return errors.Wrapf(ErrInvalidBigObjectQuery, "operator %s is not supported on %s", operator, field)
+		}
+	}
+	return nil
+}
+
+// containsFold reports whether names contains name, compared case-insensitively.
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AsyncQuery is an Async SOQL job, which runs a query in the background and inserts the results into TargetObject,
+// typically a big object. TargetFieldMap maps the selected fields to the fields of the target object.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.bigobjects.meta/bigobjects/async_query_running_queries.htm
+type AsyncQuery struct {
+	JobID          string            `json:"jobId,omitempty"`
+	Query          string            `json:"query"`
+	Operation      string            `json:"operation,omitempty"`
+	TargetObject   string            `json:"targetObject"`
+	TargetFieldMap map[string]string `json:"targetFieldMap"`
+	TargetValueMap map[string]string `json:"targetValueMap,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Message        string            `json:"message,omitempty"`
+}
+
+// SubmitAsyncQuery submits an Async SOQL job and returns it with its JobID and Status set. Operation defaults to
+// "insert". ErrAsyncSOQLUnavailable is returned if the org does not offer Async SOQL.
+func (client *Client) SubmitAsyncQuery(query AsyncQuery) (*AsyncQuery, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	if query.Operation == "" {
+		query.Operation = "insert"
+	}
+
+	reqData, err := client.marshalJSON(query)
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.httpRequest(http.MethodPost, client.makeURL("async-queries/"), bytes.NewReader(reqData))
+	if err != nil {
+		return nil, asyncSOQLError(err)
+	}
+
+	var job AsyncQuery
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// AsyncQueryStatus returns the Async SOQL job jobID with its current Status.
+func (client *Client) AsyncQueryStatus(jobID string) (*AsyncQuery, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("async-queries/"+url.PathEscape(jobID)), nil)
+	if err != nil {
+		return nil, asyncSOQLError(err)
+	}
+
+	var job AsyncQuery
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// asyncSOQLError reports a missing Async SOQL resource as ErrAsyncSOQLUnavailable.
+func asyncSOQLError(err error) error {
+	var sfErr SalesforceError
+	if errors.As(err, &sfErr) && sfErr.HttpCode == http.StatusNotFound && sfErr.ErrorCode == "NOT_FOUND" {
+		return errors.Wrap(ErrAsyncSOQLUnavailable, sfErr.ErrorMessage)
+	}
+	return err
+}
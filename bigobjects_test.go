@@ -0,0 +1,95 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestValidateBigObjectQuery(t *testing.T) {
+	index := []string{"Account__c", "Game_Platform__c", "Play_Date__c"}
+	for soql, valid := range map[string]bool{
+		"SELECT Account__c FROM Customer_Interaction__b":                                                                true,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = '001A' LIMIT 10":                             true,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE account__c = '001A' AND Game_Platform__c IN ('PS', 'XB')": true,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = '001A' AND Game_Platform__c = 'PS' " +
+			"AND Play_Date__c >= 2022-01-01T00:00:00Z AND Play_Date__c < 2022-02-01T00:00:00Z": true,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Game_Platform__c = 'PS'":                            false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = '001A' AND Play_Date__c > TODAY":       false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c > '001A' AND Game_Platform__c = 'PS'":    false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = '001A' OR Account__c = '001B'":         false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c LIKE '001%'":                             false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = 'A OR B' AND Game_Platform__c != 'PS'": false,
+		"SELECT Account__c FROM Customer_Interaction__b WHERE Account__c = '001A' AND Score__c = 10":              false,
+	} {
+		err := ValidateBigObjectQuery(soql, index)
+		if valid != (err == nil) || (err != nil && errors.Cause(err) != ErrInvalidBigObjectQuery) {
+			t.Errorf("unexpected result %v for %s", err, soql)
+		}
+	}
+}
+
+func TestClient_BigObjectWrites(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+
+	record := client.SObject("Customer_Interaction__b").Set("Id", "000000000000000AAA")
+	if record.Update() != nil {
+		t.Error("expected update to fail")
+	}
+	if err := record.Delete(); errors.Cause(err) != ErrBigObjectUnsupported {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := client.UpdateAll([]*SObject{record}); errors.Cause(err) != ErrBigObjectUnsupported {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := client.DeleteByQuery("SELECT Id FROM Customer_Interaction__b"); errors.Cause(err) != ErrBigObjectUnsupported {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestClient_SubmitAsyncQuery(t *testing.T) {
+	available := true
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/async-queries/"):
+			var job AsyncQuery
+			json.NewDecoder(r.Body).Decode(&job)
+			if job.Operation != "insert" || job.TargetObject != "Customer_Interaction__b" {
+				t.Errorf("unexpected job %+v", job)
+			}
+			job.JobID, job.Status = "08PA", "New"
+			json.NewEncoder(w).Encode(job)
+		case strings.HasSuffix(r.URL.Path, "/async-queries/08PA"):
+			w.Write([]byte(`{"jobId": "08PA", "status": "Complete"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	job, err := client.SubmitAsyncQuery(AsyncQuery{
+		Query:          "SELECT Account__c, Play_Date__c FROM Customer_Interaction__b",
+		TargetObject:   "Customer_Interaction__b",
+		TargetFieldMap: map[string]string{"Account__c": "Account__c", "Play_Date__c": "Play_Date__c"},
+	})
+	if err != nil || job.JobID != "08PA" || job.Status != "New" {
+		t.Fatalf("unexpected job %+v, %v", job, err)
+	}
+	if job, err = client.AsyncQueryStatus(job.JobID); err != nil || job.Status != "Complete" {
+		t.Errorf("unexpected status %+v, %v", job, err)
+	}
+
+	available = false
+	if _, err = client.AsyncQueryStatus("08PA"); errors.Cause(err) != ErrAsyncSOQLUnavailable {
+		t.Errorf("unexpected error %v", err)
+	}
+}
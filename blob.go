@@ -0,0 +1,96 @@
+package simpleforce
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// NewBase64JSONReader returns a reader producing the JSON object of fields plus the field blobField holding the base64
+// encoding of content. The body is encoded incrementally in a goroutine as it is read, so large files can be sent to
+// APIs which require base64 JSON bodies, e.g. ContentVersion.VersionData, without holding the whole encoded string in
+// memory. Errors reading content are returned by Read. The reader must be closed, which also stops the goroutine.
+func NewBase64JSONReader(fields map[string]interface{}, blobField string, content io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeBase64JSON(pw, fields, blobField, content))
+	}()
+	return pr
+}
+
+// writeBase64JSON writes the body of NewBase64JSONReader to w.
+func writeBase64JSON(w io.Writer, fields map[string]interface{}, blobField string, content io.Reader) error {
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	head, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	key, err := json.Marshal(blobField)
+	if err != nil {
+		return err
+	}
+
+	// Reopen the marshaled object to append the blob field.
+	head = head[:len(head)-1]
+	if len(fields) > 0 {
+		head = append(head, ',')
+	}
+	head = append(head, key...)
+	head = append(head, ':', '"')
+	if _, err = w.Write(head); err != nil {
+		return err
+	}
+
+	encoder := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err = io.Copy(encoder, content); err != nil {
+		return err
+	}
+	if err = encoder.Close(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, `"}`)
+	return err
+}
+
+// CreateWithBlob creates a record of objectType with fields and the base64 field blobField, e.g. "VersionData" of
+// ContentVersion or "Body" of Attachment, streaming content into the request body. The ID of the new record is
+// returned.
+func (client *Client) CreateWithBlob(
+	objectType string,
+	fields map[string]interface{},
+	blobField string,
+	content io.Reader,
+) (string, error) {
+	u := client.makeURL("sobjects/" + url.PathEscape(objectType) + "/")
+	data, err := client.sendWithBlob(http.MethodPost, u, fields, blobField, content)
+	if err != nil {
+		return "", err
+	}
+
+	obj := &SObject{}
+	err = obj.setIDFromResponseData(data)
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
+
+// sendWithBlob sends fields and the base64 field blobField, streamed from content, to the URL u with method.
+func (client *Client) sendWithBlob(
+	method, u string,
+	fields map[string]interface{},
+	blobField string,
+	content io.Reader,
+) ([]byte, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	body := NewBase64JSONReader(fields, blobField, content)
+	defer body.Close()
+	return client.httpRequest(method, u, body)
+}
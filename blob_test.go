@@ -0,0 +1,65 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewBase64JSONReader(t *testing.T) {
+	content := bytes.Repeat([]byte("simpleforce\x00\xff"), 10000)
+	reader := NewBase64JSONReader(map[string]interface{}{"Name": "a\"b"}, "Body", bytes.NewReader(content))
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]string
+	if err = json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body["Body"])
+	if err != nil || !bytes.Equal(decoded, content) || body["Name"] != "a\"b" {
+		t.Errorf("unexpected body %v", err)
+	}
+
+	data, _ = ioutil.ReadAll(NewBase64JSONReader(nil, "Body", strings.NewReader("hi")))
+	if string(data) != `{"Body":"aGk="}` {
+		t.Errorf("unexpected body %s", data)
+	}
+}
+
+func TestClient_UploadFileToContentVersionStreamed(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/sobjects/ContentVersion/"):
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["VersionData"] != base64.StdEncoding.EncodeToString([]byte("file content")) ||
+				body["PathOnClient"] != "notes.txt" || body["Title"] != "Notes" || body["FirstPublishLocationId"] != "001A" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "068A", "success": true, "errors": []}`))
+		default:
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+				{"attributes": {"type": "ContentVersion"}, "ContentDocumentId": "069A"}]}`))
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("file content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	versionID, documentID, err := client.UploadFileToContentVersion(path, "001A", WithTitle("Notes"))
+	if err != nil || versionID != "068A" || documentID != "069A" {
+		t.Errorf("unexpected upload %s %s %v", versionID, documentID, err)
+	}
+}
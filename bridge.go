@@ -0,0 +1,113 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBridgeMaxRetries   = 5
+	defaultBridgeRetryBackoff = time.Second
+)
+
+// EventBridge relays streaming events to an HTTP endpoint, turning change data capture and platform events into
+// webhooks. Each event is POSTed as its CometD JSON message with the X-Salesforce-Channel and X-Salesforce-Replay-Id
+// headers set. Delivery is retried with exponential backoff; an event is only considered delivered, and Checkpoint is
+// only called, once the endpoint responds with a 2xx status. If an event cannot be delivered, Run stops so the bridge
+// can be restarted from the last checkpoint, giving at-least-once semantics.
+type EventBridge struct {
+	Subscriber *Subscriber
+	Endpoint   string
+
+	// HTTPClient is used to call Endpoint; http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+	// Header holds additional headers sent with every delivery, e.g. for authentication.
+	Header http.Header
+	// MaxRetries is the number of retries after the first failed delivery attempt. Defaults to 5.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each further retry. Defaults to 1 second.
+	RetryBackoff time.Duration
+	// Checkpoint is called after each successful delivery, e.g. to persist the replay ID of the channel.
+	Checkpoint func(channel string, replayID int64)
+}
+
+// Run relays events until the Subscriber is stopped, in which case nil is returned, or an event cannot be delivered.
+func (bridge *EventBridge) Run() error {
+	return bridge.Subscriber.Run(bridge.deliver)
+}
+
+// Stop stops the underlying Subscriber.
+func (bridge *EventBridge) Stop() {
+	bridge.Subscriber.Stop()
+}
+
+// deliver posts a single event to the endpoint, retrying failed attempts.
+func (bridge *EventBridge) deliver(msg *StreamingMessage) error {
+	maxRetries := bridge.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultBridgeMaxRetries
+	}
+	backoff := bridge.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultBridgeRetryBackoff
+	}
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			log.Println(logPrefix, "event delivery failed, retrying,", err)
+			select {
+			case <-bridge.Subscriber.ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		err = bridge.post(msg)
+		if err == nil {
+			if bridge.Checkpoint != nil {
+				bridge.Checkpoint(msg.Channel, msg.ReplayID)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to deliver event %d of %s: %w", msg.ReplayID, msg.Channel, err)
+}
+
+func (bridge *EventBridge) post(msg *StreamingMessage) error {
+	req, err := http.NewRequestWithContext(bridge.Subscriber.ctx, http.MethodPost, bridge.Endpoint, bytes.NewReader(msg.Raw))
+	if err != nil {
+		return err
+	}
+	for key, values := range bridge.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Salesforce-Channel", msg.Channel)
+	req.Header.Set("X-Salesforce-Replay-Id", strconv.FormatInt(msg.ReplayID, 10))
+
+	httpClient := bridge.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventBridge_Run(t *testing.T) {
+	cometd, _ := newCometDServer(t,
+		`{"channel": "/data/AccountChangeEvent", "data": {"event": {"replayId": 1}, "payload": {"Name": "Acme"}}}`,
+		`{"channel": "/data/AccountChangeEvent", "data": {"event": {"replayId": 2}, "payload": {"Name": "Globex"}}}`,
+	)
+	defer cometd.Close()
+
+	var mu sync.Mutex
+	var attempts int
+	var delivered []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			// Fail the first attempt to exercise the retry.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("X-Test") != "yes" || r.Header.Get("X-Salesforce-Channel") != "/data/AccountChangeEvent" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		delivered = append(delivered, r.Header.Get("X-Salesforce-Replay-Id"))
+	}))
+	defer webhook.Close()
+
+	client := NewClient(cometd.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", cometd.URL)
+	subscriber, err := client.NewSubscriber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriber.Subscribe("/data/AccountChangeEvent", ReplayLatest)
+
+	var checkpoints []int64
+	bridge := &EventBridge{
+		Subscriber:   subscriber,
+		Endpoint:     webhook.URL,
+		Header:       http.Header{"X-Test": []string{"yes"}},
+		RetryBackoff: time.Millisecond,
+		Checkpoint: func(channel string, replayID int64) {
+			checkpoints = append(checkpoints, replayID)
+			if len(checkpoints) == 2 {
+				subscriber.Stop()
+			}
+		},
+	}
+	err = bridge.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(delivered) != 2 || delivered[0] != "1" || delivered[1] != "2" || attempts != 3 {
+		t.Errorf("unexpected deliveries %v after %d attempts", delivered, attempts)
+	}
+	if len(checkpoints) != 2 || checkpoints[1] != 2 {
+		t.Errorf("unexpected checkpoints %v", checkpoints)
+	}
+}
+
+func TestEventBridge_RunDeliveryFailure(t *testing.T) {
+	cometd, _ := newCometDServer(t,
+		`{"channel": "/event/Order_Shipped__e", "data": {"event": {"replayId": 3}, "payload": {}}}`,
+	)
+	defer cometd.Close()
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+
+	client := NewClient(cometd.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", cometd.URL)
+	subscriber, _ := client.NewSubscriber()
+	subscriber.Subscribe("/event/Order_Shipped__e", ReplayLatest)
+
+	bridge := &EventBridge{Subscriber: subscriber, Endpoint: webhook.URL, MaxRetries: 2, RetryBackoff: time.Millisecond}
+	if bridge.Run() == nil {
+		t.Fail()
+	}
+	if subscriber.ReplayID("/event/Order_Shipped__e") != ReplayLatest {
+		t.Error("replay ID advanced for undelivered event")
+	}
+}
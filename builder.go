@@ -0,0 +1,316 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// RecordBuilder builds an SObject to create or update, checking every value against the describe metadata of the
+// object as it is set: unknown and read-only fields, values of the wrong type, values of restricted picklists which are
+// not active and too long strings are collected and returned by Build, together with the required fields missing on
+// creation, so bad payloads are caught before they are sent. The errors carry the status codes salesforce would use.
+//
+//	builder, err := client.NewRecordBuilder("Opportunity")
+//	...
+//	opp, err := builder.Set("Name", "Big deal").Set("StageName", "Prospecting").Set("CloseDate", "2024-06-30").Build()
+type RecordBuilder struct {
+	operation     string
+	objectType    string
+	meta          *SObjectMeta
+	fields        map[string]map[string]interface{}
+	relationships map[string]bool
+
+	obj  *SObject
+	errs map[string]SaveError // by lower case field name
+}
+
+// BuildError reports the problems found by RecordBuilder.Build.
+type BuildError struct {
+	Object string
+	Errors []SaveError
+}
+
+// Error implements the error interface.
+func (err *BuildError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, saveErr := range err.Errors {
+		messages = append(messages, saveErr.Error())
+	}
+	return fmt.Sprintf("invalid %s: %s", err.Object, strings.Join(messages, "; "))
+}
+
+// NewRecordBuilder returns a RecordBuilder for a new record of objectType, described with DescribeSObjects.
+func (client *Client) NewRecordBuilder(objectType string) (*RecordBuilder, error) {
+	return client.newRecordBuilder("create", objectType)
+}
+
+// NewUpdateBuilder returns a RecordBuilder for the changes to the record id of objectType, described with
+// DescribeSObjects. Fields which are not set are left unchanged by the update.
+func (client *Client) NewUpdateBuilder(objectType, id string) (*RecordBuilder, error) {
+	if id == "" {
+		return nil, ErrMissingID
+	}
+	builder, err := client.newRecordBuilder("update", objectType)
+	if err != nil {
+		return nil, err
+	}
+	builder.obj.setID(id)
+	return builder, nil
+}
+
+func (client *Client) newRecordBuilder(operation, objectType string) (*RecordBuilder, error) {
+	metas, err := client.DescribeSObjects(objectType)
+	if err != nil {
+		return nil, err
+	}
+	meta := metas[objectType]
+	fields, relationships := indexFields(meta)
+	return &RecordBuilder{
+		operation:     operation,
+		objectType:    objectType,
+		meta:          meta,
+		fields:        fields,
+		relationships: relationships,
+		obj:           client.SObject(objectType),
+		errs:          make(map[string]SaveError),
+	}, nil
+}
+
+// Set sets field to value, or replaces its value, after checking it. Numbers may be of any Go numeric type or
+// json.Number, datetimes time.Time values or strings, and dates strings like "2024-06-30". Set returns the builder for
+// chained calls.
+func (builder *RecordBuilder) Set(field string, value interface{}) *RecordBuilder {
+	key := strings.ToLower(field)
+	delete(builder.errs, key)
+	if saveErr := builder.check(field, value); saveErr != nil {
+		builder.errs[key] = *saveErr
+	}
+	builder.obj.Set(field, value)
+	return builder
+}
+
+// SetLookupByExternalID sets the lookup relationship relationshipName to reference the related record by one of its
+// external ID fields, see SObject.SetLookupByExternalID. The relationship must exist.
+func (builder *RecordBuilder) SetLookupByExternalID(relationshipName, externalIDField string, value interface{}) *RecordBuilder {
+	key := strings.ToLower(relationshipName)
+	delete(builder.errs, key)
+	if !builder.relationships[key] {
+		builder.errs[key] = SaveError{
+			StatusCode: errcode.InvalidField,
+			Message:    fmt.Sprintf("No such relationship '%s' on sobject of type %s", relationshipName, builder.objectType),
+			Fields:     []string{relationshipName},
+		}
+	}
+	builder.obj.SetLookupByExternalID(relationshipName, externalIDField, value)
+	return builder
+}
+
+// Errors returns the problems found so far, ordered by field, without the check of required fields done by Build.
+func (builder *RecordBuilder) Errors() []SaveError {
+	keys := make([]string, 0, len(builder.errs))
+	for key := range builder.errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	errs := make([]SaveError, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, builder.errs[key])
+	}
+	return errs
+}
+
+// Build returns the SObject, ready to be created or updated, or a *BuildError with all the problems found if any.
+func (builder *RecordBuilder) Build() (*SObject, error) {
+	errs := builder.Errors()
+	if builder.operation == "create" {
+		var missing []string
+		rawFields, _ := (*builder.meta)["fields"].([]interface{})
+		for _, rawField := range rawFields {
+			field, _ := rawField.(map[string]interface{})
+			name, _ := field["name"].(string)
+			if !requiredOnCreate(field) {
+				continue
+			}
+			if value, present, _ := builder.obj.Value(name); !present || value == nil {
+				if _, reported := builder.errs[strings.ToLower(name)]; !reported {
+					missing = append(missing, name)
+				}
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, SaveError{
+				StatusCode: errcode.RequiredFieldMissing,
+				Message:    fmt.Sprintf("Required fields are missing: [%s]", strings.Join(missing, ", ")),
+				Fields:     missing,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &BuildError{Object: builder.objectType, Errors: errs}
+	}
+	return builder.obj, nil
+}
+
+// check checks value for field, returning the error salesforce would report for it, if any.
+func (builder *RecordBuilder) check(name string, value interface{}) *SaveError {
+	field, ok := builder.fields[strings.ToLower(name)]
+	if !ok {
+		return &SaveError{
+			StatusCode: errcode.InvalidField,
+			Message:    fmt.Sprintf("No such column '%s' on sobject of type %s", name, builder.objectType),
+			Fields:     []string{name},
+		}
+	}
+	if !fieldWritable(builder.operation, field) {
+		return &SaveError{
+			StatusCode: errcode.InvalidFieldForInsertUpdate,
+			Message:    fmt.Sprintf("Unable to create/update fields: %s", name),
+			Fields:     []string{name},
+		}
+	}
+
+	fieldType, _ := field["type"].(string)
+	if value == nil {
+		nillable, _ := field["nillable"].(bool)
+		if nillable || fieldType == "boolean" {
+			return nil
+		}
+		return &SaveError{
+			StatusCode: errcode.RequiredFieldMissing,
+			Message:    fmt.Sprintf("Required fields are missing: [%s]", name),
+			Fields:     []string{name},
+		}
+	}
+
+	if !valueMatchesType(fieldType, value) {
+		return &SaveError{
+			StatusCode: errcode.JSONParserError,
+			Message:    fmt.Sprintf("%s: cannot use %T value %v for a field of type %s", name, value, value, fieldType),
+			Fields:     []string{name},
+		}
+	}
+
+	s, isString := value.(string)
+	if !isString {
+		return nil
+	}
+	length, _ := field["length"].(float64)
+	if textFieldTypes[fieldType] && length > 0 && utf8.RuneCountInString(s) > int(length) {
+		return &SaveError{
+			StatusCode: errcode.StringTooLong,
+			Message:    fmt.Sprintf("%s: data value too large: %s (max length=%d)", name, s, int(length)),
+			Fields:     []string{name},
+		}
+	}
+	if restricted, _ := field["restrictedPicklist"].(bool); restricted {
+		values := []string{s}
+		if fieldType == "multipicklist" {
+			values = strings.Split(s, ";")
+		}
+		active := activePicklistValues(field)
+		for _, v := range values {
+			if !active[v] {
+				return &SaveError{
+					StatusCode: errcode.InvalidOrNullForRestrictedPicklist,
+					Message:    fmt.Sprintf("%s: bad value for restricted picklist field: %s", name, v),
+					Fields:     []string{name},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// activePicklistValues returns the active values of the picklist field.
+func activePicklistValues(field map[string]interface{}) map[string]bool {
+	values := make(map[string]bool)
+	rawValues, _ := field["picklistValues"].([]interface{})
+	for _, rawValue := range rawValues {
+		entry, _ := rawValue.(map[string]interface{})
+		value, _ := entry["value"].(string)
+		if active, _ := entry["active"].(bool); active {
+			values[value] = true
+		}
+	}
+	return values
+}
+
+// valueMatchesType reports whether value can be sent for a field of the describe fieldType.
+func valueMatchesType(fieldType string, value interface{}) bool {
+	switch fieldType {
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		number, ok := numericValue(value)
+		return ok && number == math.Trunc(number)
+	case "double", "currency", "percent":
+		_, ok := numericValue(value)
+		return ok
+	case "date":
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "datetime":
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := ParseDateTime(v)
+			return err == nil
+		}
+		return false
+	case "id", "reference":
+		s, ok := value.(string)
+		return ok && (len(s) == 15 || len(s) == 18)
+	case "address", "location", "anyType", "complexvalue":
+		return true
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+// numericValue returns value as a float64 if it is a number.
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,119 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+const builderDescribe = `{"name": "Opportunity", "fields": [
+	{"name": "Id", "type": "id", "createable": false, "updateable": false, "nillable": false, "defaultedOnCreate": true},
+	{"name": "Name", "type": "string", "length": 12, "createable": true, "updateable": true, "nillable": false},
+	{"name": "StageName", "type": "picklist", "length": 40, "createable": true, "updateable": true, "nillable": false,
+		"restrictedPicklist": true, "picklistValues": [
+			{"value": "Prospecting", "active": true}, {"value": "Closed Won", "active": true},
+			{"value": "Legacy", "active": false}]},
+	{"name": "CloseDate", "type": "date", "createable": true, "updateable": true, "nillable": false},
+	{"name": "Amount", "type": "currency", "createable": true, "updateable": true, "nillable": true},
+	{"name": "TotalOpportunityQuantity", "type": "int", "createable": true, "updateable": true, "nillable": true},
+	{"name": "IsPrivate", "type": "boolean", "createable": true, "updateable": true, "nillable": false,
+		"defaultedOnCreate": true},
+	{"name": "ExpectedRevenue", "type": "currency", "createable": false, "updateable": false, "nillable": true},
+	{"name": "AccountId", "type": "reference", "relationshipName": "Account", "createable": true, "updateable": true,
+		"nillable": true}
+]}`
+
+func newBuilderClient(t *testing.T) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v54.0/sobjects/Opportunity/describe" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(builderDescribe))
+	})
+	return client
+}
+
+func TestRecordBuilder(t *testing.T) {
+	client := newBuilderClient(t)
+	builder, err := client.NewRecordBuilder("Opportunity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := builder.
+		Set("Name", "Big deal").
+		Set("StageName", "Prospecting").
+		Set("CloseDate", "2024-06-30").
+		Set("Amount", 1250.5).
+		Set("TotalOpportunityQuantity", 3).
+		SetLookupByExternalID("Account", "External_Id__c", "ABC-1").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Type() != "Opportunity" || obj.StringField("StageName") != "Prospecting" || obj.client() != client {
+		t.Errorf("unexpected object %v", obj)
+	}
+}
+
+func TestRecordBuilder_Errors(t *testing.T) {
+	client := newBuilderClient(t)
+	builder, err := client.NewRecordBuilder("Opportunity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder.
+		Set("Name", "Much too long a name").
+		Set("StageName", "Legacy").
+		Set("Amount", "lots").
+		Set("TotalOpportunityQuantity", 1.5).
+		Set("ExpectedRevenue", 10).
+		Set("Bogus__c", true).
+		SetLookupByExternalID("Owner", "External_Id__c", "X")
+	// Replacing a value replaces its error.
+	builder.Set("Name", "Fixed")
+
+	_, err = builder.Build()
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("expected a BuildError, got %v", err)
+	}
+	codes := make([]string, 0, len(buildErr.Errors))
+	for _, saveErr := range buildErr.Errors {
+		codes = append(codes, saveErr.Fields[0]+" "+saveErr.StatusCode)
+	}
+	expected := []string{
+		"Amount " + errcode.JSONParserError,
+		"Bogus__c " + errcode.InvalidField,
+		"ExpectedRevenue " + errcode.InvalidFieldForInsertUpdate,
+		"Owner " + errcode.InvalidField,
+		"StageName " + errcode.InvalidOrNullForRestrictedPicklist,
+		"TotalOpportunityQuantity " + errcode.JSONParserError,
+		"CloseDate " + errcode.RequiredFieldMissing,
+	}
+	if strings.Join(codes, "|") != strings.Join(expected, "|") {
+		t.Errorf("unexpected errors %v", buildErr.Errors)
+	}
+}
+
+func TestRecordBuilder_Update(t *testing.T) {
+	client := newBuilderClient(t)
+	builder, err := client.NewUpdateBuilder("Opportunity", "006A00000012345")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := builder.Set("StageName", "Closed Won").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ID() != "006A00000012345" {
+		t.Errorf("unexpected ID %s", obj.ID())
+	}
+
+	if _, err = builder.Set("CloseDate", nil).Build(); err == nil ||
+		!strings.Contains(err.Error(), errcode.RequiredFieldMissing) {
+		t.Errorf("expected clearing a required field to fail, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBulkPollInterval is used by WaitBulkQuery when no positive interval is given.
+const DefaultBulkPollInterval = 2 * time.Second
+
+// ErrBulkJobFailed is returned by WaitBulkQuery when a Bulk API 2.0 job fails or is aborted.
+var ErrBulkJobFailed = errors.New("bulk job failed")
+
+// Bulk API 2.0 job states.
+const (
+	BulkJobUploadComplete = "UploadComplete"
+	BulkJobInProgress     = "InProgress"
+	BulkJobComplete       = "JobComplete"
+	BulkJobFailed         = "Failed"
+	BulkJobAborted        = "Aborted"
+)
+
+// BulkQueryJob is a Bulk API 2.0 query job.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/query_get_one_job.htm
+type BulkQueryJob struct {
+	ID                     string `json:"id"`
+	Operation              string `json:"operation"`
+	Object                 string `json:"object"`
+	State                  string `json:"state"`
+	ErrorMessage           string `json:"errorMessage"`
+	NumberRecordsProcessed int    `json:"numberRecordsProcessed"`
+	Retries                int    `json:"retries"`
+	TotalProcessingTime    int    `json:"totalProcessingTime"`
+}
+
+// CreateBulkQuery starts a Bulk API 2.0 job running soql, including deleted and archived records if queryAll is set.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/query_create_job.htm
+func (client *Client) CreateBulkQuery(soql string, queryAll bool) (*BulkQueryJob, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	operation := "query"
+	if queryAll {
+		operation = "queryAll"
+	}
+	reqData, err := client.marshalJSON(map[string]string{"operation": operation, "query": soql})
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.httpRequest(http.MethodPost, client.makeURL("jobs/query"), bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var job BulkQueryJob
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// BulkQueryStatus returns the Bulk API 2.0 query job id with its current state.
+func (client *Client) BulkQueryStatus(id string) (*BulkQueryJob, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("jobs/query/"+url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var job BulkQueryJob
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitBulkQuery polls the Bulk API 2.0 query job id every interval, DefaultBulkPollInterval if not positive, until it
+// is complete or ctx is canceled. If the job fails or is aborted, the error matches ErrBulkJobFailed with errors.Is.
+func (client *Client) WaitBulkQuery(ctx context.Context, id string, interval time.Duration) (*BulkQueryJob, error) {
+	if interval <= 0 {
+		interval = DefaultBulkPollInterval
+	}
+	for {
+		job, err := client.BulkQueryStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		switch job.State {
+		case BulkJobComplete:
+			return job, nil
+		case BulkJobFailed, BulkJobAborted:
+			return job, errors.Wrapf(ErrBulkJobFailed, "%s: %s", job.State, job.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// BulkQueryResults streams a page of the CSV results of the completed Bulk API 2.0 query job id, starting at locator,
+// or at the first page if empty, with up to maxRecords records, or as many as salesforce chooses if not positive. The
+// locator of the next page is returned, or an empty string after the last page. The caller must close the reader.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/query_get_job_results.htm
+func (client *Client) BulkQueryResults(id, locator string, maxRecords int) (io.ReadCloser, string, error) {
+	if !client.isLoggedIn() {
+		return nil, "", ErrAuthentication
+	}
+
+	params := url.Values{}
+	if locator != "" {
+		params.Set("locator", locator)
+	}
+	if maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+	u := client.makeURL("jobs/query/" + url.PathEscape(id) + "/results")
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Add("Authorization", "Bearer "+client.sessionID)
+	req.Header.Add("Accept", "text/csv")
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return nil, "", client.withRequestContext(ParseSalesforceError(resp.StatusCode, buf.Bytes()), resp.Header)
+	}
+
+	next := resp.Header.Get("Sforce-Locator")
+	if next == "null" {
+		next = ""
+	}
+	return resp.Body, next, nil
+}
+
+// BulkQueryEach runs soql as a Bulk API 2.0 query job, waits for it to complete and calls fn with the header and every
+// row of the CSV results, page by page, so large extractions do not need to fit into memory. Empty cells are null
+// values. If fn returns an error, BulkQueryEach stops and returns it. With a job store, see SetJobStore, the job is
+// recorded until all results are processed or it failed.
+func (client *Client) BulkQueryEach(ctx context.Context, soql string, fn func(header, row []string) error) error {
+	job, err := client.CreateBulkQuery(soql, false)
+	if err != nil {
+		return err
+	}
+	return client.consumeBulkQuery(ctx, JobRecord{ID: job.ID, Kind: JobBulkQuery, State: job.State, Query: soql}, fn)
+}
+
+// consumeBulkQuery waits for the bulk query job to complete and calls fn with the rows of its results, from the page
+// of job.Locator on, keeping the record of the job in the job store up to date.
+func (client *Client) consumeBulkQuery(ctx context.Context, job JobRecord, fn func(header, row []string) error) error {
+	client.saveJob(job)
+	status, err := client.WaitBulkQuery(ctx, job.ID, 0)
+	if errors.Is(err, ErrBulkJobFailed) {
+		client.deleteJob(job.ID)
+	}
+	if err != nil {
+		return err
+	}
+	job.State = status.State
+	client.saveJob(job)
+
+	for {
+		body, next, err := client.BulkQueryResults(job.ID, job.Locator, 0)
+		if err != nil {
+			return err
+		}
+		err = eachCSVRow(body, fn)
+		body.Close()
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			client.deleteJob(job.ID)
+			return nil
+		}
+		job.Locator = next
+		client.saveJob(job)
+	}
+}
+
+// eachCSVRow calls fn with the header and every following row of the CSV document r.
+func eachCSVRow(r io.Reader, fn func(header, row []string) error) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = fn(header, row); err != nil {
+			return err
+		}
+	}
+}
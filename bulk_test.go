@@ -0,0 +1,50 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_BulkQueryEach(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/query"):
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["operation"] != "query" || body["query"] != "SELECT Id, Name FROM Account" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.Write([]byte(`{"id": "750A", "operation": "query", "object": "Account", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750A"):
+			w.Write([]byte(`{"id": "750A", "state": "JobComplete", "numberRecordsProcessed": 3}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750A/results"):
+			if r.URL.Query().Get("locator") == "" {
+				w.Header().Set("Sforce-Locator", "MTAwMA")
+				w.Write([]byte("\"Id\",\"Name\"\n\"001A\",\"Acme, Inc.\"\n\"001B\",\"\"\n"))
+				return
+			}
+			w.Header().Set("Sforce-Locator", "null")
+			w.Write([]byte("\"Id\",\"Name\"\n\"001C\",\"Globex\"\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	var rows []string
+	err := client.BulkQueryEach(context.Background(), "SELECT Id, Name FROM Account", func(header, row []string) error {
+		if strings.Join(header, ",") != "Id,Name" {
+			t.Errorf("unexpected header %v", header)
+		}
+		rows = append(rows, strings.Join(row, "|"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rows, ";") != "001A|Acme, Inc.;001B|;001C|Globex" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
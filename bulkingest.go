@@ -0,0 +1,446 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Bulk API 2.0 ingest operations.
+const (
+	BulkInsert     = "insert"
+	BulkUpdate     = "update"
+	BulkUpsert     = "upsert"
+	BulkDelete     = "delete"
+	BulkHardDelete = "hardDelete"
+)
+
+// Kinds of results of Bulk API 2.0 ingest jobs, see BulkIngestResults.
+const (
+	BulkSuccessfulResults = "successfulResults"
+	BulkFailedResults     = "failedResults"
+	BulkUnprocessed       = "unprocessedrecords"
+)
+
+// bulkNull is the value of null fields in the CSV data of ingest jobs.
+const bulkNull = "#N/A"
+
+// BulkIngestJob is a Bulk API 2.0 ingest job.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/get_job_info.htm
+type BulkIngestJob struct {
+	ID                     string `json:"id"`
+	Operation              string `json:"operation"`
+	Object                 string `json:"object"`
+	ExternalIDFieldName    string `json:"externalIdFieldName"`
+	State                  string `json:"state"`
+	ErrorMessage           string `json:"errorMessage"`
+	NumberRecordsProcessed int    `json:"numberRecordsProcessed"`
+	NumberRecordsFailed    int    `json:"numberRecordsFailed"`
+}
+
+// CreateBulkIngest opens a Bulk API 2.0 job for operation, e.g. BulkInsert, on records of objectType. externalIDField
+// is only used by upserts. The CSV data of the records is uploaded with UploadBulkIngest.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/create_job.htm
+func (client *Client) CreateBulkIngest(objectType, operation, externalIDField string) (*BulkIngestJob, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	body := map[string]string{"object": objectType, "operation": operation, "contentType": "CSV", "lineEnding": "LF"}
+	if operation == BulkUpsert {
+		body["externalIdFieldName"] = externalIDField
+	}
+	reqData, err := client.marshalJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.httpRequest(http.MethodPost, client.makeURL("jobs/ingest"), bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var job BulkIngestJob
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UploadBulkIngest uploads the CSV data of the records of the open ingest job id. Null values are written as "#N/A".
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/upload_job_data.htm
+func (client *Client) UploadBulkIngest(id string, data io.Reader) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	req, err := http.NewRequest(http.MethodPut, client.makeURL("jobs/ingest/"+url.PathEscape(id)+"/batches"), data)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", "Bearer "+client.sessionID)
+	req.Header.Add("Content-Type", "text/csv")
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return client.withRequestContext(ParseSalesforceError(resp.StatusCode, buf.Bytes()), resp.Header)
+	}
+	return nil
+}
+
+// CloseBulkIngest marks the upload of the data of the ingest job id complete, so salesforce starts processing it.
+func (client *Client) CloseBulkIngest(id string) (*BulkIngestJob, error) {
+	return client.setBulkIngestState(id, BulkJobUploadComplete)
+}
+
+// AbortBulkIngest aborts the ingest job id. Records already processed are not rolled back.
+func (client *Client) AbortBulkIngest(id string) (*BulkIngestJob, error) {
+	return client.setBulkIngestState(id, BulkJobAborted)
+}
+
+// setBulkIngestState changes the state of the ingest job id.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/close_job.htm
+func (client *Client) setBulkIngestState(id, state string) (*BulkIngestJob, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	reqData, err := client.marshalJSON(map[string]string{"state": state})
+	if err != nil {
+		return nil, err
+	}
+	u := client.makeURL("jobs/ingest/" + url.PathEscape(id))
+	data, err := client.httpRequest(http.MethodPatch, u, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var job BulkIngestJob
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// BulkIngestStatus returns the Bulk API 2.0 ingest job id with its current state.
+func (client *Client) BulkIngestStatus(id string) (*BulkIngestJob, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("jobs/ingest/"+url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var job BulkIngestJob
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitBulkIngest polls the ingest job id every interval, DefaultBulkPollInterval if not positive, until it is complete
+// or ctx is canceled. If the job fails or is aborted, the error matches ErrBulkJobFailed with errors.Is; the results of
+// the records processed before are still available.
+func (client *Client) WaitBulkIngest(ctx context.Context, id string, interval time.Duration) (*BulkIngestJob, error) {
+	if interval <= 0 {
+		interval = DefaultBulkPollInterval
+	}
+	for {
+		job, err := client.BulkIngestStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		switch job.State {
+		case BulkJobComplete:
+			return job, nil
+		case BulkJobFailed, BulkJobAborted:
+			return job, errors.Wrapf(ErrBulkJobFailed, "%s: %s", job.State, job.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// BulkIngestResults streams the CSV results of kind, e.g. BulkFailedResults, of the ingest job id. Successful results
+// start with the columns "sf__Id" and "sf__Created", failed results with "sf__Id" and "sf__Error", followed by the
+// uploaded columns of the records. The caller must close the reader.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/get_job_successful_results.htm
+func (client *Client) BulkIngestResults(id, kind string) (io.ReadCloser, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	req, err := http.NewRequest(http.MethodGet, client.makeURL("jobs/ingest/"+url.PathEscape(id)+"/"+kind), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "Bearer "+client.sessionID)
+	req.Header.Add("Accept", "text/csv")
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return nil, client.withRequestContext(ParseSalesforceError(resp.StatusCode, buf.Bytes()), resp.Header)
+	}
+	return resp.Body, nil
+}
+
+// bulkSave saves records with one Bulk API 2.0 ingest job of operation per object, objectType or the types of the
+// records if empty, and returns SaveResults aligned with records like the collection requests. Bulk results do not
+// tell which record they belong to, so they are matched to the records by the uploaded values. Records which were not
+// processed, e.g. because the job failed, are failed with the error of the job.
+func (client *Client) bulkSave(operation, objectType, externalIDField string, records []*SObject) ([]SaveResult, error) {
+	byType := map[string][]int{}
+	var types []string
+	for idx, record := range records {
+		recordType := objectType
+		if recordType == "" {
+			recordType = record.Type()
+		}
+		if _, ok := byType[recordType]; !ok {
+			types = append(types, recordType)
+		}
+		byType[recordType] = append(byType[recordType], idx)
+	}
+
+	results := make([]SaveResult, len(records))
+	for _, recordType := range types {
+		indexes := byType[recordType]
+		group := make([]*SObject, 0, len(indexes))
+		for _, idx := range indexes {
+			group = append(group, records[idx])
+		}
+		groupResults, err := client.bulkSaveObject(operation, recordType, externalIDField, group)
+		if err != nil {
+			return nil, err
+		}
+		for pos, idx := range indexes {
+			results[idx] = groupResults[pos]
+		}
+	}
+	return results, nil
+}
+
+// bulkSaveObject saves records of objectType with a single ingest job, see bulkSave.
+func (client *Client) bulkSaveObject(operation, objectType, externalIDField string, records []*SObject) ([]SaveResult, error) {
+	header, rows := bulkRows(operation, externalIDField, records)
+	var data bytes.Buffer
+	writer := csv.NewWriter(&data)
+	writer.Write(header)
+	writer.WriteAll(rows)
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	job, err := client.CreateBulkIngest(objectType, operation, externalIDField)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.UploadBulkIngest(job.ID, &data); err != nil {
+		client.AbortBulkIngest(job.ID)
+		return nil, err
+	}
+	if _, err = client.CloseBulkIngest(job.ID); err != nil {
+		client.AbortBulkIngest(job.ID)
+		return nil, err
+	}
+	status, err := client.WaitBulkIngest(context.Background(), job.ID, 0)
+	if err != nil && !errors.Is(err, ErrBulkJobFailed) {
+		return nil, err
+	}
+
+	pending := map[string][]int{}
+	for idx, row := range rows {
+		key := strings.Join(row, "\x00")
+		pending[key] = append(pending[key], idx)
+	}
+	results := make([]SaveResult, len(records))
+	matched := make([]bool, len(records))
+	for _, kind := range []string{BulkSuccessfulResults, BulkFailedResults} {
+		err = client.eachBulkResult(job.ID, kind, header, func(key string, fields map[string]string) {
+			indexes := pending[key]
+			if len(indexes) == 0 {
+				return
+			}
+			idx := indexes[0]
+			pending[key] = indexes[1:]
+			matched[idx] = true
+			results[idx] = bulkSaveResult(fields)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	message := "not processed by bulk job " + job.ID
+	if status != nil && status.ErrorMessage != "" {
+		message += ": " + status.ErrorMessage
+	}
+	for idx := range results {
+		if !matched[idx] {
+			results[idx] = SaveResult{ID: records[idx].ID(), Errors: []SaveError{{Message: message}}}
+		}
+	}
+	return results, nil
+}
+
+// eachBulkResult calls fn with the uploaded values of every result row of kind of the ingest job id, joined like the
+// rows keyed in bulkSaveObject, and with all its columns by name.
+func (client *Client) eachBulkResult(id, kind string, header []string, fn func(key string, fields map[string]string)) error {
+	body, err := client.BulkIngestResults(id, kind)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return eachCSVRow(body, func(resultHeader, row []string) error {
+		fields := make(map[string]string, len(row))
+		for idx, name := range resultHeader {
+			if idx < len(row) {
+				fields[name] = row[idx]
+			}
+		}
+		values := make([]string, len(header))
+		for idx, name := range header {
+			values[idx] = fields[name]
+		}
+		fn(strings.Join(values, "\x00"), fields)
+		return nil
+	})
+}
+
+// bulkSaveResult converts a row of the successful or failed results of an ingest job to a SaveResult. Errors are
+// reported as "STATUS_CODE:message".
+func bulkSaveResult(fields map[string]string) SaveResult {
+	result := SaveResult{ID: fields["sf__Id"]}
+	failure, failed := fields["sf__Error"]
+	if !failed {
+		result.Success = true
+		result.Created, _ = strconv.ParseBool(fields["sf__Created"])
+		return result
+	}
+	saveErr := SaveError{Message: failure}
+	if pos := strings.Index(failure, ":"); pos > 0 {
+		saveErr.StatusCode = failure[:pos]
+		saveErr.Message = failure[pos+1:]
+	}
+	result.Errors = []SaveError{saveErr}
+	return result
+}
+
+// bulkRows returns the CSV header and rows of records for an ingest job of operation. Deletes only send the IDs,
+// inserts and upserts no IDs. Lookups by external ID, i.e. nested records, become columns like "Account.ExtId__c".
+func bulkRows(operation, externalIDField string, records []*SObject) ([]string, [][]string) {
+	values := make([]map[string]string, len(records))
+	columns := map[string]bool{}
+	for idx, record := range records {
+		fields := map[string]string{}
+		switch operation {
+		case BulkDelete, BulkHardDelete:
+			fields[sobjectIDKey] = record.ID()
+		default:
+			for key, val := range record.makeCopy() {
+				if nested, ok := val.(map[string]interface{}); ok {
+					for nestedKey, nestedVal := range nested {
+						if nestedKey != sobjectAttributesKey {
+							fields[key+"."+nestedKey] = bulkValue(nestedVal)
+						}
+					}
+					continue
+				}
+				fields[key] = bulkValue(val)
+			}
+			if operation == BulkUpdate {
+				fields[sobjectIDKey] = record.ID()
+			} else if operation == BulkUpsert {
+				fields[externalIDField] = bulkValue((*record)[externalIDField])
+			}
+		}
+		for key := range fields {
+			columns[key] = true
+		}
+		values[idx] = fields
+	}
+
+	header := make([]string, 0, len(columns))
+	for column := range columns {
+		header = append(header, column)
+	}
+	sort.Strings(header)
+	rows := make([][]string, len(records))
+	for idx, fields := range values {
+		row := make([]string, len(header))
+		for col, name := range header {
+			// Fields missing on a record are left empty, which bulk jobs do not change, unlike bulkNull.
+			row[col] = fields[name]
+		}
+		rows[idx] = row
+	}
+	return header, rows
+}
+
+// bulkValue formats a field value for the CSV data of an ingest job like its JSON representation.
+func bulkValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return bulkNull
+	case string:
+		return v
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return ""
+	}
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		// e.g. time.Time
+		return str
+	}
+	return string(data)
+}
+
+// bulkDelete deletes records by ID with bulkSave, with one job per object told by the key prefixes of the IDs.
+func (client *Client) bulkDelete(records []*SObject) ([]SaveResult, error) {
+	objects, err := client.ListSObjects()
+	if err != nil {
+		return nil, err
+	}
+	typed := make([]*SObject, len(records))
+	for idx, record := range records {
+		info := objects.ByKeyPrefix(record.ID())
+		if info == nil {
+			return nil, errors.Errorf("no object with the key prefix of %s", record.ID())
+		}
+		typed[idx] = client.SObject(info.Name)
+		typed[idx].setID(record.ID())
+	}
+	return client.bulkSave(BulkDelete, "", "", typed)
+}
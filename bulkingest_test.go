@@ -0,0 +1,103 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_CreateAllBulk(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/ingest"):
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["object"] != "Account" || body["operation"] != "insert" || body["contentType"] != "CSV" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.Write([]byte(`{"id": "750I", "state": "Open"}`))
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I/batches"):
+			data, _ := ioutil.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "text/csv" ||
+				string(data) != "Name,NumberOfEmployees,Parent.ExtId__c\nAcme,#N/A,\nGlobex,5,P1\nAcme,#N/A,\n" {
+				t.Errorf("unexpected upload %s %q", r.Header.Get("Content-Type"), data)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I"):
+			w.Write([]byte(`{"id": "750I", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I"):
+			w.Write([]byte(`{"id": "750I", "state": "JobComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I/successfulResults"):
+			w.Write([]byte("\"sf__Id\",\"sf__Created\",\"Name\",\"NumberOfEmployees\",\"Parent.ExtId__c\"\n" +
+				"\"001B\",\"true\",\"Acme\",\"#N/A\",\"\"\n\"001A\",\"true\",\"Acme\",\"#N/A\",\"\"\n"))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I/failedResults"):
+			w.Write([]byte("\"sf__Id\",\"sf__Error\",\"Name\",\"NumberOfEmployees\",\"Parent.ExtId__c\"\n" +
+				"\"\",\"INVALID_FIELD:Foreign key external ID: p1 not found\",\"Globex\",\"5\",\"P1\"\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	records := []*SObject{
+		client.SObject("Account").Set("Name", "Acme").Set("NumberOfEmployees", nil),
+		client.SObject("Account").Set("Name", "Globex").Set("NumberOfEmployees", 5).
+			Set("Parent", map[string]interface{}{"attributes": map[string]string{"type": "Account"}, "ExtId__c": "P1"}),
+		client.SObject("Account").Set("Name", "Acme").Set("NumberOfEmployees", nil),
+	}
+	results, err := client.CreateAll(records, WithBulkThreshold(3))
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Records) != 1 || batchErr.Records[0].Index != 1 {
+		t.Fatalf("expected a BatchError for record 1, got %v", err)
+	}
+	if len(results) != 3 || !results[0].Success || !results[2].Success || results[1].Success {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if records[0].ID() != "001B" || records[2].ID() != "001A" || records[1].ID() != "" {
+		t.Errorf("unexpected IDs %s %s %s", records[0].ID(), records[1].ID(), records[2].ID())
+	}
+	if saveErr := results[1].Errors[0]; saveErr.StatusCode != "INVALID_FIELD" ||
+		saveErr.Message != "Foreign key external ID: p1 not found" {
+		t.Errorf("unexpected error %+v", saveErr)
+	}
+}
+
+func TestClient_DeleteAllBulk(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects"):
+			w.Write([]byte(`{"sobjects": [{"name": "Account", "keyPrefix": "001"}]}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/ingest"):
+			w.Write([]byte(`{"id": "750D", "state": "Open"}`))
+		case r.Method == http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			if string(data) != "Id\n001A\n001B\n" {
+				t.Errorf("unexpected upload %q", data)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPatch:
+			w.Write([]byte(`{"id": "750D", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750D"):
+			w.Write([]byte(`{"id": "750D", "state": "Failed", "errorMessage": "InvalidBatch"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750D/successfulResults"):
+			w.Write([]byte("\"sf__Id\",\"sf__Created\",\"Id\"\n\"001A\",\"false\",\"001A\"\n"))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750D/failedResults"):
+			w.Write([]byte("\"sf__Id\",\"sf__Error\",\"Id\"\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	results, err := client.DeleteAll([]string{"001A", "001B"}, WithBulkThreshold(2))
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Records) != 1 || batchErr.Records[0].ID != "001B" {
+		t.Fatalf("expected a BatchError for 001B, got %v", err)
+	}
+	if !results[0].Success || results[1].Success ||
+		results[1].Errors[0].Message != "not processed by bulk job 750D: InvalidBatch" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
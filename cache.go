@@ -0,0 +1,258 @@
+package simpleforce
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Cache stores values by key for a time to live, e.g. in memory with MemoryCache or in a store shared by the instances
+// of a horizontally scaled service. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value of key, and false if there is none or it expired.
+	Get(key string) (value []byte, ok bool, err error)
+	// Set stores value under key for ttl, or without expiry if ttl is not positive.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes key, which may not exist.
+	Delete(key string) error
+}
+
+// MemoryCache is a Cache keeping values in memory. The zero value is ready to use.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{}
+}
+
+// Get implements Cache.
+func (cache *MemoryCache) Get(key string) ([]byte, bool, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && !cache.time().Before(entry.expires) {
+		delete(cache.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements Cache.
+func (cache *MemoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.entries == nil {
+		cache.entries = make(map[string]memoryCacheEntry)
+	}
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expires = cache.time().Add(ttl)
+	}
+	cache.entries[key] = entry
+	return nil
+}
+
+// Delete implements Cache.
+func (cache *MemoryCache) Delete(key string) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	delete(cache.entries, key)
+	return nil
+}
+
+func (cache *MemoryCache) time() time.Time {
+	if cache.now != nil {
+		return cache.now()
+	}
+	return time.Now()
+}
+
+// clientCache is a Cache used by the client for a kind of values with a time to live.
+type clientCache struct {
+	cache Cache
+	ttl   time.Duration
+}
+
+// SetDescribeCache makes DescribeSObjects, and the functions built on it, keep the metadata of objects in cache for
+// ttl, in addition to the cache of the client, so that several clients or processes describe each object once. As
+// field-level security is applied to describe results, entries are specific to the org, user and API version. For
+// sessions set with SetSidLoc, the user is requested once per session; if it cannot be determined, the cache is not
+// used. A nil cache disables it.
+func (client *Client) SetDescribeCache(cache Cache, ttl time.Duration) {
+	client.describeCache = newClientCache(cache, ttl)
+}
+
+// SetQueryCache sets the cache used by CachedQuery, keeping results for ttl. A nil cache disables it.
+func (client *Client) SetQueryCache(cache Cache, ttl time.Duration) {
+	client.queryCache = newClientCache(cache, ttl)
+}
+
+func newClientCache(cache Cache, ttl time.Duration) *clientCache {
+	if cache == nil {
+		return nil
+	}
+	return &clientCache{cache: cache, ttl: ttl}
+}
+
+// CachedQuery returns the first page of the results of soql like Query, from the cache set with SetQueryCache if the
+// same query, ignoring differences of case and white space outside of string literals, was run by the user within the
+// time to live of the cache. Without a query cache, or if the user of the session cannot be determined, it is
+// equivalent to Query. Only use it for data which may be stale for the time to live, e.g. reference data.
+func (client *Client) CachedQuery(soql string) (*QueryResult, error) {
+	qc := client.queryCache
+	if qc == nil {
+		return client.Query(soql)
+	}
+
+	key, err := client.cacheKey("query", normalizeSOQL(soql))
+	if err != nil {
+		log.Println(logPrefix, "query cache skipped,", err)
+		return client.Query(soql)
+	}
+	if data, ok := qc.get(key); ok {
+		var result QueryResult
+		if err := client.unmarshalJSON(data, &result); err == nil {
+			for idx := range result.Records {
+				result.Records[idx].setClient(client)
+			}
+			return &result, nil
+		}
+	}
+
+	result, err := client.Query(soql)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := client.marshalJSON(result); err == nil {
+		qc.set(key, data)
+	}
+	return result, nil
+}
+
+// cacheKey returns the key of name of the given kind, specific to the org, user and API version of the client. It
+// fails if the user cannot be determined, as entries would otherwise be shared between users.
+func (client *Client) cacheKey(kind, name string) (string, error) {
+	userID, err := client.cacheUserID()
+	if err != nil {
+		return "", err
+	}
+	return client.userCacheKey(kind, userID, name), nil
+}
+
+// userCacheKey returns the key of name of the given kind for the user userID.
+func (client *Client) userCacheKey(kind, userID, name string) string {
+	return strings.Join([]string{
+		"simpleforce", kind, client.organizationID, userID, strings.TrimPrefix(client.apiVersion, "v"), name,
+	}, ":")
+}
+
+// cacheUserID returns the ID of the logged in user. Sessions set with SetSidLoc do not tell the user, so it is
+// requested from the OAuth userinfo endpoint, which every session can read, once per session. Failures are remembered
+// for the session as well.
+func (client *Client) cacheUserID() (string, error) {
+	if client.user.id != "" {
+		return client.user.id, nil
+	}
+	client.sessionUserMu.Lock()
+	defer client.sessionUserMu.Unlock()
+	user := &client.sessionUser
+	if user.resolved && user.session == client.sessionID {
+		return user.id, user.err
+	}
+
+	session := client.sessionID
+	id, err := client.requestUserID()
+	user.session, user.id, user.err, user.resolved = session, id, err, true
+	return id, err
+}
+
+// requestUserID requests the ID of the user of the session from the OAuth userinfo endpoint.
+func (client *Client) requestUserID() (string, error) {
+	data, err := client.httpRequest(http.MethodGet, client.servicesURL()+"/services/oauth2/userinfo", nil)
+	if err != nil {
+		return "", errors.Wrap(err, "requesting the user of the session")
+	}
+	var info struct {
+		UserID string `json:"user_id"`
+	}
+	if err := client.unmarshalJSON(data, &info); err != nil {
+		return "", errors.Wrap(err, "requesting the user of the session")
+	}
+	if info.UserID == "" {
+		return "", errors.New("the user of the session is unknown")
+	}
+	return info.UserID, nil
+}
+
+// get returns the value of key, logging and ignoring failures of the cache.
+func (cc *clientCache) get(key string) ([]byte, bool) {
+	data, ok, err := cc.cache.Get(key)
+	if err != nil {
+		log.Println(logPrefix, "cache lookup failed,", err)
+		return nil, false
+	}
+	return data, ok
+}
+
+// set stores value under key, logging failures of the cache.
+func (cc *clientCache) set(key string, value []byte) {
+	if err := cc.cache.Set(key, value, cc.ttl); err != nil {
+		log.Println(logPrefix, "cache update failed,", err)
+	}
+}
+
+// delete removes key, logging failures of the cache.
+func (cc *clientCache) delete(key string) {
+	if err := cc.cache.Delete(key); err != nil {
+		log.Println(logPrefix, "cache update failed,", err)
+	}
+}
+
+// normalizeSOQL lowercases soql and collapses runs of white space, outside of string literals which are kept as is.
+func normalizeSOQL(soql string) string {
+	var b strings.Builder
+	inString, escaped, space := false, false, false
+	for _, r := range strings.TrimSpace(soql) {
+		switch {
+		case inString:
+			b.WriteRune(r)
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == '\'' {
+				inString = false
+			}
+			continue
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if r == '\'' {
+			inString = true
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	cache := &MemoryCache{now: func() time.Time { return now }}
+
+	cache.Set("short", []byte("a"), time.Minute)
+	cache.Set("forever", []byte("b"), 0)
+	if value, ok, _ := cache.Get("short"); !ok || string(value) != "a" {
+		t.Errorf("unexpected value %q", value)
+	}
+	now = now.Add(time.Minute)
+	if _, ok, _ := cache.Get("short"); ok {
+		t.Error("expected the entry to expire")
+	}
+	if value, ok, _ := cache.Get("forever"); !ok || string(value) != "b" {
+		t.Errorf("unexpected value %q", value)
+	}
+	cache.Delete("forever")
+	if _, ok, _ := cache.Get("forever"); ok {
+		t.Error("expected the entry to be deleted")
+	}
+}
+
+func TestNormalizeSOQL(t *testing.T) {
+	for soql, expected := range map[string]string{
+		"SELECT Id\n\tFROM  Account ":                  "select id from account",
+		"select id from Account where Name = 'A  B'":   "select id from account where name = 'A  B'",
+		`SELECT Id FROM Account WHERE Name = 'O\'K X'`: `select id from account where name = 'O\'K X'`,
+	} {
+		if got := normalizeSOQL(soql); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestClient_CachedQuery(t *testing.T) {
+	var requests int32
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/userinfo" {
+			w.Write([]byte(`{"user_id": "005A"}`))
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+			{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme"}]}`))
+	})
+	client.SetQueryCache(NewMemoryCache(), time.Minute)
+
+	for _, soql := range []string{"SELECT Id, Name FROM Account", "select id, name\nfrom Account"} {
+		result, err := client.CachedQuery(soql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.TotalSize != 1 || result.Records[0].StringField("Name") != "Acme" || result.Records[0].client() == nil {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request, got %d", requests)
+	}
+}
+
+func TestClient_SetDescribeCache(t *testing.T) {
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/userinfo" {
+			w.Write([]byte(`{"user_id": "005A"}`))
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"name": "Account", "fields": [{"name": "Id"}]}`))
+	}
+	shared := NewMemoryCache()
+	first, _ := newStubClient(t, handler)
+	second, _ := newStubClient(t, handler)
+	first.SetDescribeCache(shared, time.Hour)
+	second.SetDescribeCache(shared, time.Hour)
+
+	for _, client := range []*Client{first, second} {
+		metas, err := client.DescribeSObjects("Account")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (*metas["Account"])["name"] != "Account" {
+			t.Errorf("unexpected describe %+v", metas["Account"])
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected a single describe, got %d", requests)
+	}
+
+	key, err := second.cacheKey("describe", "Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.ClearDescribeCache()
+	if _, ok, _ := shared.Get(key); ok {
+		t.Error("expected the shared entry to be cleared")
+	}
+
+	// Entries cached by other clients are cleared as well.
+	first.ClearDescribeCache()
+	if _, err := first.DescribeSObjects("Account"); err != nil {
+		t.Fatal(err)
+	}
+	third, _ := newStubClient(t, handler)
+	third.SetDescribeCache(shared, time.Hour)
+	third.dropDescribes([]string{"Account"})
+	if _, ok, _ := shared.Get(key); ok {
+		t.Error("expected the shared entry to be cleared by another client")
+	}
+}
+
+func TestClient_cacheKey(t *testing.T) {
+	var lookups int32
+	newClient := func(userID string) *Client {
+		client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/services/oauth2/userinfo" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			atomic.AddInt32(&lookups, 1)
+			if userID == "" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`[{"errorCode": "INSUFFICIENT_ACCESS", "message": "no access"}]`))
+				return
+			}
+			fmt.Fprintf(w, `{"user_id": %q}`, userID)
+		})
+		return client
+	}
+
+	first, second := newClient("005A"), newClient("005B")
+	firstKey, err := first.cacheKey("describe", "Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := first.cacheKey("describe", "Account"); again != firstKey || lookups != 1 {
+		t.Errorf("expected the user to be requested once, got %d requests", lookups)
+	}
+	secondKey, err := second.cacheKey("describe", "Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstKey == secondKey || !strings.Contains(firstKey, ":005A:") {
+		t.Errorf("expected keys specific to the users, got %s and %s", firstKey, secondKey)
+	}
+
+	unknown := newClient("")
+	for i := 0; i < 2; i++ {
+		if _, err := unknown.cacheKey("describe", "Account"); err == nil {
+			t.Error("expected an error for an unknown user")
+		}
+	}
+	if lookups != 3 {
+		t.Errorf("expected the failed lookup to be remembered, got %d requests", lookups)
+	}
+}
+
+func TestClient_CachedQueryUnknownUser(t *testing.T) {
+	var queries int32
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/userinfo" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		atomic.AddInt32(&queries, 1)
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
+	cache := NewMemoryCache()
+	client.SetQueryCache(cache, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.CachedQuery("SELECT Id FROM Account"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if queries != 2 || len(cache.entries) != 0 {
+		t.Errorf("expected the cache to be bypassed, got %d queries and %d entries", queries, len(cache.entries))
+	}
+}
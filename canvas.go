@@ -0,0 +1,116 @@
+package simpleforce
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidSignedRequest is returned when a Canvas signed request is malformed or its signature does not match.
+var ErrInvalidSignedRequest = errors.New("invalid canvas signed request")
+
+// CanvasRequest is the decoded context of a Canvas signed request.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.platform_connect.meta/platform_connect/canvas_app_canvasrequest_object.htm
+type CanvasRequest struct {
+	Algorithm string        `json:"algorithm"`
+	IssuedAt  int64         `json:"issuedAt"`
+	UserID    string        `json:"userId"`
+	Client    CanvasClient  `json:"client"`
+	Context   CanvasContext `json:"context"`
+}
+
+// CanvasClient holds the OAuth credentials and the instance information of a CanvasRequest.
+type CanvasClient struct {
+	InstanceID   string `json:"instanceId"`
+	InstanceURL  string `json:"instanceUrl"`
+	TargetOrigin string `json:"targetOrigin"`
+	OAuthToken   string `json:"oauthToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// CanvasContext describes the user, organization and environment a Canvas app is rendered in.
+type CanvasContext struct {
+	User struct {
+		UserID           string `json:"userId"`
+		UserName         string `json:"userName"`
+		FullName         string `json:"fullName"`
+		Email            string `json:"email"`
+		Language         string `json:"language"`
+		Locale           string `json:"locale"`
+		TimeZone         string `json:"timeZone"`
+		ProfileID        string `json:"profileId"`
+		RoleID           string `json:"roleId"`
+		UserType         string `json:"userType"`
+		SiteURL          string `json:"siteUrl"`
+		IsDefaultNetwork bool   `json:"isDefaultNetwork"`
+	} `json:"user"`
+	Organization struct {
+		OrganizationID  string `json:"organizationId"`
+		Name            string `json:"name"`
+		CurrencyISOCode string `json:"currencyIsoCode"`
+		MultiCurrency   bool   `json:"multicurrencyEnabled"`
+		NamespacePrefix string `json:"namespacePrefix"`
+	} `json:"organization"`
+	Environment struct {
+		DisplayLocation string                 `json:"displayLocation"`
+		LocationURL     string                 `json:"locationUrl"`
+		UITheme         string                 `json:"uiTheme"`
+		Version         map[string]string      `json:"version"`
+		Parameters      map[string]interface{} `json:"parameters"`
+		Record          map[string]interface{} `json:"record"`
+	} `json:"environment"`
+	Links map[string]string `json:"links"`
+}
+
+// VerifySignedRequest verifies the HMAC-SHA256 signature of a Canvas signed_request with the consumer secret of the
+// connected app and decodes its payload. ErrInvalidSignedRequest is returned if the signature does not match.
+func VerifySignedRequest(signedRequest, consumerSecret string) (*CanvasRequest, error) {
+	parts := strings.SplitN(signedRequest, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidSignedRequest
+	}
+	signature, err := decodeBase64(parts[0])
+	if err != nil {
+		return nil, ErrInvalidSignedRequest
+	}
+
+	mac := hmac.New(sha256.New, []byte(consumerSecret))
+	mac.Write([]byte(parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidSignedRequest
+	}
+
+	payload, err := decodeBase64(parts[1])
+	if err != nil {
+		return nil, ErrInvalidSignedRequest
+	}
+	var canvasRequest CanvasRequest
+	err = json.Unmarshal(payload, &canvasRequest)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignedRequest, err.Error())
+	}
+	if canvasRequest.Algorithm != "" && canvasRequest.Algorithm != "HMACSHA256" {
+		return nil, ErrInvalidSignedRequest
+	}
+	return &canvasRequest, nil
+}
+
+// NewClient creates a Client authenticated with the OAuth token of the CanvasRequest.
+func (canvasRequest *CanvasRequest) NewClient(apiVersion string) *Client {
+	client := NewClient(canvasRequest.Client.InstanceURL, DefaultClientID, apiVersion)
+	client.SetSidLoc(canvasRequest.Client.OAuthToken, client.baseURL)
+	return client
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,59 @@
+package simpleforce
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func signCanvasRequest(payload, secret string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)) + "." + encoded
+}
+
+func TestVerifySignedRequest(t *testing.T) {
+	payload := `{
+		"algorithm": "HMACSHA256",
+		"issuedAt": 1234567890,
+		"userId": "005000000000001AAA",
+		"client": {"instanceUrl": "https://example.my.salesforce.com", "oauthToken": "__TOKEN__"},
+		"context": {
+			"user": {"userName": "admin@example.com"},
+			"organization": {"organizationId": "00D000000000001AAA"},
+			"environment": {"displayLocation": "Chatter", "parameters": {"foo": "bar"}}
+		}
+	}`
+	signed := signCanvasRequest(payload, "__SECRET__")
+
+	canvasRequest, err := VerifySignedRequest(signed, "__SECRET__")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canvasRequest.UserID != "005000000000001AAA" ||
+		canvasRequest.Context.User.UserName != "admin@example.com" ||
+		canvasRequest.Context.Organization.OrganizationID != "00D000000000001AAA" ||
+		canvasRequest.Context.Environment.Parameters["foo"] != "bar" {
+		t.Errorf("unexpected request %+v", canvasRequest)
+	}
+
+	client := canvasRequest.NewClient(DefaultAPIVersion)
+	if client.GetSid() != "__TOKEN__" || client.GetLoc() != "https://example.my.salesforce.com" {
+		t.Fail()
+	}
+
+	if _, err := VerifySignedRequest(signed, "__WRONG_SECRET__"); err != ErrInvalidSignedRequest {
+		t.Errorf("expected ErrInvalidSignedRequest, got %v", err)
+	}
+	if _, err := VerifySignedRequest("garbage", "__SECRET__"); err != ErrInvalidSignedRequest {
+		t.Errorf("expected ErrInvalidSignedRequest, got %v", err)
+	}
+	if _, err := VerifySignedRequest(signCanvasRequest("not json", "__SECRET__"), "__SECRET__"); !errors.Is(err,
+		ErrInvalidSignedRequest) {
+		t.Errorf("expected ErrInvalidSignedRequest for a payload which is not JSON, got %v", err)
+	}
+}
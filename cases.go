@@ -0,0 +1,146 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AssignmentRule selects the assignment rule CreateCase applies: DefaultAssignmentRule, NoAssignmentRule or the ID of
+// an AssignmentRule, e.g. AssignmentRule("01Q5g000000ABCD").
+type AssignmentRule string
+
+const (
+	// DefaultAssignmentRule applies the active case assignment rule of the org.
+	DefaultAssignmentRule AssignmentRule = "TRUE"
+	// NoAssignmentRule applies no assignment rule.
+	NoAssignmentRule AssignmentRule = "FALSE"
+)
+
+// caseFeedFields are the fields of CaseFeed selected by CaseFeed.
+const caseFeedFields = "Id, ParentId, Type, Title, Body, LinkUrl, Visibility, CreatedById, CreatedDate"
+
+// CaseEmail is an email message attached to a case with AttachEmail.
+type CaseEmail struct {
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	FromAddress string
+	FromName    string
+	ToAddress   string // semicolon separated addresses
+	CcAddress   string
+	BccAddress  string
+	Incoming    bool      // received from the customer rather than sent by an agent
+	MessageDate time.Time // now if zero
+}
+
+// CaseFeedItem is a post or tracked change in the feed of a case. CreatedDate can be parsed with ParseDateTime.
+type CaseFeedItem struct {
+	ID          string `json:"Id"`
+	ParentID    string `json:"ParentId"`
+	Type        string `json:"Type"` // e.g. "TextPost", "EmailMessageEvent" or "TrackedChange"
+	Title       string `json:"Title"`
+	Body        string `json:"Body"`
+	LinkURL     string `json:"LinkUrl"`
+	Visibility  string `json:"Visibility"`
+	CreatedByID string `json:"CreatedById"`
+	CreatedDate string `json:"CreatedDate"`
+}
+
+// CreateCase creates the Case obj applying the assignment rule rule, and sets and returns its ID.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_autoassign.htm
+func (client *Client) CreateCase(obj *SObject, rule AssignmentRule) (string, error) {
+	if !client.isLoggedIn() {
+		return "", ErrAuthentication
+	}
+	if obj.Type() != "Case" {
+		return "", fmt.Errorf("expected a Case, got %s", obj.Type())
+	}
+
+	reqData, err := client.marshalJSON(obj.makeCopy())
+	if err != nil {
+		return "", err
+	}
+	header := http.Header{}
+	if rule != "" {
+		header.Set("Sforce-Auto-Assign", string(rule))
+	}
+	data, err := client.httpRequestWithHeader(http.MethodPost, client.makeURL("sobjects/Case/"), bytes.NewReader(reqData), header)
+	if err != nil {
+		return "", err
+	}
+	err = obj.setIDFromResponseData(data)
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
+
+// AddCaseComment adds a comment with the given body to the case caseID, visible in the customer portal if published,
+// and returns its ID.
+func (client *Client) AddCaseComment(caseID, body string, published bool) (string, error) {
+	comment := client.SObject("CaseComment").
+		Set("ParentId", caseID).
+		Set("CommentBody", body).
+		Set("IsPublished", published)
+	_, err := client.CreateAll([]*SObject{comment})
+	if err != nil {
+		return "", err
+	}
+	return comment.ID(), nil
+}
+
+// AttachEmail attaches email to the case caseID as a sent or received EmailMessage, which appears in the case feed,
+// and returns its ID. No email is sent.
+func (client *Client) AttachEmail(caseID string, email CaseEmail) (string, error) {
+	if email.MessageDate.IsZero() {
+		email.MessageDate = time.Now()
+	}
+	// Status "0" is new, "3" is sent.
+	status := "3"
+	if email.Incoming {
+		status = "0"
+	}
+
+	message := client.SObject("EmailMessage").
+		Set("ParentId", caseID).
+		Set("Incoming", email.Incoming).
+		Set("Status", status).
+		Set("MessageDate", email.MessageDate.UTC().Format(time.RFC3339))
+	setOptional(message, "Subject", email.Subject)
+	setOptional(message, "TextBody", email.TextBody)
+	setOptional(message, "HtmlBody", email.HTMLBody)
+	setOptional(message, "FromAddress", email.FromAddress)
+	setOptional(message, "FromName", email.FromName)
+	setOptional(message, "ToAddress", email.ToAddress)
+	setOptional(message, "CcAddress", email.CcAddress)
+	setOptional(message, "BccAddress", email.BccAddress)
+	_, err := client.CreateAll([]*SObject{message})
+	if err != nil {
+		return "", err
+	}
+	return message.ID(), nil
+}
+
+// CaseFeed returns the newest limit items of the feed of the case caseID, all items if limit is not positive, newest
+// first.
+func (client *Client) CaseFeed(caseID string, limit int) ([]CaseFeedItem, error) {
+	soql := "SELECT " + caseFeedFields + " FROM CaseFeed WHERE ParentId = " + QuoteSOQL(caseID) +
+		" ORDER BY CreatedDate DESC, Id DESC"
+	if limit > 0 {
+		soql += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	var items []CaseFeedItem
+	err := client.queryPages("query", soql, func(records []byte) error {
+		var page []CaseFeedItem
+		err := client.unmarshalJSON(records, &page)
+		items = append(items, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
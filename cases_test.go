@@ -0,0 +1,81 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateCase(t *testing.T) {
+	var assign []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/sobjects/Case/") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["Subject"] != "Broken" || body["Id"] != nil {
+			t.Errorf("unexpected body %v", body)
+		}
+		assign = append(assign, r.Header.Get("Sforce-Auto-Assign"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "500NEW", "success": true, "errors": []}`))
+	})
+
+	for _, rule := range []AssignmentRule{DefaultAssignmentRule, NoAssignmentRule, AssignmentRule("01QRULE"), ""} {
+		obj := client.SObject("Case").Set("Subject", "Broken")
+		id, err := client.CreateCase(obj, rule)
+		if err != nil || id != "500NEW" || obj.ID() != "500NEW" {
+			t.Errorf("unexpected result %s, %v", id, err)
+		}
+	}
+	if strings.Join(assign, ",") != "TRUE,FALSE,01QRULE," {
+		t.Errorf("unexpected assignment headers %v", assign)
+	}
+	if _, err := client.CreateCase(client.SObject("Lead"), DefaultAssignmentRule); err == nil {
+		t.Error("expected an error for a Lead")
+	}
+}
+
+func TestClient_CaseCommentsAndEmails(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newActivityClient(t, &sent)
+
+	if _, err := client.AddCaseComment("500A", "Restarted the service", true); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	_, err := client.AttachEmail("500A", CaseEmail{Subject: "Re: Broken", TextBody: "Works again",
+		FromAddress: "jane@example.com", ToAddress: "support@example.com", Incoming: true, MessageDate: date})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if comment := sent[0]; comment["attributes"].(map[string]interface{})["type"] != "CaseComment" ||
+		comment["ParentId"] != "500A" || comment["CommentBody"] != "Restarted the service" || comment["IsPublished"] != true {
+		t.Errorf("unexpected comment %v", comment)
+	}
+	if email := sent[1]; email["ParentId"] != "500A" || email["Incoming"] != true || email["Status"] != "0" ||
+		email["MessageDate"] != "2022-05-01T10:00:00Z" || email["FromAddress"] != "jane@example.com" || email["HtmlBody"] != nil {
+		t.Errorf("unexpected email %v", email)
+	}
+}
+
+func TestClient_CaseFeed(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "SELECT " + caseFeedFields + " FROM CaseFeed WHERE ParentId = '500A' ORDER BY CreatedDate DESC, Id DESC LIMIT 2"
+		if q := r.URL.Query().Get("q"); q != expected {
+			t.Errorf("unexpected query %s", q)
+		}
+		w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+			{"attributes": {"type": "CaseFeed"}, "Id": "0D5B", "ParentId": "500A", "Type": "TextPost", "Body": "Escalated"},
+			{"attributes": {"type": "CaseFeed"}, "Id": "0D5A", "ParentId": "500A", "Type": "EmailMessageEvent"}]}`))
+	})
+
+	items, err := client.CaseFeed("500A", 2)
+	if err != nil || len(items) != 2 || items[0].Body != "Escalated" || items[1].Type != "EmailMessageEvent" {
+		t.Errorf("unexpected feed %+v, %v", items, err)
+	}
+}
@@ -0,0 +1,76 @@
+package simpleforce_test
+
+import (
+	"testing"
+
+	"github.com/scottraio/simpleforce"
+	"github.com/scottraio/simpleforce/testserver"
+)
+
+// These tests run the client against the in-memory testserver, covering the same flows as the live integration tests
+// without credentials.
+
+func TestClient_LoginAndQuery(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.Username, server.Password = "user@example.com", "secret"
+	server.Seed("Case",
+		map[string]interface{}{"Subject": "simpleforce test 1"},
+		map[string]interface{}{"Subject": "other"},
+	)
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	if err := client.LoginPassword("user@example.com", "wrong", "token"); err == nil {
+		t.Fatal("expected login failure")
+	}
+	if err := client.LoginPassword("user@example.com", "secret", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.Query("SELECT Id, Subject FROM Case WHERE Subject LIKE '%simpleforce%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalSize != 1 || result.Records[0].Type() != "Case" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestSObject_Lifecycle(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	client := server.Client()
+
+	case1 := client.SObject("Case").
+		Set("Subject", "Case created by simpleforce").
+		Set("CaseNumber", "read-only").
+		Create()
+	if case1 == nil || case1.ID() == "" {
+		t.Fatal("create failed")
+	}
+	if _, ok := server.Record("Case", case1.ID())["CaseNumber"]; ok {
+		t.Error("read-only field was sent")
+	}
+
+	comment := client.SObject("CaseComment").Set("ParentId", case1.ID()).Set("CommentBody", "hello").Create()
+	parent := comment.Get().SObjectField("Case", "ParentId").Get()
+	if parent == nil || parent.StringField("Subject") != "Case created by simpleforce" {
+		t.Errorf("unexpected parent %v", parent)
+	}
+
+	if case1.Set("Subject", "Updated").Update().Get().StringField("Subject") != "Updated" {
+		t.Error("update failed")
+	}
+	if err := case1.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if client.SObject("Case").Get(case1.ID()) != nil {
+		t.Error("record not deleted")
+	}
+
+	// Negative: invalid session.
+	client.SetSidLoc("__INVALID__", server.URL)
+	if client.SObject("Case").Create() != nil {
+		t.Error("expected failure with invalid session")
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottraio/simpleforce"
+	"github.com/scottraio/simpleforce/dataloader"
+)
+
+func runLogin(client *simpleforce.Client, args []string) error {
+	fmt.Printf("export SF_SESSION_ID=%s\n", client.GetSid())
+	fmt.Printf("export SF_INSTANCE_URL=%s\n", client.GetLoc())
+	return nil
+}
+
+func runQuery(client *simpleforce.Client, args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	format := flags.String("format", "table", "output format: table, csv or json")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", usages["query"])
+	}
+	soql := flags.Arg(0)
+
+	switch *format {
+	case "csv":
+		_, err := (&dataloader.Exporter{Client: client, Format: dataloader.CSV}).Export(soql, os.Stdout)
+		return err
+	case "json":
+		_, err := (&dataloader.Exporter{Client: client, Format: dataloader.JSONLines}).Export(soql, os.Stdout)
+		return err
+	case "table":
+		return printTable(client, soql, os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %s", *format)
+	}
+}
+
+// printTable writes the query results as an aligned text table.
+func printTable(client *simpleforce.Client, soql string, w io.Writer) error {
+	columns, err := dataloader.QueryColumns(soql)
+	if err != nil {
+		return err
+	}
+
+	// Reuse the CSV exporter for value flattening, then align its output.
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := (&dataloader.Exporter{Client: client, Format: dataloader.CSV}).Export(soql, pw)
+		pw.CloseWithError(err)
+	}()
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	err = copyCSVTable(pr, table, len(columns))
+	if flushErr := table.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+func runGet(client *simpleforce.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s", usages["get"])
+	}
+	obj, err := client.SObject(args[0]).GetE(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %v", args[0], args[1], err)
+	}
+	return printJSON(stripClient(obj))
+}
+
+func runCreate(client *simpleforce.Client, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: %s", usages["create"])
+	}
+	obj := client.SObject(args[0])
+	err := setAssignments(obj, args[1:])
+	if err != nil {
+		return err
+	}
+	if _, err = obj.CreateE(); err != nil {
+		return fmt.Errorf("failed to create %s: %v", args[0], err)
+	}
+	fmt.Println(obj.ID())
+	return nil
+}
+
+func runUpdate(client *simpleforce.Client, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: %s", usages["update"])
+	}
+	obj := client.SObject(args[0]).Set("Id", args[1])
+	err := setAssignments(obj, args[2:])
+	if err != nil {
+		return err
+	}
+	if _, err = obj.UpdateE(); err != nil {
+		return fmt.Errorf("failed to update %s %s: %v", args[0], args[1], err)
+	}
+	return nil
+}
+
+func runDelete(client *simpleforce.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s", usages["delete"])
+	}
+	return client.SObject(args[0]).Set("Id", args[1]).Delete()
+}
+
+func runUpload(client *simpleforce.Client, args []string) error {
+	flags := flag.NewFlagSet("upload", flag.ExitOnError)
+	title := flags.String("title", "", "title of the file")
+	description := flags.String("description", "", "description of the file")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: %s", usages["upload"])
+	}
+
+	var opts []simpleforce.UploadOption
+	if *title != "" {
+		opts = append(opts, simpleforce.WithTitle(*title))
+	}
+	if *description != "" {
+		opts = append(opts, simpleforce.WithDescription(*description))
+	}
+	contentVersionID, contentDocumentID, err := client.UploadFileToContentVersion(flags.Arg(0), flags.Arg(1), opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Println("ContentVersion:", contentVersionID)
+	fmt.Println("ContentDocument:", contentDocumentID)
+	return nil
+}
+
+func runDownload(client *simpleforce.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s", usages["download"])
+	}
+	return client.DownloadFile(args[0], args[1])
+}
+
+func runImport(client *simpleforce.Client, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	object := flags.String("object", "", "object to import into")
+	op := flags.String("op", string(dataloader.Insert), "operation: insert, update or delete")
+	successPath := flags.String("success", "", "file to write succeeded rows to")
+	errorsPath := flags.String("errors", "", "file to write failed rows to")
+	bulk := flags.Bool("bulk", false, "load with Bulk API 2.0 jobs")
+	flags.Parse(args)
+	if *object == "" || flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", usages["import"])
+	}
+
+	in, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	importer := &dataloader.Importer{Client: client, Object: *object, Operation: dataloader.Operation(*op), Bulk: *bulk}
+	if *successPath != "" {
+		out, err := os.Create(*successPath)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		importer.Success = out
+	}
+	if *errorsPath != "" {
+		out, err := os.Create(*errorsPath)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		importer.Errors = out
+	}
+
+	result, err := importer.Import(in)
+	if result != nil {
+		fmt.Printf("rows: %d, succeeded: %d, failed: %d\n", result.Rows, result.Succeeded, result.Failed)
+	}
+	return err
+}
+
+func runExport(client *simpleforce.Client, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "csv", "output format: csv or jsonl")
+	outPath := flags.String("out", "", "output file, standard output if empty")
+	bulk := flags.Bool("bulk", false, "run the query as a Bulk API 2.0 job")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s", usages["export"])
+	}
+
+	exporter := &dataloader.Exporter{Client: client, Format: dataloader.CSV, Bulk: *bulk}
+	switch *format {
+	case "csv":
+	case "jsonl":
+		exporter.Format = dataloader.JSONLines
+	default:
+		return fmt.Errorf("unknown format %s", *format)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	count, err := exporter.Export(flags.Arg(0), out)
+	fmt.Fprintf(os.Stderr, "%d records exported\n", count)
+	return err
+}
+
+func runDescribe(client *simpleforce.Client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s", usages["describe"])
+	}
+	meta := client.SObject(args[0]).Describe()
+	if meta == nil {
+		return fmt.Errorf("failed to describe %s", args[0])
+	}
+	return printJSON(meta)
+}
+
+func runPicklists(client *simpleforce.Client, args []string) error {
+	flags := flag.NewFlagSet("picklists", flag.ExitOnError)
+	packageName := flags.String("package", "picklists", "package of the generated source")
+	inactive := flags.Bool("inactive", false, "also generate inactive values, marked as deprecated")
+	outPath := flags.String("out", "", "output file, standard output if empty")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: %s", usages["picklists"])
+	}
+
+	var opts []simpleforce.PicklistOption
+	if *inactive {
+		opts = append(opts, simpleforce.WithInactivePicklistValues())
+	}
+	src, err := client.GeneratePicklists(*packageName, flags.Args(), opts...)
+	if err != nil {
+		return err
+	}
+	if *outPath == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(*outPath, src, 0644)
+}
+
+// setAssignments sets field=value arguments on obj. The values true, false and null are converted, all other values
+// are sent as strings, which salesforce converts to the type of the field.
+func setAssignments(obj *simpleforce.SObject, assignments []string) error {
+	for _, assignment := range assignments {
+		idx := strings.Index(assignment, "=")
+		if idx <= 0 {
+			return fmt.Errorf("invalid assignment %q, expected field=value", assignment)
+		}
+		obj.Set(assignment[:idx], parseValue(assignment[idx+1:]))
+	}
+	return nil
+}
+
+func parseValue(raw string) interface{} {
+	switch raw {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	default:
+		return raw
+	}
+}
+
+// stripClient returns the fields of obj without its private client reference.
+func stripClient(obj *simpleforce.SObject) map[string]interface{} {
+	fields := make(map[string]interface{}, len(*obj))
+	for key, value := range *obj {
+		if _, ok := value.(*simpleforce.Client); !ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// copyCSVTable converts CSV from r into tab separated cells on w.
+func copyCSVTable(r io.Reader, w io.Writer, columns int) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = columns
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/scottraio/simpleforce"
+)
+
+func TestSetAssignments(t *testing.T) {
+	obj := &simpleforce.SObject{}
+	err := setAssignments(obj, []string{"Name=Acme = Co", "IsActive=true", "Website=null", "Zip=01234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.StringField("Name") != "Acme = Co" || obj.InterfaceField("IsActive") != true ||
+		obj.InterfaceField("Website") != nil || obj.StringField("Zip") != "01234" {
+		t.Errorf("unexpected fields %v", *obj)
+	}
+
+	if setAssignments(obj, []string{"=value"}) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,131 @@
+// Command simpleforce is a command line client for Salesforce built on the simpleforce package.
+//
+// Usage:
+//
+//	simpleforce [global flags] <command> [flags] [arguments]
+//
+// Commands:
+//
+//	login                                   log in and print the session as environment variables
+//	query [-format table|csv|json] <soql>    run a SOQL query
+//	get <type> <id>                         print a record as JSON
+//	create <type> field=value...            create a record and print its ID
+//	update <type> <id> field=value...       update a record
+//	delete <type> <id>                      delete a record
+//	upload [-title t] <file> <parent id>    upload a file as a ContentVersion
+//	download <content version id> <file>    download the data of a ContentVersion
+//	import -object <type> [-op insert|update|delete] [-success f] [-errors f] <csv file>
+//	export [-format csv|jsonl] <soql>       export query results
+//	describe <type>                         print the describe metadata of an object as JSON
+//	picklists [-package p] [-inactive] [-out file] <type>...
+//	                                        generate Go constants for the picklist values of objects
+//
+// Credentials are taken from the environment: either SF_SESSION_ID and SF_INSTANCE_URL, as printed by login, or
+// SF_USER, SF_PASS and optionally SF_TOKEN for the username-password flow. SF_URL sets the login URL.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/scottraio/simpleforce"
+)
+
+var commands = map[string]func(client *simpleforce.Client, args []string) error{
+	"login":     runLogin,
+	"query":     runQuery,
+	"get":       runGet,
+	"create":    runCreate,
+	"update":    runUpdate,
+	"delete":    runDelete,
+	"upload":    runUpload,
+	"download":  runDownload,
+	"import":    runImport,
+	"export":    runExport,
+	"describe":  runDescribe,
+	"picklists": runPicklists,
+}
+
+var commandOrder = []string{"login", "query", "get", "create", "update", "delete", "upload", "download", "import", "export", "describe", "picklists"}
+
+var usages = map[string]string{
+	"login":     "login",
+	"query":     "query [-format table|csv|json] <soql>",
+	"get":       "get <type> <id>",
+	"create":    "create <type> field=value...",
+	"update":    "update <type> <id> field=value...",
+	"delete":    "delete <type> <id>",
+	"upload":    "upload [-title title] [-description text] <file> <parent id>",
+	"download":  "download <content version id> <file>",
+	"import":    "import -object <type> [-op insert|update|delete] [-success file] [-errors file] [-bulk] <csv file>",
+	"export":    "export [-format csv|jsonl] [-out file] [-bulk] <soql>",
+	"describe":  "describe <type>",
+	"picklists": "picklists [-package name] [-inactive] [-out file] <type>...",
+}
+
+func main() {
+	flags := flag.NewFlagSet("simpleforce", flag.ExitOnError)
+	loginURL := flags.String("url", envOr("SF_URL", simpleforce.DefaultURL), "login URL")
+	apiVersion := flags.String("api-version", simpleforce.DefaultAPIVersion, "API version")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: simpleforce [global flags] <command> [flags] [arguments]")
+		fmt.Fprintln(flags.Output(), "\nGlobal flags:")
+		flags.PrintDefaults()
+		fmt.Fprintln(flags.Output(), "\nCommands:")
+		for _, name := range commandOrder {
+			fmt.Fprintln(flags.Output(), "  "+usages[name])
+		}
+	}
+	flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	run, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "unknown command:", flags.Arg(0))
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client, err := newClient(*loginURL, *apiVersion)
+	if err == nil {
+		err = run(client, flags.Args()[1:])
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// newClient creates a client from the session or the credentials in the environment.
+func newClient(loginURL, apiVersion string) (*simpleforce.Client, error) {
+	client := simpleforce.NewClient(loginURL, simpleforce.DefaultClientID, apiVersion)
+	if sid := os.Getenv("SF_SESSION_ID"); sid != "" {
+		instanceURL := os.Getenv("SF_INSTANCE_URL")
+		if instanceURL == "" {
+			return nil, fmt.Errorf("SF_INSTANCE_URL must be set with SF_SESSION_ID")
+		}
+		client.SetSidLoc(sid, instanceURL)
+		return client, nil
+	}
+
+	user, pass := os.Getenv("SF_USER"), os.Getenv("SF_PASS")
+	if user == "" || pass == "" {
+		return nil, fmt.Errorf("set SF_SESSION_ID and SF_INSTANCE_URL, or SF_USER and SF_PASS")
+	}
+	err := client.LoginPassword(user, pass, os.Getenv("SF_TOKEN"))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,51 @@
+package simpleforce
+
+import (
+	"encoding/json"
+)
+
+// Codec encodes and decodes the JSON bodies of API requests and responses, see SetCodec. Implementations must be
+// compatible with encoding/json, honoring struct tags and the json.Marshaler and json.Unmarshaler interfaces.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// stdCodec is the Codec of encoding/json.
+type stdCodec struct{}
+
+// Marshal implements Codec.
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// SetCodec replaces encoding/json with codec for all requests and responses of the client, e.g. with a faster
+// drop-in replacement such as jsoniter.ConfigCompatibleWithStandardLibrary or sonic.ConfigStd, which pays off for
+// large query results. Query records are decoded into plain maps by codec. SetUseNumber does not apply to custom
+// codecs; configure the codec to decode numbers as json.Number instead. nil restores encoding/json.
+func (client *Client) SetCodec(codec Codec) {
+	client.jsonCodec = codec
+}
+
+// codec returns the Codec of the client. client may be nil, e.g. for SObjects without a client.
+func (client *Client) codec() Codec {
+	if client == nil || client.jsonCodec == nil {
+		return stdCodec{}
+	}
+	return client.jsonCodec
+}
+
+// marshalJSON encodes v with the Codec of the client.
+func (client *Client) marshalJSON(v interface{}) ([]byte, error) {
+	return client.codec().Marshal(v)
+}
+
+// unmarshalJSON decodes data into v with the Codec of the client.
+func (client *Client) unmarshalJSON(data []byte, v interface{}) error {
+	return client.codec().Unmarshal(data, v)
+}
@@ -0,0 +1,58 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// countingCodec wraps encoding/json, counting its calls.
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (codec *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	codec.marshals++
+	return json.Marshal(v)
+}
+
+func (codec *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	codec.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestClient_SetCodec(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`[{"id": "001A", "success": true}]`))
+			return
+		}
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+			{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme", "NumberOfEmployees": 12}]}`))
+	})
+	codec := &countingCodec{}
+	client.SetCodec(codec)
+
+	result, err := client.Query("SELECT Id, Name, NumberOfEmployees FROM Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := result.Records[0]
+	if record.Type() != "Account" || record.StringField("Name") != "Acme" || record.InterfaceField("NumberOfEmployees") != 12.0 {
+		t.Errorf("unexpected record %v", record)
+	}
+	if codec.unmarshals != 1 {
+		t.Errorf("expected the query result to be decoded by the codec, got %d calls", codec.unmarshals)
+	}
+
+	_, err = client.CreateAll([]*SObject{client.SObject("Account").Set("Name", "Acme")})
+	if err != nil || codec.marshals != 1 || codec.unmarshals != 2 {
+		t.Errorf("expected the collection request to use the codec, got %d, %d calls, %v",
+			codec.marshals, codec.unmarshals, err)
+	}
+
+	client.SetCodec(nil)
+	if _, err = client.Query("SELECT Id FROM Account"); err != nil || codec.unmarshals != 2 {
+		t.Errorf("expected encoding/json to be restored, got %d calls, %v", codec.unmarshals, err)
+	}
+}
@@ -0,0 +1,219 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxCollectionSize is the maximum number of records accepted by a single sObject Collections request.
+const maxCollectionSize = 200
+
+// SaveError is a single error reported by salesforce for a record of a collection request or a metadata component.
+type SaveError struct {
+	StatusCode string   `json:"statusCode" xml:"statusCode"`
+	Message    string   `json:"message" xml:"message"`
+	Fields     []string `json:"fields" xml:"fields"`
+}
+
+// SaveResult is the outcome of a DML operation on a single record of a collection request. Created is only reported
+// by upserts, telling whether the record was inserted rather than updated.
+type SaveResult struct {
+	ID      string      `json:"id"`
+	Success bool        `json:"success"`
+	Created bool        `json:"created"`
+	Errors  []SaveError `json:"errors"`
+}
+
+// deleteCollection deletes up to maxCollectionSize records with a single sObject Collections request.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_sobjects_collections_delete.htm
+func (client *Client) deleteCollection(ids []string, allOrNone bool) ([]SaveResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	params := url.Values{}
+	params.Set("ids", strings.Join(ids, ","))
+	params.Set("allOrNone", strconv.FormatBool(allOrNone))
+	u := client.makeURL("composite/sobjects?" + params.Encode())
+
+	data, err := client.httpRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SaveResult
+	err = client.unmarshalJSON(data, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// updateCollection updates up to maxCollectionSize records with a single sObject Collections request. Every record must
+// have its type and ID set.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_sobjects_collections_update.htm
+func (client *Client) updateCollection(records []*SObject, allOrNone bool) ([]SaveResult, error) {
+	return client.saveCollection(http.MethodPatch, records, allOrNone)
+}
+
+// saveCollection posts records to the sObject Collections resource with the given method.
+func (client *Client) saveCollection(method string, records []*SObject, allOrNone bool) ([]SaveResult, error) {
+	reqRecords := make([]map[string]interface{}, 0, len(records))
+	for _, obj := range records {
+		reqRecords = append(reqRecords, obj.makeCollectionRecord())
+	}
+	return client.sendCollection(method, "composite/sobjects", reqRecords, allOrNone)
+}
+
+// upsertCollection upserts up to maxCollectionSize records of objectType by the external ID field externalIDField with
+// a single sObject Collections request. Every record must have the external ID field set.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_sobjects_collections_upsert.htm
+func (client *Client) upsertCollection(
+	objectType, externalIDField string,
+	records []*SObject,
+	allOrNone bool,
+) ([]SaveResult, error) {
+	reqRecords := make([]map[string]interface{}, 0, len(records))
+	for _, obj := range records {
+		record := obj.makeCollectionRecord()
+		record[sobjectAttributesKey] = map[string]string{"type": objectType}
+		// The record is identified by its external ID, salesforce rejects the request if the ID is given as well.
+		delete(record, sobjectIDKey)
+		record[externalIDField] = (*obj)[externalIDField]
+		reqRecords = append(reqRecords, record)
+	}
+	apiPath := "composite/sobjects/" + url.PathEscape(objectType) + "/" + url.PathEscape(externalIDField)
+	return client.sendCollection(http.MethodPatch, apiPath, reqRecords, allOrNone)
+}
+
+// sendCollection sends records to the sObject Collections resource at apiPath with the given method.
+func (client *Client) sendCollection(
+	method, apiPath string,
+	reqRecords []map[string]interface{},
+	allOrNone bool,
+) ([]SaveResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	reqData, err := client.marshalJSON(map[string]interface{}{
+		"allOrNone": allOrNone,
+		"records":   reqRecords,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u := client.makeURL(apiPath)
+	data, err := client.httpRequest(method, u, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SaveResult
+	err = client.unmarshalJSON(data, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// makeCollectionRecord copies the fields of an SObject like makeCopy, but keeps the type attribute and the ID which
+// are required to identify records in collection requests.
+func (obj *SObject) makeCollectionRecord() map[string]interface{} {
+	record := obj.makeCopy()
+	record[sobjectAttributesKey] = map[string]string{"type": obj.Type()}
+	if obj.ID() != "" {
+		record[sobjectIDKey] = obj.ID()
+	}
+	return record
+}
+
+// createCollection creates up to maxCollectionSize records with a single sObject Collections request.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_sobjects_collections_create.htm
+func (client *Client) createCollection(records []*SObject, allOrNone bool) ([]SaveResult, error) {
+	return client.saveCollection(http.MethodPost, records, allOrNone)
+}
+
+// Error implements the error interface.
+func (err SaveError) Error() string {
+	if len(err.Fields) == 0 {
+		return fmt.Sprintf("%s: %s", err.StatusCode, err.Message)
+	}
+	return fmt.Sprintf("%s: %s (fields: %s)", err.StatusCode, err.Message, strings.Join(err.Fields, ", "))
+}
+
+// RecordError reports the failure of a single record of a batch operation. Index is the position of the record in the
+// input of the operation. Err is set if the request carrying the record failed as a whole, or was never sent because
+// an earlier request failed; Errors holds the errors reported by salesforce for the record otherwise.
+type RecordError struct {
+	Index  int
+	ID     string
+	Errors []SaveError
+	Err    error
+}
+
+// Error implements the error interface.
+func (err *RecordError) Error() string {
+	prefix := fmt.Sprintf("record %d", err.Index)
+	if err.ID != "" {
+		prefix = fmt.Sprintf("record %d (%s)", err.Index, err.ID)
+	}
+	if err.Err != nil {
+		return fmt.Sprintf("%s: %v", prefix, err.Err)
+	}
+
+	messages := make([]string, 0, len(err.Errors))
+	for _, saveErr := range err.Errors {
+		messages = append(messages, saveErr.Error())
+	}
+	return fmt.Sprintf("%s: %s", prefix, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the request error which caused the record to fail, if any.
+func (err *RecordError) Unwrap() error {
+	return err.Err
+}
+
+// BatchError combines the RecordErrors of a batch operation, so callers can inspect or retry only the failed records.
+type BatchError struct {
+	Records []*RecordError
+}
+
+// newBatchError returns a *BatchError for failures, or nil if there are none.
+func newBatchError(failures []*RecordError) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &BatchError{Records: failures}
+}
+
+// Error implements the error interface.
+func (err *BatchError) Error() string {
+	if len(err.Records) == 1 {
+		return fmt.Sprintf("1 record failed: %v", err.Records[0])
+	}
+	return fmt.Sprintf("%d records failed, first: %v", len(err.Records), err.Records[0])
+}
+
+// Unwrap returns the RecordErrors as a slice of errors, which allows errors.Is and errors.As to inspect every failure.
+func (err *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(err.Records))
+	for _, recordErr := range err.Records {
+		errs = append(errs, recordErr)
+	}
+	return errs
+}
+
+// Indexes returns the input positions of the failed records in ascending order.
+func (err *BatchError) Indexes() []int {
+	indexes := make([]int, 0, len(err.Records))
+	for _, recordErr := range err.Records {
+		indexes = append(indexes, recordErr.Index)
+	}
+	return indexes
+}
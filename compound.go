@@ -0,0 +1,62 @@
+package simpleforce
+
+import (
+	"encoding/json"
+)
+
+// Address is the value of a compound address field, e.g. BillingAddress of Account or MailingAddress of Contact.
+// Latitude and Longitude are nil unless the address is geocoded.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/compound_fields_address.htm
+type Address struct {
+	Street          string   `json:"street"`
+	City            string   `json:"city"`
+	State           string   `json:"state"`
+	StateCode       string   `json:"stateCode"`
+	PostalCode      string   `json:"postalCode"`
+	Country         string   `json:"country"`
+	CountryCode     string   `json:"countryCode"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	GeocodeAccuracy string   `json:"geocodeAccuracy"`
+}
+
+// Location is the value of a compound geolocation field.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/compound_fields_geolocation.htm
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// AddressField accesses a compound address field in the SObject. nil is returned if the field is empty or not an
+// address.
+func (obj *SObject) AddressField(key string) *Address {
+	var address Address
+	if !obj.decodeCompoundField(key, &address) {
+		return nil
+	}
+	return &address
+}
+
+// LocationField accesses a compound geolocation field in the SObject. nil is returned if the field is empty or not a
+// geolocation.
+func (obj *SObject) LocationField(key string) *Location {
+	var location Location
+	if !obj.decodeCompoundField(key, &location) {
+		return nil
+	}
+	return &location
+}
+
+// decodeCompoundField decodes the nested map of a compound field into v, reporting whether it succeeded.
+func (obj *SObject) decodeCompoundField(key string, v interface{}) bool {
+	fields, ok := obj.InterfaceField(key).(map[string]interface{})
+	if !ok {
+		return false
+	}
+	// Go through JSON so that numbers decoded as either float64 or json.Number are handled alike.
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,33 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSObject_CompoundFields(t *testing.T) {
+	var obj SObject
+	err := json.Unmarshal([]byte(`{"attributes": {"type": "Account"},
+		"BillingAddress": {"street": "1 Market St", "city": "San Francisco", "stateCode": "CA", "postalCode": "94105",
+			"country": "United States", "latitude": 37.79, "longitude": -122.39, "geocodeAccuracy": "Address"},
+		"ShippingAddress": {"city": "Paris", "latitude": null, "longitude": null},
+		"Site__c": {"latitude": 48.85, "longitude": 2.35},
+		"Name": "Acme"}`), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	billing := obj.AddressField("BillingAddress")
+	if billing == nil || billing.City != "San Francisco" || billing.StateCode != "CA" || *billing.Latitude != 37.79 {
+		t.Errorf("unexpected address %+v", billing)
+	}
+	if shipping := obj.AddressField("ShippingAddress"); shipping == nil || shipping.Latitude != nil {
+		t.Errorf("unexpected address %+v", shipping)
+	}
+	if site := obj.LocationField("Site__c"); site == nil || site.Latitude != 48.85 || site.Longitude != 2.35 {
+		t.Errorf("unexpected location %+v", site)
+	}
+	if obj.AddressField("Name") != nil || obj.LocationField("Missing__c") != nil {
+		t.Fail()
+	}
+}
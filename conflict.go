@@ -0,0 +1,58 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrConflict is returned by UpdateIfUnchanged if the record was modified after the given time.
+var ErrConflict = errors.New("record changed since it was read")
+
+// UpdateIfUnchanged updates obj like SObject.Update, but only if the record has not been modified since
+// lastKnownModstamp, typically the SystemModstamp or LastModifiedDate read along with the record (see ParseDateTime).
+// If the record was modified by someone else in the meantime, nothing is updated and ErrConflict is returned, so
+// concurrent editors do not overwrite each other's changes silently; the caller can reload the record and retry.
+// The check is done by salesforce through the If-Unmodified-Since header, which compares LastModifiedDate at the
+// precision of seconds. Modstamps with fractions of a second are rounded up, so a change within the same second as
+// lastKnownModstamp is not detected.
+func (client *Client) UpdateIfUnchanged(obj *SObject, lastKnownModstamp time.Time) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+	if obj.Type() == "" || obj.ID() == "" {
+		return fmt.Errorf("update requires type and ID")
+	}
+	if err := checkBigObjectWrite(obj.Type(), "update"); err != nil {
+		return err
+	}
+
+	reqData, err := client.marshalJSON(obj.makeCopy())
+	if err != nil {
+		return err
+	}
+
+	since := lastKnownModstamp.UTC()
+	if truncated := since.Truncate(time.Second); !truncated.Equal(since) {
+		since = truncated.Add(time.Second)
+	}
+	header := http.Header{}
+	header.Set("If-Unmodified-Since", since.Format(http.TimeFormat))
+
+	queryBase := "sobjects/"
+	if client.useToolingAPI {
+		queryBase = "tooling/sobjects/"
+	}
+	u := fmt.Sprintf("%s/services/data/v%s/%s%s/%s", client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"),
+		queryBase, url.PathEscape(obj.Type()), url.PathEscape(obj.ID()))
+	_, err = client.httpRequestWithHeader(http.MethodPatch, u, bytes.NewReader(reqData), header)
+	if sfErr, ok := err.(SalesforceError); ok && sfErr.HttpCode == http.StatusPreconditionFailed {
+		return errors.Wrapf(ErrConflict, "%s %s", obj.Type(), obj.ID())
+	}
+	return err
+}
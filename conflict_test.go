@@ -0,0 +1,43 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_UpdateIfUnchanged(t *testing.T) {
+	lastModified := time.Date(2024, 5, 1, 10, 0, 0, 500e6, time.UTC)
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/services/data/v54.0/sobjects/Account/001A" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastModified.After(since) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	account := client.SObject("Account").Set("Id", "001A").Set("Name", "Acme")
+	modstamp, _ := ParseDateTime("2024-05-01T10:00:00.500+0000")
+	if err := client.UpdateIfUnchanged(account, modstamp); err != nil {
+		t.Errorf("expected the update to succeed, got %v", err)
+	}
+
+	lastModified = lastModified.Add(time.Minute)
+	err := client.UpdateIfUnchanged(account, modstamp)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	if err := client.UpdateIfUnchanged(client.SObject("Account"), modstamp); err == nil || errors.Is(err, ErrConflict) {
+		t.Errorf("expected an error for a record without ID, got %v", err)
+	}
+}
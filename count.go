@@ -0,0 +1,32 @@
+package simpleforce
+
+// Count returns the number of records of objectType matching the SOQL condition where, which may be empty, using a
+// SELECT COUNT() query. External objects are counted by querying the IDs of the matching records, see IsExternalObject.
+func (client *Client) Count(objectType, where string) (int, error) {
+	if IsExternalObject(objectType) {
+		return client.countExternal(objectType, where)
+	}
+	result, err := client.Query("SELECT COUNT() FROM " + objectType + whereClause(where))
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalSize, nil
+}
+
+// Exists reports whether any record of objectType matches the SOQL condition where, which may be empty. It queries at
+// most one ID, which is cheaper than counting all matching records.
+func (client *Client) Exists(objectType, where string) (bool, error) {
+	result, err := client.Query("SELECT Id FROM " + objectType + whereClause(where) + " LIMIT 1")
+	if err != nil {
+		return false, err
+	}
+	return len(result.Records) > 0, nil
+}
+
+// whereClause returns the WHERE clause for the condition where, or an empty string if there is no condition.
+func whereClause(where string) string {
+	if where == "" {
+		return ""
+	}
+	return " WHERE " + where
+}
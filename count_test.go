@@ -0,0 +1,35 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClient_CountAndExists(t *testing.T) {
+	var queries []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+		switch q {
+		case "SELECT COUNT() FROM Case WHERE IsClosed = false":
+			w.Write([]byte(`{"totalSize": 42, "done": true, "records": []}`))
+		case "SELECT Id FROM Case WHERE Subject = 'x' LIMIT 1":
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Case"}, "Id": "500A"}]}`))
+		default:
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		}
+	})
+
+	if count, err := client.Count("Case", "IsClosed = false"); err != nil || count != 42 {
+		t.Errorf("unexpected count %d, %v", count, err)
+	}
+	if exists, err := client.Exists("Case", "Subject = 'x'"); err != nil || !exists {
+		t.Errorf("unexpected exists %v, %v", exists, err)
+	}
+	if exists, err := client.Exists("Case", ""); err != nil || exists {
+		t.Errorf("unexpected exists %v, %v", exists, err)
+	}
+	if queries[2] != "SELECT Id FROM Case LIMIT 1" {
+		t.Errorf("unexpected query %s", queries[2])
+	}
+}
@@ -0,0 +1,106 @@
+package simpleforce
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoCoverage is returned by OrgWideCoverage when no code coverage has been computed for the org yet.
+var ErrNoCoverage = errors.New("no code coverage available")
+
+// CodeCoverage describes the aggregate code coverage of an Apex class or trigger over all test runs.
+type CodeCoverage struct {
+	ID                string // ID of the class or trigger
+	Name              string
+	NumLinesCovered   int
+	NumLinesUncovered int
+	CoveredLines      []int
+	UncoveredLines    []int
+}
+
+// Percent returns the covered lines as a percentage of all coverable lines, or 0 if there are none.
+func (coverage *CodeCoverage) Percent() float64 {
+	total := coverage.NumLinesCovered + coverage.NumLinesUncovered
+	if total == 0 {
+		return 0
+	}
+	return float64(coverage.NumLinesCovered) * 100 / float64(total)
+}
+
+// QueryCodeCoverage returns the aggregate code coverage of the Apex classes and triggers of the given names, or of
+// all of them if none are given.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_apexcodecoverageaggregate.htm
+func (client *Client) QueryCodeCoverage(names ...string) ([]CodeCoverage, error) {
+	soql := "SELECT ApexClassOrTriggerId, ApexClassOrTrigger.Name, NumLinesCovered, NumLinesUncovered, Coverage " +
+		"FROM ApexCodeCoverageAggregate"
+	if len(names) > 0 {
+		quoted := make([]string, len(names))
+		for idx, name := range names {
+			quoted[idx] = QuoteSOQL(name)
+		}
+		soql += " WHERE ApexClassOrTrigger.Name IN (" + strings.Join(quoted, ", ") + ")"
+	}
+
+	var coverages []CodeCoverage
+	err := client.toolingQueryPages(soql, func(records []byte) error {
+		var page []struct {
+			ApexClassOrTriggerID string `json:"ApexClassOrTriggerId"`
+			ApexClassOrTrigger   struct {
+				Name string `json:"Name"`
+			} `json:"ApexClassOrTrigger"`
+			NumLinesCovered   int `json:"NumLinesCovered"`
+			NumLinesUncovered int `json:"NumLinesUncovered"`
+			Coverage          struct {
+				CoveredLines   []int `json:"coveredLines"`
+				UncoveredLines []int `json:"uncoveredLines"`
+			} `json:"Coverage"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		if err != nil {
+			return err
+		}
+		for _, record := range page {
+			coverages = append(coverages, CodeCoverage{
+				ID:                record.ApexClassOrTriggerID,
+				Name:              record.ApexClassOrTrigger.Name,
+				NumLinesCovered:   record.NumLinesCovered,
+				NumLinesUncovered: record.NumLinesUncovered,
+				CoveredLines:      record.Coverage.CoveredLines,
+				UncoveredLines:    record.Coverage.UncoveredLines,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// OrgWideCoverage returns the percentage of Apex code covered by tests across the org, as used to gate deployments to
+// production, e.g. in CI:
+//
+//	if percent, err := client.OrgWideCoverage(); err == nil && percent < 75 {
+//		log.Fatal("insufficient coverage")
+//	}
+func (client *Client) OrgWideCoverage() (float64, error) {
+	var percent *float64
+	err := client.toolingQueryPages("SELECT PercentCovered FROM ApexOrgWideCoverage", func(records []byte) error {
+		var page []struct {
+			PercentCovered float64 `json:"PercentCovered"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		if err == nil && len(page) > 0 {
+			percent = &page[0].PercentCovered
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if percent == nil {
+		return 0, ErrNoCoverage
+	}
+	return *percent, nil
+}
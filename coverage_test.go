@@ -0,0 +1,43 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_QueryCodeCoverage(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/tooling/query") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		q := r.URL.Query().Get("q")
+		switch {
+		case strings.HasSuffix(q, "FROM ApexCodeCoverageAggregate WHERE ApexClassOrTrigger.Name IN ('Hello', 'World')"):
+			w.Write([]byte(`{"done": true, "records": [{
+				"ApexClassOrTriggerId": "01pA",
+				"ApexClassOrTrigger": {"attributes": {"type": "Name"}, "Name": "Hello"},
+				"NumLinesCovered": 3,
+				"NumLinesUncovered": 1,
+				"Coverage": {"coveredLines": [1, 2, 3], "uncoveredLines": [5]}
+			}]}`))
+		case q == "SELECT PercentCovered FROM ApexOrgWideCoverage":
+			w.Write([]byte(`{"done": true, "records": [{"PercentCovered": 81}]}`))
+		default:
+			t.Errorf("unexpected query %s", q)
+		}
+	})
+
+	coverages, err := client.QueryCodeCoverage("Hello", "World")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverages) != 1 || coverages[0].Name != "Hello" || coverages[0].Percent() != 75 ||
+		len(coverages[0].CoveredLines) != 3 || coverages[0].UncoveredLines[0] != 5 {
+		t.Errorf("unexpected coverage %+v", coverages)
+	}
+
+	if percent, err := client.OrgWideCoverage(); err != nil || percent != 81 {
+		t.Errorf("unexpected org-wide coverage %v, %v", percent, err)
+	}
+}
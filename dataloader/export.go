@@ -0,0 +1,371 @@
+package dataloader
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/scottraio/simpleforce"
+)
+
+// Format is the output format of an Exporter.
+type Format int
+
+const (
+	// CSV writes a header row followed by one row per record. Null values are written as empty cells.
+	CSV Format = iota
+	// JSONLines writes one JSON object per line, keyed by column. Null values are written as null.
+	JSONLines
+)
+
+// Exporter runs SOQL queries and streams the records to a writer, page by page. Columns are derived from the SELECT
+// clause of the query, and relationship fields such as "Account.Owner.Name" are flattened into a single column.
+// Aggregate expressions are named by their alias, or expr0, expr1, ... like salesforce does. Subqueries are written
+// as a JSON array of the child records.
+type Exporter struct {
+	Client *simpleforce.Client
+	Format Format
+	// Bulk runs the query as a Bulk API 2.0 query job, which suits large extractions. Bulk queries do not support
+	// subqueries and aggregate expressions. Empty values are exported as null.
+	Bulk bool
+	// Masks replaces the values of sensitive columns before they are written, see Masking.
+	Masks Masking
+}
+
+// Export runs soql and writes all records to w, returning the number of records written.
+func (exporter *Exporter) Export(soql string, w io.Writer) (int, error) {
+	columns, err := QueryColumns(soql)
+	if err != nil {
+		return 0, err
+	}
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	switch exporter.Format {
+	case CSV:
+		csvWriter = csv.NewWriter(w)
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+		csvWriter.Write(names)
+	case JSONLines:
+		jsonEncoder = json.NewEncoder(w)
+	default:
+		return 0, fmt.Errorf("unsupported format %d", exporter.Format)
+	}
+
+	count := 0
+	write := func(record map[string]interface{}) error {
+		var err error
+		if csvWriter != nil {
+			row := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row = append(row, formatCell(exporter.Masks.apply(column.Name, column.value(record))))
+			}
+			err = csvWriter.Write(row)
+		} else {
+			values := make(orderedObject, 0, len(columns))
+			for _, column := range columns {
+				value := exporter.Masks.apply(column.Name, column.value(record))
+				values = append(values, orderedField{column.Name, cleanValue(value)})
+			}
+			err = jsonEncoder.Encode(values)
+		}
+		if err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+	flush := func() error {
+		if csvWriter == nil {
+			return nil
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+
+	if exporter.Bulk {
+		err = exporter.Client.BulkQueryEach(context.Background(), soql, func(header, row []string) error {
+			return write(bulkRecord(header, row))
+		})
+		if err != nil {
+			flush()
+			return count, err
+		}
+		return count, flush()
+	}
+
+	q := soql
+	for {
+		result, err := exporter.Client.Query(q)
+		if err != nil {
+			return count, err
+		}
+		for idx := range result.Records {
+			if err = write(map[string]interface{}(result.Records[idx])); err != nil {
+				return count, err
+			}
+		}
+		if err = flush(); err != nil {
+			return count, err
+		}
+
+		if result.Done || result.NextRecordsURL == "" {
+			break
+		}
+		q = result.NextRecordsURL
+	}
+	return count, nil
+}
+
+// bulkRecord converts a row of the CSV results of a bulk query to a record like the ones of the REST API, nesting the
+// values of relationship columns such as "Account.Name". Empty cells are null.
+func bulkRecord(header, row []string) map[string]interface{} {
+	record := map[string]interface{}{}
+	for idx, name := range header {
+		if idx >= len(row) {
+			break
+		}
+		var value interface{}
+		if row[idx] != "" {
+			value = row[idx]
+		}
+		path := strings.Split(name, ".")
+		parent := record
+		for _, key := range path[:len(path)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = value
+	}
+	return record
+}
+
+// Column is an output column of an export.
+type Column struct {
+	// Name is the header of the column, e.g. "Account.Name".
+	Name string
+	// Path is the sequence of keys leading to the value in a record, e.g. ["Account", "Name"].
+	Path []string
+}
+
+// value looks up the column in a record. Keys are matched case-insensitively, as the casing of the query may differ
+// from the casing of the API names in the response.
+func (column Column) value(record map[string]interface{}) interface{} {
+	var current interface{} = record
+	for _, key := range column.Path {
+		mapper, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = lookupFold(mapper, key)
+	}
+	return current
+}
+
+func lookupFold(mapper map[string]interface{}, key string) interface{} {
+	if value, ok := mapper[key]; ok {
+		return value
+	}
+	for k, value := range mapper {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
+// QueryColumns derives the output columns from the SELECT clause of a SOQL query.
+func QueryColumns(soql string) ([]Column, error) {
+	selectList, err := selectClause(soql)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []Column
+	exprCount := 0
+	for _, item := range splitTopLevel(selectList) {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty item in SELECT clause of %q", soql)
+		}
+
+		switch {
+		case strings.HasPrefix(item, "("):
+			// Subquery: (SELECT ... FROM Relationship ...)
+			inner := strings.TrimSpace(item[1 : len(item)-1])
+			fromIdx := indexKeyword(inner, "FROM")
+			if fromIdx < 0 {
+				return nil, fmt.Errorf("invalid subquery %q", item)
+			}
+			relationship := strings.Fields(inner[fromIdx+len("FROM"):])[0]
+			columns = append(columns, Column{Name: relationship, Path: []string{relationship}})
+		case strings.Contains(item, "("):
+			function := strings.ToLower(strings.TrimSpace(item[:strings.Index(item, "(")]))
+			closing := strings.LastIndex(item, ")")
+			alias := strings.TrimSpace(item[closing+1:])
+			if alias == "" && (function == "tolabel" || function == "format" || function == "convertcurrency") {
+				// Non-aggregate functions are returned under the field name.
+				field := strings.TrimSpace(item[strings.Index(item, "(")+1 : closing])
+				columns = append(columns, Column{Name: field, Path: strings.Split(field, ".")})
+				continue
+			}
+			if alias == "" {
+				alias = "expr" + strconv.Itoa(exprCount)
+				exprCount++
+			}
+			columns = append(columns, Column{Name: alias, Path: []string{alias}})
+		default:
+			columns = append(columns, Column{Name: fields[0], Path: strings.Split(fields[0], ".")})
+		}
+	}
+	return columns, nil
+}
+
+// selectClause returns the text between the leading SELECT and the top-level FROM of a query.
+func selectClause(soql string) (string, error) {
+	trimmed := strings.TrimSpace(soql)
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return "", fmt.Errorf("not a SELECT query: %q", soql)
+	}
+	rest := trimmed[len("SELECT"):]
+	fromIdx := indexKeyword(rest, "FROM")
+	if fromIdx < 0 {
+		return "", fmt.Errorf("missing FROM in %q", soql)
+	}
+	return rest[:fromIdx], nil
+}
+
+// indexKeyword returns the index of the first occurrence of keyword outside of parentheses and quotes, or -1.
+func indexKeyword(s, keyword string) int {
+	depth := 0
+	quoted := false
+	for idx := 0; idx < len(s); idx++ {
+		switch c := s[idx]; {
+		case c == '\'' && (idx == 0 || s[idx-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && idx+len(keyword) <= len(s) && strings.EqualFold(s[idx:idx+len(keyword)], keyword):
+			before := idx == 0 || unicode.IsSpace(rune(s[idx-1]))
+			after := idx+len(keyword) == len(s) || unicode.IsSpace(rune(s[idx+len(keyword)]))
+			if before && after {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s at commas outside of parentheses.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth := 0
+	start := 0
+	for idx, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:idx]))
+				start = idx + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
+
+// cleanValue strips the attributes of nested records and subquery results.
+func cleanValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if records, ok := v["records"].([]interface{}); ok {
+			// Subquery results are wrapped in a query result.
+			return cleanValue(records)
+		}
+		cleaned := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			if key != "attributes" {
+				cleaned[key] = cleanValue(val)
+			}
+		}
+		return cleaned
+	case []interface{}:
+		cleaned := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			cleaned = append(cleaned, cleanValue(val))
+		}
+		return cleaned
+	default:
+		return value
+	}
+}
+
+// formatCell formats a value for a CSV cell.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(cleanValue(v))
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// orderedObject is a JSON object which keeps the order of its fields.
+type orderedObject []orderedField
+
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (object orderedObject) MarshalJSON() ([]byte, error) {
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for idx, field := range object {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return []byte(buf.String()), nil
+}
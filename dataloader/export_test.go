@@ -0,0 +1,129 @@
+package dataloader
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottraio/simpleforce"
+)
+
+func TestQueryColumns(t *testing.T) {
+	columns, err := QueryColumns("select Id, account.owner.name, toLabel(Status), COUNT(Id), MAX(Amount) maxAmount, " +
+		"(SELECT Id FROM Contacts WHERE Name IN ('a, b')) FROM Opportunity WHERE Name = 'from'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	if strings.Join(names, "|") != "Id|account.owner.name|Status|expr0|maxAmount|Contacts" {
+		t.Errorf("unexpected columns %v", names)
+	}
+
+	if _, err := QueryColumns("DELETE FROM Account"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestExporter_Export(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalSize":      2,
+				"done":           false,
+				"nextRecordsUrl": "/services/data/v" + simpleforce.DefaultAPIVersion + "/query/01g-1",
+				"records": []map[string]interface{}{{
+					"attributes": map[string]string{"type": "Contact"},
+					"Id":         "003A",
+					"Name":       "Smith, John",
+					"Account":    map[string]interface{}{"attributes": map[string]string{"type": "Account"}, "Name": "Acme"},
+					"Score__c":   12.5,
+				}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"totalSize": 2,
+			"done":      true,
+			"records": []map[string]interface{}{{
+				"attributes": map[string]string{"type": "Contact"},
+				"Id":         "003B",
+				"Name":       "Jones",
+				"Account":    nil,
+				"Score__c":   nil,
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	soql := "SELECT Id, Name, Account.Name, Score__c FROM Contact"
+
+	var buf bytes.Buffer
+	count, err := (&Exporter{Client: client, Format: CSV}).Export(soql, &buf)
+	if err != nil || count != 2 {
+		t.Fatalf("unexpected count %d, err %v", count, err)
+	}
+	expected := "Id,Name,Account.Name,Score__c\n003A,\"Smith, John\",Acme,12.5\n003B,Jones,,\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected CSV %q", buf.String())
+	}
+
+	buf.Reset()
+	count, err = (&Exporter{Client: client, Format: JSONLines}).Export(soql, &buf)
+	if err != nil || count != 2 {
+		t.Fatalf("unexpected count %d, err %v", count, err)
+	}
+	expected = `{"Id":"003A","Name":"Smith, John","Account.Name":"Acme","Score__c":12.5}` + "\n" +
+		`{"Id":"003B","Name":"Jones","Account.Name":null,"Score__c":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected JSON Lines %q", buf.String())
+	}
+
+	buf.Reset()
+	masks := Masking{"name": Truncate(3), "account.name": Null}
+	if _, err = (&Exporter{Client: client, Format: CSV, Masks: masks}).Export(soql, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expected = "Id,Name,Account.Name,Score__c\n003A,Smi,,12.5\n003B,Jon,,\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected masked CSV %q", buf.String())
+	}
+}
+
+func TestExporter_ExportBulk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/query"):
+			w.Write([]byte(`{"id": "750Q", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750Q"):
+			w.Write([]byte(`{"id": "750Q", "state": "JobComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750Q/results"):
+			w.Write([]byte("\"Id\",\"Name\",\"Account.Name\"\n\"003A\",\"Smith, John\",\"Acme\"\n\"003B\",\"Jones\",\"\"\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	var buf bytes.Buffer
+	exporter := &Exporter{Client: client, Format: JSONLines, Bulk: true}
+	count, err := exporter.Export("SELECT Id, Name, Account.Name FROM Contact", &buf)
+	if err != nil || count != 2 {
+		t.Fatalf("unexpected count %d, err %v", count, err)
+	}
+	expected := `{"Id":"003A","Name":"Smith, John","Account.Name":"Acme"}` + "\n" +
+		`{"Id":"003B","Name":"Jones","Account.Name":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected JSON Lines %q", buf.String())
+	}
+}
@@ -0,0 +1,390 @@
+// Package dataloader imports CSV files into salesforce and exports query results to CSV or JSON Lines, much like a
+// programmatic Data Loader.
+package dataloader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce"
+)
+
+// Operation is the DML operation performed by an Importer.
+type Operation string
+
+const (
+	Insert Operation = "insert"
+	Update Operation = "update"
+	Delete Operation = "delete"
+
+	// SuccessIDColumn and ErrorColumn are appended to the columns of the input in the result files.
+	SuccessIDColumn = "sf__Id"
+	ErrorColumn     = "sf__Error"
+
+	defaultImportBatchSize = 200
+	defaultBulkBatchSize   = 10000
+)
+
+// defaultDateLayouts are tried in order to parse date and datetime cells.
+var defaultDateLayouts = []string{
+	"2006-01-02T15:04:05.000Z0700",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"1/2/2006",
+}
+
+// Importer loads the rows of a CSV file into records of Object with sObject Collections requests, or Bulk API 2.0
+// ingest jobs if Bulk is set. The first row of
+// the input must be a header. Columns are mapped to fields by Mapping, or to the field of the same name
+// (case-insensitively) if the column is not in Mapping; unmapped columns are ignored. A column of the form
+// "Relationship.ExternalIdField", e.g. "Account.External_Id__c", sets a lookup through the external ID of the related
+// record. Cells are converted to the field types from the describe metadata of Object.
+//
+// Rows processed successfully are written to Success with the record ID appended, failed rows are written to Errors
+// with the error message appended. Both writers are optional.
+type Importer struct {
+	Client    *simpleforce.Client
+	Object    string
+	Operation Operation
+	Mapping   map[string]string
+
+	// DateLayouts overrides the layouts used to parse date and datetime cells.
+	DateLayouts []string
+	// InsertNulls sets fields of empty cells to null instead of leaving them out.
+	InsertNulls bool
+	// BatchSize is the number of rows per collection request, at most 200, or per ingest job with Bulk, 10000 by
+	// default.
+	BatchSize int
+	// Bulk loads the rows with Bulk API 2.0 ingest jobs, which saves API requests for large files.
+	Bulk bool
+
+	Success io.Writer
+	Errors  io.Writer
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	Rows      int
+	Succeeded int
+	Failed    int
+}
+
+// importField describes how a column is converted into a field of the request.
+type importField struct {
+	name         string
+	fieldType    string
+	relationship string
+	relatedType  string
+}
+
+type importRow struct {
+	cells  []string
+	record *simpleforce.SObject
+}
+
+// Import reads CSV rows from r and loads them into salesforce. An error is returned if the input cannot be read or a
+// request fails as a whole; errors of single rows are only reported through the result and the Errors file.
+func (importer *Importer) Import(r io.Reader) (*ImportResult, error) {
+	fields, err := importer.describeFields()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read header")
+	}
+	columns, idColumn, err := importer.mapColumns(header, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var successWriter, errorWriter *csv.Writer
+	if importer.Success != nil {
+		successWriter = csv.NewWriter(importer.Success)
+		successWriter.Write(append(append([]string{}, header...), SuccessIDColumn))
+		defer successWriter.Flush()
+	}
+	if importer.Errors != nil {
+		errorWriter = csv.NewWriter(importer.Errors)
+		errorWriter.Write(append(append([]string{}, header...), ErrorColumn))
+		defer errorWriter.Flush()
+	}
+
+	result := &ImportResult{}
+	fail := func(cells []string, message string) {
+		result.Failed++
+		if errorWriter != nil {
+			errorWriter.Write(append(append([]string{}, cells...), message))
+		}
+	}
+
+	batchSize := importer.BatchSize
+	if importer.Bulk && batchSize <= 0 {
+		batchSize = defaultBulkBatchSize
+	} else if !importer.Bulk && (batchSize <= 0 || batchSize > defaultImportBatchSize) {
+		batchSize = defaultImportBatchSize
+	}
+	var batch []importRow
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		saveResults, err := importer.load(batch)
+		if len(saveResults) != len(batch) {
+			return err
+		}
+		for idx, saveResult := range saveResults {
+			if saveResult.Success {
+				result.Succeeded++
+				if successWriter != nil {
+					successWriter.Write(append(append([]string{}, batch[idx].cells...), saveResult.ID))
+				}
+				continue
+			}
+			messages := make([]string, 0, len(saveResult.Errors))
+			for _, saveErr := range saveResult.Errors {
+				messages = append(messages, saveErr.Error())
+			}
+			if len(messages) == 0 && err != nil {
+				messages = append(messages, err.Error())
+			}
+			fail(batch[idx].cells, strings.Join(messages, "; "))
+		}
+		batch = batch[:0]
+		if batchErr, ok := err.(*simpleforce.BatchError); ok {
+			// Row failures are reported through the error file; only stop if a request failed as a whole.
+			for _, recordErr := range batchErr.Records {
+				if recordErr.Err != nil {
+					return recordErr.Err
+				}
+			}
+			return nil
+		}
+		return err
+	}
+
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Rows++
+
+		record, err := importer.makeRecord(cells, columns, idColumn)
+		if err != nil {
+			fail(cells, err.Error())
+			continue
+		}
+		batch = append(batch, importRow{cells: cells, record: record})
+		if len(batch) == batchSize {
+			err = flush()
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+// describeFields returns the fields of Object by name, and by relationship name for lookups.
+func (importer *Importer) describeFields() (map[string]importField, error) {
+	metas, err := importer.Client.DescribeSObjects(importer.Object)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe %s", importer.Object)
+	}
+	meta := metas[importer.Object]
+
+	fields := make(map[string]importField)
+	rawFields, _ := (*meta)["fields"].([]interface{})
+	for _, rawField := range rawFields {
+		field, ok := rawField.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		fieldType, _ := field["type"].(string)
+		fields[strings.ToLower(name)] = importField{name: name, fieldType: fieldType}
+
+		relationship, _ := field["relationshipName"].(string)
+		referenceTo, _ := field["referenceTo"].([]interface{})
+		if relationship != "" && len(referenceTo) > 0 {
+			relatedType, _ := referenceTo[0].(string)
+			fields[strings.ToLower(relationship)+"."] = importField{relationship: relationship, relatedType: relatedType}
+		}
+	}
+	return fields, nil
+}
+
+// mapColumns resolves the field of every column. Unmapped columns are nil. The position of the Id column is returned
+// as well, or -1 if there is none.
+func (importer *Importer) mapColumns(header []string, fields map[string]importField) ([]*importField, int, error) {
+	columns := make([]*importField, len(header))
+	idColumn := -1
+	for idx, column := range header {
+		target := column
+		if mapped, ok := importer.Mapping[column]; ok {
+			target = mapped
+		}
+		if target == "" {
+			continue
+		}
+
+		if dot := strings.Index(target, "."); dot > 0 {
+			relation, ok := fields[strings.ToLower(target[:dot])+"."]
+			if !ok {
+				return nil, -1, fmt.Errorf("column %s: unknown relationship %s", column, target[:dot])
+			}
+			relation.name = target[dot+1:]
+			columns[idx] = &relation
+			continue
+		}
+
+		field, ok := fields[strings.ToLower(target)]
+		if !ok {
+			if _, mapped := importer.Mapping[column]; mapped {
+				return nil, -1, fmt.Errorf("column %s: unknown field %s", column, target)
+			}
+			continue
+		}
+		if field.name == "Id" {
+			idColumn = idx
+			continue
+		}
+		columns[idx] = &field
+	}
+
+	if (importer.Operation == Update || importer.Operation == Delete) && idColumn == -1 {
+		return nil, -1, fmt.Errorf("%s requires an Id column", importer.Operation)
+	}
+	return columns, idColumn, nil
+}
+
+// makeRecord converts a row into a record.
+func (importer *Importer) makeRecord(cells []string, columns []*importField, idColumn int) (*simpleforce.SObject, error) {
+	record := importer.Client.SObject(importer.Object)
+	if idColumn >= 0 && idColumn < len(cells) {
+		record.Set("Id", cells[idColumn])
+	}
+	if importer.Operation == Delete {
+		return record, nil
+	}
+
+	for idx, field := range columns {
+		if field == nil || idx >= len(cells) {
+			continue
+		}
+		cell := strings.TrimSpace(cells[idx])
+
+		if field.relationship != "" {
+			if cell != "" {
+				record.Set(field.relationship, map[string]interface{}{
+					"attributes": map[string]string{"type": field.relatedType},
+					field.name:   cell,
+				})
+			}
+			continue
+		}
+
+		if cell == "" {
+			if importer.InsertNulls {
+				record.Set(field.name, nil)
+			}
+			continue
+		}
+		value, err := importer.convert(cell, field.fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", field.name, err)
+		}
+		record.Set(field.name, value)
+	}
+	return record, nil
+}
+
+// convert coerces a cell into the JSON value of a field of the given describe type.
+func (importer *Importer) convert(cell, fieldType string) (interface{}, error) {
+	switch fieldType {
+	case "boolean":
+		switch strings.ToLower(cell) {
+		case "true", "yes", "y", "1":
+			return true, nil
+		case "false", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid boolean %q", cell)
+	case "int":
+		return strconv.ParseInt(strings.Replace(cell, ",", "", -1), 10, 64)
+	case "double", "currency", "percent":
+		return strconv.ParseFloat(strings.Replace(cell, ",", "", -1), 64)
+	case "date":
+		t, err := importer.parseTime(cell)
+		if err != nil {
+			return nil, err
+		}
+		return t.Format("2006-01-02"), nil
+	case "datetime":
+		t, err := importer.parseTime(cell)
+		if err != nil {
+			return nil, err
+		}
+		return t.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	default:
+		return cell, nil
+	}
+}
+
+func (importer *Importer) parseTime(cell string) (time.Time, error) {
+	layouts := importer.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, cell)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", cell)
+}
+
+// load sends a batch of rows with the configured operation, in a single request or ingest job.
+func (importer *Importer) load(batch []importRow) ([]simpleforce.SaveResult, error) {
+	opt := simpleforce.WithBatchSize(len(batch))
+	if importer.Bulk {
+		opt = simpleforce.WithBulkThreshold(1)
+	}
+	switch importer.Operation {
+	case Update:
+		records := make([]*simpleforce.SObject, 0, len(batch))
+		for _, row := range batch {
+			records = append(records, row.record)
+		}
+		return importer.Client.UpdateAll(records, opt)
+	case Delete:
+		ids := make([]string, 0, len(batch))
+		for _, row := range batch {
+			ids = append(ids, row.record.ID())
+		}
+		return importer.Client.DeleteAll(ids, opt)
+	case Insert, "":
+		records := make([]*simpleforce.SObject, 0, len(batch))
+		for _, row := range batch {
+			records = append(records, row.record)
+		}
+		return importer.Client.CreateAll(records, opt)
+	default:
+		return nil, fmt.Errorf("unsupported operation %s", importer.Operation)
+	}
+}
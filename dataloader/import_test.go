@@ -0,0 +1,164 @@
+package dataloader
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottraio/simpleforce"
+)
+
+func newDescribeServer(t *testing.T, save http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sobjects/Contact/describe") {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name": "Contact",
+				"fields": []map[string]interface{}{
+					{"name": "Id", "type": "id"},
+					{"name": "LastName", "type": "string"},
+					{"name": "Birthdate", "type": "date"},
+					{"name": "Score__c", "type": "double"},
+					{"name": "DoNotCall", "type": "boolean"},
+					{"name": "AccountId", "type": "reference", "relationshipName": "Account", "referenceTo": []string{"Account"}},
+				},
+			})
+			return
+		}
+		save(w, r)
+	}))
+}
+
+func TestImporter_Import(t *testing.T) {
+	var sent []map[string]interface{}
+	server := newDescribeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var results []simpleforce.SaveResult
+		for _, record := range body.Records {
+			sent = append(sent, record)
+			if record["LastName"] == "Fail" {
+				results = append(results, simpleforce.SaveResult{Errors: []simpleforce.SaveError{{StatusCode: "REQUIRED_FIELD_MISSING", Message: "missing"}}})
+			} else {
+				results = append(results, simpleforce.SaveResult{ID: "003" + record["LastName"].(string), Success: true})
+			}
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	input := "Last Name,birthdate,Score__c,DoNotCall,Account.External_Id__c,Ignored\n" +
+		"Smith,1980-01-31,\"1,234.5\",yes,ACME-1,x\n" +
+		"Jones,not a date,1,no,,x\n" +
+		"Fail,01/02/2006,,false,,x\n"
+	var success, failure bytes.Buffer
+	importer := &Importer{
+		Client:    client,
+		Object:    "Contact",
+		Operation: Insert,
+		Mapping:   map[string]string{"Last Name": "LastName"},
+		Success:   &success,
+		Errors:    &failure,
+	}
+	result, err := importer.Import(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 3 || result.Succeeded != 1 || result.Failed != 2 || len(sent) != 2 {
+		t.Fatalf("unexpected result %+v, sent %v", result, sent)
+	}
+
+	smith := sent[0]
+	account := smith["Account"].(map[string]interface{})
+	if smith["Birthdate"] != "1980-01-31" || smith["Score__c"] != 1234.5 || smith["DoNotCall"] != true ||
+		account["External_Id__c"] != "ACME-1" || smith["Ignored"] != nil {
+		t.Errorf("unexpected record %v", smith)
+	}
+	if sent[1]["Birthdate"] != "2006-01-02" {
+		t.Errorf("unexpected record %v", sent[1])
+	}
+
+	if !strings.Contains(success.String(), "Smith,1980-01-31,\"1,234.5\",yes,ACME-1,x,003Smith") {
+		t.Errorf("unexpected success file %s", success.String())
+	}
+	errorLines := strings.Split(strings.TrimSpace(failure.String()), "\n")
+	if len(errorLines) != 3 || !strings.Contains(errorLines[1], "invalid date") ||
+		!strings.Contains(errorLines[2], "REQUIRED_FIELD_MISSING") {
+		t.Errorf("unexpected error file %s", failure.String())
+	}
+}
+
+func TestImporter_ImportUpdateRequiresID(t *testing.T) {
+	server := newDescribeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	importer := &Importer{Client: client, Object: "Contact", Operation: Update}
+	if _, err := importer.Import(strings.NewReader("LastName\nSmith\n")); err == nil {
+		t.Fail()
+	}
+}
+
+func TestImporter_ImportDescribeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`))
+	}))
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	importer := &Importer{Client: client, Object: "Contact"}
+	_, err := importer.Import(strings.NewReader("LastName\nSmith\n"))
+	if err == nil || !strings.Contains(err.Error(), "INVALID_SESSION_ID") {
+		t.Errorf("expected the describe error, got %v", err)
+	}
+}
+
+func TestImporter_ImportBulk(t *testing.T) {
+	server := newDescribeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/ingest"):
+			w.Write([]byte(`{"id": "750I", "state": "Open"}`))
+		case r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPatch:
+			w.Write([]byte(`{"id": "750I", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750I"):
+			w.Write([]byte(`{"id": "750I", "state": "JobComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/successfulResults"):
+			w.Write([]byte("sf__Id,sf__Created,LastName\n003S,true,Smith\n"))
+		case strings.HasSuffix(r.URL.Path, "/failedResults"):
+			w.Write([]byte("sf__Id,sf__Error,LastName\n,REQUIRED_FIELD_MISSING:missing,Fail\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	var success, failure bytes.Buffer
+	importer := &Importer{Client: client, Object: "Contact", Bulk: true, Success: &success, Errors: &failure}
+	result, err := importer.Import(strings.NewReader("LastName\nFail\nSmith\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 2 || result.Succeeded != 1 || result.Failed != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if success.String() != "LastName,sf__Id\nSmith,003S\n" ||
+		failure.String() != "LastName,sf__Error\nFail,REQUIRED_FIELD_MISSING: missing\n" {
+		t.Errorf("unexpected result files %q %q", success.String(), failure.String())
+	}
+}
@@ -0,0 +1,112 @@
+package dataloader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Mask replaces a field value before it leaves the export. Null values are passed to the mask as nil.
+type Mask func(value interface{}) interface{}
+
+// Masking maps column names, e.g. "Email" or "Account.Phone", to the mask applied to them. Names are matched
+// case-insensitively. It lets production data be copied into sandboxes and other lower environments without
+// carrying personal data along.
+type Masking map[string]Mask
+
+// lookup returns the mask of a column, or nil if the column is not masked.
+func (masking Masking) lookup(column string) Mask {
+	if mask, ok := masking[column]; ok {
+		return mask
+	}
+	for name, mask := range masking {
+		if strings.EqualFold(name, column) {
+			return mask
+		}
+	}
+	return nil
+}
+
+// apply masks the value of a column.
+func (masking Masking) apply(column string, value interface{}) interface{} {
+	if mask := masking.lookup(column); mask != nil {
+		return mask(value)
+	}
+	return value
+}
+
+// Apply masks a query record in place, e.g. one returned by Client.Query. Relationship columns are followed through
+// the nested records; a column whose parent record is null is left alone.
+func (masking Masking) Apply(record map[string]interface{}) {
+	for name, mask := range masking {
+		path := strings.Split(name, ".")
+		current := record
+		for _, key := range path[:len(path)-1] {
+			next, ok := lookupFold(current, key).(map[string]interface{})
+			if !ok {
+				current = nil
+				break
+			}
+			current = next
+		}
+		if current == nil {
+			continue
+		}
+		key := path[len(path)-1]
+		for k := range current {
+			if strings.EqualFold(k, key) {
+				key = k
+				break
+			}
+		}
+		if value, ok := current[key]; ok {
+			current[key] = mask(value)
+		}
+	}
+}
+
+// Null masks every value as null, e.g. for free-text fields.
+func Null(value interface{}) interface{} {
+	return nil
+}
+
+// Hash masks a value with the hex encoded SHA-256 hash of salt and the value. Equal values hash to equal results, so
+// the column can still be joined on. Null values stay null.
+func Hash(salt string) Mask {
+	return func(value interface{}) interface{} {
+		if value == nil {
+			return nil
+		}
+		sum := sha256.Sum256([]byte(salt + fmt.Sprint(value)))
+		return hex.EncodeToString(sum[:])
+	}
+}
+
+// HashEmail masks an email address as a hashed address at the reserved example.invalid domain, so the result still
+// passes email validation but can never be delivered. Addresses are lower cased before hashing.
+func HashEmail(salt string) Mask {
+	hash := Hash(salt)
+	return func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok || s == "" {
+			return value
+		}
+		return hash(strings.ToLower(s)).(string)[:16] + "@example.invalid"
+	}
+}
+
+// Truncate keeps the first n characters of a string value, e.g. the area code of phone numbers. Other values are left
+// unchanged.
+func Truncate(n int) Mask {
+	return func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+		if runes := []rune(s); len(runes) > n {
+			return string(runes[:n])
+		}
+		return s
+	}
+}
@@ -0,0 +1,51 @@
+package dataloader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMasking_Apply(t *testing.T) {
+	record := map[string]interface{}{
+		"Email":       "John.Smith@acme.com",
+		"Phone":       "(415) 555-0100",
+		"Description": "call after 5pm",
+		"Account":     map[string]interface{}{"Phone": "(212) 555-0199"},
+		"Owner":       nil,
+	}
+	masking := Masking{
+		"email":         HashEmail("salt"),
+		"Phone":         Truncate(5),
+		"Description":   Null,
+		"Account.Phone": Truncate(5),
+		"Owner.Email":   HashEmail("salt"),
+	}
+	masking.Apply(record)
+
+	email, _ := record["Email"].(string)
+	if !strings.HasSuffix(email, "@example.invalid") || strings.Contains(email, "smith") {
+		t.Errorf("unexpected email %q", email)
+	}
+	if other := HashEmail("salt")("john.smith@ACME.com"); other != email {
+		t.Errorf("expected hashing to ignore case, got %q and %q", email, other)
+	}
+	if record["Phone"] != "(415)" || record["Account"].(map[string]interface{})["Phone"] != "(212)" {
+		t.Errorf("unexpected phones %v, %v", record["Phone"], record["Account"])
+	}
+	if record["Description"] != nil {
+		t.Errorf("expected description to be nulled, got %v", record["Description"])
+	}
+	if record["Owner"] != nil {
+		t.Errorf("expected null parent to be left alone, got %v", record["Owner"])
+	}
+}
+
+func TestHash(t *testing.T) {
+	hash := Hash("salt")
+	if hash(nil) != nil {
+		t.Error("expected null to stay null")
+	}
+	if hash("a") != hash("a") || hash("a") == hash("b") || hash("a") == Hash("pepper")("a") {
+		t.Error("expected hashes to depend on value and salt only")
+	}
+}
@@ -0,0 +1,124 @@
+package simpleforce
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDebugLevelNotFound is returned when a debug level cannot be resolved by its developer name.
+var ErrDebugLevelNotFound = errors.New("debug level not found")
+
+// apexLogFields are the fields of ApexLog selected by ListApexLogs.
+const apexLogFields = "Id, LogUserId, Operation, Request, Status, LogLength, DurationMilliseconds, StartTime"
+
+// ApexLog describes a debug log captured for a user. StartTime can be parsed with ParseDateTime.
+type ApexLog struct {
+	ID                   string `json:"Id"`
+	LogUserID            string `json:"LogUserId"`
+	Operation            string `json:"Operation"`
+	Request              string `json:"Request"`
+	Status               string `json:"Status"`
+	LogLength            int    `json:"LogLength"`
+	DurationMilliseconds int    `json:"DurationMilliseconds"`
+	StartTime            string `json:"StartTime"`
+}
+
+// CreateTraceFlag starts capturing debug logs for the user userID, the logged in user if empty, with the debug level of
+// the given developer name, e.g. "SFDC_DevConsole", for duration (at most 24 hours). The ID of the TraceFlag is
+// returned; ErrDebugLevelNotFound is returned if the debug level does not exist.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_traceflag.htm
+func (client *Client) CreateTraceFlag(userID, debugLevel string, duration time.Duration) (string, error) {
+	result, err := client.toolingQuery("SELECT Id FROM DebugLevel WHERE DeveloperName = " + QuoteSOQL(debugLevel))
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", errors.Wrap(ErrDebugLevelNotFound, debugLevel)
+	}
+	if userID == "" {
+		userID = client.user.id
+	}
+
+	start := time.Now().UTC()
+	reqData, err := client.marshalJSON(map[string]interface{}{
+		"TracedEntityId": userID,
+		"DebugLevelId":   result.Records[0].ID(),
+		"LogType":        "USER_DEBUG",
+		"StartDate":      start.Format(time.RFC3339),
+		"ExpirationDate": start.Add(duration).Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", err
+	}
+	data, err := client.httpRequest(http.MethodPost, client.makeURL("tooling/sobjects/TraceFlag/"), bytes.NewReader(reqData))
+	if err != nil {
+		return "", err
+	}
+
+	obj := &SObject{}
+	err = obj.setIDFromResponseData(data)
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
+
+// ListApexLogs lists the debug logs of the user userID, all users if empty, started at or after since (if not zero),
+// oldest first.
+func (client *Client) ListApexLogs(userID string, since time.Time) ([]ApexLog, error) {
+	var conditions []string
+	if userID != "" {
+		conditions = append(conditions, "LogUserId = "+QuoteSOQL(userID))
+	}
+	if !since.IsZero() {
+		conditions = append(conditions, "StartTime >= "+since.UTC().Format(time.RFC3339))
+	}
+	soql := "SELECT " + apexLogFields + " FROM ApexLog"
+	if len(conditions) > 0 {
+		soql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	soql += " ORDER BY StartTime"
+
+	var logs []ApexLog
+	err := client.toolingQueryPages(soql, func(records []byte) error {
+		var page []ApexLog
+		err := client.unmarshalJSON(records, &page)
+		logs = append(logs, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ApexLogBody streams the content of the debug log logID. The caller must close the returned reader.
+func (client *Client) ApexLogBody(logID string) (io.ReadCloser, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	req, err := http.NewRequest(http.MethodGet, client.makeURL("tooling/sobjects/ApexLog/"+url.PathEscape(logID)+"/Body"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "Bearer "+client.sessionID)
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return nil, client.withRequestContext(ParseSalesforceError(resp.StatusCode, buf.Bytes()), resp.Header)
+	}
+	return resp.Body, nil
+}
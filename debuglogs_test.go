@@ -0,0 +1,89 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_CreateTraceFlag(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tooling/query"):
+			if r.URL.Query().Get("q") == "SELECT Id FROM DebugLevel WHERE DeveloperName = 'SFDC_DevConsole'" {
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "7dlA"}]}`))
+				return
+			}
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/tooling/sobjects/TraceFlag/"):
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			start, _ := time.Parse(time.RFC3339, body["StartDate"])
+			expiration, _ := time.Parse(time.RFC3339, body["ExpirationDate"])
+			if body["TracedEntityId"] != "005A" || body["DebugLevelId"] != "7dlA" || body["LogType"] != "USER_DEBUG" ||
+				expiration.Sub(start) != time.Hour {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "7tfA", "success": true, "errors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	id, err := client.CreateTraceFlag("005A", "SFDC_DevConsole", time.Hour)
+	if err != nil || id != "7tfA" {
+		t.Errorf("unexpected result %s, %v", id, err)
+	}
+	if _, err = client.CreateTraceFlag("005A", "Missing", time.Hour); errors.Cause(err) != ErrDebugLevelNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestClient_ApexLogs(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tooling/query"):
+			expected := "SELECT " + apexLogFields + " FROM ApexLog WHERE LogUserId = '005A' AND " +
+				"StartTime >= 2022-05-01T10:00:00Z ORDER BY StartTime"
+			if q := r.URL.Query().Get("q"); q != expected {
+				t.Errorf("unexpected query %s", q)
+			}
+			w.Write([]byte(`{"done": false, "nextRecordsUrl": "/services/data/v54.0/tooling/query/01g-1",
+				"records": [{"Id": "07LA", "Operation": "/apex/Hello", "LogLength": 120}]}`))
+		case strings.HasSuffix(r.URL.Path, "/tooling/query/01g-1"):
+			w.Write([]byte(`{"done": true, "records": [{"Id": "07LB", "Status": "Success"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/tooling/sobjects/ApexLog/07LA/Body"):
+			w.Write([]byte("54.0 APEX_CODE,DEBUG"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`[{"message": "not found", "errorCode": "NOT_FOUND"}]`))
+		}
+	})
+
+	logs, err := client.ListApexLogs("005A", time.Date(2022, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 7200)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].Operation != "/apex/Hello" || logs[0].LogLength != 120 || logs[1].ID != "07LB" {
+		t.Errorf("unexpected logs %+v", logs)
+	}
+
+	body, err := client.ApexLogBody("07LA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if data, _ := ioutil.ReadAll(body); string(data) != "54.0 APEX_CODE,DEBUG" {
+		t.Errorf("unexpected body %s", data)
+	}
+
+	if _, err = client.ApexLogBody("07LZ"); err == nil {
+		t.Error("expected an error for a missing log")
+	}
+}
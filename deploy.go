@@ -0,0 +1,93 @@
+package simpleforce
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DeployStaticResource creates the static resource name, or replaces the content of the existing one, through the
+// Tooling API. content is streamed base64 encoded. cacheControl is "Public" or "Private", the latter being the
+// default if empty. The ID of the static resource is returned.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_staticresource.htm
+func (client *Client) DeployStaticResource(name, contentType, cacheControl string, content io.Reader) (string, error) {
+	if cacheControl == "" {
+		cacheControl = "Private"
+	}
+	fields := map[string]interface{}{"ContentType": contentType, "CacheControl": cacheControl}
+	return client.deployToolingRecord("StaticResource", name, fields, "Body", content)
+}
+
+// DeployApexPage creates the Visualforce page name with markup, or updates the markup of the existing one, through the
+// Tooling API. The ID of the page is returned.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_apexpage.htm
+func (client *Client) DeployApexPage(name, markup string) (string, error) {
+	return client.deployMarkup("ApexPage", name, markup)
+}
+
+// DeployApexComponent creates the Visualforce component name with markup, or updates the markup of the existing one,
+// through the Tooling API. The ID of the component is returned.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_tooling.meta/api_tooling/tooling_api_objects_apexcomponent.htm
+func (client *Client) DeployApexComponent(name, markup string) (string, error) {
+	return client.deployMarkup("ApexComponent", name, markup)
+}
+
+// deployMarkup deploys a Visualforce page or component.
+func (client *Client) deployMarkup(objectType, name, markup string) (string, error) {
+	fields := map[string]interface{}{"Markup": markup, "MasterLabel": name}
+	if apiVersion, err := strconv.ParseFloat(client.apiVersion, 64); err == nil {
+		fields["ApiVersion"] = apiVersion
+	}
+	return client.deployToolingRecord(objectType, name, fields, "", nil)
+}
+
+// deployToolingRecord creates the Tooling API record of objectType with the given Name and fields, or updates the
+// existing one. If blobField is not empty, content is streamed into it.
+func (client *Client) deployToolingRecord(
+	objectType, name string,
+	fields map[string]interface{},
+	blobField string,
+	content io.Reader,
+) (string, error) {
+	result, err := client.toolingQuery("SELECT Id FROM " + objectType + " WHERE Name = " + QuoteSOQL(name))
+	if err != nil {
+		return "", err
+	}
+
+	method, apiPath, id := http.MethodPost, "tooling/sobjects/"+objectType+"/", ""
+	if len(result.Records) > 0 {
+		id = result.Records[0].ID()
+		method, apiPath = http.MethodPatch, "tooling/sobjects/"+objectType+"/"+url.PathEscape(id)
+	} else {
+		fields["Name"] = name
+	}
+
+	u := client.makeURL(apiPath)
+	var data []byte
+	if blobField != "" {
+		data, err = client.sendWithBlob(method, u, fields, blobField, content)
+	} else {
+		var reqData []byte
+		reqData, err = client.marshalJSON(fields)
+		if err != nil {
+			return "", err
+		}
+		data, err = client.httpRequest(method, u, bytes.NewReader(reqData))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Updates respond with 204 and no content.
+	if id != "" {
+		return id, nil
+	}
+	obj := &SObject{}
+	err = obj.setIDFromResponseData(data)
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
@@ -0,0 +1,60 @@
+package simpleforce
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_DeployStaticResource(t *testing.T) {
+	var requests []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tooling/query"):
+			if r.URL.Query().Get("q") == "SELECT Id FROM StaticResource WHERE Name = 'app_js'" {
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "StaticResource"}, "Id": "081A"}]}`))
+				return
+			}
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		case r.Method == http.MethodPatch:
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["Body"] != base64.StdEncoding.EncodeToString([]byte("alert(1)")) || body["CacheControl"] != "Public" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["Name"] != "Hello" || body["Markup"] != "<apex:page/>" || body["ApiVersion"] != 54.0 {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "066A", "success": true, "errors": []}`))
+		}
+	})
+
+	id, err := client.DeployStaticResource("app_js", "application/javascript", "Public", strings.NewReader("alert(1)"))
+	if err != nil || id != "081A" {
+		t.Errorf("unexpected result %s, %v", id, err)
+	}
+	id, err = client.DeployApexPage("Hello", "<apex:page/>")
+	if err != nil || id != "066A" {
+		t.Errorf("unexpected result %s, %v", id, err)
+	}
+
+	expected := "GET /services/data/v54.0/tooling/query,PATCH /services/data/v54.0/tooling/sobjects/StaticResource/081A," +
+		"GET /services/data/v54.0/tooling/query,POST /services/data/v54.0/tooling/sobjects/ApexPage/"
+	if strings.Join(requests, ",") != expected {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+func TestQuoteSOQL(t *testing.T) {
+	if quoted := QuoteSOQL(`O'Brien \ Co`); quoted != `'O\'Brien \\ Co'` {
+		t.Errorf("unexpected literal %s", quoted)
+	}
+}
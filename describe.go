@@ -0,0 +1,280 @@
+package simpleforce
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxConcurrentDescribes limits the number of describe requests DescribeSObjects sends at once.
+const maxConcurrentDescribes = 8
+
+// GlobalDescribe is the typed result of the describe global resource, listing the objects available in the org.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_describeGlobal.htm
+type GlobalDescribe struct {
+	Encoding     string        `json:"encoding"`
+	MaxBatchSize int           `json:"maxBatchSize"`
+	SObjects     []SObjectInfo `json:"sobjects"`
+}
+
+// SObjectInfo describes a single object of the describe global result.
+type SObjectInfo struct {
+	Name          string            `json:"name"`
+	Label         string            `json:"label"`
+	LabelPlural   string            `json:"labelPlural"`
+	KeyPrefix     string            `json:"keyPrefix"`
+	Custom        bool              `json:"custom"`
+	CustomSetting bool              `json:"customSetting"`
+	Createable    bool              `json:"createable"`
+	Updateable    bool              `json:"updateable"`
+	Deletable     bool              `json:"deletable"`
+	Queryable     bool              `json:"queryable"`
+	Searchable    bool              `json:"searchable"`
+	Retrieveable  bool              `json:"retrieveable"`
+	Triggerable   bool              `json:"triggerable"`
+	Layoutable    bool              `json:"layoutable"`
+	URLs          map[string]string `json:"urls"`
+}
+
+// ListSObjects lists the objects available in the org with their typed metadata. Unlike DescribeGlobal, errors
+// returned by salesforce are reported as SalesforceError.
+func (client *Client) ListSObjects() (*GlobalDescribe, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("sobjects"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GlobalDescribe
+	err = client.unmarshalJSON(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Filter returns the objects for which keep returns true.
+func (result *GlobalDescribe) Filter(keep func(SObjectInfo) bool) []SObjectInfo {
+	var filtered []SObjectInfo
+	for _, info := range result.SObjects {
+		if keep(info) {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
+
+// OnlyCustom returns the custom objects, excluding custom settings.
+func (result *GlobalDescribe) OnlyCustom() []SObjectInfo {
+	return result.Filter(func(info SObjectInfo) bool {
+		return info.Custom && !info.CustomSetting
+	})
+}
+
+// OnlyQueryable returns the objects which can be queried with SOQL.
+func (result *GlobalDescribe) OnlyQueryable() []SObjectInfo {
+	return result.Filter(func(info SObjectInfo) bool {
+		return info.Queryable
+	})
+}
+
+// ByName returns the object with the given API name, compared case-insensitively, or nil if there is none.
+func (result *GlobalDescribe) ByName(name string) *SObjectInfo {
+	for idx := range result.SObjects {
+		if strings.EqualFold(result.SObjects[idx].Name, name) {
+			return &result.SObjects[idx]
+		}
+	}
+	return nil
+}
+
+// ByKeyPrefix resolves the object type of a record ID (15 or 18 characters) or of a bare 3-character key prefix. nil
+// is returned if no object has the prefix. Some prefixes are shared by several objects, e.g. feeds, in which case the
+// first one listed is returned.
+func (result *GlobalDescribe) ByKeyPrefix(id string) *SObjectInfo {
+	if len(id) < 3 {
+		return nil
+	}
+	prefix := id[:3]
+	for idx := range result.SObjects {
+		if result.SObjects[idx].KeyPrefix == prefix {
+			return &result.SObjects[idx]
+		}
+	}
+	return nil
+}
+
+// describeCall is a describe request of a single object, shared by all callers asking for the object while the request
+// is in flight, and kept as the cached result once it succeeded.
+type describeCall struct {
+	done chan struct{}
+	meta *SObjectMeta
+	err  error
+}
+
+// DescribeSObjects describes the objects names concurrently and returns their metadata by name. Results are cached on
+// the client, and concurrent requests for the same object, from this or other calls, are deduplicated. If any describe
+// fails, the metadata of the other objects is returned along with the first error; failures are not cached.
+func (client *Client) DescribeSObjects(names ...string) (map[string]*SObjectMeta, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	calls := make(map[string]*describeCall, len(names))
+	sem := make(chan struct{}, maxConcurrentDescribes)
+	for _, name := range names {
+		if _, ok := calls[name]; ok {
+			continue
+		}
+		call, leader := client.describeCall(name)
+		calls[name] = call
+		if !leader {
+			continue
+		}
+
+		// Do not use makeURL here as DescribeSObjects may be called from several goroutines.
+		u := fmt.Sprintf("%s/services/data/v%s/sobjects/%s/describe",
+			client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"), url.PathEscape(name))
+		go func(name, u string, call *describeCall) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			client.finishDescribe(name, call, u)
+		}(name, u, call)
+	}
+
+	result := make(map[string]*SObjectMeta, len(calls))
+	var firstErr error
+	for _, name := range names {
+		call := calls[name]
+		<-call.done
+		if call.err != nil {
+			if firstErr == nil {
+				firstErr = call.err
+			}
+			continue
+		}
+		result[name] = call.meta
+	}
+	return result, firstErr
+}
+
+// ClearDescribeCache drops the metadata cached by DescribeSObjects, e.g. after fields have been deployed.
+func (client *Client) ClearDescribeCache() {
+	client.dropDescribes(nil)
+}
+
+// dropDescribes removes the cached describes of names, or of all objects described by the client if names is nil,
+// also from the describe cache if set, whether or not the client described them itself. Requests in flight are kept so
+// their callers are not duplicated. The user of the describe cache keys is resolved before, and the entries are
+// deleted after, holding describeMu, as both may take network round trips.
+func (client *Client) dropDescribes(names []string) {
+	dc, userID := client.describeCache, ""
+	if dc != nil {
+		var err error
+		if userID, err = client.cacheUserID(); err != nil {
+			log.Println(logPrefix, "describe cache not cleared,", err)
+			dc = nil
+		}
+	}
+
+	client.describeMu.Lock()
+	if names == nil {
+		for name := range client.describeCalls {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		call, ok := client.describeCalls[name]
+		if !ok {
+			continue
+		}
+		select {
+		case <-call.done:
+			delete(client.describeCalls, name)
+		default:
+		}
+	}
+	client.describeMu.Unlock()
+
+	if dc != nil {
+		for _, name := range names {
+			dc.delete(client.userCacheKey("describe", userID, name))
+		}
+	}
+}
+
+// cachedDescribes returns the names of the objects whose describe is cached.
+func (client *Client) cachedDescribes() []string {
+	client.describeMu.Lock()
+	defer client.describeMu.Unlock()
+	var names []string
+	for name, call := range client.describeCalls {
+		select {
+		case <-call.done:
+			names = append(names, name)
+		default:
+		}
+	}
+	return names
+}
+
+// describeCall returns the cached or in-flight describe of name, or registers a new one in which case leader is true
+// and the caller must complete it with finishDescribe.
+func (client *Client) describeCall(name string) (call *describeCall, leader bool) {
+	client.describeMu.Lock()
+	defer client.describeMu.Unlock()
+	if call, ok := client.describeCalls[name]; ok {
+		return call, false
+	}
+	if client.describeCalls == nil {
+		client.describeCalls = make(map[string]*describeCall)
+	}
+	call = &describeCall{done: make(chan struct{})}
+	client.describeCalls[name] = call
+	return call, true
+}
+
+// finishDescribe sends the describe request of call, unless the describe cache holds the result, and releases its
+// waiters. Failed calls are removed from the cache.
+func (client *Client) finishDescribe(name string, call *describeCall, u string) {
+	defer close(call.done)
+
+	dc, key := client.describeCache, ""
+	if dc != nil {
+		var err error
+		if key, err = client.cacheKey("describe", name); err != nil {
+			log.Println(logPrefix, "describe cache skipped,", err)
+			dc = nil
+		}
+	}
+	if dc != nil {
+		if data, ok := dc.get(key); ok {
+			var meta SObjectMeta
+			if client.unmarshalJSON(data, &meta) == nil {
+				call.meta = &meta
+				return
+			}
+		}
+	}
+
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err == nil {
+		var meta SObjectMeta
+		err = client.unmarshalJSON(data, &meta)
+		call.meta = &meta
+	}
+	if err == nil && dc != nil {
+		dc.set(key, data)
+	}
+	if err != nil {
+		call.meta, call.err = nil, err
+		client.describeMu.Lock()
+		delete(client.describeCalls, name)
+		client.describeMu.Unlock()
+	}
+}
@@ -0,0 +1,104 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ListSObjects(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v"+DefaultAPIVersion+"/sobjects" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"encoding": "UTF-8", "maxBatchSize": 200, "sobjects": [
+			{"name": "Account", "label": "Account", "keyPrefix": "001", "queryable": true, "triggerable": true,
+				"urls": {"sobject": "/services/data/v54.0/sobjects/Account"}},
+			{"name": "Invoice__c", "label": "Invoice", "keyPrefix": "a01", "custom": true, "queryable": true},
+			{"name": "Settings__c", "label": "Settings", "keyPrefix": "a02", "custom": true, "customSetting": true},
+			{"name": "AccountFeed", "label": "Account Feed", "keyPrefix": null}
+		]}`))
+	})
+
+	result, err := client.ListSObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MaxBatchSize != 200 || len(result.SObjects) != 4 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if custom := result.OnlyCustom(); len(custom) != 1 || custom[0].Name != "Invoice__c" {
+		t.Errorf("unexpected custom objects %v", custom)
+	}
+	if len(result.OnlyQueryable()) != 2 {
+		t.Fail()
+	}
+	if info := result.ByKeyPrefix("001000000000001AAA"); info == nil || info.Name != "Account" ||
+		info.URLs["sobject"] != "/services/data/v54.0/sobjects/Account" || !info.Triggerable {
+		t.Errorf("unexpected object %v", info)
+	}
+	if result.ByKeyPrefix("zz") != nil || result.ByKeyPrefix("999000000000001") != nil {
+		t.Fail()
+	}
+	if info := result.ByName("invoice__C"); info == nil || info.KeyPrefix != "a01" {
+		t.Errorf("unexpected object %v", info)
+	}
+}
+
+func TestClient_DescribeSObjectsCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	release := make(chan struct{})
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Split(r.URL.Path, "/")[5]
+		mu.Lock()
+		requests[name]++
+		mu.Unlock()
+		<-release
+		if name == "Missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`[{"message": "The requested resource does not exist", "errorCode": "NOT_FOUND"}]`))
+			return
+		}
+		w.Write([]byte(`{"name": "` + name + `"}`))
+	})
+
+	var wg sync.WaitGroup
+	results := make([]map[string]*SObjectMeta, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = client.DescribeSObjects("Account", "Contact", "Account", "Missing")
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] == nil || len(results[i]) != 2 || (*results[i]["Contact"])["name"] != "Contact" {
+			t.Errorf("unexpected result %v, %v", results[i], errs[i])
+		}
+	}
+	if requests["Account"] != 1 || requests["Contact"] != 1 {
+		t.Errorf("describes not deduplicated: %v", requests)
+	}
+
+	// Successful results are cached, failures are retried.
+	missing := requests["Missing"]
+	if _, err := client.DescribeSObjects("Account", "Missing"); err == nil {
+		t.Fail()
+	}
+	if requests["Account"] != 1 || requests["Missing"] != missing+1 {
+		t.Errorf("unexpected requests %v", requests)
+	}
+
+	client.ClearDescribeCache()
+	if _, err := client.DescribeSObjects("Account"); err != nil || requests["Account"] != 2 {
+		t.Errorf("cache not cleared: %v, %v", requests, err)
+	}
+}
@@ -0,0 +1,227 @@
+package simpleforce
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	partnerNS       = "urn:partner.soap.sforce.com"
+	partnerObjectNS = "urn:sobject.partner.soap.sforce.com"
+
+	partnerEnvelope = `<?xml version="1.0" encoding="utf-8"?>
+<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="` + xmlSchemaInstanceNS + `">
+    <env:Header>
+        <SessionHeader xmlns="` + partnerNS + `">
+            <sessionId>%s</sessionId>
+        </SessionHeader>
+    </env:Header>
+    <env:Body>%s</env:Body>
+</env:Envelope>`
+)
+
+// DuplicateResult is the outcome of an active duplicate rule for a record checked with FindDuplicates. AllowSave tells
+// whether the rule would let the record be saved anyway, ErrorMessage is the message shown to users if it would not.
+type DuplicateResult struct {
+	DuplicateRule string        `xml:"duplicateRule"`
+	EntityType    string        `xml:"duplicateRuleEntityType"`
+	ErrorMessage  string        `xml:"errorMessage"`
+	AllowSave     bool          `xml:"allowSave"`
+	MatchResults  []MatchResult `xml:"matchResults"`
+}
+
+// MatchResult lists the records found by a matching rule of a duplicate rule.
+type MatchResult struct {
+	Rule         string        `xml:"rule"`
+	EntityType   string        `xml:"entityType"`
+	MatchEngine  string        `xml:"matchEngine"`
+	Size         int           `xml:"size"`
+	Success      bool          `xml:"success"`
+	Errors       []SaveError   `xml:"errors"`
+	MatchRecords []MatchRecord `xml:"matchRecords"`
+}
+
+// MatchRecord is an existing record matching the checked record. Record holds the fields returned by the matching
+// rule, including the Id. FieldDiffs compares the matched fields, see FieldDiff.
+type MatchRecord struct {
+	Record          *SObject
+	MatchConfidence float64
+	FieldDiffs      []FieldDiff
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (record *MatchRecord) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Record          partnerRecord `xml:"record"`
+		MatchConfidence float64       `xml:"matchConfidence"`
+		FieldDiffs      []FieldDiff   `xml:"fieldDiffs"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	record.Record = raw.Record.sobject()
+	record.MatchConfidence = raw.MatchConfidence
+	record.FieldDiffs = raw.FieldDiffs
+	return nil
+}
+
+// FieldDiff tells how a field of a matched record compares to the checked record: "SAME", "DIFFERENT" or "NULL".
+type FieldDiff struct {
+	Name       string `xml:"name"`
+	Difference string `xml:"difference"`
+}
+
+// Matches returns the records found by all matching rules of results, each record once, in the order found.
+func Matches(results []DuplicateResult) []*SObject {
+	var records []*SObject
+	seen := map[string]bool{}
+	for _, result := range results {
+		for _, matchResult := range result.MatchResults {
+			for _, matchRecord := range matchResult.MatchRecords {
+				if id := matchRecord.Record.ID(); id == "" || !seen[id] {
+					seen[id] = true
+					records = append(records, matchRecord.Record)
+				}
+			}
+		}
+	}
+	return records
+}
+
+// FindDuplicates runs the active duplicate rules of the object of obj against obj without saving it, so callers can
+// look for existing matches of a prospective record and update one of them instead of inserting a duplicate. The REST
+// API has no resource for this, so the findDuplicates call of the SOAP API is used. Only fields with string, number,
+// boolean or time values are sent. An empty result means no duplicate rule is active for the object or none matched.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_findduplicates.htm
+func (client *Client) FindDuplicates(obj *SObject) ([]DuplicateResult, error) {
+	if obj.Type() == "" {
+		return nil, fmt.Errorf("record without type")
+	}
+
+	request := struct {
+		XMLName  xml.Name      `xml:"urn:partner.soap.sforce.com findDuplicates"`
+		SObjects partnerObject `xml:"sObjects"`
+	}{SObjects: partnerObject{obj}}
+	var response struct {
+		Results []struct {
+			DuplicateResults []DuplicateResult `xml:"duplicateResults"`
+			Success          bool              `xml:"success"`
+			Errors           []SaveError       `xml:"errors"`
+		} `xml:"Body>findDuplicatesResponse>result"`
+	}
+	u := fmt.Sprintf("%s/services/Soap/u/%s", client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"))
+	err := client.soapCall(u, partnerEnvelope, &request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(response.Results))
+	}
+	result := response.Results[0]
+	if !result.Success && len(result.Errors) > 0 {
+		return nil, result.Errors[0]
+	}
+
+	for i := range result.DuplicateResults {
+		for j := range result.DuplicateResults[i].MatchResults {
+			records := result.DuplicateResults[i].MatchResults[j].MatchRecords
+			for k := range records {
+				records[k].Record.setClient(client)
+			}
+		}
+	}
+	return result.DuplicateResults, nil
+}
+
+// partnerObject marshals an SObject as an sObject of the partner SOAP API.
+type partnerObject struct {
+	obj *SObject
+}
+
+// MarshalXML implements xml.Marshaler.
+func (object partnerObject) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	fields := object.obj.makeCopy()
+	if id := object.obj.ID(); id != "" {
+		fields[sobjectIDKey] = id
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	start.Name = xml.Name{Space: partnerNS, Local: start.Name.Local}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	typeName := xml.Name{Space: partnerObjectNS, Local: "type"}
+	if err := e.EncodeElement(object.obj.Type(), xml.StartElement{Name: typeName}); err != nil {
+		return err
+	}
+	for _, name := range names {
+		var value string
+		switch v := fields[name].(type) {
+		case string:
+			value = v
+		case bool, int, int32, int64, float32, float64:
+			value = fmt.Sprint(v)
+		case time.Time:
+			value = v.Format(time.RFC3339)
+		default:
+			// Null values and parent records cannot be matched on.
+			continue
+		}
+		fieldName := xml.Name{Space: partnerObjectNS, Local: name}
+		if err := e.EncodeElement(value, xml.StartElement{Name: fieldName}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// partnerRecord is an sObject returned by the partner SOAP API, with its fields as strings. Null fields are nil.
+type partnerRecord map[string]interface{}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (record *partnerRecord) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*record = partnerRecord{}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value struct {
+				Nil  string `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
+				Text string `xml:",chardata"`
+			}
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			if value.Nil == "true" {
+				(*record)[t.Name.Local] = nil
+			} else {
+				(*record)[t.Name.Local] = value.Text
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// sobject converts the record into an SObject.
+func (record partnerRecord) sobject() *SObject {
+	typeName, _ := record["type"].(string)
+	obj := &SObject{}
+	obj.setType(typeName)
+	for name, value := range record {
+		if name != "type" {
+			(*obj)[name] = value
+		}
+	}
+	return obj
+}
@@ -0,0 +1,91 @@
+package simpleforce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_FindDuplicates(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/services/Soap/u/54.0" || !strings.Contains(string(body), "<sessionId>__SESSION__</sessionId>") {
+			t.Errorf("unexpected request %s %s", r.URL.Path, body)
+		}
+		for _, part := range []string{`<findDuplicates xmlns="urn:partner.soap.sforce.com">`,
+			`<type xmlns="urn:sobject.partner.soap.sforce.com">Lead</type>`,
+			`<Email xmlns="urn:sobject.partner.soap.sforce.com">jane@acme.com</Email>`,
+			`<NumberOfEmployees xmlns="urn:sobject.partner.soap.sforce.com">12</NumberOfEmployees>`} {
+			if !strings.Contains(string(body), part) {
+				t.Errorf("expected %s in %s", part, body)
+			}
+		}
+		if strings.Contains(string(body), "Phone") {
+			t.Errorf("expected null fields to be skipped, got %s", body)
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com"
+	xmlns:sf="urn:sobject.partner.soap.sforce.com" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<soapenv:Body><findDuplicatesResponse><result>
+	<duplicateResults>
+		<allowSave>false</allowSave>
+		<duplicateRule>Standard_Lead_Duplicate_Rule</duplicateRule>
+		<duplicateRuleEntityType>Lead</duplicateRuleEntityType>
+		<errorMessage>Use one of these records?</errorMessage>
+		<matchResults>
+			<entityType>Lead</entityType>
+			<matchEngine>FuzzyMatchEngine</matchEngine>
+			<matchRecords>
+				<fieldDiffs><difference>SAME</difference><name>Email</name></fieldDiffs>
+				<fieldDiffs><difference>DIFFERENT</difference><name>Company</name></fieldDiffs>
+				<matchConfidence>87.5</matchConfidence>
+				<record xsi:type="sf:sObject">
+					<sf:type>Lead</sf:type><sf:Id>00QA</sf:Id><sf:Id>00QA</sf:Id>
+					<sf:Email>jane@acme.com</sf:Email><sf:Company xsi:nil="true"/>
+				</record>
+			</matchRecords>
+			<rule>Standard_Lead_Match_Rule_v1_0</rule>
+			<size>1</size>
+			<success>true</success>
+		</matchResults>
+	</duplicateResults>
+	<success>true</success>
+</result></findDuplicatesResponse></soapenv:Body></soapenv:Envelope>`))
+	})
+
+	lead := client.SObject("Lead").Set("Email", "jane@acme.com").Set("NumberOfEmployees", 12).Set("Phone", nil)
+	results, err := client.FindDuplicates(lead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].AllowSave || results[0].DuplicateRule != "Standard_Lead_Duplicate_Rule" ||
+		len(results[0].MatchResults) != 1 || results[0].MatchResults[0].Size != 1 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	match := results[0].MatchResults[0].MatchRecords[0]
+	if match.MatchConfidence != 87.5 || len(match.FieldDiffs) != 2 || match.FieldDiffs[1].Difference != "DIFFERENT" {
+		t.Errorf("unexpected match %+v", match)
+	}
+	if match.Record.Type() != "Lead" || match.Record.ID() != "00QA" || match.Record.StringField("Email") != "jane@acme.com" ||
+		match.Record.InterfaceField("Company") != nil || match.Record.client() != client {
+		t.Errorf("unexpected record %v", *match.Record)
+	}
+
+	if matches := Matches(results); len(matches) != 1 || matches[0].ID() != "00QA" {
+		t.Errorf("unexpected matches %v", matches)
+	}
+}
+
+func TestClient_FindDuplicatesFault(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>
+			<soapenv:Fault><faultcode>sf:INVALID_TYPE</faultcode><faultstring>INVALID_TYPE: bad type</faultstring></soapenv:Fault>
+			</soapenv:Body></soapenv:Envelope>`))
+	})
+	_, err := client.FindDuplicates(client.SObject("Nope__c").Set("Name", "x"))
+	if sfErr, ok := err.(SalesforceError); !ok || sfErr.ErrorCode != "INVALID_TYPE" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package errcode defines constants for common status and error codes returned by salesforce, as found in the
+// ErrorCode of simpleforce.SalesforceError and the StatusCode of simpleforce.SaveError:
+//
+//	if sfErr, ok := err.(simpleforce.SalesforceError); ok && sfErr.ErrorCode == errcode.MalformedQuery {
+//		...
+//	}
+//
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_concepts_core_data_objects.htm#statuscode
+package errcode
+
+// Request and query errors.
+const (
+	MalformedQuery         = "MALFORMED_QUERY"
+	MalformedID            = "MALFORMED_ID"
+	InvalidField           = "INVALID_FIELD"
+	InvalidType            = "INVALID_TYPE"
+	InvalidQueryLocator    = "INVALID_QUERY_LOCATOR"
+	QueryTimeout           = "QUERY_TIMEOUT"
+	InvalidQueryFilter     = "INVALID_QUERY_FILTER_OPERATOR"
+	JSONParserError        = "JSON_PARSER_ERROR"
+	NotFound               = "NOT_FOUND"
+	MethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	APIDisabledForOrg      = "API_DISABLED_FOR_ORG"
+	APICurrentlyDisabled   = "API_CURRENTLY_DISABLED"
+	RequestRunningTooLong  = "REQUEST_RUNNING_TOO_LONG"
+	ServerUnavailable      = "SERVER_UNAVAILABLE"
+	InvalidReplicationDate = "INVALID_REPLICATION_DATE"
+)
+
+// Authentication and limit errors.
+const (
+	InvalidSessionID     = "INVALID_SESSION_ID"
+	InvalidLogin         = "INVALID_LOGIN"
+	PasswordLockout      = "PASSWORD_LOCKOUT"
+	OrgLocked            = "ORG_LOCKED"
+	InsufficientAccess   = "INSUFFICIENT_ACCESS"
+	RequestLimitExceeded = "REQUEST_LIMIT_EXCEEDED"
+	TooManyApexRequests  = "TOO_MANY_APEX_REQUESTS"
+)
+
+// DML status codes reported per record.
+const (
+	CannotInsertUpdateActivateEntity   = "CANNOT_INSERT_UPDATE_ACTIVATE_ENTITY"
+	DuplicateValue                     = "DUPLICATE_VALUE"
+	DuplicatesDetected                 = "DUPLICATES_DETECTED"
+	EntityIsDeleted                    = "ENTITY_IS_DELETED"
+	FieldCustomValidationException     = "FIELD_CUSTOM_VALIDATION_EXCEPTION"
+	FieldIntegrityException            = "FIELD_INTEGRITY_EXCEPTION"
+	InsufficientAccessOnCrossReference = "INSUFFICIENT_ACCESS_ON_CROSS_REFERENCE_ENTITY"
+	InvalidCrossReferenceKey           = "INVALID_CROSS_REFERENCE_KEY"
+	InvalidFieldForInsertUpdate        = "INVALID_FIELD_FOR_INSERT_UPDATE"
+	InvalidOrNullForRestrictedPicklist = "INVALID_OR_NULL_FOR_RESTRICTED_PICKLIST"
+	MissingArgument                    = "MISSING_ARGUMENT"
+	RequiredFieldMissing               = "REQUIRED_FIELD_MISSING"
+	StringTooLong                      = "STRING_TOO_LONG"
+	UnableToLockRow                    = "UNABLE_TO_LOCK_ROW"
+	AllOrNoneOperationRolledBack       = "ALL_OR_NONE_OPERATION_ROLLED_BACK"
+)
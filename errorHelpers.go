@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
 )
 
 var (
@@ -14,8 +17,28 @@ var (
 
 	// ErrAuthentication is returned when authentication failed.
 	ErrAuthentication = errors.New("authentication failure")
+
+	// ErrInvalidLogin matches a LoginError caused by a wrong username, password or security token.
+	ErrInvalidLogin = errors.New("invalid login")
+
+	// ErrPasswordLockout matches a LoginError caused by the user being locked out after too many failed logins.
+	ErrPasswordLockout = errors.New("password lockout")
+
+	// ErrOrgLocked matches a LoginError caused by the org being locked.
+	ErrOrgLocked = errors.New("org locked")
+
+	// ErrAPIDisabledForOrg matches a LoginError caused by the API not being enabled for the org or the user.
+	ErrAPIDisabledForOrg = errors.New("API disabled for org")
 )
 
+// loginFaultErrors maps SOAP login exception codes to the errors they match.
+var loginFaultErrors = map[string]error{
+	errcode.InvalidLogin:      ErrInvalidLogin,
+	errcode.PasswordLockout:   ErrPasswordLockout,
+	errcode.OrgLocked:         ErrOrgLocked,
+	errcode.APIDisabledForOrg: ErrAPIDisabledForOrg,
+}
+
 type jsonError []struct {
 	Message   string `json:"message"`
 	ErrorCode string `json:"errorCode"`
@@ -26,15 +49,62 @@ type xmlError struct {
 	ErrorCode string `xml:"Body>Fault>faultcode"`
 }
 
+// SalesforceError is an error response of salesforce. For failed API calls of a client, RequestID, InstanceURL and
+// OrganizationID are filled in where known, so the failure can be correlated with salesforce support cases and event
+// monitoring logs.
 type SalesforceError struct {
-	Message      string
-	HttpCode     int
-	ErrorCode    string
-	ErrorMessage string
+	Message        string
+	HttpCode       int
+	ErrorCode      string
+	ErrorMessage   string
+	RequestID      string
+	InstanceURL    string
+	OrganizationID string
 }
 
 func (err SalesforceError) Error() string {
-	return err.Message
+	var context []string
+	if err.InstanceURL != "" {
+		context = append(context, "instance: "+err.InstanceURL)
+	}
+	if err.OrganizationID != "" {
+		context = append(context, "org: "+err.OrganizationID)
+	}
+	if err.RequestID != "" {
+		context = append(context, "request id: "+err.RequestID)
+	}
+	if len(context) == 0 {
+		return err.Message
+	}
+	return fmt.Sprintf("%s (%s)", err.Message, strings.Join(context, ", "))
+}
+
+// requestIDHeaders are the response headers which may carry the ID salesforce assigned to a request.
+var requestIDHeaders = []string{"X-Request-Id", "X-Sfdc-Request-Id", "Sforce-Request-Id"}
+
+// withRequestContext adds the request ID from the response header and the instance and organization of the client to
+// err if it is a SalesforceError. A rejected session of a different org than the pinned one is returned as an
+// *OrgChangedError.
+func (client *Client) withRequestContext(err error, header http.Header) error {
+	sfErr, ok := err.(SalesforceError)
+	if !ok {
+		return err
+	}
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			sfErr.RequestID = id
+			break
+		}
+	}
+	sfErr.InstanceURL = client.instanceURL
+	if sfErr.InstanceURL == "" {
+		sfErr.InstanceURL = client.baseURL
+	}
+	sfErr.OrganizationID = client.organizationID
+	if orgErr := client.orgChangedError(sfErr); orgErr != nil {
+		return orgErr
+	}
+	return sfErr
 }
 
 //Need to get information out of this package.
@@ -72,3 +142,67 @@ func ParseSalesforceError(statusCode int, responseBody []byte) (err error) {
 		HttpCode: statusCode,
 	}
 }
+
+// LoginError is returned by LoginPassword when salesforce rejects the login with a SOAP fault. Code is the exception
+// code of the fault, e.g. errcode.InvalidLogin. A LoginError matches ErrAuthentication and, depending on the code,
+// ErrInvalidLogin, ErrPasswordLockout, ErrOrgLocked or ErrAPIDisabledForOrg with errors.Is. The fault as parsed by
+// ParseSalesforceError is available through Unwrap.
+type LoginError struct {
+	Code    string
+	Message string
+	Err     SalesforceError
+}
+
+// Error implements the error interface.
+func (err *LoginError) Error() string {
+	return fmt.Sprintf("login failed: %s: %s", err.Code, err.Message)
+}
+
+// Unwrap returns the fault as a SalesforceError.
+func (err *LoginError) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether the login failure matches target.
+func (err *LoginError) Is(target error) bool {
+	return target == ErrAuthentication || (target != nil && loginFaultErrors[err.Code] == target)
+}
+
+// parseLoginFault parses the SOAP fault of a failed login into a *LoginError. Responses which are not SOAP faults are
+// parsed with ParseSalesforceError.
+func parseLoginFault(statusCode int, responseBody []byte) error {
+	var fault struct {
+		FaultCode        string `xml:"Body>Fault>faultcode"`
+		FaultString      string `xml:"Body>Fault>faultstring"`
+		ExceptionCode    string `xml:"Body>Fault>detail>LoginFault>exceptionCode"`
+		ExceptionMessage string `xml:"Body>Fault>detail>LoginFault>exceptionMessage"`
+	}
+	err := xml.Unmarshal(responseBody, &fault)
+	if err != nil || fault.FaultCode == "" {
+		return ParseSalesforceError(statusCode, responseBody)
+	}
+
+	code := fault.ExceptionCode
+	if code == "" {
+		// Strip the namespace prefix, e.g. "sf:INVALID_LOGIN".
+		code = fault.FaultCode[strings.LastIndex(fault.FaultCode, ":")+1:]
+	}
+	message := fault.ExceptionMessage
+	if message == "" {
+		message = strings.TrimPrefix(fault.FaultString, code+": ")
+	}
+
+	return &LoginError{
+		Code:    code,
+		Message: message,
+		Err: SalesforceError{
+			Message: fmt.Sprintf(
+				logPrefix+" Error. http code: %v Error Message:  %v Error Code: %v",
+				statusCode, message, code,
+			),
+			HttpCode:     statusCode,
+			ErrorCode:    code,
+			ErrorMessage: message,
+		},
+	}
+}
@@ -1,6 +1,9 @@
 package simpleforce
 
 import (
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -54,3 +57,65 @@ func TestUnsuccessfulParse(t *testing.T) {
 		t.Errorf("failed to parse unknown error, got %s", err)
 	}
 }
+
+func TestSalesforceError_RequestContext(t *testing.T) {
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "4f1a2b3c")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`[{"message": "No such column 'Foo' on entity 'Account'", "errorCode": "INVALID_FIELD"}]`))
+	})
+	client.organizationID = "00D000000000001AAA"
+
+	_, err := client.Query("SELECT Foo FROM Account")
+	sfErr, ok := err.(SalesforceError)
+	if !ok || sfErr.RequestID != "4f1a2b3c" || sfErr.InstanceURL != server.URL || sfErr.OrganizationID != "00D000000000001AAA" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if !strings.HasSuffix(sfErr.Error(), "(instance: "+server.URL+", org: 00D000000000001AAA, request id: 4f1a2b3c)") {
+		t.Errorf("unexpected message %s", sfErr.Error())
+	}
+}
+
+func TestParseLoginFault(t *testing.T) {
+	response := `<?xml version="1.0" encoding="UTF-8"?>
+		<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:sf="urn:fault.partner.soap.sforce.com">
+			<soapenv:Body>
+				<soapenv:Fault>
+					<faultcode>sf:PASSWORD_LOCKOUT</faultcode>
+					<faultstring>PASSWORD_LOCKOUT: Password locked out</faultstring>
+					<detail>
+						<sf:LoginFault>
+							<sf:exceptionCode>PASSWORD_LOCKOUT</sf:exceptionCode>
+							<sf:exceptionMessage>Password locked out</sf:exceptionMessage>
+						</sf:LoginFault>
+					</detail>
+				</soapenv:Fault>
+			</soapenv:Body>
+		</soapenv:Envelope>`
+
+	err := parseLoginFault(500, []byte(response))
+	loginErr, ok := err.(*LoginError)
+	if !ok || loginErr.Code != "PASSWORD_LOCKOUT" || loginErr.Message != "Password locked out" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !errors.Is(err, ErrPasswordLockout) || !errors.Is(err, ErrAuthentication) || errors.Is(err, ErrInvalidLogin) {
+		t.Error("unexpected error matching")
+	}
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) || sfErr.ErrorCode != "PASSWORD_LOCKOUT" || sfErr.HttpCode != 500 {
+		t.Errorf("unexpected fault %v", sfErr)
+	}
+
+	// Faults without details only carry the prefixed fault code.
+	err = parseLoginFault(500, []byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+		<soapenv:Body><soapenv:Fault><faultcode>sf:API_DISABLED_FOR_ORG</faultcode>
+		<faultstring>API_DISABLED_FOR_ORG: API is not enabled for this Organization or Partner</faultstring>
+		</soapenv:Fault></soapenv:Body></soapenv:Envelope>`))
+	if !errors.Is(err, ErrAPIDisabledForOrg) || err.(*LoginError).Message != "API is not enabled for this Organization or Partner" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, ok := parseLoginFault(503, []byte("unavailable")).(SalesforceError); !ok {
+		t.Error("expected SalesforceError for non-fault response")
+	}
+}
@@ -0,0 +1,176 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EventEnvelope holds the metadata delivered with every platform event or change data capture event.
+type EventEnvelope struct {
+	Channel     string
+	APIName     string
+	ReplayID    int64
+	CreatedDate time.Time
+	CreatedByID string
+}
+
+// Event is a decoded streaming event. Payload is a pointer to a new value of the type registered for the event's API
+// name, or a map[string]interface{} if no type is registered.
+type Event struct {
+	EventEnvelope
+	Payload interface{}
+}
+
+// EventRegistry maps event API names, e.g. "Order_Shipped__e", to the Go struct types their payloads are decoded into.
+// Struct fields are mapped with regular encoding/json tags. An EventRegistry is safe for concurrent use.
+type EventRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// NewEventRegistry creates an empty EventRegistry.
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{types: make(map[string]reflect.Type)}
+}
+
+// Register associates apiName with the type of prototype, which must be a struct or a pointer to a struct.
+func (registry *EventRegistry) Register(apiName string, prototype interface{}) error {
+	typ := reflect.TypeOf(prototype)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("event type for %s must be a struct, got %T", apiName, prototype)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.types[apiName] = typ
+	return nil
+}
+
+// APIName returns the API name registered for the type of payload, or an empty string if there is none.
+func (registry *EventRegistry) APIName(payload interface{}) string {
+	typ := reflect.TypeOf(payload)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for apiName, registered := range registry.types {
+		if registered == typ {
+			return apiName
+		}
+	}
+	return ""
+}
+
+// Decode decodes a streaming message as delivered over CometD, e.g.
+//
+//	{"channel": "/event/Order_Shipped__e", "data": {"event": {"replayId": 1}, "payload": {...}}}
+//
+// into an Event. The API name is taken from the channel.
+func (registry *EventRegistry) Decode(message []byte) (*Event, error) {
+	var raw struct {
+		Channel string `json:"channel"`
+		Data    struct {
+			Event struct {
+				ReplayID int64 `json:"replayId"`
+			} `json:"event"`
+			Payload json.RawMessage `json:"payload"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(message, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw.Data.Payload) == 0 {
+		return nil, errors.New("event message has no payload")
+	}
+
+	var meta struct {
+		CreatedDate string `json:"CreatedDate"`
+		CreatedByID string `json:"CreatedById"`
+	}
+	err = json.Unmarshal(raw.Data.Payload, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		EventEnvelope: EventEnvelope{
+			Channel:     raw.Channel,
+			APIName:     raw.Channel[strings.LastIndex(raw.Channel, "/")+1:],
+			ReplayID:    raw.Data.Event.ReplayID,
+			CreatedByID: meta.CreatedByID,
+		},
+	}
+	if meta.CreatedDate != "" {
+		event.CreatedDate, err = ParseDateTime(meta.CreatedDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	registry.mu.RLock()
+	typ, ok := registry.types[event.APIName]
+	registry.mu.RUnlock()
+	if ok {
+		payload := reflect.New(typ).Interface()
+		err = json.Unmarshal(raw.Data.Payload, payload)
+		event.Payload = payload
+	} else {
+		var payload map[string]interface{}
+		err = json.Unmarshal(raw.Data.Payload, &payload)
+		event.Payload = payload
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Publish publishes payload, a value of a registered struct type, as a platform event using client.
+func (registry *EventRegistry) Publish(client *Client, payload interface{}) (string, error) {
+	apiName := registry.APIName(payload)
+	if apiName == "" {
+		return "", fmt.Errorf("no event registered for %T", payload)
+	}
+	return client.PublishEvent(apiName, payload)
+}
+
+// PublishEvent publishes a platform event of type apiName through the REST API and returns the ID assigned to it.
+// payload is either a struct with encoding/json tags matching the event fields, or a map of field values.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.platform_events.meta/platform_events/platform_events_publish_api.htm
+func (client *Client) PublishEvent(apiName string, payload interface{}) (string, error) {
+	if !client.isLoggedIn() {
+		return "", ErrAuthentication
+	}
+
+	reqData, err := client.marshalJSON(payload)
+	if err != nil {
+		return "", err
+	}
+
+	u := client.makeURL("sobjects/" + apiName + "/")
+	respData, err := client.httpRequest(http.MethodPost, u, bytes.NewReader(reqData))
+	if err != nil {
+		return "", err
+	}
+
+	obj := client.SObject(apiName)
+	err = obj.setIDFromResponseData(respData)
+	if err != nil {
+		return "", err
+	}
+	return obj.ID(), nil
+}
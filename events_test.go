@@ -0,0 +1,60 @@
+package simpleforce
+
+import (
+	"testing"
+	"time"
+)
+
+type orderShipped struct {
+	OrderNumber string  `json:"Order_Number__c"`
+	Weight      float64 `json:"Weight__c"`
+}
+
+func TestEventRegistry_Decode(t *testing.T) {
+	registry := NewEventRegistry()
+	if registry.Register("Order_Shipped__e", orderShipped{}) != nil {
+		t.FailNow()
+	}
+	if registry.Register("Invalid__e", "not a struct") == nil {
+		t.Fail()
+	}
+	if registry.APIName(&orderShipped{}) != "Order_Shipped__e" {
+		t.Fail()
+	}
+
+	message := `{
+		"channel": "/event/Order_Shipped__e",
+		"data": {
+			"schema": "dffQ2QLzDNHqwB8_sHMxdA",
+			"event": {"replayId": 42},
+			"payload": {
+				"CreatedDate": "2022-05-01T10:00:00.000Z",
+				"CreatedById": "005000000000001",
+				"Order_Number__c": "ORD-1",
+				"Weight__c": 1.5
+			}
+		}
+	}`
+	event, err := registry.Decode([]byte(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.APIName != "Order_Shipped__e" || event.ReplayID != 42 || event.CreatedByID != "005000000000001" {
+		t.Errorf("unexpected envelope %+v", event.EventEnvelope)
+	}
+	if !event.CreatedDate.Equal(time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created date %v", event.CreatedDate)
+	}
+	payload, ok := event.Payload.(*orderShipped)
+	if !ok || payload.OrderNumber != "ORD-1" || payload.Weight != 1.5 {
+		t.Errorf("unexpected payload %#v", event.Payload)
+	}
+
+	event, err = registry.Decode([]byte(`{"channel": "/data/AccountChangeEvent", "data": {"payload": {"Name": "Acme"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields, ok := event.Payload.(map[string]interface{}); !ok || fields["Name"] != "Acme" {
+		t.Errorf("unexpected payload %#v", event.Payload)
+	}
+}
@@ -0,0 +1,38 @@
+package simpleforce
+
+import "strings"
+
+const (
+	// externalObjectIDKey and externalObjectDisplayURLKey are the standard fields of external objects referencing the
+	// row in the external system.
+	externalObjectIDKey         = "ExternalId"
+	externalObjectDisplayURLKey = "DisplayUrl"
+)
+
+// IsExternalObject reports whether objectType is an external object of Salesforce Connect, e.g. "Order__x". The
+// totalSize reported by queries of external objects depends on the adapter and is often only the size of the page, so
+// Count counts their records by paging through them, and QueryEach should be used to read all of them.
+func IsExternalObject(objectType string) bool {
+	return strings.HasSuffix(strings.ToLower(objectType), "__x")
+}
+
+// ExternalObjectID returns the ID of the row in the external system an external object record maps to.
+func (obj *SObject) ExternalObjectID() string {
+	return obj.StringField(externalObjectIDKey)
+}
+
+// DisplayURL returns the URL of the row in the external system an external object record maps to, if the external
+// data source provides one.
+func (obj *SObject) DisplayURL() string {
+	return obj.StringField(externalObjectDisplayURLKey)
+}
+
+// countExternal counts the records of the external object objectType matching where by paging through their IDs.
+func (client *Client) countExternal(objectType, where string) (int, error) {
+	count := 0
+	err := client.QueryEach("SELECT Id FROM "+objectType+whereClause(where), nil, func(*SObject) error {
+		count++
+		return nil
+	})
+	return count, err
+}
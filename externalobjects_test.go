@@ -0,0 +1,44 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_ExternalObjects(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			if q := r.URL.Query().Get("q"); q != "SELECT Id FROM Order__x WHERE Status__c = 'Open'" {
+				t.Errorf("unexpected query %s", q)
+			}
+			w.Write([]byte(`{"totalSize": 2, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-2", "records": [
+				{"Id": "x00A", "ExternalId": "1001", "DisplayUrl": "https://erp.example.com/orders/1001"},
+				{"Id": "x00B", "ExternalId": "1002"}
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/query/01g-2"):
+			w.Write([]byte(`{"totalSize": 1, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-3", "records": [
+				{"Id": "x00C", "ExternalId": "1003"}
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/query/01g-3"):
+			w.Write([]byte(`{"totalSize": 0, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-4", "records": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	count, err := client.Count("Order__x", "Status__c = 'Open'")
+	if err != nil || count != 3 {
+		t.Errorf("unexpected count %d, %v", count, err)
+	}
+
+	result, err := client.Query("SELECT Id FROM Order__x WHERE Status__c = 'Open'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Records[0].ExternalObjectID() != "1001" || result.Records[0].DisplayURL() != "https://erp.example.com/orders/1001" ||
+		result.Records[1].DisplayURL() != "" {
+		t.Errorf("unexpected records %v", result.Records)
+	}
+}
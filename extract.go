@@ -0,0 +1,453 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExtractStrategy is the API ExtractTable reads records with.
+type ExtractStrategy string
+
+// Strategies of ExtractTable.
+const (
+	// ExtractAuto picks a strategy from the estimated number of records.
+	ExtractAuto ExtractStrategy = ""
+	// ExtractREST pages through the results of a REST API query.
+	ExtractREST ExtractStrategy = "REST"
+	// ExtractBulk runs a Bulk API 2.0 query job.
+	ExtractBulk ExtractStrategy = "Bulk"
+	// ExtractPKChunking runs a Bulk API 1.0 query job with PK chunking, splitting the table into ranges of record IDs
+	// queried in parallel by salesforce.
+	ExtractPKChunking ExtractStrategy = "PKChunking"
+)
+
+// Defaults of ExtractTable.
+const (
+	// DefaultExtractBulkThreshold is the estimated number of records from which Bulk API 2.0 is used.
+	DefaultExtractBulkThreshold = 50000
+	// DefaultExtractPKChunkingThreshold is the estimated number of records from which PK chunking is used.
+	DefaultExtractPKChunkingThreshold = 10000000
+	// DefaultPKChunkSize is the number of records per chunk of PK chunked extractions.
+	DefaultPKChunkSize = 100000
+)
+
+// bulkV1Batch is the state of a batch of a Bulk API 1.0 job.
+type bulkV1Batch struct {
+	ID           string `xml:"id"`
+	State        string `xml:"state"`
+	StateMessage string `xml:"stateMessage"`
+}
+
+// ExtractOption configures ExtractTable.
+type ExtractOption func(*extractOptions)
+
+type extractOptions struct {
+	where            string
+	strategy         ExtractStrategy
+	bulkThreshold    int
+	chunkThreshold   int
+	chunkSize        int
+	strategyReporter func(strategy ExtractStrategy, estimate int)
+}
+
+// WithExtractCondition extracts only the records matching the SOQL condition where.
+func WithExtractCondition(where string) ExtractOption {
+	return func(opts *extractOptions) {
+		opts.where = where
+	}
+}
+
+// WithExtractStrategy forces ExtractTable to use strategy instead of picking one.
+func WithExtractStrategy(strategy ExtractStrategy) ExtractOption {
+	return func(opts *extractOptions) {
+		opts.strategy = strategy
+	}
+}
+
+// WithExtractThresholds sets the estimated numbers of records from which Bulk API 2.0 and PK chunking are used.
+// Non-positive values keep the defaults.
+func WithExtractThresholds(bulk, pkChunking int) ExtractOption {
+	return func(opts *extractOptions) {
+		if bulk > 0 {
+			opts.bulkThreshold = bulk
+		}
+		if pkChunking > 0 {
+			opts.chunkThreshold = pkChunking
+		}
+	}
+}
+
+// WithPKChunkSize sets the number of records per chunk of PK chunked extractions, at most 250,000.
+func WithPKChunkSize(size int) ExtractOption {
+	return func(opts *extractOptions) {
+		if size > 0 {
+			opts.chunkSize = size
+		}
+	}
+}
+
+// WithExtractStrategyReporter calls report with the strategy ExtractTable picked and the estimated number of records,
+// -1 if not estimated, before extracting, e.g. for logging.
+func WithExtractStrategyReporter(report func(strategy ExtractStrategy, estimate int)) ExtractOption {
+	return func(opts *extractOptions) {
+		opts.strategyReporter = report
+	}
+}
+
+// ExtractTable calls fn with the header and every row of the fields of the records of objectType, choosing the API by
+// the number of records: the REST API for small tables, a Bulk API 2.0 query job for large ones and a PK chunked Bulk
+// API 1.0 job for very large ones, see WithExtractThresholds. The number of records is estimated from the record
+// counts of the org, or from the query plan if a condition is set. Rows are passed to fn as they are read, with empty
+// cells for null values, so the table does not need to fit into memory. Relationship fields, e.g. "Owner.Name", are
+// supported. If fn returns an error, ExtractTable stops and returns it. Rows of PK chunked extractions are not
+// ordered, and the header is passed again with the rows of every chunk.
+func (client *Client) ExtractTable(
+	ctx context.Context,
+	objectType string,
+	fields []string,
+	fn func(header, row []string) error,
+	opts ...ExtractOption,
+) error {
+	options := &extractOptions{
+		bulkThreshold:  DefaultExtractBulkThreshold,
+		chunkThreshold: DefaultExtractPKChunkingThreshold,
+		chunkSize:      DefaultPKChunkSize,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	soql := "SELECT " + strings.Join(fields, ", ") + " FROM " + objectType + whereClause(options.where)
+
+	strategy, estimate := options.strategy, -1
+	if strategy == ExtractAuto {
+		var err error
+		estimate, err = client.estimateRecords(objectType, soql, options.where)
+		if err != nil {
+			return err
+		}
+		switch {
+		case estimate >= options.chunkThreshold:
+			strategy = ExtractPKChunking
+		case estimate >= options.bulkThreshold:
+			strategy = ExtractBulk
+		default:
+			strategy = ExtractREST
+		}
+	}
+	if options.strategyReporter != nil {
+		options.strategyReporter(strategy, estimate)
+	}
+
+	switch strategy {
+	case ExtractREST:
+		return client.extractREST(soql, fields, fn)
+	case ExtractBulk:
+		return client.BulkQueryEach(ctx, soql, fn)
+	case ExtractPKChunking:
+		return client.extractPKChunked(ctx, objectType, soql, options.chunkSize, fn)
+	default:
+		return fmt.Errorf("unknown extract strategy %q", strategy)
+	}
+}
+
+// estimateRecords estimates the number of records of objectType returned by soql, from the record count of the object
+// if there is no condition where, or from the cardinality of the cheapest query plan otherwise.
+func (client *Client) estimateRecords(objectType, soql, where string) (int, error) {
+	if !client.isLoggedIn() {
+		return 0, ErrAuthentication
+	}
+
+	if where == "" {
+		u := client.makeURL("limits/recordCount?sObjects=" + url.QueryEscape(objectType))
+		data, err := client.httpRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return 0, err
+		}
+		var counts struct {
+			SObjects []struct {
+				Count int    `json:"count"`
+				Name  string `json:"name"`
+			} `json:"sObjects"`
+		}
+		err = client.unmarshalJSON(data, &counts)
+		if err != nil {
+			return 0, err
+		}
+		for _, count := range counts.SObjects {
+			if strings.EqualFold(count.Name, objectType) {
+				return count.Count, nil
+			}
+		}
+		return 0, nil
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("query?explain="+url.QueryEscape(soql)), nil)
+	if err != nil {
+		return 0, err
+	}
+	var explain struct {
+		Plans []struct {
+			Cardinality int `json:"cardinality"`
+		} `json:"plans"`
+	}
+	err = client.unmarshalJSON(data, &explain)
+	if err != nil {
+		return 0, err
+	}
+	if len(explain.Plans) == 0 {
+		return 0, nil
+	}
+	// Plans are ordered by their relative cost, the first one is used.
+	return explain.Plans[0].Cardinality, nil
+}
+
+// extractREST calls fn with the values of fields of every record returned by soql through the REST API.
+func (client *Client) extractREST(soql string, fields []string, fn func(header, row []string) error) error {
+	return client.queryPages("query", soql, func(records []byte) error {
+		var page []map[string]interface{}
+		err := client.unmarshalJSON(records, &page)
+		if err != nil {
+			return err
+		}
+		for _, record := range page {
+			row := make([]string, len(fields))
+			for idx, field := range fields {
+				row[idx] = extractValue(record, field)
+			}
+			err = fn(fields, row)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// extractValue returns the value of field of record formatted like in Bulk API results, following relationships of
+// field, e.g. "Owner.Name".
+func extractValue(record map[string]interface{}, field string) string {
+	var value interface{} = record
+	for _, name := range strings.Split(field, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = nil
+		for key, fieldValue := range fields {
+			if strings.EqualFold(key, name) {
+				value = fieldValue
+				break
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// extractPKChunked runs soql as a Bulk API 1.0 query job on objectType with PK chunks of chunkSize records, and calls
+// fn with the rows of the results of every chunk.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_asynch.meta/api_asynch/async_api_headers_enable_pk_chunking.htm
+func (client *Client) extractPKChunked(
+	ctx context.Context,
+	objectType, soql string,
+	chunkSize int,
+	fn func(header, row []string) error,
+) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	reqData, err := client.marshalJSON(map[string]string{"operation": "query", "object": objectType, "contentType": "CSV"})
+	if err != nil {
+		return err
+	}
+	header := http.Header{"Sforce-Enable-PKChunking": []string{"chunkSize=" + strconv.Itoa(chunkSize)}}
+	data, err := client.bulkV1Request(http.MethodPost, "job", "application/json", bytes.NewReader(reqData), header)
+	if err != nil {
+		return err
+	}
+	var job struct {
+		ID string `json:"id"`
+	}
+	err = client.unmarshalJSON(data, &job)
+	if err != nil {
+		return err
+	}
+	defer client.closeBulkV1Job(job.ID)
+
+	data, err = client.bulkV1Request(http.MethodPost, "job/"+job.ID+"/batch", "text/csv", strings.NewReader(soql), nil)
+	if err != nil {
+		return err
+	}
+	var original bulkV1Batch
+	err = xml.Unmarshal(data, &original)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := client.waitPKChunks(ctx, job.ID, original.ID)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		data, err = client.bulkV1Request(http.MethodGet, "job/"+job.ID+"/batch/"+chunk.ID+"/result", "", nil, nil)
+		if err != nil {
+			return err
+		}
+		var results struct {
+			IDs []string `xml:"result"`
+		}
+		err = xml.Unmarshal(data, &results)
+		if err != nil {
+			return err
+		}
+		for _, resultID := range results.IDs {
+			err = client.eachBulkV1Result(job.ID, chunk.ID, resultID, fn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// waitPKChunks polls the batches of the PK chunked Bulk API 1.0 job jobID until the chunks of the original batch are
+// completed, and returns them. With PK chunking, salesforce does not process the original batch but adds a batch for
+// every chunk.
+func (client *Client) waitPKChunks(ctx context.Context, jobID, originalID string) ([]bulkV1Batch, error) {
+	for {
+		data, err := client.bulkV1Request(http.MethodGet, "job/"+jobID+"/batch", "", nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var list struct {
+			Batches []bulkV1Batch `xml:"batchInfo"`
+		}
+		err = xml.Unmarshal(data, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		var chunks []bulkV1Batch
+		chunked, pending := false, false
+		for _, batch := range list.Batches {
+			if batch.ID == originalID {
+				if batch.State == "Failed" {
+					return nil, errors.Wrap(ErrBulkJobFailed, batch.StateMessage)
+				}
+				chunked = batch.State == "NotProcessed"
+				continue
+			}
+			switch batch.State {
+			case "Failed", "NotProcessed":
+				return nil, errors.Wrapf(ErrBulkJobFailed, "batch %s: %s", batch.ID, batch.StateMessage)
+			case "Completed":
+			default:
+				pending = true
+			}
+			chunks = append(chunks, batch)
+		}
+		if chunked && !pending {
+			return chunks, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(DefaultBulkPollInterval):
+		}
+	}
+}
+
+// eachBulkV1Result calls fn with the header and every row of the CSV result resultID of a batch of a Bulk API 1.0 job.
+func (client *Client) eachBulkV1Result(jobID, batchID, resultID string, fn func(header, row []string) error) error {
+	req, err := http.NewRequest(http.MethodGet, client.bulkV1URL("job/"+jobID+"/batch/"+batchID+"/result/"+resultID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("X-SFDC-Session", client.sessionID)
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+		return client.withRequestContext(ParseSalesforceError(resp.StatusCode, buf.Bytes()), resp.Header)
+	}
+	return eachCSVRow(resp.Body, fn)
+}
+
+// closeBulkV1Job closes the Bulk API 1.0 job jobID, logging failures.
+func (client *Client) closeBulkV1Job(jobID string) {
+	body := strings.NewReader(`{"state": "Closed"}`)
+	_, err := client.bulkV1Request(http.MethodPost, "job/"+jobID, "application/json", body, nil)
+	if err != nil {
+		log.Println(logPrefix, "failed to close bulk job", jobID, err)
+	}
+}
+
+// bulkV1URL returns the URL of the Bulk API 1.0 resource path.
+func (client *Client) bulkV1URL(path string) string {
+	return fmt.Sprintf("%s/services/async/%s/%s", client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"), path)
+}
+
+// bulkV1Request sends a request to the Bulk API 1.0 resource path, which authenticates with the X-SFDC-Session header,
+// and returns the response body.
+func (client *Client) bulkV1Request(method, path, contentType string, body io.Reader, header http.Header) ([]byte, error) {
+	req, err := http.NewRequest(method, client.bulkV1URL(path), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("X-SFDC-Session", client.sessionID)
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := client.readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, client.withRequestContext(ParseSalesforceError(resp.StatusCode, data), resp.Header)
+	}
+	return data, nil
+}
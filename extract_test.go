@@ -0,0 +1,150 @@
+package simpleforce
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_ExtractTableREST(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/services/data/v54.0/query" && r.URL.Query().Get("explain") != "":
+			if q := r.URL.Query().Get("explain"); q != "SELECT Id, Name, Owner.Name, IsDeleted FROM Account WHERE Rating = 'Hot'" {
+				t.Errorf("unexpected explained query %s", q)
+			}
+			w.Write([]byte(`{"plans": [{"cardinality": 2, "leadingOperationType": "Index"},
+				{"cardinality": 90000, "leadingOperationType": "TableScan"}]}`))
+		case r.URL.Path == "/services/data/v54.0/query":
+			w.Write([]byte(`{"done": true, "records": [
+				{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme", "IsDeleted": false,
+					"Owner": {"attributes": {"type": "User"}, "Name": "Jane Doe"}},
+				{"attributes": {"type": "Account"}, "Id": "001B", "Name": null, "IsDeleted": false, "Owner": null}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	var strategy ExtractStrategy
+	var rows []string
+	err := client.ExtractTable(context.Background(), "Account", []string{"Id", "Name", "Owner.Name", "IsDeleted"},
+		func(header, row []string) error {
+			rows = append(rows, strings.Join(row, ","))
+			return nil
+		},
+		WithExtractCondition("Rating = 'Hot'"),
+		WithExtractStrategyReporter(func(picked ExtractStrategy, estimate int) {
+			strategy = picked
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy != ExtractREST {
+		t.Errorf("expected the REST strategy, got %s", strategy)
+	}
+	if strings.Join(rows, "|") != "001A,Acme,Jane Doe,false|001B,,,false" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestClient_ExtractTableBulk(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/limits/recordCount":
+			w.Write([]byte(`{"sObjects": [{"count": 75000, "name": "Contact"}]}`))
+		case "/services/data/v54.0/jobs/query":
+			w.Write([]byte(`{"id": "750A", "state": "UploadComplete"}`))
+		case "/services/data/v54.0/jobs/query/750A":
+			w.Write([]byte(`{"id": "750A", "state": "JobComplete"}`))
+		case "/services/data/v54.0/jobs/query/750A/results":
+			w.Header().Set("Sforce-Locator", "null")
+			w.Write([]byte("\"Id\",\"LastName\"\n\"003A\",\"Doe\"\n"))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	var strategy ExtractStrategy
+	var rows [][]string
+	err := client.ExtractTable(context.Background(), "Contact", []string{"Id", "LastName"},
+		func(header, row []string) error {
+			rows = append(rows, row)
+			return nil
+		},
+		WithExtractStrategyReporter(func(picked ExtractStrategy, estimate int) {
+			if estimate != 75000 {
+				t.Errorf("unexpected estimate %d", estimate)
+			}
+			strategy = picked
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy != ExtractBulk || len(rows) != 1 || rows[0][1] != "Doe" {
+		t.Errorf("unexpected extraction with %s: %v", strategy, rows)
+	}
+}
+
+func TestClient_ExtractTablePKChunking(t *testing.T) {
+	closed := false
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-SFDC-Session") != "__SESSION__" {
+			t.Errorf("missing session header on %s", r.URL)
+		}
+		switch r.URL.Path {
+		case "/services/async/54.0/job":
+			if r.Header.Get("Sforce-Enable-PKChunking") != "chunkSize=250000" {
+				t.Errorf("unexpected PK chunking header %q", r.Header.Get("Sforce-Enable-PKChunking"))
+			}
+			w.Write([]byte(`{"id": "750J", "state": "Open"}`))
+		case "/services/async/54.0/job/750J":
+			body, _ := ioutil.ReadAll(r.Body)
+			closed = strings.Contains(string(body), "Closed")
+			w.Write([]byte(`{"id": "750J", "state": "Closed"}`))
+		case "/services/async/54.0/job/750J/batch":
+			if r.Method == http.MethodPost {
+				w.Write([]byte(`<batchInfo xmlns="http://www.force.com/2009/06/asyncapi/dataload">
+					<id>751O</id><state>Queued</state></batchInfo>`))
+				return
+			}
+			w.Write([]byte(`<batchInfoList xmlns="http://www.force.com/2009/06/asyncapi/dataload">
+				<batchInfo><id>7511</id><state>Completed</state></batchInfo>
+				<batchInfo><id>751O</id><state>NotProcessed</state></batchInfo>
+				<batchInfo><id>7512</id><state>Completed</state></batchInfo>
+			</batchInfoList>`))
+		case "/services/async/54.0/job/750J/batch/7511/result", "/services/async/54.0/job/750J/batch/7512/result":
+			batch := strings.Split(r.URL.Path, "/")[7]
+			w.Write([]byte(`<result-list xmlns="http://www.force.com/2009/06/asyncapi/dataload"><result>752` + batch +
+				`</result></result-list>`))
+		case "/services/async/54.0/job/750J/batch/7511/result/7527511":
+			w.Write([]byte("\"Id\"\n\"00QA\"\n\"00QB\"\n"))
+		case "/services/async/54.0/job/750J/batch/7512/result/7527512":
+			w.Write([]byte("\"Id\"\n\"00QC\"\n"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	var ids []string
+	err := client.ExtractTable(context.Background(), "Lead", []string{"Id"},
+		func(header, row []string) error {
+			ids = append(ids, row[0])
+			return nil
+		},
+		WithExtractStrategy(ExtractPKChunking),
+		WithPKChunkSize(250000),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "00QA,00QB,00QC" {
+		t.Errorf("unexpected IDs %v", ids)
+	}
+	if !closed {
+		t.Error("expected the job to be closed")
+	}
+}
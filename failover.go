@@ -0,0 +1,146 @@
+package simpleforce
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFailoverThreshold is used by SetInstanceFailover when no positive threshold is given.
+const DefaultFailoverThreshold = 3
+
+// instanceFailover re-runs the login of a client after consecutive connection failures.
+type instanceFailover struct {
+	threshold int
+	login     func(*Client) error
+
+	mu       sync.Mutex
+	failures int
+	active   bool
+}
+
+// SetInstanceFailover makes the client call login, e.g. a closure calling LoginPassword, after threshold consecutive
+// requests failed to connect, DefaultFailoverThreshold if not positive. Org migrations move orgs to new instances, so
+// the instance URL returned by the login may change while the old host stops resolving or accepting connections. If it
+// changed, the failed REST request, which never reached salesforce, is retried once on the new instance. Only DNS and
+// dial errors count as failures to connect; e.g. timeouts and canceled requests neither count nor reset the failures.
+// A nil login disables failover.
+func (client *Client) SetInstanceFailover(threshold int, login func(*Client) error) {
+	if login == nil {
+		client.failover = nil
+		return
+	}
+	if threshold <= 0 {
+		threshold = DefaultFailoverThreshold
+	}
+	client.failover = &instanceFailover{threshold: threshold, login: login}
+}
+
+// do sends req like send, re-running the login once enough requests failed to connect if failover is enabled.
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := client.send(req)
+	failover := client.failover
+	if failover == nil {
+		return resp, err
+	}
+	if err == nil {
+		failover.reset()
+		return resp, err
+	}
+	if !isConnectFailure(err) || !failover.fail() {
+		return resp, err
+	}
+
+	oldInstance, oldSession := client.instanceURL, client.sessionID
+	loginErr := failover.login(client)
+	failover.done()
+	if loginErr != nil {
+		log.Println(logPrefix, "login after connection failures failed,", loginErr)
+		return resp, err
+	}
+	if client.instanceURL == oldInstance {
+		return resp, err
+	}
+	log.Println(logPrefix, "instance moved from", oldInstance, "to", client.instanceURL)
+
+	retry, ok := client.moveRequest(req, oldInstance, oldSession)
+	if !ok {
+		return resp, err
+	}
+	return client.send(retry)
+}
+
+// isConnectFailure reports whether err tells that a request could not be sent because the host could not be resolved or
+// connected to, so it did not reach the server.
+func isConnectFailure(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// moveRequest returns a copy of the REST request req, sent to oldInstance with oldSession, for the current instance
+// and session. ok is false if the request cannot be moved, e.g. a SOAP request with the session in its body.
+func (client *Client) moveRequest(req *http.Request, oldInstance, oldSession string) (moved *http.Request, ok bool) {
+	old, err := url.Parse(oldInstance)
+	if err != nil || req.URL.Host != old.Host || req.Header.Get("Authorization") != "Bearer "+oldSession {
+		return nil, false
+	}
+	instance, err := url.Parse(client.instanceURL)
+	if err != nil || strings.EqualFold(old.Host, instance.Host) {
+		return nil, false
+	}
+
+	moved = req.Clone(req.Context())
+	moved.URL.Scheme, moved.URL.Host = instance.Scheme, instance.Host
+	moved.Host = ""
+	moved.Header.Set("Authorization", "Bearer "+client.sessionID)
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		moved.Body, err = req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+	}
+	return moved, true
+}
+
+// fail records a connection failure and reports whether the caller should re-run the login, in which case it must call
+// done afterwards.
+func (failover *instanceFailover) fail() bool {
+	failover.mu.Lock()
+	defer failover.mu.Unlock()
+	if failover.active {
+		return false
+	}
+	failover.failures++
+	if failover.failures < failover.threshold {
+		return false
+	}
+	failover.failures = 0
+	failover.active = true
+	return true
+}
+
+// done marks the end of a login started after fail.
+func (failover *instanceFailover) done() {
+	failover.mu.Lock()
+	failover.active = false
+	failover.mu.Unlock()
+}
+
+// reset clears the failures after a response was received.
+func (failover *instanceFailover) reset() {
+	failover.mu.Lock()
+	failover.failures = 0
+	failover.mu.Unlock()
+}
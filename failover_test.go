@@ -0,0 +1,99 @@
+package simpleforce
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_SetInstanceFailover(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	moved := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer __NEW_SESSION__" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		if r.Method == http.MethodPost {
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"Name":"Acme"}` {
+				t.Errorf("unexpected body %s", body)
+			}
+		}
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	}))
+	defer moved.Close()
+
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", dead.URL)
+	logins := 0
+	client.SetInstanceFailover(2, func(c *Client) error {
+		logins++
+		c.SetSidLoc("__NEW_SESSION__", moved.URL)
+		return nil
+	})
+
+	if _, err := client.Query("SELECT Id FROM Account"); err == nil {
+		t.Fatal("expected the first request to fail")
+	}
+	if _, err := client.Query("SELECT Id FROM Account"); err != nil {
+		t.Fatalf("expected the request to be retried on the new instance, got %v", err)
+	}
+	if logins != 1 || client.GetLoc() != moved.URL {
+		t.Errorf("expected a single login, got %d", logins)
+	}
+	_, err := client.httpRequest(http.MethodPost, client.makeURL("sobjects/Account"), strings.NewReader(`{"Name":"Acme"}`))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_SetInstanceFailoverSameInstance(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", dead.URL)
+	logins := 0
+	client.SetInstanceFailover(0, func(c *Client) error {
+		logins++
+		return nil
+	})
+
+	for i := 0; i < 2*DefaultFailoverThreshold; i++ {
+		if _, err := client.Query("SELECT Id FROM Account"); err == nil {
+			t.Fatal("expected the request to fail")
+		}
+	}
+	if logins != 2 {
+		t.Errorf("expected a login every %d failures, got %d", DefaultFailoverThreshold, logins)
+	}
+
+	client.SetInstanceFailover(1, nil)
+	if _, err := client.Query("SELECT Id FROM Account"); err == nil || logins != 2 {
+		t.Errorf("expected failover to be disabled, got %d logins", logins)
+	}
+}
+
+func TestIsConnectFailure(t *testing.T) {
+	dial := &url.Error{Op: "Get", URL: "https://na1.salesforce.com", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}
+	dns := &url.Error{Op: "Get", URL: "https://na1.salesforce.com", Err: &net.OpError{Op: "dial", Err: &net.DNSError{}}}
+	read := &url.Error{Op: "Post", URL: "https://na1.salesforce.com", Err: &net.OpError{Op: "read", Err: errors.New("reset")}}
+	for err, expected := range map[error]bool{
+		dial:                     true,
+		dns:                      true,
+		read:                     false,
+		context.DeadlineExceeded: false,
+		ErrClientClosed:          false,
+	} {
+		if isConnectFailure(err) != expected {
+			t.Errorf("expected isConnectFailure(%v) to be %v", err, expected)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package simpleforce
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxFieldsQueryLimit is the largest LIMIT salesforce accepts for queries selecting FIELDS(ALL) or FIELDS(CUSTOM),
+// which cannot be paged through with queryMore either.
+const maxFieldsQueryLimit = 200
+
+var fieldsFunctionRegexp = regexp.MustCompile(`(?i)^FIELDS\s*\(\s*(ALL|STANDARD|CUSTOM)\s*\)$`)
+
+// FieldFilter selects the fields returned by QueryFields.
+type FieldFilter int
+
+const (
+	// FieldsAll selects every field of the object, like SELECT FIELDS(ALL).
+	FieldsAll FieldFilter = iota
+	// FieldsStandard selects the standard fields, like SELECT FIELDS(STANDARD).
+	FieldsStandard
+	// FieldsCustom selects the custom fields, like SELECT FIELDS(CUSTOM).
+	FieldsCustom
+	// FieldsCreateable selects the fields which can be set when creating a record.
+	FieldsCreateable
+	// FieldsUpdateable selects the fields which can be set when updating a record.
+	FieldsUpdateable
+)
+
+// keep reports whether the describe metadata of a field matches the filter.
+func (filter FieldFilter) keep(field map[string]interface{}) bool {
+	custom, _ := field["custom"].(bool)
+	switch filter {
+	case FieldsStandard:
+		return !custom
+	case FieldsCustom:
+		return custom
+	case FieldsCreateable:
+		createable, _ := field["createable"].(bool)
+		return createable
+	case FieldsUpdateable:
+		updateable, _ := field["updateable"].(bool)
+		return updateable
+	default:
+		return true
+	}
+}
+
+// QueryFields returns the names of the fields of objectType matching filter, in describe order. The describe metadata
+// is cached by the client (see DescribeSObjects), so this is cheap to call repeatedly.
+func (client *Client) QueryFields(objectType string, filter FieldFilter) ([]string, error) {
+	metas, err := client.DescribeSObjects(objectType)
+	if err != nil {
+		return nil, err
+	}
+	meta := metas[objectType]
+
+	rawFields, _ := (*meta)["fields"].([]interface{})
+	fields := make([]string, 0, len(rawFields))
+	for _, rawField := range rawFields {
+		field, ok := rawField.(map[string]interface{})
+		if !ok || !filter.keep(field) {
+			continue
+		}
+		if name, _ := field["name"].(string); name != "" {
+			fields = append(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields of %s match the filter", objectType)
+	}
+	return fields, nil
+}
+
+// SelectFields builds "SELECT <fields> FROM objectType" with the fields matching filter, emulating SELECT FIELDS(ALL)
+// without its restrictions, e.g. the limit of 200 records. Conditions such as WHERE or ORDER BY clauses can be
+// appended to the returned query.
+func (client *Client) SelectFields(objectType string, filter FieldFilter) (string, error) {
+	fields, err := client.QueryFields(objectType, filter)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), objectType), nil
+}
+
+// expandFieldsQuery rewrites a query selecting FIELDS(ALL) or FIELDS(CUSTOM) without a LIMIT of at most 200 records,
+// which salesforce rejects, into a query with the explicit field list from the describe metadata so the results can be
+// paged through as usual. ok is false if the query does not need to be rewritten.
+func (client *Client) expandFieldsQuery(soql string) (expanded string, ok bool, err error) {
+	trimmed := strings.TrimSpace(soql)
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return "", false, nil
+	}
+	rest := trimmed[len("SELECT"):]
+	fromIdx := indexTopLevelKeyword(rest, "FROM")
+	if fromIdx < 0 {
+		return "", false, nil
+	}
+	items := splitTopLevel(rest[:fromIdx])
+	tail := rest[fromIdx:]
+
+	// Only FIELDS(ALL) and FIELDS(CUSTOM) are restricted, FIELDS(STANDARD) is passed to salesforce as is.
+	restricted := false
+	for _, item := range items {
+		match := fieldsFunctionRegexp.FindStringSubmatch(item)
+		if match != nil && !strings.EqualFold(match[1], "STANDARD") {
+			restricted = true
+		}
+	}
+	if !restricted {
+		return "", false, nil
+	}
+	if limitIdx := indexTopLevelKeyword(tail, "LIMIT"); limitIdx >= 0 {
+		limitFields := strings.Fields(tail[limitIdx+len("LIMIT"):])
+		if len(limitFields) > 0 {
+			if limit, err := strconv.Atoi(limitFields[0]); err == nil && limit <= maxFieldsQueryLimit {
+				return "", false, nil
+			}
+		}
+	}
+
+	tailFields := strings.Fields(tail[len("FROM"):])
+	if len(tailFields) == 0 {
+		return "", false, nil
+	}
+	objectType := tailFields[0]
+
+	seen := make(map[string]bool)
+	var selected []string
+	add := func(field string) {
+		if !seen[strings.ToLower(field)] {
+			seen[strings.ToLower(field)] = true
+			selected = append(selected, field)
+		}
+	}
+	for _, item := range items {
+		match := fieldsFunctionRegexp.FindStringSubmatch(item)
+		if match == nil {
+			add(item)
+			continue
+		}
+		filter := map[string]FieldFilter{"ALL": FieldsAll, "STANDARD": FieldsStandard, "CUSTOM": FieldsCustom}[strings.ToUpper(match[1])]
+		fields, err := client.QueryFields(objectType, filter)
+		if err != nil {
+			return "", false, err
+		}
+		for _, field := range fields {
+			add(field)
+		}
+	}
+
+	return "SELECT " + strings.Join(selected, ", ") + " " + tail, true, nil
+}
+
+// indexTopLevelKeyword returns the index of the first occurrence of keyword, delimited by whitespace, which is neither
+// in a subquery nor in a string literal, or -1.
+func indexTopLevelKeyword(s, keyword string) int {
+	depth := 0
+	quoted := false
+	for idx := 0; idx < len(s); idx++ {
+		switch c := s[idx]; {
+		case c == '\'' && (idx == 0 || s[idx-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && idx+len(keyword) <= len(s) && strings.EqualFold(s[idx:idx+len(keyword)], keyword):
+			before := idx == 0 || unicode.IsSpace(rune(s[idx-1]))
+			after := idx+len(keyword) == len(s) || unicode.IsSpace(rune(s[idx+len(keyword)]))
+			if before && after {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a select list at the commas which are not in a subquery or function call, trimming the items.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth := 0
+	start := 0
+	for idx := 0; idx < len(s); idx++ {
+		switch s[idx] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:idx]))
+				start = idx + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
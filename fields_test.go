@@ -0,0 +1,105 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+func TestClient_QueryFields(t *testing.T) {
+	var describes int
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sobjects/Account/describe") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		describes++
+		w.Write([]byte(`{"name": "Account", "fields": [
+			{"name": "Id", "custom": false, "createable": false, "updateable": false},
+			{"name": "Name", "custom": false, "createable": true, "updateable": true},
+			{"name": "Region__c", "custom": true, "createable": true, "updateable": false}
+		]}`))
+	})
+
+	tests := []struct {
+		filter   FieldFilter
+		expected string
+	}{
+		{FieldsAll, "Id,Name,Region__c"},
+		{FieldsStandard, "Id,Name"},
+		{FieldsCustom, "Region__c"},
+		{FieldsCreateable, "Name,Region__c"},
+		{FieldsUpdateable, "Name"},
+	}
+	for _, test := range tests {
+		fields, err := client.QueryFields("Account", test.filter)
+		if err != nil || strings.Join(fields, ",") != test.expected {
+			t.Errorf("filter %d: unexpected fields %v, %v", test.filter, fields, err)
+		}
+	}
+	if describes != 1 {
+		t.Errorf("describe not cached, %d requests", describes)
+	}
+
+	soql, err := client.SelectFields("Account", FieldsCustom)
+	if err != nil || soql != "SELECT Region__c FROM Account" {
+		t.Errorf("unexpected query %s, %v", soql, err)
+	}
+}
+
+func TestClient_QueryExpandsFields(t *testing.T) {
+	var queries []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			w.Write([]byte(`{"name": "Account", "fields": [
+				{"name": "Id", "custom": false}, {"name": "Name", "custom": false}, {"name": "Region__c", "custom": true}]}`))
+			return
+		}
+		queries = append(queries, r.URL.Query().Get("q"))
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
+
+	soqls := []string{
+		"SELECT Id, FIELDS(ALL), (SELECT Id FROM Contacts) FROM Account WHERE Name = 'limit 1' ORDER BY Name",
+		"SELECT FIELDS(CUSTOM) FROM Account LIMIT 500",
+		"SELECT FIELDS(ALL) FROM Account LIMIT 200",
+		"SELECT FIELDS(STANDARD) FROM Account",
+		"SELECT Id FROM Account",
+	}
+	for _, soql := range soqls {
+		if _, err := client.Query(soql); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"SELECT Id, Name, Region__c, (SELECT Id FROM Contacts) FROM Account WHERE Name = 'limit 1' ORDER BY Name",
+		"SELECT Region__c FROM Account LIMIT 500",
+		soqls[2],
+		soqls[3],
+		soqls[4],
+	}
+	for idx := range expected {
+		if queries[idx] != expected[idx] {
+			t.Errorf("unexpected query %q, expected %q", queries[idx], expected[idx])
+		}
+	}
+}
+
+func TestClient_QueryExpandsFieldsDescribeError(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/describe") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`))
+	})
+
+	_, err := client.Query("SELECT FIELDS(ALL) FROM Acount")
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) || sfErr.ErrorCode != errcode.NotFound {
+		t.Errorf("expected the describe error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package simpleforce
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// FieldSecurity selects how CreateAll, UpdateAll and UpsertAll treat fields the running user cannot write, see
+// WithFieldSecurity. The describe metadata the decision is based on reflects the field-level security of the user the
+// client is logged in as: fields without read access are missing from it, and fields without edit access are neither
+// createable nor updateable.
+type FieldSecurity int
+
+const (
+	// FieldSecurityOff sends all fields and leaves it to salesforce to reject the records.
+	FieldSecurityOff FieldSecurity = iota
+	// FieldSecurityStrip removes the fields the running user cannot write before sending the records. The records
+	// passed in are left unchanged, and the stripped fields are logged.
+	FieldSecurityStrip
+	// FieldSecurityError fails records with fields the running user cannot write without sending them, with a SaveError
+	// per field telling why the field cannot be written.
+	FieldSecurityError
+)
+
+// restrictedFields returns the errors for the fields of records which the running user cannot write with operation,
+// "create", "update" or "upsert", by record index. objectType overrides the types of the records if not empty.
+func (client *Client) restrictedFields(operation, objectType string, records []*SObject) (map[int][]SaveError, error) {
+	return client.checkRecords(objectType, records, func(recordType string, meta *SObjectMeta, record *SObject) []SaveError {
+		return restrictedFieldErrors(operation, recordType, meta, record)
+	})
+}
+
+// restrictedFieldErrors reports the fields of a single record which the running user cannot write.
+func restrictedFieldErrors(operation, objectType string, meta *SObjectMeta, record *SObject) []SaveError {
+	fields, relationships := indexFields(meta)
+	payload := record.makeCopy()
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []SaveError
+	for _, key := range keys {
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			if _, isParent := payload[key].(map[string]interface{}); isParent && relationships[strings.ToLower(key)] {
+				continue
+			}
+			errs = append(errs, SaveError{
+				StatusCode: errcode.InvalidField,
+				Message: fmt.Sprintf("%s.%s does not exist or is not visible to the running user, check the field-level "+
+					"security in the profile and permission sets of the user", objectType, key),
+				Fields: []string{key},
+			})
+			continue
+		}
+		if fieldWritable(operation, field) {
+			continue
+		}
+
+		var reason string
+		if calculated, _ := field["calculated"].(bool); calculated {
+			reason = "is a formula field"
+		} else if autoNumber, _ := field["autoNumber"].(bool); autoNumber {
+			reason = "is an auto number field"
+		} else {
+			access := "edit"
+			if operation == "update" {
+				if createable, _ := field["createable"].(bool); createable {
+					access = "update"
+				}
+			}
+			reason = fmt.Sprintf("is read-only for the running user, who needs %s access through the field-level "+
+				"security in the profile or permission sets of the user", access)
+		}
+		errs = append(errs, SaveError{
+			StatusCode: errcode.InvalidFieldForInsertUpdate,
+			Message:    fmt.Sprintf("%s.%s cannot be written with %s: it %s", objectType, key, operation, reason),
+			Fields:     []string{key},
+		})
+	}
+	return errs
+}
+
+// stripFields returns records with the fields of restricted removed. Records with restricted fields are copied, the
+// others are returned as is.
+func stripFields(records []*SObject, restricted map[int][]SaveError) []*SObject {
+	if len(restricted) == 0 {
+		return records
+	}
+	stripped := make([]*SObject, len(records))
+	copy(stripped, records)
+	for idx, errs := range restricted {
+		record := make(SObject, len(*records[idx]))
+		for key, val := range *records[idx] {
+			record[key] = val
+		}
+		var names []string
+		for _, saveErr := range errs {
+			for _, name := range saveErr.Fields {
+				delete(record, name)
+				names = append(names, name)
+			}
+		}
+		log.Println(logPrefix, "not sending fields", strings.Join(names, ", "), "of", records[idx].Type(),
+			"which the running user cannot write")
+		stripped[idx] = &record
+	}
+	return stripped
+}
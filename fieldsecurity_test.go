@@ -0,0 +1,68 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+func TestClient_CreateAllFieldSecurityStrip(t *testing.T) {
+	var sent []interface{}
+	client := newValidateClient(t, &sent)
+
+	record := client.SObject("Account").Set("Name", "Acme").Set("Score__c", 2).Set("Secret__c", "x")
+	results, err := client.CreateAll([]*SObject{record}, WithFieldSecurity(FieldSecurityStrip))
+	if err != nil || !results[0].Success || record.ID() != "001NEW" {
+		t.Fatalf("unexpected results %v, %v", results, err)
+	}
+	payload := sent[0].(map[string]interface{})
+	if _, ok := payload["Score__c"]; ok || payload["Name"] != "Acme" {
+		t.Errorf("expected restricted fields to be stripped, got %v", payload)
+	}
+	if _, ok := payload["Secret__c"]; ok {
+		t.Errorf("expected invisible fields to be stripped, got %v", payload)
+	}
+	if record.InterfaceField("Score__c") == nil {
+		t.Error("expected the record passed in to be left unchanged")
+	}
+}
+
+func TestClient_UpdateAllFieldSecurityError(t *testing.T) {
+	var sent []interface{}
+	client := newValidateClient(t, &sent)
+
+	records := []*SObject{
+		client.SObject("Account").Set("Id", "001A").Set("Name", "Acme"),
+		client.SObject("Account").Set("Id", "001B").Set("Score__c", 2).Set("Secret__c", "x"),
+	}
+	results, err := client.UpdateAll(records, WithFieldSecurity(FieldSecurityError), WithValidation(true))
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Records) != 1 || batchErr.Records[0].Index != 1 {
+		t.Fatalf("expected the second record to fail, got %v", err)
+	}
+	if len(sent) != 1 || !results[0].Success {
+		t.Errorf("expected the first record to be sent, got %v", sent)
+	}
+	errs := results[1].Errors
+	if len(errs) != 2 || errs[0].StatusCode != errcode.InvalidFieldForInsertUpdate ||
+		!strings.Contains(errs[0].Message, "formula field") || errs[1].StatusCode != errcode.InvalidField ||
+		!strings.Contains(errs[1].Message, "not visible to the running user") {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestClient_CreateAllFieldSecurityDescribeFailure(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`[{"message": "The requested resource does not exist", "errorCode": "NOT_FOUND"}]`))
+	})
+	_, err := client.CreateAll([]*SObject{client.SObject("Foo__c").Set("Name", "x")},
+		WithFieldSecurity(FieldSecurityError))
+	if err == nil {
+		t.Error("expected the describe failure to be returned")
+	}
+}
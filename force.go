@@ -2,7 +2,6 @@ package simpleforce
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -15,6 +14,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -34,12 +37,50 @@ type Client struct {
 		fullName string
 		email    string
 	}
-	clientID      string
-	apiVersion    string
-	baseURL       string
-	instanceURL   string
-	useToolingAPI bool
-	httpClient    *http.Client
+	organizationID string
+	expectedOrgID  string
+	clientID       string
+	apiVersion     string
+	baseURL        string
+	instanceURL    string
+	useToolingAPI  bool
+	useNumber      bool
+	jsonCodec      Codec
+	maxBodySize    int64
+	acceptLanguage string
+	sitePrefix     string
+	httpClient     *http.Client
+	loginGuard     *loginGuard
+	auditHook      func(AuditRecord)
+	failover       *instanceFailover
+	migrationMode  int32 // 1 if enabled, see migrating
+	throttle       atomic.Value
+	readRouter     *readRouter
+	queue          *requestQueue
+	jobStore       JobStore
+
+	describeMu    sync.Mutex
+	describeCalls map[string]*describeCall
+	describeCache *clientCache
+	queryCache    *clientCache
+
+	hostMu        sync.RWMutex
+	hostOverrides map[string]*url.URL
+
+	lookupMu sync.Mutex
+	lookups  map[string]map[string]string
+
+	sessionUserMu sync.Mutex
+	sessionUser   struct { // user of a session set with SetSidLoc, see cacheUserID
+		session  string
+		id       string
+		err      error
+		resolved bool
+	}
+
+	migrationScopes int32 // WithMigrationMode calls running
+
+	lifecycle lifecycle
 }
 
 // QueryResult holds the response data from an SOQL query.
@@ -64,36 +105,62 @@ func (client *Client) GetLoc() (loc string) {
 func (client *Client) SetSidLoc(sid string, loc string) {
 	client.sessionID = sid
 	client.instanceURL = loc
+	if orgID := sessionOrgID(sid); orgID != "" {
+		client.organizationID = orgID
+	}
 }
 
 // Query runs an SOQL query. q could either be the SOQL string or the nextRecordsURL.
+// Queries selecting FIELDS(ALL) or FIELDS(CUSTOM) without a LIMIT of at most 200 records, which salesforce rejects,
+// are sent with the explicit field list of the object instead, so all records can be paged through. The field list is
+// taken from the describe metadata cached by the client; if the object cannot be described, its error is returned.
 func (client *Client) Query(q string) (*QueryResult, error) {
+	return client.query(q, nil)
+}
+
+// QuoteSOQL returns value as an SOQL string literal, escaping quotes and backslashes, e.g. for use in WHERE clauses.
+func QuoteSOQL(value string) string {
+	return "'" + soqlEscaper.Replace(value) + "'"
+}
+
+var soqlEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// query runs a query like Query, adding header to the request.
+func (client *Client) query(q string, header http.Header) (*QueryResult, error) {
 	if !client.isLoggedIn() {
 		return nil, ErrAuthentication
 	}
 
 	var u string
-	if strings.HasPrefix(q, "/services/data") {
+	if strings.HasPrefix(q, "/services/data") || strings.HasPrefix(q, client.sitePrefix+"/services/data") {
 		// q is nextRecordsURL.
-		u = fmt.Sprintf("%s%s", client.instanceURL, q)
+		u = client.resolveURL(q)
 	} else {
 		// q is SOQL.
+		if !client.useToolingAPI {
+			expanded, ok, err := client.expandFieldsQuery(q)
+			if err != nil {
+				return nil, errors.Wrap(err, "expanding FIELDS() query")
+			}
+			if ok {
+				q = expanded
+			}
+		}
 		formatString := "%s/services/data/v%s/query?q=%s"
-		baseURL := client.instanceURL
+		baseURL := client.servicesURL()
 		if client.useToolingAPI {
 			formatString = strings.Replace(formatString, "query", "tooling/query", -1)
 		}
 		u = fmt.Sprintf(formatString, baseURL, client.apiVersion, url.QueryEscape(q))
 	}
 
-	data, err := client.httpRequest("GET", u, nil)
+	data, err := client.httpRequestWithHeader("GET", u, nil, header)
 	if err != nil {
 		log.Println(logPrefix, "HTTP GET request failed:", u)
 		return nil, err
 	}
 
-	var result QueryResult
-	err = json.Unmarshal(data, &result)
+	result, err := client.decodeQueryResult(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +170,7 @@ func (client *Client) Query(q string) (*QueryResult, error) {
 		result.Records[idx].setClient(client)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // ApexREST executes a custom rest request with the provided method, path, and body. The path is relative to the domain.
@@ -112,7 +179,7 @@ func (client *Client) ApexREST(method, path string, requestBody io.Reader) ([]by
 		return nil, ErrAuthentication
 	}
 
-	u := fmt.Sprintf("%s/%s", client.instanceURL, path)
+	u := fmt.Sprintf("%s/%s", client.servicesURL(), path)
 
 	data, err := client.httpRequest(method, u, requestBody)
 	if err != nil {
@@ -139,9 +206,35 @@ func (client *Client) isLoggedIn() bool {
 }
 
 // LoginPassword signs into salesforce using password. token is optional if trusted IP is configured.
+// If salesforce rejects the login, a *LoginError is returned. Repeated failures suspend further logins of the username
+// for a while, see SetLoginGuard. If the login succeeds into a different org than expected, e.g. after a sandbox
+// refresh, the client is signed into the new org and an *OrgChangedError is returned, see SetExpectedOrgID.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/intro_understanding_username_password_oauth_flow.htm
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api.meta/api/sforce_api_calls_login.htm
 func (client *Client) LoginPassword(username, password, token string) error {
+	return client.guardedLogin(username, func() error {
+		return client.loginPassword(username, password, token, "")
+	})
+}
+
+// guardedLogin runs login for username unless logins of username are suspended, and records its outcome.
+func (client *Client) guardedLogin(username string, login func() error) error {
+	err := client.loginGuard.check(username)
+	if err != nil {
+		return err
+	}
+	err = login()
+	if errors.Is(err, ErrOrgChanged) {
+		// The credentials were accepted.
+		client.loginGuard.record(username, nil)
+	} else {
+		client.loginGuard.record(username, err)
+	}
+	return err
+}
+
+// loginPassword performs the SOAP login of LoginPassword, adding the SOAP header scopeHeader if not empty.
+func (client *Client) loginPassword(username, password, token, scopeHeader string) error {
 	// Use the SOAP interface to acquire session ID with username, password, and token.
 	// Do not use REST interface here as REST interface seems to have strong checking against client_id, while the SOAP
 	// interface allows a non-exist placeholder client_id to be used.
@@ -155,7 +248,7 @@ func (client *Client) LoginPassword(username, password, token string) error {
                 <urn:CallOptions>
                     <urn:client>%s</urn:client>
                     <urn:defaultNamespace>sf</urn:defaultNamespace>
-                </urn:CallOptions>
+                </urn:CallOptions>%s
             </env:Header>
             <env:Body>
                 <n1:login xmlns:n1="urn:partner.soap.sforce.com">
@@ -164,7 +257,7 @@ func (client *Client) LoginPassword(username, password, token string) error {
                 </n1:login>
             </env:Body>
         </env:Envelope>`
-	soapBody = fmt.Sprintf(soapBody, client.clientID, username, html.EscapeString(password), token)
+	soapBody = fmt.Sprintf(soapBody, client.clientID, scopeHeader, username, html.EscapeString(password), token)
 
 	url := fmt.Sprintf("%s/services/Soap/u/%s", client.baseURL, client.apiVersion)
 	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(soapBody))
@@ -176,7 +269,7 @@ func (client *Client) LoginPassword(username, password, token string) error {
 	req.Header.Add("charset", "UTF-8")
 	req.Header.Add("SOAPAction", "login")
 
-	resp, err := client.httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		log.Println(logPrefix, "error occurred submitting request,", err)
 		return err
@@ -189,8 +282,14 @@ func (client *Client) LoginPassword(username, password, token string) error {
 		buf.ReadFrom(resp.Body)
 		newStr := buf.String()
 		log.Println(logPrefix, "Failed resp.body: ", newStr)
-		theError := ParseSalesforceError(resp.StatusCode, buf.Bytes())
-		return theError
+		theError := parseLoginFault(resp.StatusCode, buf.Bytes())
+		if loginErr, ok := theError.(*LoginError); ok {
+			if sfErr, ok := client.withRequestContext(loginErr.Err, resp.Header).(SalesforceError); ok {
+				loginErr.Err = sfErr
+			}
+			return loginErr
+		}
+		return client.withRequestContext(theError, resp.Header)
 	}
 
 	respData, err := ioutil.ReadAll(resp.Body)
@@ -207,6 +306,7 @@ func (client *Client) LoginPassword(username, password, token string) error {
 		UserEmail    string   `xml:"Body>loginResponse>result>userInfo>userEmail"`
 		UserFullName string   `xml:"Body>loginResponse>result>userInfo>userFullName"`
 		UserName     string   `xml:"Body>loginResponse>result>userInfo>userName"`
+		OrgID        string   `xml:"Body>loginResponse>result>userInfo>organizationId"`
 	}
 
 	err = xml.Unmarshal(respData, &loginResponse)
@@ -216,15 +316,18 @@ func (client *Client) LoginPassword(username, password, token string) error {
 	}
 
 	// Now we should all be good and the sessionID can be used to talk to salesforce further.
+	previousOrgID := client.organizationID
 	client.sessionID = loginResponse.SessionID
 	client.instanceURL = parseHost(loginResponse.ServerURL)
+	client.sitePrefix = sitePrefixFromServerURL(loginResponse.ServerURL)
 	client.user.id = loginResponse.UserID
 	client.user.name = loginResponse.UserName
 	client.user.email = loginResponse.UserEmail
 	client.user.fullName = loginResponse.UserFullName
+	client.organizationID = loginResponse.OrgID
 
 	log.Println(logPrefix, "User", client.user.name, "authenticated.")
-	return nil
+	return client.checkLoginOrg(previousOrgID, loginResponse.OrgID)
 }
 
 // QueryMore fetches the next set of records using the NextRecordsURL from a previous query result.
@@ -234,7 +337,7 @@ func (client *Client) QueryMore(nextRecordsURL string) (*QueryResult, error) {
 	}
 
 	// Construct full URL
-	url := fmt.Sprintf("%s%s", client.instanceURL, nextRecordsURL)
+	url := client.resolveURL(nextRecordsURL)
 
 	// Send HTTP GET request using existing httpClient
 	req, err := http.NewRequest("GET", url, nil)
@@ -244,7 +347,7 @@ func (client *Client) QueryMore(nextRecordsURL string) (*QueryResult, error) {
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.sessionID))
 	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := client.httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -256,8 +359,11 @@ func (client *Client) QueryMore(nextRecordsURL string) (*QueryResult, error) {
 	}
 
 	// Decode JSON response into QueryResult
-	var result QueryResult
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	data, err := client.readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.decodeQueryResult(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -266,11 +372,16 @@ func (client *Client) QueryMore(nextRecordsURL string) (*QueryResult, error) {
 		result.Records[idx].setClient(client)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // httpRequest executes an HTTP request to the salesforce server and returns the response data in byte buffer.
 func (client *Client) httpRequest(method, url string, body io.Reader) ([]byte, error) {
+	return client.httpRequestWithHeader(method, url, body, nil)
+}
+
+// httpRequestWithHeader executes an HTTP request like httpRequest, adding header to the request.
+func (client *Client) httpRequestWithHeader(method, url string, body io.Reader, header http.Header) ([]byte, error) {
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
@@ -278,8 +389,19 @@ func (client *Client) httpRequest(method, url string, body io.Reader) ([]byte, e
 
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.sessionID))
 	req.Header.Add("Content-Type", "application/json")
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if client.acceptLanguage != "" && req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", client.acceptLanguage)
+	}
+	if client.migrating() && method != http.MethodGet && method != http.MethodHead {
+		setMigrationHeaders(req.Header)
+	}
 
-	resp, err := client.httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -292,19 +414,26 @@ func (client *Client) httpRequest(method, url string, body io.Reader) ([]byte, e
 		newStr := buf.String()
 		theError := ParseSalesforceError(resp.StatusCode, buf.Bytes())
 		log.Println(logPrefix, "Failed resp.body: ", newStr)
-		return nil, theError
+		return nil, client.withRequestContext(theError, resp.Header)
 	}
 
-	return ioutil.ReadAll(resp.Body)
+	return client.readResponse(resp)
 }
 
 // makeURL generates a REST API URL based on baseURL, APIVersion of the client.
 func (client *Client) makeURL(req string) string {
 	client.apiVersion = strings.Replace(client.apiVersion, "v", "", -1)
-	retURL := fmt.Sprintf("%s/services/data/v%s/%s", client.instanceURL, client.apiVersion, req)
+	retURL := fmt.Sprintf("%s/services/data/v%s/%s", client.servicesURL(), client.apiVersion, req)
 	return retURL
 }
 
+// Attach associates obj with the client, e.g. after it was decoded from JSON, so that it can be used for online
+// operations again. The same SObject is returned for chained access.
+func (client *Client) Attach(obj *SObject) *SObject {
+	obj.setClient(client)
+	return obj
+}
+
 // NewClient creates a new instance of the client.
 func NewClient(url, clientID, apiVersion string) *Client {
 	client := &Client{
@@ -312,6 +441,7 @@ func NewClient(url, clientID, apiVersion string) *Client {
 		baseURL:    url,
 		clientID:   clientID,
 		httpClient: &http.Client{},
+		loginGuard: newLoginGuard(),
 	}
 
 	// Remove trailing "/" from base url to prevent "//" when paths are appended
@@ -325,6 +455,75 @@ func (client *Client) SetHttpClient(c *http.Client) {
 	client.httpClient = c
 }
 
+// SetUseNumber makes the client decode numeric fields of records as json.Number instead of float64, preserving the
+// precision of e.g. currency values and large numbers. Use NumberField to read numeric fields in either mode.
+func (client *Client) SetUseNumber(useNumber bool) {
+	client.useNumber = useNumber
+}
+
+// decodeQueryResult decodes a query response, honoring SetUseNumber and SetCodec.
+func (client *Client) decodeQueryResult(r io.Reader) (*QueryResult, error) {
+	if client.jsonCodec != nil {
+		return client.decodeQueryResultWithCodec(r)
+	}
+
+	decoder := json.NewDecoder(r)
+	if !client.useNumber {
+		var result QueryResult
+		err := decoder.Decode(&result)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	// Records are decoded as plain maps, as SObject.UnmarshalJSON cannot see the decoder settings.
+	decoder.UseNumber()
+	var raw struct {
+		QueryResult
+		Records []map[string]interface{} `json:"records"`
+	}
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+	result := raw.QueryResult
+	result.Records = make([]SObject, 0, len(raw.Records))
+	for _, record := range raw.Records {
+		result.Records = append(result.Records, SObject(record))
+	}
+	return &result, nil
+}
+
+// decodeQueryResultWithCodec decodes a query response with the Codec of the client. Records are decoded as plain maps,
+// so the codec does not have to go through SObject.UnmarshalJSON.
+func (client *Client) decodeQueryResultWithCodec(r io.Reader) (*QueryResult, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		TotalSize      int                      `json:"totalSize"`
+		Done           bool                     `json:"done"`
+		NextRecordsURL string                   `json:"nextRecordsUrl"`
+		Records        []map[string]interface{} `json:"records"`
+	}
+	err = client.jsonCodec.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{
+		TotalSize:      raw.TotalSize,
+		Done:           raw.Done,
+		NextRecordsURL: raw.NextRecordsURL,
+		Records:        make([]SObject, 0, len(raw.Records)),
+	}
+	for _, record := range raw.Records {
+		result.Records = append(result.Records, SObject(record))
+	}
+	return result, nil
+}
+
 /*
 UploadFileToContentVersion uploads a file to Salesforce as a ContentVersion and relates it to a parent record.
 
@@ -356,18 +555,17 @@ func (client *Client) UploadFileToContentVersion(
 	parentRecordID string,
 	opts ...UploadOption,
 ) (contentVersionID string, contentDocumentID string, err error) {
-	// Read file
-	data, err := os.ReadFile(filePath)
+	// Open file, its content is base64 encoded as the request body is sent.
+	file, err := os.Open(filePath)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to read file: %w", err)
 	}
-	encoded := base64.StdEncoding.EncodeToString(data)
+	defer file.Close()
 	fileName := filepath.Base(filePath)
 
 	// Build ContentVersion SObject
 	cv := client.SObject("ContentVersion").
 		Set("PathOnClient", fileName).
-		Set("VersionData", encoded).
 		Set("FirstPublishLocationId", parentRecordID)
 
 	// Apply options
@@ -376,11 +574,10 @@ func (client *Client) UploadFileToContentVersion(
 	}
 
 	// Create ContentVersion
-	result := cv.Create()
-	if result == nil || result.ID() == "" {
-		return "", "", fmt.Errorf("failed to create ContentVersion")
+	contentVersionID, err = client.CreateWithBlob("ContentVersion", cv.makeCopy(), "VersionData", file)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create ContentVersion: %w", err)
 	}
-	contentVersionID = result.ID()
 
 	// Query for ContentDocumentId
 	q := fmt.Sprintf("SELECT ContentDocumentId FROM ContentVersion WHERE Id = '%s'", contentVersionID)
@@ -442,13 +639,12 @@ func (client *Client) DownloadLegacyFile(attachmentID string, filepath string) e
 
 func (client *Client) download(apiPath string, filepath string) error {
 	// Get the data
-	httpClient := client.httpClient
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", strings.TrimRight(client.instanceURL, "/"), apiPath), nil)
+	req, err := http.NewRequest("GET", client.resolveURL(apiPath), nil)
 	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Authorization", "Bearer "+client.sessionID)
 
-	resp, err := httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		return err
 	}
@@ -485,13 +681,12 @@ func (client *Client) DescribeGlobal() (*SObjectMeta, error) {
 	apiPath := fmt.Sprintf("/services/data/v%s/sobjects", client.apiVersion)
 	baseURL := strings.TrimRight(client.baseURL, "/")
 	url := fmt.Sprintf("%s%s", baseURL, apiPath) // Get the objects
-	httpClient := client.httpClient
 	req, err := http.NewRequest("GET", url, nil)
 	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Authorization", "Bearer "+client.sessionID)
 	// resp, err := http.Get(url)
-	resp, err := httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -499,13 +694,13 @@ func (client *Client) DescribeGlobal() (*SObjectMeta, error) {
 
 	var meta SObjectMeta
 
-	respData, err := ioutil.ReadAll(resp.Body)
+	respData, err := client.readResponse(resp)
 	log.Println(logPrefix, fmt.Sprintf("status code %d", resp.StatusCode))
 	if err != nil {
 		log.Println(logPrefix, "error while reading all body")
 	}
 
-	err = json.Unmarshal(respData, &meta)
+	err = client.unmarshalJSON(respData, &meta)
 	if err != nil {
 		return nil, err
 	}
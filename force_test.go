@@ -1,271 +1,183 @@
 package simpleforce
 
 import (
-	"log"
-	"os"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
 
-var (
-	sfUser  = os.ExpandEnv("${SF_USER}")
-	sfPass  = os.ExpandEnv("${SF_PASS}")
-	sfToken = os.ExpandEnv("${SF_TOKEN}")
-	sfURL   = func() string {
-		if os.ExpandEnv("${SF_URL}") != "" {
-			return os.ExpandEnv("${SF_URL}")
-		} else {
-			return DefaultURL
-		}
-	}()
-)
+// newStubClient returns a client logged in to a stub server serving handler.
+func newStubClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
 
-func checkCredentialsAndSkip(t *testing.T) {
-	if sfUser == "" || sfPass == "" {
-		log.Println(logPrefix, "SF_USER, SF_PASS environment variables are not set.")
-		t.Skip()
-	}
+	client := NewClient(server.URL+"/", DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	return client, server
 }
 
-func requireClient(t *testing.T, skippable bool) *Client {
-	if skippable {
-		checkCredentialsAndSkip(t)
+func TestNewClient(t *testing.T) {
+	client := NewClient("https://example.my.salesforce.com/", DefaultClientID, DefaultAPIVersion)
+	if client.baseURL != "https://example.my.salesforce.com" {
+		t.Errorf("trailing slash not removed: %s", client.baseURL)
 	}
-
-	client := NewClient(sfURL, DefaultClientID, DefaultAPIVersion)
-	if client == nil {
+	if client.isLoggedIn() {
 		t.Fail()
 	}
-	err := client.LoginPassword(sfUser, sfPass, sfToken)
-	if err != nil {
-		t.Fatal()
-	}
-	return client
 }
 
-func TestClient_LoginPassword(t *testing.T) {
-	checkCredentialsAndSkip(t)
-
-	client := NewClient(sfURL, DefaultClientID, DefaultAPIVersion)
-	if client == nil {
-		t.Fatal()
-	}
-
-	// Use token
-	err := client.LoginPassword(sfUser, sfPass, sfToken)
-	if err != nil {
-		t.Fail()
-	} else {
-		log.Println(logPrefix, "sessionID:", client.sessionID)
-	}
-
-	err = client.LoginPassword("__INVALID_USER__", "__INVALID_PASS__", "__INVALID_TOKEN__")
-	if err == nil {
-		t.Fail()
+func TestClient_makeURL(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, "v"+DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", "https://example.my.salesforce.com")
+	if u := client.makeURL("sobjects/Case/"); u != "https://example.my.salesforce.com/services/data/v54.0/sobjects/Case/" {
+		t.Errorf("unexpected URL %s", u)
 	}
 }
 
-func TestClient_LoginPasswordNoToken(t *testing.T) {
-	checkCredentialsAndSkip(t)
+func TestClient_QueryURL(t *testing.T) {
+	var paths, queries []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer __SESSION__" {
+			t.Errorf("missing session header")
+		}
+		paths = append(paths, r.URL.Path)
+		queries = append(queries, r.URL.Query().Get("q"))
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
 
-	client := NewClient(sfURL, DefaultClientID, DefaultAPIVersion)
-	if client == nil {
-		t.Fatal()
+	soql := "SELECT Id FROM Case WHERE Subject = 'a & b'"
+	if _, err := client.Query(soql); err != nil {
+		t.Fatal(err)
 	}
-
-	// Trusted IP must be configured AND the request must be initiated from the trusted IP range.
-	err := client.LoginPassword(sfUser, sfPass, "")
-	if err != nil {
-		t.FailNow()
-	} else {
-		log.Println(logPrefix, "sessionID:", client.sessionID)
+	if _, err := client.Query("/services/data/v54.0/query/01g-2000"); err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestClient_LoginOAuth(t *testing.T) {
-
-}
-
-func TestClient_Query(t *testing.T) {
-	client := requireClient(t, true)
-
-	q := "SELECT Id,LastModifiedById,LastModifiedDate,ParentId,CommentBody FROM CaseComment"
-	result, err := client.Query(q)
-	if err != nil {
-		log.Println(logPrefix, "query failed,", err)
-		t.FailNow()
+	if _, err := client.Tooling().Query(soql); err != nil {
+		t.Fatal(err)
 	}
+	client.UnTooling()
 
-	log.Println(logPrefix, result.TotalSize, result.Done, result.NextRecordsURL)
-	if result.TotalSize < 1 {
-		log.Println(logPrefix, "no records returned.")
-		t.FailNow()
+	expected := []string{"/services/data/v54.0/query", "/services/data/v54.0/query/01g-2000", "/services/data/v54.0/tooling/query"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected paths %v", paths)
 	}
-	for _, record := range result.Records {
-		if record.Type() != "CaseComment" {
-			t.Fail()
-		}
+	if queries[0] != soql || queries[2] != soql {
+		t.Errorf("query not escaped properly: %v", queries)
 	}
 }
 
-func TestClient_Query2(t *testing.T) {
-	client := requireClient(t, true)
+func TestClient_QueryPagination(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			w.Write([]byte(`{"totalSize": 2, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-1",
+				"records": [{"attributes": {"type": "Case"}, "Id": "500A"}]}`))
+			return
+		}
+		w.Write([]byte(`{"totalSize": 2, "done": true, "records": [{"attributes": {"type": "Case"}, "Id": "500B"}]}`))
+	})
 
-	q := "Select+id,createdbyid,parentid,parent.casenumber,parent.subject,createdby.name,createdby.alias+from+casecomment"
-	result, err := client.Query(q)
-	if err != nil {
-		t.FailNow()
+	result, err := client.Query("SELECT Id FROM Case")
+	if err != nil || result.Done || result.Records[0].ID() != "500A" {
+		t.Fatalf("unexpected first page %+v, %v", result, err)
 	}
-	if len(result.Records) > 0 {
-		comment1 := &result.Records[0]
-		case1 := comment1.SObjectField("Case", "Parent").Get()
-		if comment1.StringField("ParentId") != case1.ID() {
-			t.Fail()
-		}
+	result, err = client.QueryMore(result.NextRecordsURL)
+	if err != nil || !result.Done || result.Records[0].ID() != "500B" || result.Records[0].client() != client {
+		t.Fatalf("unexpected second page %+v, %v", result, err)
 	}
 }
 
-func TestClient_Query3(t *testing.T) {
-	client := requireClient(t, true)
-
-	q := "SELECT Id FROM CaseComment WHERE CommentBody = 'This comment is created by simpleforce & used for testing'"
-	result, err := client.Query(q)
-	if err != nil {
-		log.Println(logPrefix, "query failed,", err)
-		t.FailNow()
+func TestClient_QueryNotLoggedIn(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	if _, err := client.Query("SELECT Id FROM Case"); err != ErrAuthentication {
+		t.Errorf("expected ErrAuthentication, got %v", err)
 	}
-
-	log.Println(logPrefix, result.TotalSize, result.Done, result.NextRecordsURL)
-	if result.TotalSize < 1 {
-		log.Println(logPrefix, "no records returned.")
-		t.FailNow()
-	}
-	for _, record := range result.Records {
-		if record.Type() != "CaseComment" {
-			t.Fail()
-		}
+	if _, err := client.QueryMore("/services/data/v54.0/query/01g-1"); err != ErrAuthentication {
+		t.Errorf("expected ErrAuthentication, got %v", err)
 	}
 }
 
-func TestClient_ApexREST(t *testing.T) {
-	client := requireClient(t, true)
+func TestClient_QueryError(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`[{"message": "unexpected token: FORM", "errorCode": "MALFORMED_QUERY"}]`))
+	})
 
-	endpoint := "services/apexrest/my-custom-endpoint"
-	result, err := client.ApexREST(endpoint, "POST", strings.NewReader(`{"my-property": "my-value"}`))
-	if err != nil {
-		log.Println(logPrefix, "request failed,", err)
-		t.FailNow()
+	_, err := client.Query("SELECT Id FORM Case")
+	sfErr, ok := err.(SalesforceError)
+	if !ok || sfErr.HttpCode != http.StatusBadRequest || sfErr.ErrorCode != "MALFORMED_QUERY" {
+		t.Errorf("unexpected error %v", err)
 	}
-
-	log.Println(logPrefix, string(result))
 }
 
-func TestClient_QueryLike(t *testing.T) {
-	client := requireClient(t, true)
-
-	q := "Select Id, createdby.name, subject from case where subject like '%simpleforce%'"
-	result, err := client.Query(q)
-	if err != nil {
-		t.FailNow()
-	}
-	if len(result.Records) > 0 {
-		case0 := &result.Records[0]
-		if !strings.Contains(case0.StringField("Subject"), "simpleforce") {
-			t.FailNow()
+func TestClient_ApexRESTPath(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/apexrest/my-custom-endpoint" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
 		}
-	}
-}
-
-func TestClient_UploadFileToContentVersion(t *testing.T) {
-	client := requireClient(t, true)
-
-	// Create a temporary file
-	tmpFile, err := os.CreateTemp("", "simpleforce_testfile_*.txt")
-	if err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-	defer os.Remove(tmpFile.Name())
-	content := []byte("This is a test file for Salesforce upload.")
-	if _, err := tmpFile.Write(content); err != nil {
-		t.Fatalf("failed to write to temp file: %v", err)
-	}
-	tmpFile.Close()
-
-	// Use a valid Salesforce record ID for FirstPublishLocationId (replace with a real one for integration)
-	// For test purposes, we use an invalid ID to check error handling
-	invalidParentID := "001INVALIDID"
-	_, _, err = client.UploadFileToContentVersion(tmpFile.Name(), invalidParentID)
-	if err == nil {
-		t.Error("expected error for invalid parent ID, got nil")
-	}
+		w.Write([]byte(`"ok"`))
+	})
 
-	// Skip actual upload if no valid parent ID is available
-	validParentID := os.Getenv("SF_TEST_PARENT_ID")
-	if validParentID == "" {
-		t.Skip("SF_TEST_PARENT_ID not set; skipping real upload test")
+	result, err := client.ApexREST(http.MethodPost, "services/apexrest/my-custom-endpoint", strings.NewReader(`{}`))
+	if err != nil || string(result) != `"ok"` {
+		t.Errorf("unexpected result %s, %v", result, err)
 	}
+}
 
-	cvID, cdID, err := client.UploadFileToContentVersion(
-		tmpFile.Name(),
-		validParentID,
-		WithTitle("Test File"),
-		WithDescription("Uploaded by simpleforce test"),
-	)
-	if err != nil {
-		t.Fatalf("upload failed: %v", err)
-	}
-	if cvID == "" || cdID == "" {
-		t.Errorf("expected non-empty IDs, got cvID=%q, cdID=%q", cvID, cdID)
+func TestClient_LoginPasswordStub(t *testing.T) {
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/Soap/u/54.0" || r.Header.Get("SOAPAction") != "login" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com">
+				<soapenv:Body><loginResponse><result>
+					<serverUrl>https://example.my.salesforce.com/services/Soap/u/54.0/00D000000000001</serverUrl>
+					<sessionId>__NEW_SESSION__</sessionId>
+					<userId>005000000000001AAA</userId>
+					<userInfo><userName>user@example.com</userName></userInfo>
+				</result></loginResponse></soapenv:Body>
+			</soapenv:Envelope>`))
+	})
+	client.SetSidLoc("", "")
+	client.baseURL = server.URL
+
+	if err := client.LoginPassword("user@example.com", "pass<word>", ""); err != nil {
+		t.Fatal(err)
+	}
+	if client.GetSid() != "__NEW_SESSION__" || client.GetLoc() != "https://example.my.salesforce.com" ||
+		client.user.name != "user@example.com" {
+		t.Errorf("unexpected session %s %s", client.GetSid(), client.GetLoc())
 	}
 }
 
-func TestClient_DownloadLegacyFile(t *testing.T) {
-	client := requireClient(t, true)
+func TestClient_SetUseNumber(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+			{"attributes": {"type": "Opportunity"}, "Id": "006A", "Amount": 12345678901234567.89, "Probability": 10}]}`))
+	})
 
-	// Test with an invalid Attachment ID (should error)
-	invalidAttachmentID := "00PINVALIDID"
-	tmpFile, err := os.CreateTemp("", "simpleforce_legacyfile_*.bin")
+	result, err := client.Query("SELECT Id, Amount FROM Opportunity")
 	if err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-	tmpFilePath := tmpFile.Name()
-	tmpFile.Close()
-	defer os.Remove(tmpFilePath)
-
-	err = client.DownloadLegacyFile(invalidAttachmentID, tmpFilePath)
-	if err == nil {
-		t.Error("expected error for invalid attachment ID, got nil")
+		t.Fatal(err)
 	}
-
-	// Test with a valid Attachment ID if provided
-	validAttachmentID := os.Getenv("SF_TEST_ATTACHMENT_ID")
-	if validAttachmentID == "" {
-		t.Skip("SF_TEST_ATTACHMENT_ID not set; skipping real legacy file download test")
+	if _, ok := result.Records[0].InterfaceField("Amount").(float64); !ok {
+		t.Error("expected float64 by default")
 	}
-
-	realTmpFile, err := os.CreateTemp("", "simpleforce_legacyfile_real_*.bin")
-	if err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
+	if result.Records[0].NumberField("Probability") != "10" || result.Records[0].NumberField("Id") != "" {
+		t.Fail()
 	}
-	realTmpFilePath := realTmpFile.Name()
-	realTmpFile.Close()
-	defer os.Remove(realTmpFilePath)
 
-	err = client.DownloadLegacyFile(validAttachmentID, realTmpFilePath)
+	client.SetUseNumber(true)
+	result, err = client.Query("SELECT Id, Amount FROM Opportunity")
 	if err != nil {
-		t.Fatalf("download failed: %v", err)
+		t.Fatal(err)
 	}
-
-	// Check that the file exists and is non-empty
-	info, err := os.Stat(realTmpFilePath)
-	if err != nil {
-		t.Fatalf("downloaded file not found: %v", err)
+	if amount := result.Records[0].NumberField("Amount"); amount != "12345678901234567.89" {
+		t.Errorf("precision lost: %s", amount)
 	}
-	if info.Size() == 0 {
-		t.Error("downloaded file is empty")
+	result, err = client.QueryMore("/services/data/v54.0/query/01g-1")
+	if err != nil || result.Records[0].NumberField("Amount") != "12345678901234567.89" {
+		t.Errorf("precision lost in QueryMore: %v", err)
 	}
 }
-
-func TestMain(m *testing.M) {
-	m.Run()
-}
@@ -0,0 +1,125 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrAPIVersionUnavailable is returned by HealthCheck when the org does not serve the API version of the client.
+var ErrAPIVersionUnavailable = errors.New("api version unavailable")
+
+// APIVersion is a version of the REST API served by the org.
+type APIVersion struct {
+	Label   string `json:"label"`
+	URL     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// Limit is the maximum and remaining allocation of an org limit, e.g. "DailyApiRequests".
+type Limit struct {
+	Max       int `json:"Max"`
+	Remaining int `json:"Remaining"`
+}
+
+// HealthReport is the outcome of HealthCheck.
+type HealthReport struct {
+	CheckedAt           time.Time
+	Latency             time.Duration // round-trip time of an authenticated request
+	APIVersion          string        // API version of the client
+	APIVersionAvailable bool
+	LatestAPIVersion    string
+	Limits              map[string]Limit
+}
+
+// LowLimits returns the names of the limits with less than fraction, e.g. 0.1, of their maximum remaining, sorted.
+func (report *HealthReport) LowLimits(fraction float64) []string {
+	var names []string
+	for name, limit := range report.Limits {
+		if limit.Max > 0 && float64(limit.Remaining) < fraction*float64(limit.Max) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APIVersions returns the REST API versions served by the org, oldest first.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_versions.htm
+func (client *Client) APIVersions() ([]APIVersion, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.servicesURL()+"/services/data/", nil)
+	if err != nil {
+		return nil, err
+	}
+	var versions []APIVersion
+	err = client.unmarshalJSON(data, &versions)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Limits returns the org limits by name, e.g. "DailyApiRequests". Limits of connected apps are not included.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_limits.htm
+func (client *Client) Limits() (map[string]Limit, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	// Do not use makeURL here as Limits may be called concurrently, e.g. by readiness probes.
+	u := fmt.Sprintf("%s/services/data/v%s/limits/", client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"))
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var limits map[string]Limit
+	err = client.unmarshalJSON(data, &limits)
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// HealthCheck checks that the org serves the API version of the client, validates the session while measuring the
+// round-trip latency, and fetches the org limits, e.g. for the readiness probe of a service. The report is returned
+// along with the first failure, ErrAPIVersionUnavailable if the API version is not served.
+func (client *Client) HealthCheck() (*HealthReport, error) {
+	report := &HealthReport{
+		CheckedAt:  time.Now(),
+		APIVersion: strings.TrimPrefix(client.apiVersion, "v"),
+	}
+
+	versions, err := client.APIVersions()
+	if err != nil {
+		return report, err
+	}
+	for _, version := range versions {
+		if version.Version == report.APIVersion {
+			report.APIVersionAvailable = true
+		}
+	}
+	if len(versions) > 0 {
+		report.LatestAPIVersion = versions[len(versions)-1].Version
+	}
+	if !report.APIVersionAvailable {
+		return report, errors.Wrapf(ErrAPIVersionUnavailable, "%s, latest is %s", report.APIVersion, report.LatestAPIVersion)
+	}
+
+	start := time.Now()
+	err = client.Ping()
+	report.Latency = time.Since(start)
+	if err != nil {
+		return report, err
+	}
+
+	report.Limits, err = client.Limits()
+	return report, err
+}
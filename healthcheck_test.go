@@ -0,0 +1,68 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_HealthCheck(t *testing.T) {
+	versions := `[{"label": "Spring '22", "url": "/services/data/v54.0", "version": "54.0"},
+		{"label": "Summer '22", "url": "/services/data/v55.0", "version": "55.0"}]`
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/":
+			w.Write([]byte(versions))
+		case "/services/data/v54.0/":
+			w.Write([]byte(`{"limits": "/services/data/v54.0/limits"}`))
+		case "/services/data/v54.0/limits/":
+			w.Write([]byte(`{"DailyApiRequests": {"Max": 15000, "Remaining": 1000,
+				"Ant Migration Tool": {"Max": 0, "Remaining": 0}},
+				"DataStorageMB": {"Max": 5, "Remaining": 5}, "HourlyODataCallout": {"Max": 0, "Remaining": 0}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	report, err := client.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.APIVersionAvailable || report.LatestAPIVersion != "55.0" || report.Latency <= 0 || report.CheckedAt.IsZero() {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if limit := report.Limits["DailyApiRequests"]; limit.Max != 15000 || limit.Remaining != 1000 {
+		t.Errorf("unexpected limit %+v", limit)
+	}
+	if low := report.LowLimits(0.1); len(low) != 1 || low[0] != "DailyApiRequests" {
+		t.Errorf("unexpected low limits %v", low)
+	}
+
+	versions = `[{"label": "Winter '22", "url": "/services/data/v53.0", "version": "53.0"}]`
+	report, err = client.HealthCheck()
+	if !errors.Is(err, ErrAPIVersionUnavailable) || report.APIVersionAvailable || report.LatestAPIVersion != "53.0" {
+		t.Errorf("expected the API version to be unavailable, got %+v, %v", report, err)
+	}
+}
+
+func TestClient_HealthCheckSession(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/data/" {
+			w.Write([]byte(`[{"version": "54.0"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`))
+	})
+
+	report, err := client.HealthCheck()
+	if err == nil || report.Latency <= 0 || report.Limits != nil {
+		t.Errorf("expected the session to be rejected, got %+v, %v", report, err)
+	}
+
+	client = NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	if _, err = client.HealthCheck(); err != ErrAuthentication {
+		t.Errorf("expected ErrAuthentication, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package simpleforce
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SetHostOverride sends the requests of the client whose path starts with pathPrefix, e.g. "/services/apexrest/", to
+// hostURL, e.g. "https://acme.my.site.com", instead of the instance URL, for endpoints only served from specific
+// domains. The longest matching prefix wins. The session and the rest of the URL are kept. An empty hostURL removes
+// the override of pathPrefix.
+func (client *Client) SetHostOverride(pathPrefix, hostURL string) error {
+	client.hostMu.Lock()
+	defer client.hostMu.Unlock()
+	if hostURL == "" {
+		delete(client.hostOverrides, pathPrefix)
+		return nil
+	}
+
+	host, err := parseHostURL(hostURL)
+	if err != nil {
+		return err
+	}
+	if client.hostOverrides == nil {
+		client.hostOverrides = make(map[string]*url.URL)
+	}
+	client.hostOverrides[pathPrefix] = host
+	return nil
+}
+
+// ApexRESTOnHost executes a custom rest request like ApexREST, sending it to hostURL instead of the instance URL.
+func (client *Client) ApexRESTOnHost(hostURL, method, path string, requestBody io.Reader) ([]byte, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	host, err := parseHostURL(hostURL)
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s://%s/%s", host.Scheme, host.Host, strings.TrimPrefix(path, "/"))
+	data, err := client.httpRequest(method, u, requestBody)
+	if err != nil {
+		log.Println(logPrefix, fmt.Sprintf("HTTP %s request failed:", method), u)
+		return nil, err
+	}
+	return data, nil
+}
+
+// parseHostURL parses the scheme and host of hostURL, e.g. "https://acme.lightning.force.com".
+func parseHostURL(hostURL string) (*url.URL, error) {
+	host, err := url.Parse(hostURL)
+	if err != nil {
+		return nil, err
+	}
+	if host.Scheme == "" || host.Host == "" {
+		return nil, fmt.Errorf("invalid host URL %q", hostURL)
+	}
+	return &url.URL{Scheme: host.Scheme, Host: host.Host}, nil
+}
+
+// overrideHost redirects req to the host overriding its path, if it is addressed to the instance of the client.
+func (client *Client) overrideHost(req *http.Request) {
+	client.hostMu.RLock()
+	defer client.hostMu.RUnlock()
+	if len(client.hostOverrides) == 0 {
+		return
+	}
+	instance, err := url.Parse(client.instanceURL)
+	if err != nil || req.URL.Host != instance.Host {
+		return
+	}
+
+	var match string
+	var host *url.URL
+	for prefix, override := range client.hostOverrides {
+		if strings.HasPrefix(req.URL.Path, prefix) && len(prefix) >= len(match) {
+			match, host = prefix, override
+		}
+	}
+	if host != nil {
+		req.URL.Scheme, req.URL.Host, req.Host = host.Scheme, host.Host, host.Host
+	}
+}
@@ -0,0 +1,49 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_SetHostOverride(t *testing.T) {
+	var instanceRequests, siteRequests []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		instanceRequests = append(instanceRequests, r.URL.Path)
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer __SESSION__" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		siteRequests = append(siteRequests, r.URL.Path)
+		w.Write([]byte(`"ok"`))
+	}))
+	defer site.Close()
+
+	if err := client.SetHostOverride("/services/apexrest/", site.URL); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetHostOverride("/services/apexrest/", "not a URL"); err == nil {
+		t.Error("expected an error for an invalid host")
+	}
+
+	if data, err := client.ApexREST(http.MethodGet, "services/apexrest/hello", nil); err != nil || string(data) != `"ok"` {
+		t.Errorf("unexpected result %s, %v", data, err)
+	}
+	if _, err := client.Query("SELECT Id FROM Account"); err != nil {
+		t.Fatal(err)
+	}
+	client.SetHostOverride("/services/apexrest/", "")
+	client.ApexREST(http.MethodGet, "services/apexrest/bye", nil)
+	if _, err := client.ApexRESTOnHost(site.URL+"/ignored", http.MethodPost, "/services/apexrest/once", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(siteRequests) != 2 || siteRequests[0] != "/services/apexrest/hello" || siteRequests[1] != "/services/apexrest/once" {
+		t.Errorf("unexpected site requests %v", siteRequests)
+	}
+	if len(instanceRequests) != 2 || instanceRequests[1] != "/services/apexrest/bye" {
+		t.Errorf("unexpected instance requests %v", instanceRequests)
+	}
+}
@@ -0,0 +1,69 @@
+package simpleforce
+
+import (
+	"fmt"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// CreateOnce creates obj unless a record of its type with the idempotency token in tokenField already exists, so
+// at-least-once message consumers can retry creates without duplicating records. token is stored in tokenField of
+// the new record, e.g. the ID of the message the record is created from. The ID of the new or existing record is set
+// on obj and returned; created tells whether obj was inserted. An existing record is not modified.
+//
+// The check is a query before the insert, which races with concurrent creates of the same token. Use a field which is
+// unique, ideally an external ID, to rule out duplicates: an insert rejected with DUPLICATE_VALUE resolves to the
+// record created concurrently.
+func (client *Client) CreateOnce(obj *SObject, tokenField, token string) (id string, created bool, err error) {
+	if obj.Type() == "" || tokenField == "" || token == "" {
+		return "", false, fmt.Errorf("create once requires type, token field and token")
+	}
+
+	id, err = client.findByToken(obj.Type(), tokenField, token)
+	if err != nil || id != "" {
+		if id != "" {
+			obj.setID(id)
+		}
+		return id, false, err
+	}
+
+	obj.Set(tokenField, token)
+	results, err := client.createCollection([]*SObject{obj}, false)
+	if err != nil {
+		return "", false, err
+	}
+	if len(results) != 1 {
+		return "", false, fmt.Errorf("expected 1 result, got %d", len(results))
+	}
+	result := results[0]
+	if result.Success {
+		obj.setID(result.ID)
+		return result.ID, true, nil
+	}
+
+	for _, saveErr := range result.Errors {
+		if saveErr.StatusCode != errcode.DuplicateValue {
+			continue
+		}
+		// The token was created concurrently.
+		id, err = client.findByToken(obj.Type(), tokenField, token)
+		if err == nil && id != "" {
+			obj.setID(id)
+			return id, false, nil
+		}
+	}
+	return "", false, &RecordError{Index: 0, Errors: result.Errors}
+}
+
+// findByToken returns the ID of the record of objectType with token in tokenField, or an empty string if there is none.
+func (client *Client) findByToken(objectType, tokenField, token string) (string, error) {
+	soql := fmt.Sprintf("SELECT Id FROM %s WHERE %s = %s LIMIT 1", objectType, tokenField, QuoteSOQL(token))
+	result, err := client.Query(soql)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", nil
+	}
+	return result.Records[0].ID(), nil
+}
@@ -0,0 +1,63 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestClient_CreateOnce(t *testing.T) {
+	existing := map[string]string{"msg-1": "003OLD"}
+	concurrent := map[string]bool{"msg-3": true}
+	creates := 0
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			q := r.URL.Query().Get("q")
+			var records []map[string]interface{}
+			for token, id := range existing {
+				if q == "SELECT Id FROM Contact WHERE Message_ID__c = '"+token+"' LIMIT 1" {
+					records = append(records, map[string]interface{}{"attributes": map[string]string{"type": "Contact"}, "Id": id})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"totalSize": len(records), "done": true, "records": records})
+			return
+		}
+
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		token, _ := body.Records[0]["Message_ID__c"].(string)
+		if concurrent[token] {
+			existing[token] = "003RACE"
+			w.Write([]byte(`[{"success": false, "errors": [{"statusCode": "DUPLICATE_VALUE",
+				"message": "duplicate value found: Message_ID__c", "fields": []}]}]`))
+			return
+		}
+		creates++
+		existing[token] = "003NEW"
+		w.Write([]byte(`[{"id": "003NEW", "success": true, "errors": []}]`))
+	})
+
+	contact := client.SObject("Contact").Set("LastName", "Smith")
+	id, created, err := client.CreateOnce(contact, "Message_ID__c", "msg-1")
+	if err != nil || created || id != "003OLD" || contact.ID() != "003OLD" {
+		t.Errorf("expected the existing record, got %s, %v, %v", id, created, err)
+	}
+
+	contact = client.SObject("Contact").Set("LastName", "Jones")
+	id, created, err = client.CreateOnce(contact, "Message_ID__c", "msg-2")
+	if err != nil || !created || id != "003NEW" || contact.StringField("Message_ID__c") != "msg-2" {
+		t.Errorf("expected a new record, got %s, %v, %v", id, created, err)
+	}
+	// A retry of the same message finds the record created before.
+	id, created, err = client.CreateOnce(client.SObject("Contact").Set("LastName", "Jones"), "Message_ID__c", "msg-2")
+	if err != nil || created || id != "003NEW" || creates != 1 {
+		t.Errorf("expected the retry to find the record, got %s, %v, %v", id, created, err)
+	}
+
+	id, created, err = client.CreateOnce(client.SObject("Contact").Set("LastName", "Brown"), "Message_ID__c", "msg-3")
+	if err != nil || created || id != "003RACE" {
+		t.Errorf("expected the concurrently created record, got %s, %v, %v", id, created, err)
+	}
+}
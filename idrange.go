@@ -0,0 +1,135 @@
+package simpleforce
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// base62Digits are the characters of salesforce IDs in ascending order, which is also their order in SOQL.
+const base62Digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// IDRange is a range of record IDs, From inclusive and To exclusive. An empty bound is unbounded.
+type IDRange struct {
+	From string
+	To   string
+}
+
+// Condition returns the SOQL condition selecting the IDs of the range, or an empty string if it is unbounded.
+func (r IDRange) Condition() string {
+	var conditions []string
+	if r.From != "" {
+		conditions = append(conditions, fmt.Sprintf("Id >= '%s'", r.From))
+	}
+	if r.To != "" {
+		conditions = append(conditions, fmt.Sprintf("Id < '%s'", r.To))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// IDRanges divides the records of objectType matching the SOQL condition where (which may be empty) into up to n
+// non-overlapping ranges of IDs, so that a huge extraction can be run as n queries in parallel. The lowest and highest
+// matching IDs are queried and the range between is split evenly using the base-62 ordering of IDs, so the ranges
+// hold similar numbers of records if IDs are spread evenly. The first and last ranges are unbounded below and above. No
+// ranges are returned if no record matches.
+func (client *Client) IDRanges(objectType, where string, n int) ([]IDRange, error) {
+	if n < 1 {
+		n = 1
+	}
+	first, err := client.boundaryID(objectType, where, "ASC")
+	if err != nil || first == "" {
+		return nil, err
+	}
+	last, err := client.boundaryID(objectType, where, "DESC")
+	if err != nil || last == "" {
+		return nil, err
+	}
+
+	// All records of an object share the key prefix, only the remaining characters of the 15 character ID are split.
+	prefix := first[:3]
+	low, high := decodeBase62(first[3:15]), decodeBase62(last[3:15])
+	span := new(big.Int).Sub(high, low)
+
+	ranges := []IDRange{{}}
+	for i := 1; i < n; i++ {
+		step := new(big.Int).Mul(span, big.NewInt(int64(i)))
+		step.Div(step, big.NewInt(int64(n)))
+		boundary := prefix + encodeBase62(step.Add(step, low), 12)
+		// Skip empty ranges when there are fewer IDs than ranges.
+		if boundary <= first[:15] || boundary == ranges[len(ranges)-1].From {
+			continue
+		}
+		ranges[len(ranges)-1].To = boundary
+		ranges = append(ranges, IDRange{From: boundary})
+	}
+	return ranges, nil
+}
+
+// SplitQueryByID builds the queries selecting fields of the records of objectType matching where, one per range
+// returned by IDRanges.
+func (client *Client) SplitQueryByID(fields []string, objectType, where string, n int) ([]string, error) {
+	ranges, err := client.IDRanges(objectType, where, n)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		conditions := r.Condition()
+		if where != "" && conditions != "" {
+			conditions = "(" + where + ") AND " + conditions
+		} else if where != "" {
+			conditions = where
+		}
+		soql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), objectType)
+		if conditions != "" {
+			soql += " WHERE " + conditions
+		}
+		queries = append(queries, soql)
+	}
+	return queries, nil
+}
+
+// boundaryID returns the lowest or highest ID of the records matching where, depending on order, or an empty string.
+func (client *Client) boundaryID(objectType, where, order string) (string, error) {
+	soql := "SELECT Id FROM " + objectType
+	if where != "" {
+		soql += " WHERE " + where
+	}
+	result, err := client.Query(soql + " ORDER BY Id " + order + " LIMIT 1")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", nil
+	}
+	id := result.Records[0].ID()
+	if len(id) < 15 {
+		return "", fmt.Errorf("invalid ID %q", id)
+	}
+	return id, nil
+}
+
+// decodeBase62 converts the characters of an ID to a number.
+func decodeBase62(s string) *big.Int {
+	value := new(big.Int)
+	base := big.NewInt(int64(len(base62Digits)))
+	for _, c := range s {
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(strings.IndexRune(base62Digits, c))))
+	}
+	return value
+}
+
+// encodeBase62 converts a number to ID characters, left-padded to width.
+func encodeBase62(value *big.Int, width int) string {
+	digits := make([]byte, width)
+	base := big.NewInt(int64(len(base62Digits)))
+	rest := new(big.Int).Set(value)
+	mod := new(big.Int)
+	for idx := width - 1; idx >= 0; idx-- {
+		rest.DivMod(rest, base, mod)
+		digits[idx] = base62Digits[mod.Int64()]
+	}
+	return string(digits)
+}
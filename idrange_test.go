@@ -0,0 +1,55 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_IDRanges(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if !strings.HasPrefix(q, "SELECT Id FROM Account WHERE Industry = 'Energy' ORDER BY Id") {
+			t.Errorf("unexpected query %s", q)
+		}
+		id := "001000000000000AAA"
+		if strings.Contains(q, "DESC") {
+			id = "00100000000000zAAA"
+		}
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "` + id + `"}]}`))
+	})
+
+	ranges, err := client.IDRanges("Account", "Industry = 'Energy'", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// z is the 61st digit, so the midpoint is the 30th digit, U.
+	if len(ranges) != 2 || ranges[0].From != "" || ranges[0].To != "00100000000000U" || ranges[1].From != "00100000000000U" ||
+		ranges[1].To != "" {
+		t.Fatalf("unexpected ranges %+v", ranges)
+	}
+
+	queries, err := client.SplitQueryByID([]string{"Id", "Name"}, "Account", "Industry = 'Energy'", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 ||
+		queries[0] != "SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id < '00100000000000U'" ||
+		queries[1] != "SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id >= '00100000000000U'" {
+		t.Errorf("unexpected queries %v", queries)
+	}
+
+	// There are fewer IDs than requested ranges.
+	ranges, err = client.IDRanges("Account", "Industry = 'Energy'", 100)
+	if err != nil || len(ranges) != 61 {
+		t.Errorf("unexpected ranges %d, %v", len(ranges), err)
+	}
+}
+
+func TestBase62(t *testing.T) {
+	for _, s := range []string{"000000000000", "00000000000z", "0Ab9zZ000001", "zzzzzzzzzzzz"} {
+		if encoded := encodeBase62(decodeBase62(s), 12); encoded != s {
+			t.Errorf("round trip of %s returned %s", s, encoded)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Kinds of jobs recorded in a JobStore.
+const (
+	JobBulkQuery = "BulkQuery"
+	JobDeploy    = "Deploy"
+)
+
+// JobRecord is a long-running job in salesforce started by the client, as recorded in a JobStore.
+type JobRecord struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`              // JobBulkQuery or JobDeploy
+	State   string    `json:"state"`             // the last state seen, e.g. BulkJobInProgress or "InProgress"
+	Query   string    `json:"query,omitempty"`   // the SOQL of bulk queries
+	Locator string    `json:"locator,omitempty"` // the locator of the first page of bulk query results not processed
+	Updated time.Time `json:"updated"`
+}
+
+// JobStore persists the jobs of a client, so that a process restarted while jobs were running can resume them instead
+// of leaving them orphaned, see SetJobStore. Implementations must be safe for concurrent use.
+type JobStore interface {
+	// SaveJob adds or replaces the record of job.ID.
+	SaveJob(job JobRecord) error
+	// DeleteJob removes the record of the job id, which may not exist.
+	DeleteJob(id string) error
+	// Jobs lists the recorded jobs.
+	Jobs() ([]JobRecord, error)
+}
+
+// SetJobStore makes the client record the bulk queries run with BulkQueryEach and the deployments started with
+// DeployMetadata in store while they are running, or while their results are processed. After a restart, list them
+// with PendingJobs and continue them with ResumeBulkQuery and WatchDeploy. Failures of the store are logged and do not
+// fail the jobs. A nil store disables recording.
+func (client *Client) SetJobStore(store JobStore) {
+	client.jobStore = store
+}
+
+// PendingJobs returns the jobs recorded in the job store, oldest first, or nothing if no store is set.
+func (client *Client) PendingJobs() ([]JobRecord, error) {
+	if client.jobStore == nil {
+		return nil, nil
+	}
+	jobs, err := client.jobStore.Jobs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Updated.Before(jobs[j].Updated)
+	})
+	return jobs, nil
+}
+
+// ForgetJob removes the job id from the job store, e.g. after giving up on resuming it.
+func (client *Client) ForgetJob(id string) error {
+	if client.jobStore == nil {
+		return nil
+	}
+	return client.jobStore.DeleteJob(id)
+}
+
+// ResumeBulkQuery continues the bulk query job like BulkQueryEach: it waits for the job to complete and calls fn with
+// the rows of the results from the first page not processed before. Rows of a page which was being processed when the
+// process stopped are passed again.
+func (client *Client) ResumeBulkQuery(ctx context.Context, job JobRecord, fn func(header, row []string) error) error {
+	if job.Kind != JobBulkQuery {
+		return errors.Errorf("job %s is a %s job", job.ID, job.Kind)
+	}
+	return client.consumeBulkQuery(ctx, job, fn)
+}
+
+// saveJob records job in the job store, if any.
+func (client *Client) saveJob(job JobRecord) {
+	if client.jobStore == nil {
+		return
+	}
+	job.Updated = time.Now()
+	if err := client.jobStore.SaveJob(job); err != nil {
+		log.Println(logPrefix, "failed to record job", job.ID, err)
+	}
+}
+
+// deleteJob removes the job id from the job store, if any.
+func (client *Client) deleteJob(id string) {
+	if client.jobStore == nil {
+		return
+	}
+	if err := client.jobStore.DeleteJob(id); err != nil {
+		log.Println(logPrefix, "failed to remove job", id, err)
+	}
+}
+
+// FileJobStore is a JobStore keeping the jobs in a JSON file, which is replaced atomically on every change.
+type FileJobStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileJobStore returns a FileJobStore keeping the jobs in the file path, which is created when the first job is
+// saved.
+func NewFileJobStore(path string) *FileJobStore {
+	return &FileJobStore{path: path}
+}
+
+// SaveJob implements JobStore.
+func (store *FileJobStore) SaveJob(job JobRecord) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	jobs, err := store.read()
+	if err != nil {
+		return err
+	}
+	jobs[job.ID] = job
+	return store.write(jobs)
+}
+
+// DeleteJob implements JobStore.
+func (store *FileJobStore) DeleteJob(id string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	jobs, err := store.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := jobs[id]; !ok {
+		return nil
+	}
+	delete(jobs, id)
+	return store.write(jobs)
+}
+
+// Jobs implements JobStore.
+func (store *FileJobStore) Jobs() ([]JobRecord, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	jobs, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]JobRecord, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job)
+	}
+	return list, nil
+}
+
+func (store *FileJobStore) read() (map[string]JobRecord, error) {
+	jobs := make(map[string]JobRecord)
+	data, err := ioutil.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return jobs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", store.path)
+	}
+	return jobs, nil
+}
+
+func (store *FileJobStore) write(jobs map[string]JobRecord) error {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(store.path), filepath.Base(store.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), store.path)
+}
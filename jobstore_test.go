@@ -0,0 +1,102 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_ResumeBulkQuery(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/jobs/query":
+			w.Write([]byte(`{"id": "750A", "state": "UploadComplete"}`))
+		case "/services/data/v54.0/jobs/query/750A":
+			w.Write([]byte(`{"id": "750A", "state": "JobComplete"}`))
+		case "/services/data/v54.0/jobs/query/750A/results":
+			switch r.URL.Query().Get("locator") {
+			case "":
+				w.Header().Set("Sforce-Locator", "MTAwMDA")
+				w.Write([]byte("\"Id\"\n\"001A\"\n"))
+			case "MTAwMDA":
+				w.Header().Set("Sforce-Locator", "null")
+				w.Write([]byte("\"Id\"\n\"001B\"\n"))
+			default:
+				t.Errorf("unexpected locator %s", r.URL.Query().Get("locator"))
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	// The first process stops while processing the second page.
+	client, _ := newStubClient(t, handler)
+	client.SetJobStore(NewFileJobStore(path))
+	stopped := errors.New("stopped")
+	var ids []string
+	err := client.BulkQueryEach(context.Background(), "SELECT Id FROM Account", func(header, row []string) error {
+		if row[0] == "001B" {
+			return stopped
+		}
+		ids = append(ids, row[0])
+		return nil
+	})
+	if err != stopped {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+
+	client, _ = newStubClient(t, handler)
+	client.SetJobStore(NewFileJobStore(path))
+	jobs, err := client.PendingJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "750A" || jobs[0].Kind != JobBulkQuery || jobs[0].State != BulkJobComplete ||
+		jobs[0].Query != "SELECT Id FROM Account" || jobs[0].Locator != "MTAwMDA" {
+		t.Fatalf("unexpected pending jobs %+v", jobs)
+	}
+	err = client.ResumeBulkQuery(context.Background(), jobs[0], func(header, row []string) error {
+		ids = append(ids, row[0])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "001A,001B" {
+		t.Errorf("unexpected IDs %v", ids)
+	}
+	if jobs, _ = client.PendingJobs(); len(jobs) != 0 {
+		t.Errorf("expected the job to be removed, got %+v", jobs)
+	}
+}
+
+func TestFileJobStore(t *testing.T) {
+	store := NewFileJobStore(filepath.Join(t.TempDir(), "jobs.json"))
+	if jobs, err := store.Jobs(); err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no jobs, got %v %v", jobs, err)
+	}
+	if err := store.SaveJob(JobRecord{ID: "0AfA", Kind: JobDeploy, State: "Pending"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveJob(JobRecord{ID: "0AfA", Kind: JobDeploy, State: "InProgress"}); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := store.Jobs()
+	if err != nil || len(jobs) != 1 || jobs[0].State != "InProgress" {
+		t.Fatalf("unexpected jobs %+v %v", jobs, err)
+	}
+	if err = store.DeleteJob("0AfA"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.DeleteJob("0AfA"); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ = store.Jobs(); len(jobs) != 0 {
+		t.Errorf("expected no jobs, got %+v", jobs)
+	}
+}
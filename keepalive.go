@@ -0,0 +1,106 @@
+package simpleforce
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultKeepAliveInterval is used by StartKeepAlive when no positive interval is given. Salesforce sessions time out
+// after 2 hours of inactivity by default, the shortest configurable timeout being 15 minutes.
+const DefaultKeepAliveInterval = 10 * time.Minute
+
+// KeepAlive periodically pings salesforce in the background to prevent the session of a Client from timing out in
+// low-traffic processes. A KeepAlive is created by Client.StartKeepAlive and must be stopped with Stop.
+type KeepAlive struct {
+	client   *Client
+	interval time.Duration
+	onError  func(error)
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	task     int
+}
+
+// KeepAliveOption is a functional option for StartKeepAlive.
+type KeepAliveOption func(*KeepAlive)
+
+// WithKeepAliveErrorHandler registers a callback invoked whenever a ping fails, e.g. to re-login when the session has
+// already expired. Failures are only logged if no handler is registered.
+func WithKeepAliveErrorHandler(handler func(error)) KeepAliveOption {
+	return func(ka *KeepAlive) {
+		ka.onError = handler
+	}
+}
+
+// Ping issues a lightweight request (the API version resource) to salesforce to verify and refresh the session.
+func (client *Client) Ping() error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	// Do not use makeURL here as Ping may be called from the keep-alive goroutine.
+	u := fmt.Sprintf("%s/services/data/v%s/", client.servicesURL(), client.apiVersion)
+	_, err := client.httpRequest(http.MethodGet, u, nil)
+	return err
+}
+
+// StartKeepAlive starts a goroutine which pings salesforce every interval until Stop is called on the returned
+// KeepAlive. DefaultKeepAliveInterval is used if interval is not positive.
+func (client *Client) StartKeepAlive(interval time.Duration, opts ...KeepAliveOption) *KeepAlive {
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+
+	ka := &KeepAlive{
+		client:   client,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(ka)
+	}
+
+	go ka.run()
+	ka.task = client.startTask(ka.Stop)
+	return ka
+}
+
+// Stop terminates the keep-alive goroutine and waits for it to exit. It is safe to call Stop more than once. Close
+// stops the keep-alives of a client.
+func (ka *KeepAlive) Stop() {
+	ka.stopOnce.Do(func() {
+		close(ka.stop)
+		ka.client.endTask(ka.task)
+	})
+	<-ka.done
+}
+
+// run is the loop of the keep-alive goroutine.
+func (ka *KeepAlive) run() {
+	defer close(ka.done)
+
+	ticker := time.NewTicker(ka.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ka.stop:
+			return
+		case <-ticker.C:
+			err := ka.client.Ping()
+			if err == nil {
+				continue
+			}
+			if ka.onError != nil {
+				ka.onError(err)
+			} else {
+				log.Println(logPrefix, "keep-alive ping failed,", err)
+			}
+		}
+	}
+}
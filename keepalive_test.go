@@ -0,0 +1,48 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_StartKeepAlive(t *testing.T) {
+	var pings int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v"+DefaultAPIVersion+"/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		atomic.AddInt32(&pings, 1)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	ka := client.StartKeepAlive(5 * time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&pings) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	ka.Stop()
+	ka.Stop()
+
+	if atomic.LoadInt32(&pings) < 2 {
+		t.Fatalf("expected at least 2 pings, got %d", pings)
+	}
+	stopped := atomic.LoadInt32(&pings)
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&pings) != stopped {
+		t.Fail()
+	}
+}
+
+func TestClient_PingNotLoggedIn(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	if client.Ping() != ErrAuthentication {
+		t.Fail()
+	}
+}
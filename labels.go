@@ -0,0 +1,98 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SetAcceptLanguage sets the Accept-Language header sent with every REST API request, e.g. "de" or "ja", so that
+// labels in describe results and error messages are returned in that language where translations exist. An empty
+// language restores the language of the user.
+func (client *Client) SetAcceptLanguage(language string) {
+	client.acceptLanguage = language
+}
+
+// DescribeLocalized queries the metadata of objectType like SObject.Describe, with the labels translated to language
+// regardless of the language set with SetAcceptLanguage. The result is not cached.
+func (client *Client) DescribeLocalized(objectType, language string) (*SObjectMeta, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("sobjects/" + url.PathEscape(objectType) + "/describe")
+	data, err := client.httpRequestWithHeader(http.MethodGet, u, nil, http.Header{"Accept-Language": []string{language}})
+	if err != nil {
+		return nil, err
+	}
+
+	var meta SObjectMeta
+	err = client.unmarshalJSON(data, &meta)
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// localizedField is the part of the describe metadata of a field holding its labels.
+type localizedField struct {
+	Name           string `json:"name"`
+	Label          string `json:"label"`
+	PicklistValues []struct {
+		Value  string `json:"value"`
+		Label  string `json:"label"`
+		Active bool   `json:"active"`
+	} `json:"picklistValues"`
+}
+
+// describeLabels returns the labels of the fields of objectType in language.
+func (client *Client) describeLabels(objectType, language string) ([]localizedField, error) {
+	meta, err := client.DescribeLocalized(objectType, language)
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.marshalJSON((*meta)["fields"])
+	if err != nil {
+		return nil, err
+	}
+	var fields []localizedField
+	err = client.unmarshalJSON(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// FieldLabels returns the labels of the fields of objectType in language, by field name.
+func (client *Client) FieldLabels(objectType, language string) (map[string]string, error) {
+	fields, err := client.describeLabels(objectType, language)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(fields))
+	for _, field := range fields {
+		labels[field.Name] = field.Label
+	}
+	return labels, nil
+}
+
+// PicklistLabels returns the labels of the active values of the picklist field of objectType in language, by value.
+func (client *Client) PicklistLabels(objectType, field, language string) (map[string]string, error) {
+	fields, err := client.describeLabels(objectType, language)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Name != field {
+			continue
+		}
+		labels := make(map[string]string, len(f.PicklistValues))
+		for _, value := range f.PicklistValues {
+			if value.Active {
+				labels[value.Value] = value.Label
+			}
+		}
+		return labels, nil
+	}
+	return nil, fmt.Errorf("field %s not found on %s", field, objectType)
+}
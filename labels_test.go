@@ -0,0 +1,44 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClient_LocalizedLabels(t *testing.T) {
+	var languages []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		languages = append(languages, r.Header.Get("Accept-Language"))
+		if r.Header.Get("Accept-Language") != "de" {
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+			return
+		}
+		w.Write([]byte(`{"name": "Case", "fields": [
+			{"name": "Subject", "label": "Betreff"},
+			{"name": "Status", "label": "Status", "picklistValues": [
+				{"value": "New", "label": "Neu", "active": true},
+				{"value": "Closed", "label": "Geschlossen", "active": true},
+				{"value": "Legacy", "label": "Alt", "active": false}]}
+		]}`))
+	})
+
+	labels, err := client.FieldLabels("Case", "de")
+	if err != nil || labels["Subject"] != "Betreff" {
+		t.Errorf("unexpected labels %v, %v", labels, err)
+	}
+	picklist, err := client.PicklistLabels("Case", "Status", "de")
+	if err != nil || len(picklist) != 2 || picklist["Closed"] != "Geschlossen" {
+		t.Errorf("unexpected picklist labels %v, %v", picklist, err)
+	}
+	if _, err := client.PicklistLabels("Case", "Missing", "de"); err == nil {
+		t.Fail()
+	}
+
+	client.SetAcceptLanguage("fr")
+	client.Query("SELECT Id FROM Case")
+	client.SetAcceptLanguage("")
+	client.Query("SELECT Id FROM Case")
+	if languages[len(languages)-2] != "fr" || languages[len(languages)-1] != "" {
+		t.Errorf("unexpected languages %v", languages)
+	}
+}
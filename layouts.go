@@ -0,0 +1,152 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// DescribeLayoutResult holds the page layouts of an object.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_layouts.htm
+type DescribeLayoutResult struct {
+	Layouts            []Layout            `json:"layouts"`
+	RecordTypeMappings []RecordTypeMapping `json:"recordTypeMappings"`
+}
+
+// RecordTypeMapping maps a record type to the page layout assigned to the running user's profile.
+type RecordTypeMapping struct {
+	RecordTypeID             string `json:"recordTypeId"`
+	Name                     string `json:"name"`
+	DeveloperName            string `json:"developerName"`
+	LayoutID                 string `json:"layoutId"`
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	Master                   bool   `json:"master"`
+}
+
+// Layout is a page layout, made of sections shown when viewing (detail) and editing a record.
+type Layout struct {
+	ID                   string          `json:"id"`
+	DetailLayoutSections []LayoutSection `json:"detailLayoutSections"`
+	EditLayoutSections   []LayoutSection `json:"editLayoutSections"`
+}
+
+// LayoutSection is a section of a page layout, laid out in rows and columns.
+type LayoutSection struct {
+	ID         string      `json:"layoutSectionId"`
+	Heading    string      `json:"heading"`
+	UseHeading bool        `json:"useHeading"`
+	Columns    int         `json:"columns"`
+	Rows       int         `json:"rows"`
+	LayoutRows []LayoutRow `json:"layoutRows"`
+}
+
+// LayoutRow is a row of a layout section.
+type LayoutRow struct {
+	LayoutItems []LayoutItem `json:"layoutItems"`
+}
+
+// LayoutItem is a cell of a layout row, or an item of a compact layout. Placeholder items are empty cells.
+type LayoutItem struct {
+	Label             string            `json:"label"`
+	Placeholder       bool              `json:"placeholder"`
+	Required          bool              `json:"required"`
+	EditableForNew    bool              `json:"editableForNew"`
+	EditableForUpdate bool              `json:"editableForUpdate"`
+	LayoutComponents  []LayoutComponent `json:"layoutComponents"`
+}
+
+// LayoutComponent is the content of a layout item. For fields, Type is "Field" and Value is the field name.
+type LayoutComponent struct {
+	Type         string `json:"type"`
+	Value        string `json:"value"`
+	DisplayLines int    `json:"displayLines"`
+	TabOrder     int    `json:"tabOrder"`
+}
+
+// Fields returns the names of the fields in the layout item, in display order.
+func (item *LayoutItem) Fields() []string {
+	var fields []string
+	for _, component := range item.LayoutComponents {
+		if component.Type == "Field" {
+			fields = append(fields, component.Value)
+		}
+	}
+	return fields
+}
+
+// DescribeCompactLayoutsResult holds the compact layouts of an object, which define the fields shown in record
+// highlights and previews.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_compact_layouts.htm
+type DescribeCompactLayoutsResult struct {
+	CompactLayouts         []CompactLayout `json:"compactLayouts"`
+	DefaultCompactLayoutID string          `json:"defaultCompactLayoutId"`
+}
+
+// CompactLayout is a compact layout of an object.
+type CompactLayout struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Label      string       `json:"label"`
+	FieldItems []LayoutItem `json:"fieldItems"`
+}
+
+// DescribeLayouts retrieves the page layouts of objectType. If recordTypeID is not empty, only the layout assigned to
+// that record type is returned.
+func (client *Client) DescribeLayouts(objectType, recordTypeID string) (*DescribeLayoutResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	apiPath := "sobjects/" + url.PathEscape(objectType) + "/describe/layouts/"
+	if recordTypeID != "" {
+		apiPath += url.PathEscape(recordTypeID)
+	}
+	data, err := client.httpRequest(http.MethodGet, client.makeURL(apiPath), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DescribeLayoutResult
+	if recordTypeID != "" {
+		// A single layout is returned for a record type.
+		var layout Layout
+		err = client.unmarshalJSON(data, &layout)
+		result.Layouts = []Layout{layout}
+	} else {
+		err = client.unmarshalJSON(data, &result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DescribeCompactLayouts retrieves the compact layouts of objectType.
+func (client *Client) DescribeCompactLayouts(objectType string) (*DescribeCompactLayoutsResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("sobjects/" + url.PathEscape(objectType) + "/describe/compactLayouts")
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DescribeCompactLayoutsResult
+	err = client.unmarshalJSON(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Default returns the default compact layout, or nil if it is not part of the result.
+func (result *DescribeCompactLayoutsResult) Default() *CompactLayout {
+	for idx := range result.CompactLayouts {
+		if result.CompactLayouts[idx].ID == result.DefaultCompactLayoutID {
+			return &result.CompactLayouts[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+const layoutJSON = `{"id": "00h000000000001", "detailLayoutSections": [{"heading": "Information", "useHeading": true,
+	"columns": 2, "rows": 1, "layoutRows": [{"layoutItems": [
+		{"label": "Name", "required": true, "editableForNew": true,
+			"layoutComponents": [{"type": "Field", "value": "Name", "displayLines": 1, "tabOrder": 1}]},
+		{"label": "", "placeholder": true, "layoutComponents": []}
+	]}]}], "editLayoutSections": []}`
+
+func TestClient_DescribeLayouts(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v" + DefaultAPIVersion + "/sobjects/Account/describe/layouts/":
+			w.Write([]byte(`{"layouts": [` + layoutJSON + `], "recordTypeMappings": [
+				{"recordTypeId": "012000000000000AAA", "layoutId": "00h000000000001", "master": true, "available": true}]}`))
+		case "/services/data/v" + DefaultAPIVersion + "/sobjects/Account/describe/layouts/012000000000001AAA":
+			w.Write([]byte(layoutJSON))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	result, err := client.DescribeLayouts("Account", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Layouts) != 1 || len(result.RecordTypeMappings) != 1 || !result.RecordTypeMappings[0].Master {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	section := result.Layouts[0].DetailLayoutSections[0]
+	items := section.LayoutRows[0].LayoutItems
+	if section.Heading != "Information" || section.Columns != 2 || len(items) != 2 || !items[1].Placeholder {
+		t.Errorf("unexpected section %+v", section)
+	}
+	if fields := items[0].Fields(); len(fields) != 1 || fields[0] != "Name" || !items[0].Required {
+		t.Errorf("unexpected item %+v", items[0])
+	}
+
+	result, err = client.DescribeLayouts("Account", "012000000000001AAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Layouts) != 1 || result.Layouts[0].ID != "00h000000000001" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_DescribeCompactLayouts(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v"+DefaultAPIVersion+"/sobjects/Account/describe/compactLayouts" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"defaultCompactLayoutId": "0AH000000000002", "compactLayouts": [
+			{"id": null, "name": "SYSTEM", "label": "System Default", "fieldItems": []},
+			{"id": "0AH000000000002", "name": "Sales", "label": "Sales", "fieldItems": [
+				{"label": "Phone", "layoutComponents": [{"type": "Field", "value": "Phone"}]}]}
+		]}`))
+	})
+
+	result, err := client.DescribeCompactLayouts("Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := result.Default()
+	if layout == nil || layout.Name != "Sales" || layout.FieldItems[0].Fields()[0] != "Phone" {
+		t.Errorf("unexpected default layout %+v", layout)
+	}
+}
@@ -0,0 +1,290 @@
+package simpleforce
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxLoadParentIDs is the number of parent IDs in the IN list of a query loading child records of several parents.
+const maxLoadParentIDs = 200
+
+// LoadSpec describes a graph of records to load with Client.Load: records of Object with the child records of the
+// relationships in Include, e.g.
+//
+//	client.Load(LoadSpec{
+//		Object:    "Account",
+//		Condition: "Industry = 'Banking'",
+//		Include:   []string{"Contacts", "Opportunities.OpportunityLineItems"},
+//		Fields:    map[string][]string{"": {"Name"}, "Contacts": {"LastName", "Email"}},
+//	})
+type LoadSpec struct {
+	// Object is the type of the root records, e.g. "Account".
+	Object string
+
+	// Condition restricts the root records to those matching the SOQL condition, if not empty.
+	Condition string
+
+	// Include lists paths of child relationships, e.g. "Contacts" or "Opportunities.OpportunityLineItems" for the
+	// children of the children. The intermediate relationships of a path are loaded as well.
+	Include []string
+
+	// Fields selects the fields of the records by path as written in Include, "" for the root records, e.g.
+	// "Opportunities" for the opportunities of the root records. Fields of parents may be selected too, e.g.
+	// "Owner.Name". All fields are selected for paths not listed. The ID and the reference fields needed to link the
+	// records are always selected.
+	Fields map[string][]string
+}
+
+// loadNode is a relationship to load, with the relationships of its records to load.
+type loadNode struct {
+	path     string
+	name     string // as described
+	object   string
+	field    string // the reference to the parent record
+	children []*loadNode
+}
+
+// Load loads the records of spec.Object matching spec.Condition together with the child records of the relationships
+// in spec.Include, and returns the root records. The child records are attached to their parents like the results of
+// subqueries, see ChildRecords. The children of the root records are selected by subqueries of the query of the root
+// records; deeper levels are loaded with one query per relationship for up to 200 parents, again with subqueries for
+// their own children. All pages of the results and of the subqueries are fetched.
+func (client *Client) Load(spec LoadSpec) ([]*SObject, error) {
+	root := &loadNode{object: spec.Object}
+	for _, path := range spec.Include {
+		node := root
+		for depth, name := range strings.Split(path, ".") {
+			var child *loadNode
+			for _, existing := range node.children {
+				if strings.EqualFold(existing.name, name) {
+					child = existing
+				}
+			}
+			if child == nil {
+				described, object, field, err := client.childRelationship(node.object, name)
+				if err != nil {
+					return nil, err
+				}
+				child = &loadNode{
+					path:   strings.Join(strings.Split(path, ".")[:depth+1], "."),
+					name:   described,
+					object: object,
+					field:  field,
+				}
+				node.children = append(node.children, child)
+			}
+			node = child
+		}
+	}
+
+	return client.loadRecords(root, spec.Condition, spec.Fields)
+}
+
+// loadRecords queries the records of node matching condition, with the child records of all levels below.
+func (client *Client) loadRecords(node *loadNode, condition string, fields map[string][]string) ([]*SObject, error) {
+	selected, err := client.loadFields(node, fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range node.children {
+		childFields, err := client.loadFields(child, fields)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, fmt.Sprintf("(SELECT %s FROM %s)", strings.Join(childFields, ", "), child.name))
+	}
+	soql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selected, ", "), node.object)
+	if condition != "" {
+		soql += " WHERE " + condition
+	}
+
+	var records []*SObject
+	err = client.QueryEach(soql, nil, func(record *SObject) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range node.children {
+		var children []map[string]interface{}
+		for _, record := range records {
+			loaded, err := client.completeSubquery(record, child.name)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, loaded...)
+		}
+		for _, grandchild := range child.children {
+			if err := client.loadChildren(children, grandchild, fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, nil
+}
+
+// loadChildren loads the child records of node of parents, and attaches them to their parents.
+func (client *Client) loadChildren(parents []map[string]interface{}, node *loadNode, fields map[string][]string) error {
+	byID := make(map[string][]map[string]interface{}, len(parents))
+	ids := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		id, _ := parent[sobjectIDKey].(string)
+		if id == "" {
+			continue
+		}
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+		}
+		byID[id] = append(byID[id], parent)
+	}
+
+	loaded := make(map[string][]interface{}, len(ids))
+	for start := 0; start < len(ids); start += maxLoadParentIDs {
+		end := start + maxLoadParentIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		quoted := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			quoted = append(quoted, QuoteSOQL(id))
+		}
+		records, err := client.loadRecords(node, fmt.Sprintf("%s IN (%s)", node.field, strings.Join(quoted, ", ")), fields)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			parentID := record.StringField(node.field)
+			loaded[parentID] = append(loaded[parentID], detachedRecord(record))
+		}
+	}
+
+	for id, sameParents := range byID {
+		for _, parent := range sameParents {
+			records := loaded[id]
+			if records == nil {
+				records = []interface{}{}
+			}
+			parent[node.name] = map[string]interface{}{
+				"totalSize": float64(len(records)),
+				"done":      true,
+				"records":   records,
+			}
+		}
+	}
+	return nil
+}
+
+// loadFields returns the fields of the records of node, all fields if none are given in fields, with those needed to
+// link the records.
+func (client *Client) loadFields(node *loadNode, fields map[string][]string) ([]string, error) {
+	selected, ok := fields[node.path]
+	if !ok || len(selected) == 0 {
+		return client.QueryFields(node.object, FieldsAll)
+	}
+	required := []string{sobjectIDKey}
+	if node.field != "" {
+		required = append(required, node.field)
+	}
+	selected = append([]string(nil), selected...)
+	for _, name := range required {
+		found := false
+		for _, field := range selected {
+			found = found || strings.EqualFold(field, name)
+		}
+		if !found {
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
+
+// completeSubquery fetches the remaining pages of the results of the subquery of the relationship name of record, and
+// returns all its records.
+func (client *Client) completeSubquery(record *SObject, name string) ([]map[string]interface{}, error) {
+	result, ok := record.InterfaceField(name).(map[string]interface{})
+	if !ok {
+		// Salesforce returns null for subqueries without results.
+		record.Set(name, map[string]interface{}{"totalSize": float64(0), "done": true, "records": []interface{}{}})
+		return nil, nil
+	}
+	rawRecords, _ := result["records"].([]interface{})
+	for done, _ := result["done"].(bool); !done; {
+		next, _ := result["nextRecordsUrl"].(string)
+		if next == "" {
+			break
+		}
+		page, err := client.QueryMore(next)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching %s", name)
+		}
+		for idx := range page.Records {
+			rawRecords = append(rawRecords, detachedRecord(&page.Records[idx]))
+		}
+		done = page.Done
+		result["nextRecordsUrl"] = page.NextRecordsURL
+	}
+	delete(result, "nextRecordsUrl")
+	result["done"] = true
+	result["totalSize"] = float64(len(rawRecords))
+	result["records"] = rawRecords
+
+	records := make([]map[string]interface{}, 0, len(rawRecords))
+	for _, raw := range rawRecords {
+		if fields, ok := raw.(map[string]interface{}); ok {
+			records = append(records, fields)
+		}
+	}
+	return records, nil
+}
+
+// detachedRecord returns the fields of record without its client, as records of subquery results are decoded.
+func detachedRecord(record *SObject) map[string]interface{} {
+	fields := make(map[string]interface{}, len(*record))
+	for key, val := range *record {
+		if key != sobjectClientKey {
+			fields[key] = val
+		}
+	}
+	return fields
+}
+
+// ChildRecords returns the child records of the relationship relationshipName, e.g. "Contacts", from the results of a
+// subquery or of Load, associated with the client of the SObject. The records are copies, sharing the values of
+// their fields, e.g. their own child records, with the SObject. Only the records of the first page of subquery results
+// are returned if not all pages were fetched.
+func (obj *SObject) ChildRecords(relationshipName string) []*SObject {
+	result, ok := obj.InterfaceField(relationshipName).(map[string]interface{})
+	if !ok && obj != nil {
+		keys := make([]string, 0, len(*obj))
+		for key := range *obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if strings.EqualFold(key, relationshipName) {
+				result, ok = (*obj)[key].(map[string]interface{})
+				break
+			}
+		}
+	}
+	rawRecords, _ := result["records"].([]interface{})
+	records := make([]*SObject, 0, len(rawRecords))
+	for _, raw := range rawRecords {
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		record := make(SObject, len(fields)+1)
+		for key, val := range fields {
+			record[key] = val
+		}
+		record.setClient(obj.client())
+		records = append(records, &record)
+	}
+	return records
+}
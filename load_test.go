@@ -0,0 +1,89 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClient_Load(t *testing.T) {
+	var queries int
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/sobjects/Account/describe":
+			w.Write([]byte(`{"name": "Account", "childRelationships": [
+				{"childSObject": "Contact", "field": "AccountId", "relationshipName": "Contacts"},
+				{"childSObject": "Opportunity", "field": "AccountId", "relationshipName": "Opportunities"}]}`))
+		case "/services/data/v54.0/sobjects/Opportunity/describe":
+			w.Write([]byte(`{"name": "Opportunity", "childRelationships": [
+				{"childSObject": "OpportunityLineItem", "field": "OpportunityId", "relationshipName": "OpportunityLineItems"}]}`))
+		case "/services/data/v54.0/query":
+			queries++
+			switch q := r.URL.Query().Get("q"); q {
+			case "SELECT Name, Id, (SELECT LastName, Id, AccountId FROM Contacts), (SELECT Name, Id, AccountId FROM Opportunities) " +
+				"FROM Account WHERE Industry = 'Banking'":
+				w.Write([]byte(`{"done": true, "records": [
+					{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme",
+						"Contacts": {"totalSize": 2, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01gC-1", "records": [
+							{"attributes": {"type": "Contact"}, "Id": "003A", "LastName": "Doe", "AccountId": "001A"}]},
+						"Opportunities": {"totalSize": 1, "done": true, "records": [
+							{"attributes": {"type": "Opportunity"}, "Id": "006A", "Name": "Big", "AccountId": "001A"}]}},
+					{"attributes": {"type": "Account"}, "Id": "001B", "Name": "Globex", "Contacts": null,
+						"Opportunities": {"totalSize": 1, "done": true, "records": [
+							{"attributes": {"type": "Opportunity"}, "Id": "006B", "Name": "Small", "AccountId": "001B"}]}}]}`))
+			case "SELECT Quantity, Id, OpportunityId FROM OpportunityLineItem WHERE OpportunityId IN ('006A', '006B')":
+				w.Write([]byte(`{"done": true, "records": [
+					{"attributes": {"type": "OpportunityLineItem"}, "Id": "00kA", "Quantity": 2, "OpportunityId": "006A"},
+					{"attributes": {"type": "OpportunityLineItem"}, "Id": "00kB", "Quantity": 5, "OpportunityId": "006A"}]}`))
+			default:
+				t.Errorf("unexpected query %s", q)
+			}
+		case "/services/data/v54.0/query/01gC-1":
+			queries++
+			w.Write([]byte(`{"done": true, "records": [
+				{"attributes": {"type": "Contact"}, "Id": "003B", "LastName": "Roe", "AccountId": "001A"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	accounts, err := client.Load(LoadSpec{
+		Object:    "Account",
+		Condition: "Industry = 'Banking'",
+		Include:   []string{"contacts", "Opportunities.OpportunityLineItems"},
+		Fields: map[string][]string{
+			"":                                   {"Name"},
+			"contacts":                           {"LastName"},
+			"Opportunities":                      {"Name"},
+			"Opportunities.OpportunityLineItems": {"Quantity"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queries != 3 {
+		t.Errorf("expected 3 queries, got %d", queries)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("unexpected accounts %v", accounts)
+	}
+
+	contacts := accounts[0].ChildRecords("Contacts")
+	if len(contacts) != 2 || contacts[1].StringField("LastName") != "Roe" || contacts[1].client() != client {
+		t.Errorf("unexpected contacts %v", contacts)
+	}
+	if contacts = accounts[1].ChildRecords("Contacts"); len(contacts) != 0 {
+		t.Errorf("unexpected contacts %v", contacts)
+	}
+
+	opportunities := accounts[0].ChildRecords("Opportunities")
+	if len(opportunities) != 1 {
+		t.Fatalf("unexpected opportunities %v", opportunities)
+	}
+	items := opportunities[0].ChildRecords("OpportunityLineItems")
+	if len(items) != 2 || items[1].ID() != "00kB" {
+		t.Errorf("unexpected line items %v", items)
+	}
+	if items = accounts[1].ChildRecords("Opportunities")[0].ChildRecords("OpportunityLineItems"); len(items) != 0 {
+		t.Errorf("unexpected line items %v", items)
+	}
+}
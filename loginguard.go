@@ -0,0 +1,89 @@
+package simpleforce
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+const (
+	// DefaultLoginMaxFailures is the number of consecutive INVALID_LOGIN failures of a username after which
+	// LoginPassword refuses to contact salesforce until the cool-down has passed.
+	DefaultLoginMaxFailures = 3
+
+	// DefaultLoginCooldown is the default cool-down after repeated login failures.
+	DefaultLoginCooldown = 15 * time.Minute
+)
+
+// ErrLoginThrottled is returned by LoginPassword, wrapped with the time the cool-down ends, if logins of the username
+// are suspended after repeated failures.
+var ErrLoginThrottled = errors.New("login suspended after repeated failures")
+
+// loginGuard tracks failed logins by username to avoid locking out accounts with misconfigured credentials, as
+// salesforce locks users out after a number of invalid login attempts.
+type loginGuard struct {
+	mu          sync.Mutex
+	maxFailures int
+	cooldown    time.Duration
+	failures    map[string]int
+	until       map[string]time.Time
+	now         func() time.Time
+}
+
+func newLoginGuard() *loginGuard {
+	return &loginGuard{
+		maxFailures: DefaultLoginMaxFailures,
+		cooldown:    DefaultLoginCooldown,
+		failures:    make(map[string]int),
+		until:       make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// SetLoginGuard configures how LoginPassword protects accounts from lockouts: after maxFailures consecutive
+// INVALID_LOGIN failures of a username, or after a PASSWORD_LOCKOUT, further logins of the username fail with
+// ErrLoginThrottled for cooldown without contacting salesforce. A maxFailures below 1 disables the guard.
+func (client *Client) SetLoginGuard(maxFailures int, cooldown time.Duration) {
+	client.loginGuard.mu.Lock()
+	defer client.loginGuard.mu.Unlock()
+	client.loginGuard.maxFailures = maxFailures
+	client.loginGuard.cooldown = cooldown
+}
+
+// check returns ErrLoginThrottled if logins of username are suspended.
+func (guard *loginGuard) check(username string) error {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+	if guard.maxFailures < 1 {
+		return nil
+	}
+	if until, ok := guard.until[username]; ok && guard.now().Before(until) {
+		return errors.Wrapf(ErrLoginThrottled, "retry after %s", until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// record updates the failure count of username with the outcome of a login.
+func (guard *loginGuard) record(username string, err error) {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	loginErr, ok := err.(*LoginError)
+	if err == nil || (ok && loginErr.Code != errcode.InvalidLogin && loginErr.Code != errcode.PasswordLockout) {
+		delete(guard.failures, username)
+		delete(guard.until, username)
+		return
+	}
+	if !ok {
+		// Network and server errors do not count towards a lockout.
+		return
+	}
+
+	guard.failures[username]++
+	if loginErr.Code == errcode.PasswordLockout || guard.failures[username] >= guard.maxFailures {
+		guard.until[username] = guard.now().Add(guard.cooldown)
+		guard.failures[username] = 0
+	}
+}
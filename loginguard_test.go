@@ -0,0 +1,52 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_LoginGuard(t *testing.T) {
+	var logins int
+	code := "INVALID_LOGIN"
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>
+			<soapenv:Fault><faultcode>sf:` + code + `</faultcode><faultstring>` + code + `: failed</faultstring></soapenv:Fault>
+			</soapenv:Body></soapenv:Envelope>`))
+	})
+	client.baseURL = server.URL
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	client.loginGuard.now = func() time.Time { return now }
+	client.SetLoginGuard(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if err := client.LoginPassword("user@example.com", "wrong", ""); !errors.Is(err, ErrInvalidLogin) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	err := client.LoginPassword("user@example.com", "wrong", "")
+	if errors.Cause(err) != ErrLoginThrottled || logins != 2 {
+		t.Fatalf("expected throttled login, got %v after %d logins", err, logins)
+	}
+	if err := client.LoginPassword("other@example.com", "wrong", ""); !errors.Is(err, ErrInvalidLogin) {
+		t.Errorf("other usernames must not be throttled, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	code = "PASSWORD_LOCKOUT"
+	if err := client.LoginPassword("user@example.com", "wrong", ""); !errors.Is(err, ErrPasswordLockout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := client.LoginPassword("user@example.com", "wrong", ""); errors.Cause(err) != ErrLoginThrottled {
+		t.Errorf("expected throttled login after lockout, got %v", err)
+	}
+
+	client.SetLoginGuard(0, 0)
+	if err := client.LoginPassword("user@example.com", "wrong", ""); !errors.Is(err, ErrPasswordLockout) {
+		t.Errorf("expected disabled guard, got %v", err)
+	}
+}
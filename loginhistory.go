@@ -0,0 +1,134 @@
+package simpleforce
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// Common login types of LoginHistory records.
+const (
+	LoginTypeApplication = "Application"       // login to the salesforce UI
+	LoginTypeOAuth       = "Remote Access 2.0" // OAuth 2.0 flows of connected apps
+	LoginTypeSAML        = "SAML Sfdc Initiated SSO"
+	LoginTypeAPI         = "Other Apex API" // SOAP login with username and password
+)
+
+// loginSucceeded is the Status of successful logins.
+const loginSucceeded = "Success"
+
+// loginHistoryFields are the fields of LoginHistory selected by LoginHistory.
+const loginHistoryFields = "Id, UserId, LoginTime, LoginType, Status, SourceIp, LoginUrl, Application, Browser, " +
+	"Platform, ApiType, ApiVersion, CountryIso, AuthenticationServiceId"
+
+// verificationHistoryFields are the fields of VerificationHistory selected by VerificationHistory.
+const verificationHistoryFields = "Id, UserId, VerificationTime, EventGroup, Activity, VerificationMethod, Status, " +
+	"Policy, SourceIp, Remarks, LoginHistoryId"
+
+// LoginAttempt is a login attempt of a user recorded in the login history. LoginTime can be parsed with ParseDateTime.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_loginhistory.htm
+type LoginAttempt struct {
+	ID                      string `json:"Id"`
+	UserID                  string `json:"UserId"`
+	LoginTime               string `json:"LoginTime"`
+	LoginType               string `json:"LoginType"` // e.g. LoginTypeApplication or LoginTypeOAuth
+	Status                  string `json:"Status"`    // "Success" or the reason of the failure, e.g. "Invalid Password"
+	SourceIP                string `json:"SourceIp"`
+	LoginURL                string `json:"LoginUrl"`
+	Application             string `json:"Application"`
+	Browser                 string `json:"Browser"`
+	Platform                string `json:"Platform"`
+	APIType                 string `json:"ApiType"`
+	APIVersion              string `json:"ApiVersion"`
+	CountryISO              string `json:"CountryIso"`
+	AuthenticationServiceID string `json:"AuthenticationServiceId"`
+}
+
+// Succeeded reports whether the login attempt succeeded.
+func (attempt LoginAttempt) Succeeded() bool {
+	return attempt.Status == loginSucceeded
+}
+
+// IP returns the source IP address of the login attempt, or nil if salesforce did not record one, e.g. for
+// "Salesforce.com IP".
+func (attempt LoginAttempt) IP() net.IP {
+	return net.ParseIP(attempt.SourceIP)
+}
+
+// Verification is an identity verification attempt of a user, e.g. for multi-factor authentication or a device
+// activation. VerificationTime can be parsed with ParseDateTime.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_verificationhistory.htm
+type Verification struct {
+	ID                 string `json:"Id"`
+	UserID             string `json:"UserId"`
+	VerificationTime   string `json:"VerificationTime"`
+	EventGroup         string `json:"EventGroup"`
+	Activity           string `json:"Activity"`           // e.g. "Login" or "ConnectToopher"
+	VerificationMethod string `json:"VerificationMethod"` // e.g. "TotpVerification" or "EmailVerification"
+	Status             string `json:"Status"`             // e.g. "Succeeded", "FailedInvalidCode" or "Pending"
+	Policy             string `json:"Policy"`
+	SourceIP           string `json:"SourceIp"`
+	Remarks            string `json:"Remarks"`
+	LoginHistoryID     string `json:"LoginHistoryId"`
+}
+
+// IP returns the source IP address of the verification attempt, or nil if salesforce did not record one.
+func (verification Verification) IP() net.IP {
+	return net.ParseIP(verification.SourceIP)
+}
+
+// LoginHistory calls fn with the login attempts of the user userID, all users if empty, made at or after since and
+// before until, oldest first, stopping at the first error returned by fn. A zero since or until leaves the range open.
+// Login history is kept by salesforce for six months.
+func (client *Client) LoginHistory(userID string, since, until time.Time, fn func(LoginAttempt) error) error {
+	soql := historyQuery(loginHistoryFields, "LoginHistory", "LoginTime", userID, since, until)
+	return client.queryPages("query", soql, func(records []byte) error {
+		var page []LoginAttempt
+		err := client.unmarshalJSON(records, &page)
+		if err != nil {
+			return err
+		}
+		for _, attempt := range page {
+			err = fn(attempt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// VerificationHistory calls fn with the identity verification attempts of the user userID, all users if empty, made at
+// or after since and before until, oldest first, stopping at the first error returned by fn. A zero since or until
+// leaves the range open.
+func (client *Client) VerificationHistory(userID string, since, until time.Time, fn func(Verification) error) error {
+	soql := historyQuery(verificationHistoryFields, "VerificationHistory", "VerificationTime", userID, since, until)
+	return client.queryPages("query", soql, func(records []byte) error {
+		var page []Verification
+		err := client.unmarshalJSON(records, &page)
+		if err != nil {
+			return err
+		}
+		for _, verification := range page {
+			err = fn(verification)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// historyQuery returns the query of the fields of the records of objectType of the user userID, all users if empty,
+// with timeField in the range since to until, ordered by timeField.
+func historyQuery(fields, objectType, timeField, userID string, since, until time.Time) string {
+	conditions := timeRangeConditions(timeField, since, until)
+	if userID != "" {
+		conditions = append(conditions, "UserId = "+QuoteSOQL(userID))
+	}
+	soql := "SELECT " + fields + " FROM " + objectType
+	if len(conditions) > 0 {
+		soql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return soql + " ORDER BY " + timeField + ", Id"
+}
@@ -0,0 +1,76 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_LoginHistory(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/query":
+			expected := "SELECT " + loginHistoryFields + " FROM LoginHistory WHERE LoginTime >= 2022-05-01T00:00:00Z" +
+				" AND UserId = '005A' ORDER BY LoginTime, Id"
+			if q := r.URL.Query().Get("q"); q != expected {
+				t.Errorf("unexpected query %s", q)
+			}
+			w.Write([]byte(`{"done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-1", "records": [
+				{"attributes": {"type": "LoginHistory"}, "Id": "0YaA", "UserId": "005A",
+					"LoginTime": "2022-05-01T10:00:00.000+0000", "LoginType": "Application", "Status": "Invalid Password",
+					"SourceIp": "203.0.113.7", "CountryIso": "NL"}]}`))
+		case "/services/data/v54.0/query/01g-1":
+			w.Write([]byte(`{"done": true, "records": [
+				{"attributes": {"type": "LoginHistory"}, "Id": "0YaB", "UserId": "005A",
+					"LoginTime": "2022-05-01T10:01:00.000+0000", "LoginType": "Remote Access 2.0", "Status": "Success",
+					"SourceIp": "Salesforce.com IP", "Application": "Data Loader"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	var attempts []LoginAttempt
+	err := client.LoginHistory("005A", time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), time.Time{}, func(attempt LoginAttempt) error {
+		attempts = append(attempts, attempt)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(attempts))
+	}
+	if attempts[0].Succeeded() || attempts[0].IP().String() != "203.0.113.7" || attempts[0].LoginType != LoginTypeApplication {
+		t.Errorf("unexpected attempt %+v", attempts[0])
+	}
+	if !attempts[1].Succeeded() || attempts[1].IP() != nil || attempts[1].LoginType != LoginTypeOAuth {
+		t.Errorf("unexpected attempt %+v", attempts[1])
+	}
+}
+
+func TestClient_VerificationHistory(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "SELECT " + verificationHistoryFields + " FROM VerificationHistory ORDER BY VerificationTime, Id"
+		if q := r.URL.Query().Get("q"); q != expected {
+			t.Errorf("unexpected query %s", q)
+		}
+		w.Write([]byte(`{"done": true, "records": [
+			{"attributes": {"type": "VerificationHistory"}, "Id": "0YbA", "UserId": "005A",
+				"VerificationTime": "2022-05-01T10:00:00.000+0000", "Activity": "Login",
+				"VerificationMethod": "TotpVerification", "Status": "Succeeded", "SourceIp": "2001:db8::1",
+				"LoginHistoryId": "0YaB"}]}`))
+	})
+
+	var verifications []Verification
+	err := client.VerificationHistory("", time.Time{}, time.Time{}, func(verification Verification) error {
+		verifications = append(verifications, verification)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifications) != 1 || verifications[0].VerificationMethod != "TotpVerification" ||
+		verifications[0].IP().String() != "2001:db8::1" || verifications[0].LoginHistoryID != "0YaB" {
+		t.Errorf("unexpected verifications %+v", verifications)
+	}
+}
@@ -0,0 +1,115 @@
+package simpleforce
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLookupNotFound is returned when a queue, group, role or profile cannot be resolved by its name.
+var ErrLookupNotFound = errors.New("name not found")
+
+// lookup describes how the IDs of a kind of setup record are resolved by name.
+type lookup struct {
+	kind       string
+	soql       string   // selects Id and nameFields of all records of the kind
+	nameFields []string // in order of precedence
+}
+
+var (
+	queueLookup = lookup{
+		kind:       "queue",
+		soql:       "SELECT Id, DeveloperName, Name FROM Group WHERE Type = 'Queue'",
+		nameFields: []string{"DeveloperName", "Name"},
+	}
+	groupLookup = lookup{
+		kind:       "group",
+		soql:       "SELECT Id, DeveloperName, Name FROM Group WHERE Type = 'Regular'",
+		nameFields: []string{"DeveloperName", "Name"},
+	}
+	roleLookup = lookup{
+		kind:       "role",
+		soql:       "SELECT Id, DeveloperName, Name FROM UserRole",
+		nameFields: []string{"DeveloperName", "Name"},
+	}
+	profileLookup = lookup{
+		kind:       "profile",
+		soql:       "SELECT Id, Name FROM Profile",
+		nameFields: []string{"Name"},
+	}
+)
+
+// QueueID resolves the developer name or name of a queue to its ID, e.g. to assign records to it.
+func (client *Client) QueueID(name string) (string, error) {
+	return client.lookupID(queueLookup, name)
+}
+
+// GroupID resolves the developer name or name of a public group to its ID, e.g. to share records with it.
+func (client *Client) GroupID(name string) (string, error) {
+	return client.lookupID(groupLookup, name)
+}
+
+// RoleID resolves the developer name or name of a role to its ID.
+func (client *Client) RoleID(name string) (string, error) {
+	return client.lookupID(roleLookup, name)
+}
+
+// ProfileID resolves the name of a profile, e.g. "System Administrator", to its ID.
+func (client *Client) ProfileID(name string) (string, error) {
+	return client.lookupID(profileLookup, name)
+}
+
+// ClearLookupCache drops the IDs cached by QueueID, GroupID, RoleID and ProfileID.
+func (client *Client) ClearLookupCache() {
+	client.lookupMu.Lock()
+	client.lookups = nil
+	client.lookupMu.Unlock()
+}
+
+// lookupID resolves name, case insensitively, to the ID of a record of the kind described by l. All records of the kind
+// are queried and cached by the client on the first lookup, so that resolving other names needs no further request.
+// ErrLookupNotFound is returned if there is no such record, from the cache until ClearLookupCache is called.
+func (client *Client) lookupID(l lookup, name string) (string, error) {
+	client.lookupMu.Lock()
+	ids, loaded := client.lookups[l.kind]
+	client.lookupMu.Unlock()
+	if loaded {
+		return lookupCached(l, ids, name)
+	}
+
+	var records []*SObject
+	err := client.QueryEach(l.soql, nil, func(record *SObject) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	ids = make(map[string]string)
+	// Names of lower precedence are added first so that the others win.
+	for idx := len(l.nameFields) - 1; idx >= 0; idx-- {
+		for _, record := range records {
+			if name := record.StringField(l.nameFields[idx]); name != "" {
+				ids[strings.ToLower(name)] = record.ID()
+			}
+		}
+	}
+
+	client.lookupMu.Lock()
+	if client.lookups == nil {
+		client.lookups = make(map[string]map[string]string)
+	}
+	client.lookups[l.kind] = ids
+	client.lookupMu.Unlock()
+
+	return lookupCached(l, ids, name)
+}
+
+// lookupCached resolves name to an ID with the names and IDs ids of all records of the kind described by l.
+func lookupCached(l lookup, ids map[string]string, name string) (string, error) {
+	id, ok := ids[strings.ToLower(name)]
+	if !ok {
+		return "", errors.Wrapf(ErrLookupNotFound, "%s %s", l.kind, name)
+	}
+	return id, nil
+}
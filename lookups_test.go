@@ -0,0 +1,56 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_LookupIDs(t *testing.T) {
+	queries := map[string]int{}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		queries[q]++
+		switch q {
+		case queueLookup.soql:
+			w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+				{"attributes": {"type": "Group"}, "Id": "00GSUPPORT", "DeveloperName": "Support_Tier_1", "Name": "Support"},
+				{"attributes": {"type": "Group"}, "Id": "00GSALES", "DeveloperName": "Sales", "Name": "Support_Tier_1"}]}`))
+		case profileLookup.soql:
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+				{"attributes": {"type": "Profile"}, "Id": "00eADMIN", "Name": "System Administrator"}]}`))
+		default:
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		}
+	})
+
+	for name, expected := range map[string]string{"support_tier_1": "00GSUPPORT", "Support": "00GSUPPORT", "SALES": "00GSALES"} {
+		if id, err := client.QueueID(name); err != nil || id != expected {
+			t.Errorf("expected %s for %s, got %s, %v", expected, name, id, err)
+		}
+	}
+	if id, err := client.ProfileID("System Administrator"); err != nil || id != "00eADMIN" {
+		t.Errorf("unexpected profile %s, %v", id, err)
+	}
+	if queries[queueLookup.soql] != 1 || queries[profileLookup.soql] != 1 {
+		t.Errorf("expected the lookups to be cached, got %v", queries)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.RoleID("CEO"); !errors.Is(err, ErrLookupNotFound) {
+			t.Errorf("expected ErrLookupNotFound, got %v", err)
+		}
+	}
+	if queries[roleLookup.soql] != 1 {
+		t.Errorf("expected unknown names to be cached, got %v", queries)
+	}
+	if _, err := client.GroupID("Everyone"); !errors.Is(err, ErrLookupNotFound) {
+		t.Errorf("expected ErrLookupNotFound, got %v", err)
+	}
+
+	client.ClearLookupCache()
+	if _, err := client.QueueID("Sales"); err != nil || queries[queueLookup.soql] != 2 {
+		t.Errorf("expected the cache to be cleared, got %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+package simpleforce
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	metadataNS = "http://soap.sforce.com/2006/04/metadata"
+
+	metadataEnvelope = `<?xml version="1.0" encoding="utf-8"?>
+<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="` + xmlSchemaInstanceNS + `">
+    <env:Header>
+        <SessionHeader xmlns="` + metadataNS + `">
+            <sessionId>%s</sessionId>
+        </SessionHeader>
+    </env:Header>
+    <env:Body>%s</env:Body>
+</env:Envelope>`
+
+	// maxMetadataComponents is the maximum number of components of a single metadata CRUD call.
+	maxMetadataComponents = 10
+
+	// maxListMetadataQueries is the maximum number of queries of a single listMetadata call.
+	maxListMetadataQueries = 3
+)
+
+// Metadata is a component which can be created or updated with CreateMetadata and UpdateMetadata, e.g. a
+// *CustomObject.
+type Metadata interface {
+	// MetadataType returns the Metadata API type of the component, e.g. "CustomObject".
+	MetadataType() string
+}
+
+// CustomObject is the definition of a custom object, e.g. with FullName "Invoice__c". NameField is required on
+// creation.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/customobject.htm
+type CustomObject struct {
+	FullName         string       `xml:"fullName"`
+	DeploymentStatus string       `xml:"deploymentStatus,omitempty"`
+	Description      string       `xml:"description,omitempty"`
+	Label            string       `xml:"label,omitempty"`
+	NameField        *CustomField `xml:"nameField,omitempty"`
+	PluralLabel      string       `xml:"pluralLabel,omitempty"`
+	SharingModel     string       `xml:"sharingModel,omitempty"`
+}
+
+// MetadataType implements Metadata.
+func (*CustomObject) MetadataType() string { return "CustomObject" }
+
+// CustomField is the definition of a custom field, e.g. with FullName "Invoice__c.Amount__c" and Type "Currency".
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/customfield.htm
+type CustomField struct {
+	FullName         string `xml:"fullName,omitempty"`
+	DefaultValue     string `xml:"defaultValue,omitempty"`
+	Description      string `xml:"description,omitempty"`
+	ExternalID       bool   `xml:"externalId,omitempty"`
+	InlineHelpText   string `xml:"inlineHelpText,omitempty"`
+	Label            string `xml:"label,omitempty"`
+	Length           int    `xml:"length,omitempty"`
+	Precision        int    `xml:"precision,omitempty"`
+	ReferenceTo      string `xml:"referenceTo,omitempty"`
+	RelationshipName string `xml:"relationshipName,omitempty"`
+	Required         bool   `xml:"required,omitempty"`
+	Scale            int    `xml:"scale,omitempty"`
+	Type             string `xml:"type,omitempty"`
+	Unique           bool   `xml:"unique,omitempty"`
+	VisibleLines     int    `xml:"visibleLines,omitempty"`
+}
+
+// MetadataType implements Metadata.
+func (*CustomField) MetadataType() string { return "CustomField" }
+
+// ValidationRule is the definition of a validation rule, e.g. with FullName "Invoice__c.Amount_Positive".
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/customobject_validationrule.htm
+type ValidationRule struct {
+	FullName              string `xml:"fullName"`
+	Active                bool   `xml:"active"`
+	Description           string `xml:"description,omitempty"`
+	ErrorConditionFormula string `xml:"errorConditionFormula"`
+	ErrorDisplayField     string `xml:"errorDisplayField,omitempty"`
+	ErrorMessage          string `xml:"errorMessage"`
+}
+
+// MetadataType implements Metadata.
+func (*ValidationRule) MetadataType() string { return "ValidationRule" }
+
+// RemoteSiteSetting allows Apex callouts to URL.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_remotesitesetting.htm
+type RemoteSiteSetting struct {
+	FullName                string `xml:"fullName"`
+	Description             string `xml:"description,omitempty"`
+	DisableProtocolSecurity bool   `xml:"disableProtocolSecurity"`
+	IsActive                bool   `xml:"isActive"`
+	URL                     string `xml:"url"`
+}
+
+// MetadataType implements Metadata.
+func (*RemoteSiteSetting) MetadataType() string { return "RemoteSiteSetting" }
+
+// MetadataSaveResult is the outcome of a metadata CRUD call for a single component.
+type MetadataSaveResult struct {
+	FullName string      `xml:"fullName"`
+	Success  bool        `xml:"success"`
+	Errors   []SaveError `xml:"errors"`
+}
+
+// metadataComponent marshals a Metadata component with its xsi:type.
+type metadataComponent struct {
+	Metadata
+}
+
+// MarshalXML implements xml.Marshaler.
+func (component metadataComponent) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: component.MetadataType()})
+	return encoder.EncodeElement(component.Metadata, start)
+}
+
+// CreateMetadata creates up to 10 components with the synchronous Metadata API createMetadata call. A result is
+// returned for every component; if any of them failed, a *BatchError is returned as well.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_createMetadata.htm
+func (client *Client) CreateMetadata(components ...Metadata) ([]MetadataSaveResult, error) {
+	return client.saveMetadata("createMetadata", components)
+}
+
+// UpdateMetadata updates up to 10 components, identified by their full names, with the synchronous Metadata API
+// updateMetadata call. All fields of a component are replaced, so unset fields are cleared. A result is returned for
+// every component; if any of them failed, a *BatchError is returned as well.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_updateMetadata.htm
+func (client *Client) UpdateMetadata(components ...Metadata) ([]MetadataSaveResult, error) {
+	return client.saveMetadata("updateMetadata", components)
+}
+
+// UpsertMetadata creates or updates up to 10 components, identified by their full names, with the synchronous Metadata
+// API upsertMetadata call. Existing components are replaced like with UpdateMetadata. A result is returned for every
+// component; if any of them failed, a *BatchError is returned as well.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_upsertMetadata.htm
+func (client *Client) UpsertMetadata(components ...Metadata) ([]MetadataSaveResult, error) {
+	return client.saveMetadata("upsertMetadata", components)
+}
+
+// DeleteMetadata deletes up to 10 components of metadataType, e.g. "CustomField", by their full names. A result is
+// returned for every component; if any of them failed, a *BatchError is returned as well.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_deleteMetadata.htm
+func (client *Client) DeleteMetadata(metadataType string, fullNames ...string) ([]MetadataSaveResult, error) {
+	if len(fullNames) > maxMetadataComponents {
+		return nil, fmt.Errorf("at most %d components can be deleted at once", maxMetadataComponents)
+	}
+	request := struct {
+		XMLName   xml.Name `xml:"http://soap.sforce.com/2006/04/metadata deleteMetadata"`
+		Type      string   `xml:"type"`
+		FullNames []string `xml:"fullNames"`
+	}{Type: metadataType, FullNames: fullNames}
+	var response struct {
+		Results []MetadataSaveResult `xml:"Body>deleteMetadataResponse>result"`
+	}
+	err := client.metadataCall(&request, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Results, metadataBatchError(response.Results)
+}
+
+// saveMetadata creates or updates components with the metadata CRUD call action.
+func (client *Client) saveMetadata(action string, components []Metadata) ([]MetadataSaveResult, error) {
+	if len(components) > maxMetadataComponents {
+		return nil, fmt.Errorf("at most %d components can be saved at once", maxMetadataComponents)
+	}
+	request := struct {
+		XMLName  xml.Name
+		Metadata []metadataComponent `xml:"metadata"`
+	}{XMLName: xml.Name{Space: metadataNS, Local: action}}
+	for _, component := range components {
+		request.Metadata = append(request.Metadata, metadataComponent{component})
+	}
+	var response struct {
+		Body struct {
+			Response struct {
+				Results []MetadataSaveResult `xml:"result"`
+			} `xml:",any"`
+		}
+	}
+	err := client.metadataCall(&request, &response)
+	if err != nil {
+		return nil, err
+	}
+	results := response.Body.Response.Results
+	return results, metadataBatchError(results)
+}
+
+// metadataBatchError returns a *BatchError for the failed results, or nil if there are none.
+func metadataBatchError(results []MetadataSaveResult) error {
+	var failures []*RecordError
+	for idx, result := range results {
+		if !result.Success {
+			failures = append(failures, &RecordError{Index: idx, ID: result.FullName, Errors: result.Errors})
+		}
+	}
+	return newBatchError(failures)
+}
+
+// metadataCall sends request, which is marshaled into the body of the SOAP envelope, to the
+// Metadata API and unmarshals the response envelope into response. SOAP faults are returned as SalesforceError.
+func (client *Client) metadataCall(request, response interface{}) error {
+	u := fmt.Sprintf("%s/services/Soap/m/%s", client.servicesURL(), client.apiVersion)
+	return client.soapCall(u, metadataEnvelope, request, response)
+}
+
+// soapCall marshals request into the body of envelope, a format string taking the session ID and the body, posts it to
+// u and unmarshals the response envelope into response. SOAP faults are returned as SalesforceError.
+func (client *Client) soapCall(u, envelope string, request, response interface{}) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+	reqBody := fmt.Sprintf(envelope, html.EscapeString(client.sessionID), body)
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "text/xml; charset=UTF-8")
+	req.Header.Add("SOAPAction", `""`)
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respData, err := client.readResponse(resp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Println(logPrefix, "SOAP request failed,", resp.StatusCode)
+		return client.withRequestContext(parseSOAPFault(resp.StatusCode, respData), resp.Header)
+	}
+	return xml.Unmarshal(respData, response)
+}
+
+// parseSOAPFault parses a SOAP fault into a SalesforceError. Responses which are not SOAP faults are parsed with
+// ParseSalesforceError.
+func parseSOAPFault(statusCode int, responseBody []byte) error {
+	var fault struct {
+		FaultCode   string `xml:"Body>Fault>faultcode"`
+		FaultString string `xml:"Body>Fault>faultstring"`
+	}
+	err := xml.Unmarshal(responseBody, &fault)
+	if err != nil || fault.FaultCode == "" {
+		return ParseSalesforceError(statusCode, responseBody)
+	}
+
+	// Strip the namespace prefix, e.g. "sf:INVALID_SESSION_ID".
+	code := fault.FaultCode[strings.LastIndex(fault.FaultCode, ":")+1:]
+	message := strings.TrimPrefix(fault.FaultString, code+": ")
+	return SalesforceError{
+		Message: fmt.Sprintf(
+			logPrefix+" Error. http code: %v Error Message:  %v Error Code: %v",
+			statusCode, message, code,
+		),
+		HttpCode:     statusCode,
+		ErrorCode:    code,
+		ErrorMessage: message,
+	}
+}
+
+// MetadataObject describes a metadata type supported by the org, as returned by DescribeMetadata.
+type MetadataObject struct {
+	Name          string   `xml:"xmlName"` // the type name, e.g. "CustomObject"
+	DirectoryName string   `xml:"directoryName"`
+	Suffix        string   `xml:"suffix"`
+	InFolder      bool     `xml:"inFolder"`
+	MetaFile      bool     `xml:"metaFile"`
+	ChildXMLNames []string `xml:"childXmlNames"`
+}
+
+// DescribeMetadataResult is returned by DescribeMetadata.
+type DescribeMetadataResult struct {
+	MetadataObjects       []MetadataObject `xml:"metadataObjects"`
+	OrganizationNamespace string           `xml:"organizationNamespace"`
+	PartialSaveAllowed    bool             `xml:"partialSaveAllowed"`
+	TestRequired          bool             `xml:"testRequired"`
+}
+
+// DescribeMetadata lists the metadata types supported by the org at the API version of the client.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_describe.htm
+func (client *Client) DescribeMetadata() (*DescribeMetadataResult, error) {
+	request := struct {
+		XMLName     xml.Name `xml:"http://soap.sforce.com/2006/04/metadata describeMetadata"`
+		AsOfVersion string   `xml:"asOfVersion"`
+	}{AsOfVersion: client.apiVersion}
+	var response struct {
+		Result DescribeMetadataResult `xml:"Body>describeMetadataResponse>result"`
+	}
+	err := client.metadataCall(&request, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Result, nil
+}
+
+// ListMetadataQuery selects the components of Type, e.g. "ApexClass", for ListMetadata. Folder is required for types
+// stored in folders, e.g. "Report".
+type ListMetadataQuery struct {
+	Type   string `xml:"type"`
+	Folder string `xml:"folder,omitempty"`
+}
+
+// FileProperties describes a metadata component listed by ListMetadata.
+type FileProperties struct {
+	ID                 string    `xml:"id"`
+	Type               string    `xml:"type"`
+	FullName           string    `xml:"fullName"`
+	FileName           string    `xml:"fileName"`
+	NamespacePrefix    string    `xml:"namespacePrefix"`
+	ManageableState    string    `xml:"manageableState"`
+	CreatedByID        string    `xml:"createdById"`
+	CreatedByName      string    `xml:"createdByName"`
+	CreatedDate        time.Time `xml:"createdDate"`
+	LastModifiedByID   string    `xml:"lastModifiedById"`
+	LastModifiedByName string    `xml:"lastModifiedByName"`
+	LastModifiedDate   time.Time `xml:"lastModifiedDate"`
+}
+
+// ListMetadata lists the components selected by queries, e.g. to build a package.xml manifest or to detect changes by
+// LastModifiedDate. The Metadata API accepts 3 queries per call, more are sent with multiple calls.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_listmetadata.htm
+func (client *Client) ListMetadata(queries ...ListMetadataQuery) ([]FileProperties, error) {
+	var components []FileProperties
+	for start := 0; start < len(queries); start += maxListMetadataQueries {
+		end := start + maxListMetadataQueries
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		request := struct {
+			XMLName     xml.Name            `xml:"http://soap.sforce.com/2006/04/metadata listMetadata"`
+			Queries     []ListMetadataQuery `xml:"queries"`
+			AsOfVersion string              `xml:"asOfVersion"`
+		}{Queries: queries[start:end], AsOfVersion: client.apiVersion}
+		var response struct {
+			Results []FileProperties `xml:"Body>listMetadataResponse>result"`
+		}
+		err := client.metadataCall(&request, &response)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, response.Results...)
+	}
+	return components, nil
+}
+
+// PackageXML returns a package.xml manifest retrieving or deploying components at the API version of the client.
+func (client *Client) PackageXML(components []FileProperties) []byte {
+	var types []string
+	members := make(map[string][]string)
+	for _, component := range components {
+		if _, ok := members[component.Type]; !ok {
+			types = append(types, component.Type)
+		}
+		members[component.Type] = append(members[component.Type], component.FullName)
+	}
+	sort.Strings(types)
+
+	type packageTypes struct {
+		Members []string `xml:"members"`
+		Name    string   `xml:"name"`
+	}
+	manifest := struct {
+		XMLName xml.Name       `xml:"http://soap.sforce.com/2006/04/metadata Package"`
+		Types   []packageTypes `xml:"types"`
+		Version string         `xml:"version"`
+	}{Version: client.apiVersion}
+	for _, name := range types {
+		sort.Strings(members[name])
+		manifest.Types = append(manifest.Types, packageTypes{Members: members[name], Name: name})
+	}
+
+	data, _ := xml.MarshalIndent(manifest, "", "    ")
+	return append([]byte(xml.Header), data...)
+}
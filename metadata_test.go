@@ -0,0 +1,199 @@
+package simpleforce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_CreateMetadata(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/services/Soap/m/54.0" || r.Header.Get("SOAPAction") == "" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		for _, expected := range []string{
+			"<sessionId>__SESSION__</sessionId>",
+			`<createMetadata xmlns="http://soap.sforce.com/2006/04/metadata"><metadata xsi:type="CustomObject">`,
+			"<fullName>Invoice__c</fullName><deploymentStatus>Deployed</deploymentStatus><label>Invoice</label>" +
+				"<nameField><label>Invoice Number</label><type>AutoNumber</type></nameField>",
+			`<metadata xsi:type="RemoteSiteSetting"><fullName>Example</fullName>` +
+				"<disableProtocolSecurity>false</disableProtocolSecurity><isActive>true</isActive>",
+		} {
+			if !strings.Contains(string(body), expected) {
+				t.Errorf("%s missing in %s", expected, body)
+			}
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body>
+        <createMetadataResponse>
+            <result><fullName>Invoice__c</fullName><success>true</success></result>
+            <result>
+                <errors><message>Invalid URL</message><statusCode>FIELD_INTEGRITY_EXCEPTION</statusCode></errors>
+                <fullName>Example</fullName>
+                <success>false</success>
+            </result>
+        </createMetadataResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+	})
+
+	results, err := client.CreateMetadata(
+		&CustomObject{
+			FullName:         "Invoice__c",
+			DeploymentStatus: "Deployed",
+			Label:            "Invoice",
+			NameField:        &CustomField{Label: "Invoice Number", Type: "AutoNumber"},
+		},
+		&RemoteSiteSetting{FullName: "Example", IsActive: true, URL: "https://example.com"},
+	)
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Records) != 1 || batchErr.Records[0].ID != "Example" ||
+		batchErr.Records[0].Errors[0].StatusCode != "FIELD_INTEGRITY_EXCEPTION" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(results) != 2 || !results[0].Success || results[0].FullName != "Invoice__c" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestClient_DeleteMetadata(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "<fullNames>Invoice__c.Gone__c</fullNames>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+    <soapenv:Body>
+        <soapenv:Fault>
+            <faultcode>sf:INVALID_SESSION_ID</faultcode>
+            <faultstring>INVALID_SESSION_ID: Invalid Session ID found in SessionHeader</faultstring>
+        </soapenv:Fault>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+			return
+		}
+		if !strings.Contains(string(body), "<type>CustomField</type><fullNames>Invoice__c.Amount__c</fullNames>") {
+			t.Errorf("unexpected body %s", body)
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body>
+        <deleteMetadataResponse>
+            <result><fullName>Invoice__c.Amount__c</fullName><success>true</success></result>
+        </deleteMetadataResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+	})
+
+	results, err := client.DeleteMetadata("CustomField", "Invoice__c.Amount__c")
+	if err != nil || len(results) != 1 || !results[0].Success {
+		t.Errorf("unexpected results %+v, %v", results, err)
+	}
+
+	_, err = client.DeleteMetadata("CustomField", "Invoice__c.Gone__c")
+	if sfErr, ok := err.(SalesforceError); !ok || sfErr.ErrorCode != "INVALID_SESSION_ID" ||
+		sfErr.ErrorMessage != "Invalid Session ID found in SessionHeader" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestClient_ListMetadata(t *testing.T) {
+	var calls int
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls++
+		switch {
+		case strings.Contains(string(body), "<describeMetadata"):
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body>
+        <describeMetadataResponse>
+            <result>
+                <metadataObjects>
+                    <childXmlNames>CustomField</childXmlNames>
+                    <childXmlNames>ValidationRule</childXmlNames>
+                    <directoryName>objects</directoryName>
+                    <inFolder>false</inFolder>
+                    <metaFile>false</metaFile>
+                    <suffix>object</suffix>
+                    <xmlName>CustomObject</xmlName>
+                </metadataObjects>
+                <organizationNamespace></organizationNamespace>
+                <testRequired>false</testRequired>
+            </result>
+        </describeMetadataResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+		case strings.Contains(string(body), "<type>Report</type><folder>Sales</folder>"):
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body><listMetadataResponse/></soapenv:Body>
+</soapenv:Envelope>`))
+		case strings.Contains(string(body), "<listMetadata"):
+			if !strings.Contains(string(body), "<asOfVersion>54.0</asOfVersion>") {
+				t.Errorf("unexpected body %s", body)
+			}
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body>
+        <listMetadataResponse>
+            <result>
+                <fullName>Hello</fullName>
+                <id>01pA</id>
+                <lastModifiedByName>Admin</lastModifiedByName>
+                <lastModifiedDate>2022-05-01T10:00:00.000Z</lastModifiedDate>
+                <type>ApexClass</type>
+            </result>
+            <result>
+                <fullName>Account</fullName>
+                <type>CustomObject</type>
+            </result>
+        </listMetadataResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+		}
+	})
+
+	describe, err := client.DescribeMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(describe.MetadataObjects) != 1 || describe.MetadataObjects[0].Name != "CustomObject" ||
+		len(describe.MetadataObjects[0].ChildXMLNames) != 2 {
+		t.Errorf("unexpected result %+v", describe)
+	}
+
+	components, err := client.ListMetadata(
+		ListMetadataQuery{Type: "ApexClass"},
+		ListMetadataQuery{Type: "CustomObject"},
+		ListMetadataQuery{Type: "Layout"},
+		ListMetadataQuery{Type: "Report", Folder: "Sales"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(components) != 2 || components[0].LastModifiedByName != "Admin" ||
+		components[0].LastModifiedDate.Month() != 5 {
+		t.Errorf("unexpected components %+v", components)
+	}
+
+	manifest := string(client.PackageXML(append(components, FileProperties{Type: "ApexClass", FullName: "Bye"})))
+	expected := `<Package xmlns="http://soap.sforce.com/2006/04/metadata">
+    <types>
+        <members>Bye</members>
+        <members>Hello</members>
+        <name>ApexClass</name>
+    </types>
+    <types>
+        <members>Account</members>
+        <name>CustomObject</name>
+    </types>
+    <version>54.0</version>
+</Package>`
+	if !strings.HasSuffix(manifest, expected) {
+		t.Errorf("unexpected manifest %s", manifest)
+	}
+}
@@ -0,0 +1,185 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDeployPollInterval is used by WatchDeploy when no positive interval is given.
+const DefaultDeployPollInterval = 5 * time.Second
+
+// ErrDeployFailed is returned by WatchDeploy when a deployment finishes without success.
+var ErrDeployFailed = errors.New("deploy failed")
+
+// DeployOptions controls a deployment started with DeployMetadata. TestLevel is one of "NoTestRun", "RunSpecifiedTests"
+// (with RunTests), "RunLocalTests" and "RunAllTestsInOrg"; the org default is used if empty.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_deploy.htm#deploy_options
+type DeployOptions struct {
+	AllowMissingFiles bool     `xml:"allowMissingFiles"`
+	CheckOnly         bool     `xml:"checkOnly"`
+	IgnoreWarnings    bool     `xml:"ignoreWarnings"`
+	PurgeOnDelete     bool     `xml:"purgeOnDelete"`
+	RollbackOnError   bool     `xml:"rollbackOnError"`
+	RunTests          []string `xml:"runTests,omitempty"`
+	SinglePackage     bool     `xml:"singlePackage"`
+	TestLevel         string   `xml:"testLevel,omitempty"`
+}
+
+// DeployMessage reports the outcome of deploying a single component.
+type DeployMessage struct {
+	ComponentType string `xml:"componentType"`
+	FullName      string `xml:"fullName"`
+	FileName      string `xml:"fileName"`
+	Success       bool   `xml:"success"`
+	Created       bool   `xml:"created"`
+	Changed       bool   `xml:"changed"`
+	Deleted       bool   `xml:"deleted"`
+	Problem       string `xml:"problem"`
+	ProblemType   string `xml:"problemType"`
+	LineNumber    int    `xml:"lineNumber"`
+	ColumnNumber  int    `xml:"columnNumber"`
+}
+
+// RunTestFailure reports a failed Apex test method of a deployment.
+type RunTestFailure struct {
+	Name       string  `xml:"name"`
+	MethodName string  `xml:"methodName"`
+	Message    string  `xml:"message"`
+	StackTrace string  `xml:"stackTrace"`
+	Time       float64 `xml:"time"`
+}
+
+// DeployResult is the status of a deployment as returned by CheckDeployStatus. Details are only set if requested.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_deployresult.htm
+type DeployResult struct {
+	ID                       string `xml:"id"`
+	Status                   string `xml:"status"`
+	StateDetail              string `xml:"stateDetail"`
+	Done                     bool   `xml:"done"`
+	Success                  bool   `xml:"success"`
+	CheckOnly                bool   `xml:"checkOnly"`
+	ErrorStatusCode          string `xml:"errorStatusCode"`
+	ErrorMessage             string `xml:"errorMessage"`
+	NumberComponentsTotal    int    `xml:"numberComponentsTotal"`
+	NumberComponentsDeployed int    `xml:"numberComponentsDeployed"`
+	NumberComponentErrors    int    `xml:"numberComponentErrors"`
+	NumberTestsTotal         int    `xml:"numberTestsTotal"`
+	NumberTestsCompleted     int    `xml:"numberTestsCompleted"`
+	NumberTestErrors         int    `xml:"numberTestErrors"`
+	Details                  struct {
+		ComponentFailures []DeployMessage `xml:"componentFailures"`
+		RunTestResult     struct {
+			NumTestsRun int              `xml:"numTestsRun"`
+			NumFailures int              `xml:"numFailures"`
+			Failures    []RunTestFailure `xml:"failures"`
+		} `xml:"runTestResult"`
+	} `xml:"details"`
+}
+
+// DeployEvent is passed to the callback of WatchDeploy after every poll. Result is the latest status of the
+// deployment; the failures are those reported since the previous event.
+type DeployEvent struct {
+	Result            *DeployResult
+	ComponentFailures []DeployMessage
+	TestFailures      []RunTestFailure
+}
+
+// DeployMetadata starts the asynchronous deployment of zipFile, a zip archive containing a package.xml manifest and
+// the components, and returns the ID of the deployment for CheckDeployStatus or WatchDeploy.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_deploy.htm
+func (client *Client) DeployMetadata(zipFile []byte, options DeployOptions) (string, error) {
+	request := struct {
+		XMLName       xml.Name      `xml:"http://soap.sforce.com/2006/04/metadata deploy"`
+		ZipFile       string        `xml:"ZipFile"`
+		DeployOptions DeployOptions `xml:"DeployOptions"`
+	}{ZipFile: base64.StdEncoding.EncodeToString(zipFile), DeployOptions: options}
+	var response struct {
+		ID string `xml:"Body>deployResponse>result>id"`
+	}
+	err := client.metadataCall(&request, &response)
+	if err != nil {
+		return "", err
+	}
+	client.saveJob(JobRecord{ID: response.ID, Kind: JobDeploy, State: "Pending"})
+	return response.ID, nil
+}
+
+// CheckDeployStatus returns the status of the deployment id, including the component and test results if
+// includeDetails is set.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_checkdeploystatus.htm
+func (client *Client) CheckDeployStatus(id string, includeDetails bool) (*DeployResult, error) {
+	request := struct {
+		XMLName        xml.Name `xml:"http://soap.sforce.com/2006/04/metadata checkDeployStatus"`
+		AsyncProcessID string   `xml:"asyncProcessId"`
+		IncludeDetails bool     `xml:"includeDetails"`
+	}{AsyncProcessID: id, IncludeDetails: includeDetails}
+	var response struct {
+		Result DeployResult `xml:"Body>checkDeployStatusResponse>result"`
+	}
+	err := client.metadataCall(&request, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Result, nil
+}
+
+// WatchDeploy polls the status of the deployment id every interval, DefaultDeployPollInterval if not positive, until
+// it is done or ctx is canceled. onEvent, if not nil, is called after every poll with the progress of components and
+// tests and any failures reported since the previous call. The final result is returned; if the deployment did not
+// succeed, the error matches ErrDeployFailed with errors.Is. With a job store, see SetJobStore, the deployment is
+// recorded until it is done.
+func (client *Client) WatchDeploy(
+	ctx context.Context,
+	id string,
+	interval time.Duration,
+	onEvent func(*DeployEvent),
+) (*DeployResult, error) {
+	if interval <= 0 {
+		interval = DefaultDeployPollInterval
+	}
+
+	var componentFailures, testFailures int
+	for {
+		result, err := client.CheckDeployStatus(id, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if onEvent != nil {
+			// Failures accumulate over the polls of a deployment, so only the ones beyond those seen before are new.
+			event := &DeployEvent{Result: result}
+			if failures := result.Details.ComponentFailures; len(failures) > componentFailures {
+				event.ComponentFailures = failures[componentFailures:]
+				componentFailures = len(failures)
+			}
+			if failures := result.Details.RunTestResult.Failures; len(failures) > testFailures {
+				event.TestFailures = failures[testFailures:]
+				testFailures = len(failures)
+			}
+			onEvent(event)
+		}
+		if !result.Done {
+			client.saveJob(JobRecord{ID: id, Kind: JobDeploy, State: result.Status})
+		} else {
+			client.deleteJob(id)
+			if !result.Success {
+				if result.ErrorMessage != "" {
+					return result, errors.Wrapf(ErrDeployFailed, "%s: %s", result.Status, result.ErrorMessage)
+				}
+				return result, errors.Wrapf(ErrDeployFailed, "%s with %d component errors and %d test errors",
+					result.Status, result.NumberComponentErrors, result.NumberTestErrors)
+			}
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
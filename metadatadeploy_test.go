@@ -0,0 +1,77 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_WatchDeploy(t *testing.T) {
+	statuses := []string{
+		`<id>0AfA</id><done>false</done><status>InProgress</status>
+		<numberComponentsTotal>2</numberComponentsTotal><numberComponentsDeployed>1</numberComponentsDeployed>
+		<details><componentFailures><fullName>Broken</fullName><problem>Unexpected token</problem></componentFailures></details>`,
+		`<id>0AfA</id><done>false</done><status>InProgress</status><numberTestsTotal>4</numberTestsTotal>
+		<details>
+			<componentFailures><fullName>Broken</fullName><problem>Unexpected token</problem></componentFailures>
+			<runTestResult><failures><name>HelloTest</name><methodName>testHello</methodName></failures></runTestResult>
+		</details>`,
+		`<id>0AfA</id><done>true</done><success>false</success><status>Failed</status>
+		<numberComponentErrors>1</numberComponentErrors><numberTestErrors>1</numberTestErrors>`,
+	}
+	var polls int
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "<deploy "):
+			zipFile := base64.StdEncoding.EncodeToString([]byte("PK"))
+			if !strings.Contains(string(body), "<ZipFile>"+zipFile+"</ZipFile>") ||
+				!strings.Contains(string(body), "<checkOnly>true</checkOnly>") ||
+				!strings.Contains(string(body), "<runTests>HelloTest</runTests><singlePackage>false</singlePackage>"+
+					"<testLevel>RunSpecifiedTests</testLevel>") {
+				t.Errorf("unexpected body %s", body)
+			}
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body><deployResponse><result><id>0AfA</id><state>Queued</state></result></deployResponse></soapenv:Body>
+</soapenv:Envelope>`))
+		case strings.Contains(string(body), "<asyncProcessId>0AfA</asyncProcessId><includeDetails>true</includeDetails>"):
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body><checkDeployStatusResponse><result>` + statuses[polls] + `</result></checkDeployStatusResponse></soapenv:Body>
+</soapenv:Envelope>`))
+			polls++
+		default:
+			t.Errorf("unexpected body %s", body)
+		}
+	})
+
+	id, err := client.DeployMetadata([]byte("PK"), DeployOptions{
+		CheckOnly: true,
+		RunTests:  []string{"HelloTest"},
+		TestLevel: "RunSpecifiedTests",
+	})
+	if err != nil || id != "0AfA" {
+		t.Fatalf("unexpected result %s, %v", id, err)
+	}
+
+	var events []*DeployEvent
+	result, err := client.WatchDeploy(context.Background(), id, time.Millisecond, func(event *DeployEvent) {
+		events = append(events, event)
+	})
+	if errors.Cause(err) != ErrDeployFailed || result == nil || result.Status != "Failed" {
+		t.Errorf("unexpected result %+v, %v", result, err)
+	}
+	if len(events) != 3 || events[0].Result.NumberComponentsDeployed != 1 ||
+		len(events[0].ComponentFailures) != 1 || events[0].ComponentFailures[0].FullName != "Broken" ||
+		len(events[1].ComponentFailures) != 0 || len(events[1].TestFailures) != 1 ||
+		events[1].TestFailures[0].MethodName != "testHello" || len(events[2].TestFailures) != 0 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
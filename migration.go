@@ -0,0 +1,55 @@
+package simpleforce
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// migrationHeaders are the headers sent with DML requests in migration mode.
+var migrationHeaders = map[string]string{
+	// Save records matching duplicate rules whose action is "Allow" with an alert.
+	"Sforce-Duplicate-Rule-Header": "allowSave=true",
+	// Skip the case and lead assignment rules.
+	"Sforce-Auto-Assign": "FALSE",
+}
+
+// SetMigrationMode enables or disables migration mode, in which every REST API request changing data made with the
+// client, by any goroutine, is sent with the headers bypassing the automation the REST API lets callers opt out of:
+//
+//   - duplicate rules with the "Allow" action and an alert, which otherwise fail API saves with DUPLICATES_DETECTED;
+//     rules with the "Block" action still block
+//   - case and lead assignment rules, so that migrated records keep their OwnerId
+//
+// Headers set explicitly by a call, e.g. the assignment rule of CreateCase, take precedence. Validation rules, flows,
+// Apex triggers, workflow rules and emails cannot be bypassed by headers; see DeactivateFlow and
+// SetValidationRuleActive for switching the former off. Bulk API jobs are not affected.
+func (client *Client) SetMigrationMode(enabled bool) {
+	var mode int32
+	if enabled {
+		mode = 1
+	}
+	atomic.StoreInt32(&client.migrationMode, mode)
+}
+
+// WithMigrationMode calls fn with migration mode enabled. Migration mode applies to the whole client while fn runs,
+// i.e. to the requests of other goroutines using the client too; use a separate client for the migration if they must
+// not be affected. It stays enabled until the last of overlapping calls returns, or while enabled by SetMigrationMode.
+func (client *Client) WithMigrationMode(fn func() error) error {
+	atomic.AddInt32(&client.migrationScopes, 1)
+	defer atomic.AddInt32(&client.migrationScopes, -1)
+	return fn()
+}
+
+// migrating reports whether migration mode is enabled by SetMigrationMode or a running WithMigrationMode.
+func (client *Client) migrating() bool {
+	return atomic.LoadInt32(&client.migrationMode) == 1 || atomic.LoadInt32(&client.migrationScopes) > 0
+}
+
+// setMigrationHeaders adds the migration mode headers not already present to header.
+func setMigrationHeaders(header http.Header) {
+	for key, value := range migrationHeaders {
+		if header.Get(key) == "" {
+			header.Set(key, value)
+		}
+	}
+}
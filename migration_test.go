@@ -0,0 +1,83 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_WithMigrationMode(t *testing.T) {
+	headers := map[string]http.Header{}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers[r.Method] = r.Header.Clone()
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "500A", "success": true, "errors": []}`))
+		}
+	})
+
+	err := client.WithMigrationMode(func() error {
+		if _, err := client.Query("SELECT Id FROM Case"); err != nil {
+			return err
+		}
+		_, err := client.CreateCase(client.SObject("Case").Set("Subject", "Migrated"), DefaultAssignmentRule)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers[http.MethodGet].Get("Sforce-Duplicate-Rule-Header") != "" {
+		t.Error("expected no migration headers on queries")
+	}
+	post := headers[http.MethodPost]
+	if post.Get("Sforce-Duplicate-Rule-Header") != "allowSave=true" {
+		t.Errorf("unexpected duplicate rule header %q", post.Get("Sforce-Duplicate-Rule-Header"))
+	}
+	if post.Get("Sforce-Auto-Assign") != string(DefaultAssignmentRule) {
+		t.Errorf("expected the explicit assignment rule to take precedence, got %q", post.Get("Sforce-Auto-Assign"))
+	}
+	if client.migrating() {
+		t.Error("expected migration mode to be restored")
+	}
+
+	client.SetMigrationMode(true)
+	if _, err = client.CreateCase(client.SObject("Case").Set("Subject", "Migrated"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := headers[http.MethodPost].Get("Sforce-Auto-Assign"); got != "FALSE" {
+		t.Errorf("expected assignment rules to be suppressed, got %q", got)
+	}
+}
+
+func TestClient_WithMigrationModeOverlapping(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	go client.WithMigrationMode(func() error {
+		close(entered)
+		<-release
+		return nil
+	})
+	<-entered
+
+	client.WithMigrationMode(func() error { return nil })
+	if !client.migrating() {
+		t.Error("expected migration mode to stay enabled while another call runs")
+	}
+	close(release)
+	for deadline := time.Now().Add(time.Second); client.migrating() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if client.migrating() {
+		t.Error("expected migration mode to be disabled after the last call")
+	}
+
+	client.SetMigrationMode(true)
+	client.WithMigrationMode(func() error { return nil })
+	if !client.migrating() {
+		t.Error("expected migration mode to stay enabled by SetMigrationMode")
+	}
+}
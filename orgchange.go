@@ -0,0 +1,120 @@
+package simpleforce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// ErrOrgChanged matches an OrgChangedError.
+var ErrOrgChanged = errors.New("org changed")
+
+// OrgChangedError is returned when the client turns out to be connected to a different org than expected, typically
+// because a sandbox was refreshed, which gives it a new org ID and invalidates all of its sessions. Integrations should
+// treat it as a signal to re-onboard the org, e.g. to reset sync cursors and stored record IDs, rather than retrying
+// the login. An OrgChangedError matches ErrOrgChanged and ErrAuthentication with errors.Is.
+type OrgChangedError struct {
+	// ExpectedOrgID is the org ID pinned with SetExpectedOrgID, or the org ID of the previous login.
+	ExpectedOrgID string
+	// OrgID is the org ID the client is now connected to, if known.
+	OrgID string
+	// Err is the failure the change was detected from, if any, e.g. a rejected session.
+	Err error
+}
+
+// Error implements the error interface.
+func (err *OrgChangedError) Error() string {
+	msg := fmt.Sprintf("org changed from %s", err.ExpectedOrgID)
+	if err.OrgID != "" {
+		msg += " to " + err.OrgID
+	}
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the failure the change was detected from.
+func (err *OrgChangedError) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether target is ErrOrgChanged or ErrAuthentication.
+func (err *OrgChangedError) Is(target error) bool {
+	return target == ErrOrgChanged || target == ErrAuthentication
+}
+
+// SetExpectedOrgID pins the org the client must be connected to, e.g. the org ID stored when the org was onboarded.
+// Logins into a different org then fail with an OrgChangedError, as do requests rejected with INVALID_SESSION_ID whose
+// session belongs to a different org. An empty id removes the pin, in which case only logins into a different org
+// than the previous login are reported.
+func (client *Client) SetExpectedOrgID(id string) {
+	client.expectedOrgID = id
+}
+
+// OrganizationID returns the ID of the org the client is connected to, as reported at login or encoded in the session
+// ID passed to SetSidLoc.
+func (client *Client) OrganizationID() string {
+	return client.organizationID
+}
+
+// VerifyOrg queries the org the session belongs to and returns an OrgChangedError if it is not the expected org, see
+// SetExpectedOrgID. Without a pinned org ID the org ID of the last login is expected.
+func (client *Client) VerifyOrg() error {
+	result, err := client.Query("SELECT Id FROM Organization")
+	if err != nil {
+		return err
+	}
+	if len(result.Records) == 0 {
+		return errors.New("organization not found")
+	}
+	orgID := result.Records[0].ID()
+	expected := client.expectedOrgID
+	if expected == "" {
+		expected = client.organizationID
+	}
+	client.organizationID = orgID
+	if expected != "" && !sameID(expected, orgID) {
+		return &OrgChangedError{ExpectedOrgID: expected, OrgID: orgID}
+	}
+	return nil
+}
+
+// checkLoginOrg compares the org of a login with the expected org, or the org of the previous login.
+func (client *Client) checkLoginOrg(previousOrgID, orgID string) error {
+	expected := client.expectedOrgID
+	if expected == "" {
+		expected = previousOrgID
+	}
+	if expected == "" || orgID == "" || sameID(expected, orgID) {
+		return nil
+	}
+	return &OrgChangedError{ExpectedOrgID: expected, OrgID: orgID}
+}
+
+// orgChangedError turns a rejected session into an OrgChangedError if the session belongs to a different org than
+// the pinned one, and returns nil otherwise.
+func (client *Client) orgChangedError(sfErr SalesforceError) error {
+	if sfErr.ErrorCode != errcode.InvalidSessionID || client.expectedOrgID == "" {
+		return nil
+	}
+	orgID := sessionOrgID(client.sessionID)
+	if orgID == "" {
+		orgID = client.organizationID
+	}
+	if orgID == "" || sameID(orgID, client.expectedOrgID) {
+		return nil
+	}
+	return &OrgChangedError{ExpectedOrgID: client.expectedOrgID, OrgID: orgID, Err: sfErr}
+}
+
+// sessionOrgID returns the org ID salesforce session IDs start with, e.g. "00D5g000004ABCD!AQ...", or an empty string.
+func sessionOrgID(sessionID string) string {
+	idx := strings.IndexByte(sessionID, '!')
+	if (idx != 15 && idx != 18) || !strings.HasPrefix(sessionID, "00D") {
+		return ""
+	}
+	return sessionID[:idx]
+}
@@ -0,0 +1,98 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_LoginPasswordOrgChanged(t *testing.T) {
+	orgID := "00D000000000001AAA"
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com">
+				<soapenv:Body><loginResponse><result>
+					<serverUrl>https://example.my.salesforce.com/services/Soap/u/54.0/%[1]s</serverUrl>
+					<sessionId>%.15[1]s!SESSION</sessionId>
+					<userInfo><organizationId>%[1]s</organizationId><userName>user@example.com</userName></userInfo>
+				</result></loginResponse></soapenv:Body>
+			</soapenv:Envelope>`, orgID)
+	})
+	client.SetSidLoc("", "")
+	client.baseURL = server.URL
+
+	if err := client.LoginPassword("user@example.com", "password", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoginPassword("user@example.com", "password", ""); err != nil {
+		t.Fatalf("unexpected error on login into the same org, %v", err)
+	}
+
+	// The sandbox was refreshed.
+	orgID = "00D000000000002AAA"
+	err := client.LoginPassword("user@example.com", "password", "")
+	var orgErr *OrgChangedError
+	if !errors.As(err, &orgErr) || !errors.Is(err, ErrOrgChanged) || !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected OrgChangedError, got %v", err)
+	}
+	if orgErr.ExpectedOrgID != "00D000000000001AAA" || orgErr.OrgID != orgID || client.OrganizationID() != orgID {
+		t.Errorf("unexpected error %v, org %s", orgErr, client.OrganizationID())
+	}
+	if err := client.LoginPassword("user@example.com", "password", ""); err != nil {
+		t.Errorf("expected the new org to be accepted without a pin, got %v", err)
+	}
+
+	client.SetExpectedOrgID("00D000000000001")
+	if err := client.LoginPassword("user@example.com", "password", ""); !errors.Is(err, ErrOrgChanged) {
+		t.Errorf("expected pinned org to be enforced, got %v", err)
+	}
+}
+
+func TestClient_OrgChangedSession(t *testing.T) {
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`))
+	})
+	client.SetSidLoc("00D000000000002!SESSION", server.URL)
+	if client.OrganizationID() != "00D000000000002" {
+		t.Errorf("unexpected org %s", client.OrganizationID())
+	}
+
+	_, err := client.Query("SELECT Id FROM Account")
+	if errors.Is(err, ErrOrgChanged) {
+		t.Errorf("expected plain session error without a pinned org, got %v", err)
+	}
+
+	client.SetExpectedOrgID("00D000000000001AAA")
+	_, err = client.Query("SELECT Id FROM Account")
+	var sfErr SalesforceError
+	if !errors.Is(err, ErrOrgChanged) || !errors.As(err, &sfErr) || sfErr.HttpCode != http.StatusUnauthorized {
+		t.Errorf("expected OrgChangedError wrapping the session error, got %v", err)
+	}
+
+	client.SetExpectedOrgID("00D000000000002AAA")
+	if _, err = client.Query("SELECT Id FROM Account"); errors.Is(err, ErrOrgChanged) {
+		t.Errorf("expected plain session error for the pinned org, got %v", err)
+	}
+}
+
+func TestClient_VerifyOrg(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [
+			{"attributes": {"type": "Organization"}, "Id": "00D000000000002AAA"}]}`))
+	})
+
+	if err := client.VerifyOrg(); err != nil {
+		t.Fatal(err)
+	}
+	if client.OrganizationID() != "00D000000000002AAA" {
+		t.Errorf("unexpected org %s", client.OrganizationID())
+	}
+
+	client.SetExpectedOrgID("00D000000000001AAA")
+	if err := client.VerifyOrg(); !errors.Is(err, ErrOrgChanged) {
+		t.Errorf("expected ErrOrgChanged, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// OrgShape is a snapshot of the objects, fields, picklist values and selected metadata components of an org, as taken
+// by SnapshotOrgShape. It can be saved as JSON to compare against later.
+type OrgShape struct {
+	Objects  map[string]*ObjectShape `json:"objects"`
+	Metadata map[string][]string     `json:"metadata,omitempty"` // full names of components by metadata type
+}
+
+// ObjectShape describes an object of an OrgShape.
+type ObjectShape struct {
+	Name   string                 `json:"name"`
+	Custom bool                   `json:"custom"`
+	Fields map[string]*FieldShape `json:"fields"`
+}
+
+// FieldShape describes a field of an ObjectShape. PicklistValues holds the active values of picklist fields.
+type FieldShape struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Length         int      `json:"length"`
+	Precision      int      `json:"precision"`
+	Scale          int      `json:"scale"`
+	Nillable       bool     `json:"nillable"`
+	ReferenceTo    []string `json:"referenceTo"`
+	PicklistValues []string `json:"picklistValues,omitempty"`
+}
+
+// SnapshotOrgShape describes objects, all objects of the org if empty, and lists the components of metadataTypes,
+// e.g. "ApexClass", with ListMetadata.
+func (client *Client) SnapshotOrgShape(objects []string, metadataTypes ...string) (*OrgShape, error) {
+	if len(objects) == 0 {
+		global, err := client.ListSObjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range global.SObjects {
+			objects = append(objects, info.Name)
+		}
+	}
+
+	metas, err := client.DescribeSObjects(objects...)
+	if err != nil {
+		return nil, err
+	}
+	shape := &OrgShape{Objects: make(map[string]*ObjectShape, len(metas))}
+	for name, meta := range metas {
+		object, err := newObjectShape(meta)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		shape.Objects[name] = object
+	}
+
+	if len(metadataTypes) > 0 {
+		queries := make([]ListMetadataQuery, len(metadataTypes))
+		for idx, metadataType := range metadataTypes {
+			queries[idx] = ListMetadataQuery{Type: metadataType}
+		}
+		components, err := client.ListMetadata(queries...)
+		if err != nil {
+			return nil, err
+		}
+		shape.Metadata = make(map[string][]string, len(metadataTypes))
+		for _, component := range components {
+			shape.Metadata[component.Type] = append(shape.Metadata[component.Type], component.FullName)
+		}
+	}
+	return shape, nil
+}
+
+// newObjectShape extracts the shape of an object from its describe metadata.
+func newObjectShape(meta *SObjectMeta) (*ObjectShape, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	var describe struct {
+		Name   string `json:"name"`
+		Custom bool   `json:"custom"`
+		Fields []struct {
+			FieldShape
+			PicklistValues []struct {
+				Value  string `json:"value"`
+				Active bool   `json:"active"`
+			} `json:"picklistValues"`
+		} `json:"fields"`
+	}
+	err = json.Unmarshal(data, &describe)
+	if err != nil {
+		return nil, err
+	}
+
+	object := &ObjectShape{Name: describe.Name, Custom: describe.Custom, Fields: make(map[string]*FieldShape)}
+	for _, field := range describe.Fields {
+		f := field.FieldShape
+		for _, value := range field.PicklistValues {
+			if value.Active {
+				f.PicklistValues = append(f.PicklistValues, value.Value)
+			}
+		}
+		object.Fields[f.Name] = &f
+	}
+	return object, nil
+}
+
+// OrgShapeDiff lists the differences of a target org from a source org, e.g. a sandbox from production. Missing
+// elements exist in the source only, new ones in the target only. Fields are named "Object.Field", metadata components
+// "Type:FullName".
+type OrgShapeDiff struct {
+	MissingObjects   []string
+	NewObjects       []string
+	MissingFields    []string
+	NewFields        []string
+	ChangedFields    []FieldChange
+	ChangedPicklists []PicklistChange
+	MissingMetadata  []string
+	NewMetadata      []string
+}
+
+// FieldChange is a property of a field which differs between the orgs, e.g. Property "Length" changed from Source "80"
+// to Target "255".
+type FieldChange struct {
+	Field    string
+	Property string
+	Source   string
+	Target   string
+}
+
+// PicklistChange lists the active values of a picklist field which were added or removed in the target org.
+type PicklistChange struct {
+	Field   string
+	Added   []string
+	Removed []string
+}
+
+// Empty reports whether the orgs have the same shape.
+func (diff *OrgShapeDiff) Empty() bool {
+	return len(diff.MissingObjects) == 0 && len(diff.NewObjects) == 0 &&
+		len(diff.MissingFields) == 0 && len(diff.NewFields) == 0 &&
+		len(diff.ChangedFields) == 0 && len(diff.ChangedPicklists) == 0 &&
+		len(diff.MissingMetadata) == 0 && len(diff.NewMetadata) == 0
+}
+
+// CompareOrgShapes returns the differences of target from source, sorted by name. Only metadata types captured in both
+// snapshots are compared.
+func CompareOrgShapes(source, target *OrgShape) *OrgShapeDiff {
+	diff := &OrgShapeDiff{}
+	diff.MissingObjects, diff.NewObjects = compareNames(objectNames(source), objectNames(target))
+
+	for _, name := range objectNames(source) {
+		targetObject, ok := target.Objects[name]
+		if !ok {
+			continue
+		}
+		sourceObject := source.Objects[name]
+		for _, fieldName := range fieldNames(sourceObject) {
+			targetField, ok := targetObject.Fields[fieldName]
+			if !ok {
+				diff.MissingFields = append(diff.MissingFields, name+"."+fieldName)
+				continue
+			}
+			diff.compareField(name+"."+fieldName, sourceObject.Fields[fieldName], targetField)
+		}
+		for _, fieldName := range fieldNames(targetObject) {
+			if _, ok := sourceObject.Fields[fieldName]; !ok {
+				diff.NewFields = append(diff.NewFields, name+"."+fieldName)
+			}
+		}
+	}
+
+	for metadataType, sourceNames := range source.Metadata {
+		targetNames, ok := target.Metadata[metadataType]
+		if !ok {
+			continue
+		}
+		missing, added := compareNames(sourceNames, targetNames)
+		for _, fullName := range missing {
+			diff.MissingMetadata = append(diff.MissingMetadata, metadataType+":"+fullName)
+		}
+		for _, fullName := range added {
+			diff.NewMetadata = append(diff.NewMetadata, metadataType+":"+fullName)
+		}
+	}
+	sort.Strings(diff.MissingMetadata)
+	sort.Strings(diff.NewMetadata)
+	return diff
+}
+
+// compareField records the changes of the field name from source to target.
+func (diff *OrgShapeDiff) compareField(name string, source, target *FieldShape) {
+	properties := []struct {
+		name           string
+		source, target interface{}
+	}{
+		{"Type", source.Type, target.Type},
+		{"Length", source.Length, target.Length},
+		{"Precision", source.Precision, target.Precision},
+		{"Scale", source.Scale, target.Scale},
+		{"Nillable", source.Nillable, target.Nillable},
+		{"ReferenceTo", strings.Join(source.ReferenceTo, ","), strings.Join(target.ReferenceTo, ",")},
+	}
+	for _, property := range properties {
+		if property.source != property.target {
+			diff.ChangedFields = append(diff.ChangedFields, FieldChange{
+				Field:    name,
+				Property: property.name,
+				Source:   fmt.Sprint(property.source),
+				Target:   fmt.Sprint(property.target),
+			})
+		}
+	}
+
+	removed, added := compareNames(source.PicklistValues, target.PicklistValues)
+	if len(removed) > 0 || len(added) > 0 {
+		diff.ChangedPicklists = append(diff.ChangedPicklists, PicklistChange{Field: name, Added: added, Removed: removed})
+	}
+}
+
+// CompareOrgs snapshots the shapes of the orgs of source and target and returns their differences.
+func CompareOrgs(source, target *Client, objects []string, metadataTypes ...string) (*OrgShapeDiff, error) {
+	sourceShape, err := source.SnapshotOrgShape(objects, metadataTypes...)
+	if err != nil {
+		return nil, err
+	}
+	targetShape, err := target.SnapshotOrgShape(objects, metadataTypes...)
+	if err != nil {
+		return nil, err
+	}
+	return CompareOrgShapes(sourceShape, targetShape), nil
+}
+
+// compareNames returns the sorted names only in source, and only in target.
+func compareNames(source, target []string) (missing, added []string) {
+	inTarget := make(map[string]bool, len(target))
+	for _, name := range target {
+		inTarget[name] = true
+	}
+	inSource := make(map[string]bool, len(source))
+	for _, name := range source {
+		inSource[name] = true
+		if !inTarget[name] {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range target {
+		if !inSource[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(added)
+	return missing, added
+}
+
+// objectNames returns the sorted names of the objects of shape.
+func objectNames(shape *OrgShape) []string {
+	names := make([]string, 0, len(shape.Objects))
+	for name := range shape.Objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fieldNames returns the sorted names of the fields of object.
+func fieldNames(object *ObjectShape) []string {
+	names := make([]string, 0, len(object.Fields))
+	for name := range object.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+package simpleforce
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_SnapshotOrgShape(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sobjects/Account/describe") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"name": "Account", "custom": false, "fields": [
+			{"name": "Name", "type": "string", "length": 255, "nillable": false, "referenceTo": []},
+			{"name": "Rating", "type": "picklist", "length": 40, "nillable": true, "referenceTo": [], "picklistValues": [
+				{"value": "Hot", "active": true}, {"value": "Warm", "active": false}, {"value": "Cold", "active": true}
+			]}
+		]}`))
+	})
+
+	shape, err := client.SnapshotOrgShape([]string{"Account"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := shape.Objects["Account"]
+	if account == nil || len(account.Fields) != 2 || account.Fields["Name"].Length != 255 ||
+		!reflect.DeepEqual(account.Fields["Rating"].PicklistValues, []string{"Hot", "Cold"}) {
+		t.Errorf("unexpected shape %+v", account)
+	}
+}
+
+func TestCompareOrgShapes(t *testing.T) {
+	source := &OrgShape{
+		Objects: map[string]*ObjectShape{
+			"Account": {Name: "Account", Fields: map[string]*FieldShape{
+				"Name":   {Name: "Name", Type: "string", Length: 255},
+				"Rating": {Name: "Rating", Type: "picklist", PicklistValues: []string{"Hot", "Cold"}},
+				"Old__c": {Name: "Old__c", Type: "string"},
+			}},
+			"Invoice__c": {Name: "Invoice__c", Custom: true},
+		},
+		Metadata: map[string][]string{"ApexClass": {"Hello", "Bye"}, "Layout": {"Account Layout"}},
+	}
+	target := &OrgShape{
+		Objects: map[string]*ObjectShape{
+			"Account": {Name: "Account", Fields: map[string]*FieldShape{
+				"Name":   {Name: "Name", Type: "string", Length: 80},
+				"Rating": {Name: "Rating", Type: "picklist", PicklistValues: []string{"Hot", "Warm"}},
+				"New__c": {Name: "New__c", Type: "string"},
+			}},
+			"Order__c": {Name: "Order__c", Custom: true},
+		},
+		Metadata: map[string][]string{"ApexClass": {"Hello", "World"}},
+	}
+
+	diff := CompareOrgShapes(source, target)
+	expected := &OrgShapeDiff{
+		MissingObjects:   []string{"Invoice__c"},
+		NewObjects:       []string{"Order__c"},
+		MissingFields:    []string{"Account.Old__c"},
+		NewFields:        []string{"Account.New__c"},
+		ChangedFields:    []FieldChange{{Field: "Account.Name", Property: "Length", Source: "255", Target: "80"}},
+		ChangedPicklists: []PicklistChange{{Field: "Account.Rating", Added: []string{"Warm"}, Removed: []string{"Cold"}}},
+		MissingMetadata:  []string{"ApexClass:Bye"},
+		NewMetadata:      []string{"ApexClass:World"},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+	if diff.Empty() || !CompareOrgShapes(source, source).Empty() {
+		t.Error("unexpected Empty")
+	}
+}
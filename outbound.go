@@ -0,0 +1,179 @@
+package simpleforce
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const (
+	outboundAckResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+    <soapenv:Body>
+        <notificationsResponse xmlns="http://soap.sforce.com/2005/09/outbound">
+            <Ack>true</Ack>
+        </notificationsResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`
+
+	outboundFaultResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+    <soapenv:Body>
+        <soapenv:Fault>
+            <faultcode>soapenv:Server</faultcode>
+            <faultstring>%s</faultstring>
+        </soapenv:Fault>
+    </soapenv:Body>
+</soapenv:Envelope>`
+
+	xmlSchemaInstanceNS = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// OutboundMessage is a SOAP notification sent by a Salesforce workflow or flow Outbound Message action.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_om_outboundmessaging_understanding.htm
+type OutboundMessage struct {
+	OrganizationID string
+	ActionID       string
+	SessionID      string
+	EnterpriseURL  string
+	PartnerURL     string
+	Notifications  []OutboundNotification
+}
+
+// OutboundNotification is a single record notification of an OutboundMessage. SObject holds the fields selected in
+// the Outbound Message definition; its type is set from the notification.
+type OutboundNotification struct {
+	ID      string
+	SObject *SObject
+}
+
+type outboundEnvelope struct {
+	XMLName       xml.Name `xml:"Envelope"`
+	Notifications struct {
+		OrganizationID string `xml:"OrganizationId"`
+		ActionID       string `xml:"ActionId"`
+		SessionID      string `xml:"SessionId"`
+		EnterpriseURL  string `xml:"EnterpriseUrl"`
+		PartnerURL     string `xml:"PartnerUrl"`
+		Notification   []struct {
+			ID      string `xml:"Id"`
+			SObject struct {
+				Attrs  []xml.Attr `xml:",any,attr"`
+				Fields []struct {
+					XMLName xml.Name
+					Attrs   []xml.Attr `xml:",any,attr"`
+					Value   string     `xml:",chardata"`
+				} `xml:",any"`
+			} `xml:"sObject"`
+		} `xml:"Notification"`
+	} `xml:"Body>notifications"`
+}
+
+// ParseOutboundMessage parses the SOAP body of an Outbound Message notification.
+func ParseOutboundMessage(body []byte) (*OutboundMessage, error) {
+	var envelope outboundEnvelope
+	err := xml.Unmarshal(body, &envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := envelope.Notifications
+	msg := &OutboundMessage{
+		OrganizationID: notifications.OrganizationID,
+		ActionID:       notifications.ActionID,
+		SessionID:      notifications.SessionID,
+		EnterpriseURL:  notifications.EnterpriseURL,
+		PartnerURL:     notifications.PartnerURL,
+	}
+	for _, notification := range notifications.Notification {
+		obj := &SObject{}
+		for _, attr := range notification.SObject.Attrs {
+			if attr.Name.Space == xmlSchemaInstanceNS && attr.Name.Local == "type" {
+				// The type is prefixed with the namespace of the object, e.g. "sf:Account".
+				obj.setType(attr.Value[strings.LastIndex(attr.Value, ":")+1:])
+			}
+		}
+		for _, field := range notification.SObject.Fields {
+			var value interface{} = field.Value
+			for _, attr := range field.Attrs {
+				if attr.Name.Space == xmlSchemaInstanceNS && attr.Name.Local == "nil" && attr.Value == "true" {
+					value = nil
+				}
+			}
+			obj.Set(field.XMLName.Local, value)
+		}
+		msg.Notifications = append(msg.Notifications, OutboundNotification{ID: notification.ID, SObject: obj})
+	}
+	return msg, nil
+}
+
+// OutboundMessageHandler is an http.Handler receiving Outbound Message notifications. Each message is passed to
+// Handle and acknowledged if Handle returns nil; otherwise a SOAP fault is returned and Salesforce retries the
+// delivery later. If OrganizationID is set, messages from other orgs are rejected. If Client is set, it is associated
+// with the SObjects of the notifications so that they can be used for further requests.
+type OutboundMessageHandler struct {
+	OrganizationID string
+	Client         *Client
+	Handle         func(*OutboundMessage) error
+}
+
+// ServeHTTP implements http.Handler.
+func (handler *OutboundMessageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeOutboundFault(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeOutboundFault(w, http.StatusBadRequest, "failed to read request")
+		return
+	}
+	msg, err := ParseOutboundMessage(body)
+	if err != nil {
+		log.Println(logPrefix, "failed to parse outbound message,", err)
+		writeOutboundFault(w, http.StatusBadRequest, "malformed outbound message")
+		return
+	}
+	if handler.OrganizationID != "" && !sameID(handler.OrganizationID, msg.OrganizationID) {
+		log.Println(logPrefix, "outbound message from unexpected organization", msg.OrganizationID)
+		writeOutboundFault(w, http.StatusForbidden, "unexpected organization")
+		return
+	}
+
+	if handler.Client != nil {
+		for _, notification := range msg.Notifications {
+			notification.SObject.setClient(handler.Client)
+		}
+	}
+	if handler.Handle != nil {
+		err = handler.Handle(msg)
+		if err != nil {
+			writeOutboundFault(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.Write([]byte(outboundAckResponse))
+}
+
+func writeOutboundFault(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, outboundFaultResponse, html.EscapeString(message))
+}
+
+// sameID compares two salesforce IDs, either of which may be in the 15-character case-sensitive or the 18-character
+// case-insensitive form.
+func sameID(a, b string) bool {
+	if len(a) < 15 || len(b) < 15 {
+		return a == b
+	}
+	return a[:15] == b[:15]
+}
@@ -0,0 +1,81 @@
+package simpleforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testOutboundMessage = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+        xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <soapenv:Body>
+        <notifications xmlns="http://soap.sforce.com/2005/09/outbound">
+            <OrganizationId>00D000000000001AAA</OrganizationId>
+            <ActionId>04k000000000001AAA</ActionId>
+            <SessionId xsi:nil="true"/>
+            <EnterpriseUrl>https://example.my.salesforce.com/services/Soap/c/54.0/00D000000000001</EnterpriseUrl>
+            <PartnerUrl>https://example.my.salesforce.com/services/Soap/u/54.0/00D000000000001</PartnerUrl>
+            <Notification>
+                <Id>04l000000000001AAA</Id>
+                <sObject xsi:type="sf:Account" xmlns:sf="urn:sobject.enterprise.soap.sforce.com">
+                    <sf:Id>001000000000001AAA</sf:Id>
+                    <sf:Name>Acme &amp; Co</sf:Name>
+                    <sf:Website xsi:nil="true"/>
+                </sObject>
+            </Notification>
+        </notifications>
+    </soapenv:Body>
+</soapenv:Envelope>`
+
+func TestParseOutboundMessage(t *testing.T) {
+	msg, err := ParseOutboundMessage([]byte(testOutboundMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.OrganizationID != "00D000000000001AAA" || msg.ActionID != "04k000000000001AAA" || len(msg.Notifications) != 1 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	obj := msg.Notifications[0].SObject
+	if obj.Type() != "Account" || obj.ID() != "001000000000001AAA" || obj.StringField("Name") != "Acme & Co" {
+		t.Errorf("unexpected sobject %v", *obj)
+	}
+	if value, ok := (*obj)["Website"]; !ok || value != nil {
+		t.Errorf("expected nil Website, got %v", value)
+	}
+}
+
+func TestOutboundMessageHandler(t *testing.T) {
+	var handled int
+	handler := &OutboundMessageHandler{
+		OrganizationID: "00D000000000001",
+		Handle: func(msg *OutboundMessage) error {
+			handled++
+			if handled > 1 {
+				return errors.New("try again")
+			}
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testOutboundMessage)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "<Ack>true</Ack>") {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testOutboundMessage)))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "try again") {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+
+	handler.OrganizationID = "00D000000000002"
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testOutboundMessage)))
+	if w.Code != http.StatusForbidden || handled != 2 {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+}
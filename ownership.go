@@ -0,0 +1,106 @@
+package simpleforce
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ownerChangeBatchSize is the default number of records reassigned per collection request by ChangeOwner. Owner
+	// changes recalculate the sharing of the records and often of related records, so smaller batches hold locks for
+	// shorter.
+	ownerChangeBatchSize = 50
+
+	// maxOwnerChangeRetries is the number of times ChangeOwner retries records which failed on row locks.
+	maxOwnerChangeRetries = 3
+
+	// queueKeyPrefix is the key prefix of groups, which include queues.
+	queueKeyPrefix = "00G"
+)
+
+// ownerChangeRetryDelay is the delay before the first retry of ChangeOwner, which grows linearly with every retry.
+var ownerChangeRetryDelay = 5 * time.Second
+
+// ChangeOwner reassigns all records matched by the SOQL query soql, which must select the Id field and should select
+// OwnerId to skip records the owner already owns, to ownerID, an active user or a queue. Records are updated like with
+// UpdateByQuery, in collection requests of 50 records by default (see WithBatchSize), reporting progress with
+// WithProgress and previewing the number of changes with WithDryRun.
+//
+// Changing owners makes salesforce recalculate sharing, which locks the records and their parents and may collide with
+// recalculations still running from previous batches or sharing rule changes. Records failing with UNABLE_TO_LOCK_ROW
+// are therefore retried up to 3 times with increasing delays. Failures that remain are reported in a *BatchError.
+func (client *Client) ChangeOwner(soql, ownerID string, opts ...BatchOption) (*BatchResult, error) {
+	err := client.checkOwner(ownerID)
+	if err != nil {
+		return &BatchResult{}, err
+	}
+	opts = append([]BatchOption{WithBatchSize(ownerChangeBatchSize)}, opts...)
+	options := newBatchOptions(opts)
+	result, err := client.UpdateByQuery(soql, map[string]interface{}{"OwnerId": ownerID}, opts...)
+
+	var batchErr *BatchError
+	for attempt := 1; attempt <= maxOwnerChangeRetries && !options.dryRun && errors.As(err, &batchErr); attempt++ {
+		var locked, failures []*RecordError
+		for _, recordErr := range batchErr.Records {
+			if IsRowLockError(recordErr) && recordErr.ID != "" {
+				locked = append(locked, recordErr)
+			} else {
+				failures = append(failures, recordErr)
+			}
+		}
+		if len(locked) == 0 {
+			break
+		}
+
+		time.Sleep(ownerChangeRetryDelay * time.Duration(attempt))
+		records := make([]*SObject, len(locked))
+		for idx, recordErr := range locked {
+			records[idx] = client.SObject(queryObjectType(soql)).Set("Id", recordErr.ID).Set("OwnerId", ownerID)
+		}
+		results, retryErr := client.UpdateAll(records, WithBatchSize(options.batchSize), WithAllOrNone(options.allOrNone))
+		var retryBatchErr *BatchError
+		if retryErr != nil && !errors.As(retryErr, &retryBatchErr) {
+			return result, retryErr
+		}
+
+		result.Failed -= len(locked)
+		result.tally(results)
+		if retryBatchErr != nil {
+			for _, recordErr := range retryBatchErr.Records {
+				recordErr.Index = locked[recordErr.Index].Index
+				failures = append(failures, recordErr)
+			}
+		}
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+		err = newBatchError(failures)
+	}
+	return result, err
+}
+
+// checkOwner verifies that ownerID is an active user or a queue.
+func (client *Client) checkOwner(ownerID string) error {
+	if len(ownerID) < 3 {
+		return fmt.Errorf("invalid owner %q", ownerID)
+	}
+	switch ownerID[:3] {
+	case queueKeyPrefix:
+		return nil
+	case userKeyPrefix:
+		result, err := client.Query("SELECT IsActive FROM User WHERE Id = " + QuoteSOQL(ownerID))
+		if err != nil {
+			return err
+		}
+		if len(result.Records) == 0 {
+			return fmt.Errorf("user %s not found", ownerID)
+		}
+		if active, _ := result.Records[0].InterfaceField("IsActive").(bool); !active {
+			return fmt.Errorf("user %s is inactive and cannot own records", ownerID)
+		}
+		return nil
+	default:
+		return fmt.Errorf("owner %s is neither a user nor a queue", ownerID)
+	}
+}
@@ -0,0 +1,98 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ChangeOwner(t *testing.T) {
+	defer func(delay time.Duration) { ownerChangeRetryDelay = delay }(ownerChangeRetryDelay)
+	ownerChangeRetryDelay = time.Millisecond
+
+	attempts := map[string]int{}
+	var batchSizes []int
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			switch q := r.URL.Query().Get("q"); q {
+			case "SELECT IsActive FROM User WHERE Id = '005NEW'":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"IsActive": true}]}`))
+			case "SELECT IsActive FROM User WHERE Id = '005GONE'":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"IsActive": false}]}`))
+			case "SELECT Id, OwnerId FROM Account WHERE Region__c = 'EMEA'":
+				w.Write([]byte(`{"totalSize": 4, "done": true, "records": [
+					{"attributes": {"type": "Account"}, "Id": "001A", "OwnerId": "005OLD"},
+					{"attributes": {"type": "Account"}, "Id": "001B", "OwnerId": "005NEW"},
+					{"attributes": {"type": "Account"}, "Id": "001C", "OwnerId": "005OLD"},
+					{"attributes": {"type": "Account"}, "Id": "001D", "OwnerId": "005OLD"}]}`))
+			default:
+				t.Errorf("unexpected query %s", q)
+			}
+			return
+		}
+
+		var body struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batchSizes = append(batchSizes, len(body.Records))
+		var results []string
+		for _, record := range body.Records {
+			id := record["Id"].(string)
+			attempts[id]++
+			if record["OwnerId"] != "005NEW" {
+				t.Errorf("unexpected record %v", record)
+			}
+			switch {
+			case id == "001C" && attempts[id] < 3, id == "001D":
+				results = append(results, `{"id": "`+id+`", "success": false, "errors": [{"statusCode": "UNABLE_TO_LOCK_ROW",
+					"message": "unable to obtain exclusive access to this record", "fields": []}]}`)
+			default:
+				results = append(results, `{"id": "`+id+`", "success": true, "errors": []}`)
+			}
+		}
+		w.Write([]byte("[" + strings.Join(results, ",") + "]"))
+	})
+
+	if _, err := client.ChangeOwner("SELECT Id, OwnerId FROM Account", "005GONE"); err == nil {
+		t.Error("expected an error for an inactive owner")
+	}
+	if _, err := client.ChangeOwner("SELECT Id, OwnerId FROM Account", "001A"); err == nil {
+		t.Error("expected an error for an invalid owner")
+	}
+
+	var progress []int
+	result, err := client.ChangeOwner("SELECT Id, OwnerId FROM Account WHERE Region__c = 'EMEA'", "005NEW",
+		WithBatchSize(2), WithProgress(func(processed, total int) { progress = append(progress, processed) }))
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Records) != 1 || batchErr.Records[0].ID != "001D" || batchErr.Records[0].Index != 2 {
+		t.Fatalf("expected 001D to remain locked, got %v", err)
+	}
+	if result.Matched != 4 || result.Changes != 3 || result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if attempts["001B"] != 0 || attempts["001C"] != 3 || attempts["001D"] != 1+maxOwnerChangeRetries {
+		t.Errorf("unexpected attempts %v", attempts)
+	}
+	if batchSizes[0] != 2 || len(progress) != 2 || progress[1] != 3 {
+		t.Errorf("unexpected batches %v and progress %v", batchSizes, progress)
+	}
+}
+
+func TestClient_ChangeOwnerDryRun(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+			{"attributes": {"type": "Case"}, "Id": "500A", "OwnerId": "00GQUEUE"},
+			{"attributes": {"type": "Case"}, "Id": "500B", "OwnerId": "005OLD"}]}`))
+	})
+
+	result, err := client.ChangeOwner("SELECT Id, OwnerId FROM Case", "00GQUEUE", WithDryRun(true))
+	if err != nil || result.Matched != 2 || result.Changes != 1 {
+		t.Errorf("unexpected result %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,228 @@
+// Package parquetexport streams the results of Bulk API 2.0 queries into Parquet files, with column types derived from
+// the describe metadata of the queried object, for loading salesforce data into data lakes without intermediate CSV
+// files. It is a separate module so that the parquet dependency is only pulled in by programs which need it.
+package parquetexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/scottraio/simpleforce"
+	"github.com/scottraio/simpleforce/dataloader"
+)
+
+// defaultBatchSize is the number of rows buffered before they are written, if Exporter.BatchSize is not positive.
+const defaultBatchSize = 1000
+
+// Exporter runs SOQL queries as Bulk API 2.0 jobs and writes the results to a Parquet file. Every column of the SELECT
+// clause becomes an optional column: booleans, integers and numbers (including currency and percent fields) keep their
+// types, dates and datetimes become DATE and TIMESTAMP(MILLIS) columns, and all other fields, including relationship
+// fields such as "Account.Name", are strings. Empty values are written as null.
+type Exporter struct {
+	Client *simpleforce.Client
+
+	// BatchSize is the number of rows buffered before they are written to the file, 1000 if not positive.
+	BatchSize int
+}
+
+// column is a column of the Parquet file.
+type column struct {
+	name      string
+	fieldType string
+	index     int // leaf column index in the schema
+}
+
+// Export runs soql and writes all records to w as a Parquet file, returning the number of records written.
+func (exporter *Exporter) Export(ctx context.Context, soql string, w io.Writer) (int, error) {
+	columns, schema, err := exporter.schema(soql)
+	if err != nil {
+		return 0, err
+	}
+	batchSize := exporter.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	writer := parquet.NewWriter(w, schema)
+	var positions []int // position of each column in the CSV rows
+	rows := make([]parquet.Row, 0, batchSize)
+	count := 0
+	err = exporter.Client.BulkQueryEach(ctx, soql, func(header, cells []string) error {
+		if positions == nil {
+			var mapErr error
+			if positions, mapErr = mapHeader(header, columns); mapErr != nil {
+				return mapErr
+			}
+		}
+		row := make(parquet.Row, len(columns))
+		for idx, col := range columns {
+			value, err := convert(cells[positions[idx]], col.fieldType)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %w", count+1, col.name, err)
+			}
+			row[col.index] = value.Level(0, definitionLevel(value), col.index)
+		}
+		rows = append(rows, row)
+		count++
+
+		if len(rows) == batchSize {
+			if _, err := writer.WriteRows(rows); err != nil {
+				return err
+			}
+			rows = rows[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if _, err = writer.WriteRows(rows); err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
+
+// schema derives the columns and the Parquet schema from soql and the describe metadata of the queried object.
+func (exporter *Exporter) schema(soql string) ([]column, *parquet.Schema, error) {
+	queryColumns, err := dataloader.QueryColumns(soql)
+	if err != nil {
+		return nil, nil, err
+	}
+	objectType := queryObject(soql)
+	metas, err := exporter.Client.DescribeSObjects(objectType)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldTypes, err := describeFieldTypes(metas[objectType])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group := parquet.Group{}
+	columns := make([]column, 0, len(queryColumns))
+	for _, queryColumn := range queryColumns {
+		fieldType := "string"
+		if len(queryColumn.Path) == 1 {
+			if t, ok := fieldTypes[strings.ToLower(queryColumn.Name)]; ok {
+				fieldType = t
+			}
+		}
+		group[queryColumn.Name] = parquet.Optional(parquetNode(fieldType))
+		columns = append(columns, column{name: queryColumn.Name, fieldType: fieldType})
+	}
+
+	schema := parquet.NewSchema(objectType, group)
+	for idx := range columns {
+		leaf, ok := schema.Lookup(columns[idx].name)
+		if !ok {
+			return nil, nil, fmt.Errorf("column %s missing from schema", columns[idx].name)
+		}
+		columns[idx].index = leaf.ColumnIndex
+	}
+	return columns, schema, nil
+}
+
+// queryObject returns the object type a query selects from.
+func queryObject(soql string) string {
+	words := strings.Fields(soql)
+	for idx, word := range words[:len(words)-1] {
+		if strings.EqualFold(word, "FROM") {
+			return words[idx+1]
+		}
+	}
+	return ""
+}
+
+// describeFieldTypes returns the types of the fields of an object by lower-cased name.
+func describeFieldTypes(meta *simpleforce.SObjectMeta) (map[string]string, error) {
+	fields, ok := (*meta)["fields"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("describe metadata without fields")
+	}
+	types := make(map[string]string, len(fields))
+	for _, field := range fields {
+		field, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		fieldType, _ := field["type"].(string)
+		types[strings.ToLower(name)] = fieldType
+	}
+	return types, nil
+}
+
+// parquetNode returns the Parquet type of a salesforce field type.
+func parquetNode(fieldType string) parquet.Node {
+	switch fieldType {
+	case "boolean":
+		return parquet.Leaf(parquet.BooleanType)
+	case "int":
+		return parquet.Int(64)
+	case "double", "currency", "percent":
+		return parquet.Leaf(parquet.DoubleType)
+	case "date":
+		return parquet.Date()
+	case "datetime":
+		return parquet.Timestamp(parquet.Millisecond)
+	default:
+		return parquet.String()
+	}
+}
+
+// convert converts a CSV cell of a field of fieldType into a Parquet value. Empty cells are null.
+func convert(cell, fieldType string) (parquet.Value, error) {
+	if cell == "" {
+		return parquet.NullValue(), nil
+	}
+	switch fieldType {
+	case "boolean":
+		b, err := strconv.ParseBool(cell)
+		return parquet.BooleanValue(b), err
+	case "int":
+		i, err := strconv.ParseInt(cell, 10, 64)
+		return parquet.Int64Value(i), err
+	case "double", "currency", "percent":
+		f, err := strconv.ParseFloat(cell, 64)
+		return parquet.DoubleValue(f), err
+	case "date":
+		t, err := time.Parse("2006-01-02", cell)
+		return parquet.Int32Value(int32(t.Unix() / (24 * 60 * 60))), err
+	case "datetime":
+		t, err := time.Parse(time.RFC3339Nano, cell)
+		return parquet.Int64Value(t.UnixMilli()), err
+	default:
+		return parquet.ByteArrayValue([]byte(cell)), nil
+	}
+}
+
+// definitionLevel returns the definition level of a value of an optional column.
+func definitionLevel(value parquet.Value) int {
+	if value.IsNull() {
+		return 0
+	}
+	return 1
+}
+
+// mapHeader returns the position of every column in the CSV header, matched case-insensitively.
+func mapHeader(header []string, columns []column) ([]int, error) {
+	positions := make([]int, len(columns))
+	for idx, col := range columns {
+		positions[idx] = -1
+		for position, name := range header {
+			if strings.EqualFold(name, col.name) {
+				positions[idx] = position
+				break
+			}
+		}
+		if positions[idx] < 0 {
+			return nil, fmt.Errorf("column %s missing from the results", col.name)
+		}
+	}
+	return positions, nil
+}
@@ -0,0 +1,86 @@
+package parquetexport
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/scottraio/simpleforce"
+)
+
+func TestExporter_Export(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Opportunity/describe"):
+			w.Write([]byte(`{"name": "Opportunity", "fields": [
+				{"name": "Id", "type": "id"},
+				{"name": "Amount", "type": "currency"},
+				{"name": "IsWon", "type": "boolean"},
+				{"name": "CloseDate", "type": "date"},
+				{"name": "LastModifiedDate", "type": "datetime"},
+				{"name": "TotalOpportunityQuantity", "type": "double"}
+			]}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/query"):
+			w.Write([]byte(`{"id": "750A", "state": "UploadComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750A"):
+			w.Write([]byte(`{"id": "750A", "state": "JobComplete"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/750A/results"):
+			w.Header().Set("Sforce-Locator", "null")
+			w.Write([]byte(`"Id","Amount","IsWon","CloseDate","LastModifiedDate","Account.Name"
+"006A","1500.5","true","2022-05-01","2022-05-01T10:00:00.000Z","Acme"
+"006B","","false","2022-06-30","2022-06-01T08:30:00.000Z",""
+`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+
+	var buf bytes.Buffer
+	exporter := &Exporter{Client: client, BatchSize: 1}
+	soql := "SELECT Id, Amount, IsWon, CloseDate, LastModifiedDate, Account.Name FROM Opportunity"
+	count, err := exporter.Export(context.Background(), soql, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count %d", count)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.NumRows() != 2 {
+		t.Fatalf("unexpected rows %d", file.NumRows())
+	}
+	types := map[string]string{}
+	for _, field := range file.Schema().Fields() {
+		types[field.Name()] = field.Type().String()
+	}
+	if types["Amount"] != "DOUBLE" || types["IsWon"] != "BOOLEAN" || types["CloseDate"] != "DATE" ||
+		!strings.HasPrefix(types["LastModifiedDate"], "TIMESTAMP") || types["Account.Name"] != "STRING" {
+		t.Errorf("unexpected types %v", types)
+	}
+
+	type opportunity struct {
+		ID          string   `parquet:"Id"`
+		Amount      *float64 `parquet:"Amount"`
+		IsWon       *bool    `parquet:"IsWon"`
+		AccountName *string  `parquet:"Account.Name"`
+	}
+	rows, err := parquet.Read[opportunity](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].ID != "006A" || *rows[0].Amount != 1500.5 || !*rows[0].IsWon || *rows[0].AccountName != "Acme" ||
+		rows[1].Amount != nil || rows[1].AccountName != nil {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+}
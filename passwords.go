@@ -0,0 +1,61 @@
+package simpleforce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SetPassword sets the password of the user userID to newPassword. The password must satisfy the password policies of
+// the user's profile; a rejected password is returned as a SalesforceError.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/dome_sobject_user_password.htm
+func (client *Client) SetPassword(userID, newPassword string) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+	u, err := client.passwordURL(userID)
+	if err != nil {
+		return err
+	}
+
+	reqData, err := client.marshalJSON(map[string]string{"NewPassword": newPassword})
+	if err != nil {
+		return err
+	}
+	_, err = client.httpRequest(http.MethodPost, u, bytes.NewReader(reqData))
+	return err
+}
+
+// ResetPassword resets the password of the user userID to a random password, which is returned. Salesforce requires
+// the user to change the returned password on the next login.
+func (client *Client) ResetPassword(userID string) (string, error) {
+	if !client.isLoggedIn() {
+		return "", ErrAuthentication
+	}
+	u, err := client.passwordURL(userID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := client.httpRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		NewPassword string `json:"NewPassword"`
+	}
+	err = client.unmarshalJSON(data, &result)
+	if err != nil {
+		return "", err
+	}
+	return result.NewPassword, nil
+}
+
+// passwordURL returns the URL of the password resource of the user userID.
+func (client *Client) passwordURL(userID string) (string, error) {
+	if len(userID) < 3 || userID[:3] != userKeyPrefix {
+		return "", fmt.Errorf("invalid user ID %q", userID)
+	}
+	return client.makeURL("sobjects/User/" + url.PathEscape(userID) + "/password"), nil
+}
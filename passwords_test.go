@@ -0,0 +1,46 @@
+package simpleforce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SetPassword(t *testing.T) {
+	var body string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/v54.0/sobjects/User/005000000000001AAA/password" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.SetPassword("005000000000001AAA", "s3cret!"); err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"NewPassword":"s3cret!"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+	if err := client.SetPassword("001000000000001AAA", "s3cret!"); err == nil {
+		t.Error("expected an error for an account ID")
+	}
+}
+
+func TestClient_ResetPassword(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/services/data/v54.0/sobjects/User/005000000000001AAA/password" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"NewPassword": "aB3dEf9h"}`))
+	})
+
+	password, err := client.ResetPassword("005000000000001AAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "aB3dEf9h" {
+		t.Errorf("unexpected password %q", password)
+	}
+}
@@ -0,0 +1,175 @@
+package simpleforce
+
+import (
+	"strings"
+)
+
+// AssignedPermissionSet is a permission set assigned to a user. The profile of the user is represented by the
+// permission set owned by the profile.
+type AssignedPermissionSet struct {
+	ID               string
+	Name             string
+	Label            string
+	IsOwnedByProfile bool
+	ProfileName      string // the name of the profile owning the permission set, if IsOwnedByProfile
+}
+
+// ObjectPermission is the access to an object granted by permission sets.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_objectpermissions.htm
+type ObjectPermission struct {
+	Object    string `json:"SobjectType"`
+	Read      bool   `json:"PermissionsRead"`
+	Create    bool   `json:"PermissionsCreate"`
+	Edit      bool   `json:"PermissionsEdit"`
+	Delete    bool   `json:"PermissionsDelete"`
+	ViewAll   bool   `json:"PermissionsViewAllRecords"`
+	ModifyAll bool   `json:"PermissionsModifyAllRecords"`
+}
+
+// FieldPermission is the access to a field granted by permission sets.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_fieldpermissions.htm
+type FieldPermission struct {
+	Field string `json:"Field"` // the object qualified field name, e.g. "Account.Industry"
+	Read  bool   `json:"PermissionsRead"`
+	Edit  bool   `json:"PermissionsEdit"`
+}
+
+// PermissionMatrix is the effective object and field access of a user, combining the permissions of the profile and
+// all permission sets and permission set groups assigned to the user.
+type PermissionMatrix struct {
+	UserID         string
+	PermissionSets []AssignedPermissionSet
+	Objects        map[string]ObjectPermission // keyed by object name, e.g. "Account"
+	Fields         map[string]FieldPermission  // keyed by object qualified field name, e.g. "Account.Industry"
+}
+
+// Object returns the access to the object objectType, matched case-insensitively. The zero value is returned for
+// objects the user has no access to.
+func (matrix *PermissionMatrix) Object(objectType string) ObjectPermission {
+	if permission, ok := matrix.Objects[objectType]; ok {
+		return permission
+	}
+	for name, permission := range matrix.Objects {
+		if strings.EqualFold(name, objectType) {
+			return permission
+		}
+	}
+	return ObjectPermission{Object: objectType}
+}
+
+// Field returns the access to the field field of objectType, matched case-insensitively. The zero value is returned
+// for fields the user has no access to. Note that salesforce does not report permissions of required fields, which are
+// always readable and editable.
+func (matrix *PermissionMatrix) Field(objectType, field string) FieldPermission {
+	name := objectType + "." + field
+	if permission, ok := matrix.Fields[name]; ok {
+		return permission
+	}
+	for qualified, permission := range matrix.Fields {
+		if strings.EqualFold(qualified, name) {
+			return permission
+		}
+	}
+	return FieldPermission{Field: name}
+}
+
+// UserPermissions returns the permission matrix of the user userID, the logged in user if empty, from the
+// PermissionSetAssignment, ObjectPermissions and FieldPermissions of the user. Permissions granted by any assigned
+// permission set are combined.
+func (client *Client) UserPermissions(userID string) (*PermissionMatrix, error) {
+	if userID == "" {
+		userID = client.user.id
+	}
+
+	matrix := &PermissionMatrix{
+		UserID:  userID,
+		Objects: make(map[string]ObjectPermission),
+		Fields:  make(map[string]FieldPermission),
+	}
+	soql := "SELECT PermissionSetId, PermissionSet.Name, PermissionSet.Label, PermissionSet.IsOwnedByProfile, " +
+		"PermissionSet.Profile.Name FROM PermissionSetAssignment WHERE AssigneeId = " + QuoteSOQL(userID)
+	err := client.queryPages("query", soql, func(records []byte) error {
+		var page []struct {
+			PermissionSetID string `json:"PermissionSetId"`
+			PermissionSet   struct {
+				Name             string `json:"Name"`
+				Label            string `json:"Label"`
+				IsOwnedByProfile bool   `json:"IsOwnedByProfile"`
+				Profile          *struct {
+					Name string `json:"Name"`
+				} `json:"Profile"`
+			} `json:"PermissionSet"`
+		}
+		err := client.unmarshalJSON(records, &page)
+		for _, record := range page {
+			assigned := AssignedPermissionSet{
+				ID:               record.PermissionSetID,
+				Name:             record.PermissionSet.Name,
+				Label:            record.PermissionSet.Label,
+				IsOwnedByProfile: record.PermissionSet.IsOwnedByProfile,
+			}
+			if record.PermissionSet.Profile != nil {
+				assigned.ProfileName = record.PermissionSet.Profile.Name
+			}
+			matrix.PermissionSets = append(matrix.PermissionSets, assigned)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matrix.PermissionSets) == 0 {
+		return matrix, nil
+	}
+
+	quoted := make([]string, len(matrix.PermissionSets))
+	for idx, permissionSet := range matrix.PermissionSets {
+		quoted[idx] = QuoteSOQL(permissionSet.ID)
+	}
+	parents := "ParentId IN (" + strings.Join(quoted, ", ") + ")"
+
+	soql = "SELECT SobjectType, PermissionsRead, PermissionsCreate, PermissionsEdit, PermissionsDelete, " +
+		"PermissionsViewAllRecords, PermissionsModifyAllRecords FROM ObjectPermissions WHERE " + parents
+	err = client.queryPages("query", soql, func(records []byte) error {
+		var page []ObjectPermission
+		err := client.unmarshalJSON(records, &page)
+		for _, permission := range page {
+			matrix.Objects[permission.Object] = matrix.Objects[permission.Object].merge(permission)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	soql = "SELECT Field, PermissionsRead, PermissionsEdit FROM FieldPermissions WHERE " + parents
+	err = client.queryPages("query", soql, func(records []byte) error {
+		var page []FieldPermission
+		err := client.unmarshalJSON(records, &page)
+		for _, permission := range page {
+			granted := matrix.Fields[permission.Field]
+			granted.Field = permission.Field
+			granted.Read = granted.Read || permission.Read
+			granted.Edit = granted.Edit || permission.Edit
+			matrix.Fields[permission.Field] = granted
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// merge returns the access granted by either permission or other.
+func (permission ObjectPermission) merge(other ObjectPermission) ObjectPermission {
+	return ObjectPermission{
+		Object:    other.Object,
+		Read:      permission.Read || other.Read,
+		Create:    permission.Create || other.Create,
+		Edit:      permission.Edit || other.Edit,
+		Delete:    permission.Delete || other.Delete,
+		ViewAll:   permission.ViewAll || other.ViewAll,
+		ModifyAll: permission.ModifyAll || other.ModifyAll,
+	}
+}
@@ -0,0 +1,61 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_UserPermissions(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		switch {
+		case strings.Contains(q, "FROM PermissionSetAssignment WHERE AssigneeId = '005ME'"):
+			w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+				{"attributes": {"type": "PermissionSetAssignment"}, "PermissionSetId": "0PSP",
+					"PermissionSet": {"attributes": {"type": "PermissionSet"}, "Name": "X00ex000000",
+						"Label": "00ex000000", "IsOwnedByProfile": true,
+						"Profile": {"attributes": {"type": "Profile"}, "Name": "Standard User"}}},
+				{"attributes": {"type": "PermissionSetAssignment"}, "PermissionSetId": "0PSA",
+					"PermissionSet": {"attributes": {"type": "PermissionSet"}, "Name": "Auditor",
+						"Label": "Auditor", "IsOwnedByProfile": false, "Profile": null}}]}`))
+		case strings.Contains(q, "FROM ObjectPermissions WHERE ParentId IN ('0PSP', '0PSA')"):
+			w.Write([]byte(`{"totalSize": 3, "done": true, "records": [
+				{"attributes": {"type": "ObjectPermissions"}, "SobjectType": "Account", "PermissionsRead": true,
+					"PermissionsCreate": true, "PermissionsEdit": true},
+				{"attributes": {"type": "ObjectPermissions"}, "SobjectType": "Account", "PermissionsRead": true,
+					"PermissionsViewAllRecords": true},
+				{"attributes": {"type": "ObjectPermissions"}, "SobjectType": "Case", "PermissionsRead": true}]}`))
+		case strings.Contains(q, "FROM FieldPermissions WHERE ParentId IN ('0PSP', '0PSA')"):
+			w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+				{"attributes": {"type": "FieldPermissions"}, "Field": "Account.Industry", "PermissionsRead": true},
+				{"attributes": {"type": "FieldPermissions"}, "Field": "Account.Industry", "PermissionsRead": true,
+					"PermissionsEdit": true}]}`))
+		default:
+			t.Errorf("unexpected query %s", q)
+		}
+	})
+	client.user.id = "005ME"
+
+	matrix, err := client.UserPermissions("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix.PermissionSets) != 2 || matrix.PermissionSets[0].ProfileName != "Standard User" ||
+		matrix.PermissionSets[1].IsOwnedByProfile {
+		t.Errorf("unexpected permission sets %+v", matrix.PermissionSets)
+	}
+	account := matrix.Object("account")
+	if !account.Read || !account.Create || !account.Edit || !account.ViewAll || account.Delete || account.ModifyAll {
+		t.Errorf("unexpected account permission %+v", account)
+	}
+	if matrix.Object("Case").Edit || !matrix.Object("Case").Read || matrix.Object("Lead").Read {
+		t.Error("unexpected case or lead permission")
+	}
+	if industry := matrix.Field("Account", "industry"); !industry.Read || !industry.Edit {
+		t.Errorf("unexpected field permission %+v", industry)
+	}
+	if matrix.Field("Account", "Rating").Read {
+		t.Error("expected no access to Account.Rating")
+	}
+}
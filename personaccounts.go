@@ -0,0 +1,150 @@
+package simpleforce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPersonAccountsDisabled is returned by the person account helpers when person accounts are not enabled in the org.
+var ErrPersonAccountsDisabled = errors.New("person accounts not enabled")
+
+// IsPersonAccount tells whether obj is a person account, according to its IsPersonAccount field.
+func (obj *SObject) IsPersonAccount() bool {
+	isPerson, _ := obj.InterfaceField("IsPersonAccount").(bool)
+	return isPerson
+}
+
+// PersonAccountsEnabled tells whether person accounts are enabled in the org, which adds the IsPersonAccount field to
+// Account.
+func (client *Client) PersonAccountsEnabled() (bool, error) {
+	fields, err := client.accountFields()
+	if err != nil {
+		return false, err
+	}
+	_, ok := fields["ispersonaccount"]
+	return ok, nil
+}
+
+// accountFields returns the fields of Account indexed by lower case name.
+func (client *Client) accountFields() (map[string]map[string]interface{}, error) {
+	metas, err := client.DescribeSObjects("Account")
+	if err != nil {
+		return nil, err
+	}
+	fields, _ := indexFields(metas["Account"])
+	return fields, nil
+}
+
+// PersonAccountFields maps the names of Contact fields to the Account fields of person accounts: custom fields, e.g.
+// "Shoe_Size__c", are suffixed "__pc", standard fields are prefixed "Person", e.g. "PersonEmail" for "Email", unless
+// Account has the field itself, e.g. "FirstName" or "Phone". The names of the fields are taken from the describe
+// metadata of Account, an error is returned for fields without a person account counterpart.
+func (client *Client) PersonAccountFields(contactFields []string) (map[string]string, error) {
+	fields, err := client.accountFields()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields["ispersonaccount"]; !ok {
+		return nil, ErrPersonAccountsDisabled
+	}
+
+	mapped := make(map[string]string, len(contactFields))
+	var unknown []string
+	for _, contactField := range contactFields {
+		var candidates []string
+		if strings.HasSuffix(contactField, "__c") {
+			candidates = []string{strings.TrimSuffix(contactField, "__c") + "__pc"}
+		} else {
+			candidates = []string{"Person" + contactField, contactField}
+		}
+		for _, candidate := range candidates {
+			if field, ok := fields[strings.ToLower(candidate)]; ok {
+				mapped[contactField], _ = field["name"].(string)
+				break
+			}
+		}
+		if mapped[contactField] == "" {
+			unknown = append(unknown, contactField)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no person account fields for contact fields %s", strings.Join(unknown, ", "))
+	}
+	return mapped, nil
+}
+
+// PersonRecordTypeID returns the ID of an active person account record type, the first by developer name if there are
+// several. ErrRecordTypeNotFound is returned if there is none.
+func (client *Client) PersonRecordTypeID() (string, error) {
+	result, err := client.Query("SELECT Id FROM RecordType WHERE SobjectType = 'Account' AND IsPersonType = true " +
+		"AND IsActive = true ORDER BY DeveloperName LIMIT 1")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", errors.Wrap(ErrRecordTypeNotFound, "person account")
+	}
+	return result.Records[0].ID(), nil
+}
+
+// CreatePersonAccount creates a person account with values keyed by Contact field names, e.g. "LastName", "Email" or
+// "Shoe_Size__c", which are mapped with PersonAccountFields, and returns its ID. recordTypeID must be a person account
+// record type, PersonRecordTypeID is used if empty. A *BatchError is returned if salesforce rejects the record.
+func (client *Client) CreatePersonAccount(values map[string]interface{}, recordTypeID string) (string, error) {
+	contactFields := make([]string, 0, len(values))
+	for field := range values {
+		contactFields = append(contactFields, field)
+	}
+	mapped, err := client.PersonAccountFields(contactFields)
+	if err != nil {
+		return "", err
+	}
+	if recordTypeID == "" {
+		recordTypeID, err = client.PersonRecordTypeID()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	account := client.SObject("Account").Set("RecordTypeId", recordTypeID)
+	for field, value := range values {
+		account.Set(mapped[field], value)
+	}
+	_, err = client.CreateAll([]*SObject{account})
+	if err != nil {
+		return "", err
+	}
+	return account.ID(), nil
+}
+
+// QueryPersonAccounts returns the person accounts matching condition, an SOQL condition on Account fields or empty for
+// all, with the given Contact fields mapped with PersonAccountFields, Id and PersonContactId. The records hold the
+// Account field names.
+func (client *Client) QueryPersonAccounts(contactFields []string, condition string) ([]*SObject, error) {
+	mapped, err := client.PersonAccountFields(contactFields)
+	if err != nil {
+		return nil, err
+	}
+	selected := []string{"Id", "PersonContactId"}
+	for _, field := range contactFields {
+		if !containsFold(selected, mapped[field]) {
+			selected = append(selected, mapped[field])
+		}
+	}
+	soql := "SELECT " + strings.Join(selected, ", ") + " FROM Account WHERE IsPersonAccount = true"
+	if condition != "" {
+		soql += " AND (" + condition + ")"
+	}
+
+	var accounts []*SObject
+	err = client.QueryEach(soql, nil, func(account *SObject) error {
+		accounts = append(accounts, account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
@@ -0,0 +1,102 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// newPersonAccountClient returns a client whose stub server describes Account, with person account fields if enabled,
+// answers person account queries and records created accounts in sent.
+func newPersonAccountClient(t *testing.T, enabled bool, sent *[]map[string]interface{}) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Account/describe"):
+			fields := `{"name": "Id"}, {"name": "Name"}, {"name": "Phone"}, {"name": "Region__c"}`
+			if enabled {
+				fields += `, {"name": "IsPersonAccount"}, {"name": "FirstName"}, {"name": "LastName"},
+					{"name": "PersonEmail"}, {"name": "PersonContactId"}, {"name": "Shoe_Size__pc"}`
+			}
+			w.Write([]byte(`{"name": "Account", "fields": [` + fields + `]}`))
+		case strings.HasSuffix(r.URL.Path, "/composite/sobjects"):
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			*sent = append(*sent, body.Records...)
+			w.Write([]byte(`[{"id": "001NEW", "success": true, "errors": []}]`))
+		default:
+			switch q := r.URL.Query().Get("q"); q {
+			case "SELECT Id FROM RecordType WHERE SobjectType = 'Account' AND IsPersonType = true AND IsActive = true " +
+				"ORDER BY DeveloperName LIMIT 1":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "012PERSON"}]}`))
+			case "SELECT Id, PersonContactId, LastName, PersonEmail, Phone FROM Account WHERE IsPersonAccount = true " +
+				"AND (PersonEmail LIKE '%@example.com')":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"},
+					"Id": "001A", "PersonContactId": "003A", "LastName": "Smith", "PersonEmail": "jane@example.com",
+					"Phone": null, "IsPersonAccount": true}]}`))
+			default:
+				t.Errorf("unexpected query %s", q)
+			}
+		}
+	})
+	return client
+}
+
+func TestClient_PersonAccountFields(t *testing.T) {
+	client := newPersonAccountClient(t, true, nil)
+	if enabled, err := client.PersonAccountsEnabled(); err != nil || !enabled {
+		t.Errorf("expected person accounts to be enabled, got %v, %v", enabled, err)
+	}
+
+	mapped, err := client.PersonAccountFields([]string{"FirstName", "email", "Shoe_Size__c", "Phone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapped["FirstName"] != "FirstName" || mapped["email"] != "PersonEmail" || mapped["Shoe_Size__c"] != "Shoe_Size__pc" ||
+		mapped["Phone"] != "Phone" {
+		t.Errorf("unexpected mapping %v", mapped)
+	}
+	if _, err = client.PersonAccountFields([]string{"Email", "Region__c", "ReportsToId"}); err == nil ||
+		!strings.Contains(err.Error(), "Region__c, ReportsToId") {
+		t.Errorf("expected an error for fields without counterpart, got %v", err)
+	}
+
+	client = newPersonAccountClient(t, false, nil)
+	if enabled, err := client.PersonAccountsEnabled(); err != nil || enabled {
+		t.Errorf("expected person accounts to be disabled, got %v, %v", enabled, err)
+	}
+	if _, err = client.PersonAccountFields([]string{"Email"}); !errors.Is(err, ErrPersonAccountsDisabled) {
+		t.Errorf("expected ErrPersonAccountsDisabled, got %v", err)
+	}
+}
+
+func TestClient_CreatePersonAccount(t *testing.T) {
+	var sent []map[string]interface{}
+	client := newPersonAccountClient(t, true, &sent)
+
+	id, err := client.CreatePersonAccount(map[string]interface{}{"LastName": "Smith", "Email": "jane@example.com",
+		"Shoe_Size__c": 38}, "")
+	if err != nil || id != "001NEW" {
+		t.Fatalf("unexpected result %s, %v", id, err)
+	}
+	if account := sent[0]; account["RecordTypeId"] != "012PERSON" || account["PersonEmail"] != "jane@example.com" ||
+		account["Shoe_Size__pc"] != 38.0 || account["LastName"] != "Smith" || account["Email"] != nil {
+		t.Errorf("unexpected account %v", account)
+	}
+}
+
+func TestClient_QueryPersonAccounts(t *testing.T) {
+	client := newPersonAccountClient(t, true, nil)
+
+	accounts, err := client.QueryPersonAccounts([]string{"LastName", "Email", "Phone"}, "PersonEmail LIKE '%@example.com'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || !accounts[0].IsPersonAccount() || accounts[0].StringField("PersonContactId") != "003A" {
+		t.Errorf("unexpected accounts %v", accounts)
+	}
+}
@@ -0,0 +1,161 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// picklistOptions are the options of GeneratePicklists.
+type picklistOptions struct {
+	inactive bool
+}
+
+// PicklistOption is a functional option for GeneratePicklists.
+type PicklistOption func(*picklistOptions)
+
+// WithInactivePicklistValues also generates constants for inactive values, marked as deprecated, e.g. to keep code
+// compiling which handles records created before a value was deactivated. Only active values are generated by default.
+func WithInactivePicklistValues() PicklistOption {
+	return func(opts *picklistOptions) {
+		opts.inactive = true
+	}
+}
+
+// picklistField is the part of the describe metadata of a field used to generate its constants.
+type picklistField struct {
+	Name           string `json:"name"`
+	Label          string `json:"label"`
+	Type           string `json:"type"`
+	PicklistValues []struct {
+		Value  string `json:"value"`
+		Label  string `json:"label"`
+		Active bool   `json:"active"`
+	} `json:"picklistValues"`
+}
+
+// GeneratePicklists describes objectTypes and generates Go source of package packageName with the constants of their
+// picklist fields, see NewPicklistSource.
+func (client *Client) GeneratePicklists(packageName string, objectTypes []string, opts ...PicklistOption) ([]byte, error) {
+	metas, err := client.DescribeSObjects(objectTypes...)
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]*SObjectMeta, 0, len(objectTypes))
+	for _, objectType := range objectTypes {
+		ordered = append(ordered, metas[objectType])
+	}
+	return NewPicklistSource(packageName, ordered, opts...)
+}
+
+// NewPicklistSource generates formatted Go source of package packageName declaring a string type for every picklist and
+// multi-select picklist field of the objects described by metas, e.g. OpportunityStageName, with a constant for each
+// of its active values, e.g. OpportunityStageNameClosedWon, a slice of all values in the order of the picklist and a
+// Valid method. Switches over the constants can be checked for exhaustiveness by linters. The values of multi-select
+// picklists are the single values joined by ";" in records.
+func NewPicklistSource(packageName string, metas []*SObjectMeta, opts ...PicklistOption) ([]byte, error) {
+	options := &picklistOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by simpleforce; DO NOT EDIT.\n\npackage %s\n", packageName)
+	for _, meta := range metas {
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		var describe struct {
+			Name   string          `json:"name"`
+			Fields []picklistField `json:"fields"`
+		}
+		err = json.Unmarshal(data, &describe)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(describe.Fields, func(i, j int) bool { return describe.Fields[i].Name < describe.Fields[j].Name })
+		for _, field := range describe.Fields {
+			if field.Type == "picklist" || field.Type == "multipicklist" {
+				writePicklist(&src, describe.Name, field, options)
+			}
+		}
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated source: %v", err)
+	}
+	return formatted, nil
+}
+
+// writePicklist writes the declarations of the picklist field of objectType.
+func writePicklist(src *bytes.Buffer, objectType string, field picklistField, options *picklistOptions) {
+	typeName := goIdentifier(objectType) + goIdentifier(field.Name)
+	kind := "picklist"
+	if field.Type == "multipicklist" {
+		kind = "multi-select picklist"
+	}
+	fmt.Fprintf(src, "\n// %s is a value of the %s %s.%s (%s).\ntype %s string\n\n", typeName, kind, objectType,
+		field.Name, field.Label, typeName)
+
+	var names []string
+	seen := map[string]bool{}
+	var consts bytes.Buffer
+	for _, value := range field.PicklistValues {
+		if !value.Active && !options.inactive {
+			continue
+		}
+		name := typeName + goIdentifier(value.Value)
+		for suffix := 2; seen[name]; suffix++ {
+			name = typeName + goIdentifier(value.Value) + strconv.Itoa(suffix)
+		}
+		seen[name] = true
+		if !value.Active {
+			fmt.Fprintf(&consts, "\t// Deprecated: %s is inactive.\n", strconv.Quote(value.Value))
+		} else if value.Label != "" && value.Label != value.Value {
+			fmt.Fprintf(&consts, "\t// %s is labeled %s.\n", name, strconv.Quote(value.Label))
+		}
+		fmt.Fprintf(&consts, "\t%s %s = %s\n", name, typeName, strconv.Quote(value.Value))
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		fmt.Fprintf(src, "// Values of %s.\nconst (\n%s)\n\n", typeName, consts.Bytes())
+	}
+
+	fmt.Fprintf(src, "// %sValues lists the values of %s in the order of the picklist.\nvar %sValues = []%s{%s}\n\n",
+		typeName, typeName, typeName, typeName, strings.Join(names, ", "))
+
+	fmt.Fprintf(src, "// Valid reports whether v is one of the values of %s.\nfunc (v %s) Valid() bool {\n", typeName, typeName)
+	if len(names) > 0 {
+		fmt.Fprintf(src, "\tswitch v {\n\tcase %s:\n\t\treturn true\n\t}\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintf(src, "\treturn false\n}\n")
+}
+
+// goIdentifier converts name, e.g. "Region__c" or "Closed Won", to an exported Go identifier in camel case, e.g.
+// "RegionC" or "ClosedWon". Characters other than letters and digits separate words.
+func goIdentifier(name string) string {
+	var ident strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		ident.WriteRune(r)
+	}
+	if ident.Len() == 0 {
+		return "Empty"
+	}
+	return ident.String()
+}
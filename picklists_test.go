@@ -0,0 +1,53 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const picklistDescribe = `{"name": "Opportunity", "fields": [
+	{"name": "Name", "type": "string"},
+	{"name": "StageName", "label": "Stage", "type": "picklist", "picklistValues": [
+		{"value": "Prospecting", "label": "Prospecting", "active": true},
+		{"value": "Closed Won", "label": "Won", "active": true},
+		{"value": "Closed-Won", "label": "Closed-Won", "active": true},
+		{"value": "Legacy", "label": "Legacy", "active": false}]},
+	{"name": "Regions__c", "label": "Regions", "type": "multipicklist", "picklistValues": []}
+]}`
+
+func TestClient_GeneratePicklists(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(picklistDescribe))
+	})
+
+	src, err := client.GeneratePicklists("crm", []string{"Opportunity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"// Code generated by simpleforce; DO NOT EDIT.\n\npackage crm\n",
+		"// OpportunityStageName is a value of the picklist Opportunity.StageName (Stage).\ntype OpportunityStageName string\n",
+		"\t// OpportunityStageNameClosedWon is labeled \"Won\".\n\tOpportunityStageNameClosedWon  OpportunityStageName = \"Closed Won\"\n",
+		"\tOpportunityStageNameClosedWon2 OpportunityStageName = \"Closed-Won\"\n",
+		"var OpportunityStageNameValues = []OpportunityStageName{OpportunityStageNameProspecting, " +
+			"OpportunityStageNameClosedWon, OpportunityStageNameClosedWon2}\n",
+		"\tcase OpportunityStageNameProspecting, OpportunityStageNameClosedWon, OpportunityStageNameClosedWon2:\n",
+		"type OpportunityRegionsC string\n",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected %q in:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(string(src), "Legacy") {
+		t.Errorf("unexpected inactive value in:\n%s", src)
+	}
+
+	src, err = client.GeneratePicklists("crm", []string{"Opportunity"}, WithInactivePicklistValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "\t// Deprecated: \"Legacy\" is inactive.\n\tOpportunityStageNameLegacy OpportunityStageName = \"Legacy\"\n") {
+		t.Errorf("expected the inactive value in:\n%s", src)
+	}
+}
@@ -0,0 +1,64 @@
+package simpleforce
+
+import (
+	"strings"
+)
+
+// TypeOfCase is a WHEN branch of a TYPEOF clause: the fields selected if the referenced record is of type Type.
+type TypeOfCase struct {
+	Type   string
+	Fields []string
+}
+
+// TypeOfClause builds a TYPEOF clause selecting fields of the record referenced by the polymorphic relationship
+// relationshipName depending on its type, e.g.
+//
+//	TypeOfClause("What", []TypeOfCase{{"Account", []string{"Phone"}}, {"Opportunity", []string{"Amount"}}}, []string{"Name"})
+//
+// returns "TYPEOF What WHEN Account THEN Phone WHEN Opportunity THEN Amount ELSE Name END". elseFields may be empty.
+// Use SObject.Polymorphic to read the results.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_typeof.htm
+func TypeOfClause(relationshipName string, cases []TypeOfCase, elseFields []string) string {
+	var clause strings.Builder
+	clause.WriteString("TYPEOF " + relationshipName)
+	for _, c := range cases {
+		clause.WriteString(" WHEN " + c.Type + " THEN " + strings.Join(c.Fields, ", "))
+	}
+	if len(elseFields) > 0 {
+		clause.WriteString(" ELSE " + strings.Join(elseFields, ", "))
+	}
+	clause.WriteString(" END")
+	return clause.String()
+}
+
+// Polymorphic returns the record referenced by a polymorphic relationship, e.g. "Who", "What" or "Owner", with the
+// type it actually is according to its attributes, and the fields selected for it by the query. This is how the
+// results of a TYPEOF clause are told apart. An empty type and nil are returned if the relationship was not queried or
+// is empty.
+func (obj *SObject) Polymorphic(relationshipName string) (string, *SObject) {
+	fields, ok := obj.InterfaceField(relationshipName).(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+
+	ref := &SObject{}
+	for key, val := range fields {
+		(*ref)[key] = val
+	}
+	ref.setClient(obj.client())
+	attrs := ref.AttributesField()
+	if attrs == nil || attrs.Type == "" {
+		return "", nil
+	}
+	if ref.ID() == "" && attrs.URL != "" {
+		ref.setID(attrs.URL[strings.LastIndex(attrs.URL, "/")+1:])
+	}
+	return attrs.Type, ref
+}
+
+// ReferenceType returns the type of the record referenced by a polymorphic relationship, e.g. "Contact" or "Lead" for
+// "Who", or an empty string if the relationship was not queried or is empty.
+func (obj *SObject) ReferenceType(relationshipName string) string {
+	typeName, _ := obj.Polymorphic(relationshipName)
+	return typeName
+}
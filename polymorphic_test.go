@@ -0,0 +1,45 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTypeOfClause(t *testing.T) {
+	clause := TypeOfClause("What", []TypeOfCase{
+		{Type: "Account", Fields: []string{"Phone", "NumberOfEmployees"}},
+		{Type: "Opportunity", Fields: []string{"Amount"}},
+	}, []string{"Name"})
+	if clause != "TYPEOF What WHEN Account THEN Phone, NumberOfEmployees WHEN Opportunity THEN Amount ELSE Name END" {
+		t.Errorf("unexpected clause %s", clause)
+	}
+}
+
+func TestSObject_Polymorphic(t *testing.T) {
+	var records []SObject
+	err := json.Unmarshal([]byte(`[
+		{"attributes": {"type": "Event"}, "Id": "00UA", "What": {
+			"attributes": {"type": "Account", "url": "/services/data/v54.0/sobjects/Account/001A"}, "Phone": "555"}},
+		{"attributes": {"type": "Event"}, "Id": "00UB", "What": {
+			"attributes": {"type": "Opportunity", "url": "/services/data/v54.0/sobjects/Opportunity/006A"}, "Amount": 10}},
+		{"attributes": {"type": "Event"}, "Id": "00UC", "What": null}
+	]`), &records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeName, what := records[0].Polymorphic("What")
+	if typeName != "Account" || what.Type() != "Account" || what.ID() != "001A" || what.StringField("Phone") != "555" {
+		t.Errorf("unexpected reference %s %v", typeName, what)
+	}
+	typeName, what = records[1].Polymorphic("What")
+	if typeName != "Opportunity" || what.ID() != "006A" || what.NumberField("Amount") != "10" {
+		t.Errorf("unexpected reference %s %v", typeName, what)
+	}
+	if typeName, what = records[2].Polymorphic("What"); typeName != "" || what != nil {
+		t.Fail()
+	}
+	if records[0].ReferenceType("What") != "Account" || records[0].ReferenceType("Who") != "" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,31 @@
+package simpleforce
+
+import (
+	"fmt"
+	"html"
+)
+
+// LoginPortal signs a Customer Portal or Self-Service portal user into salesforce, on behalf of the user, with the SOAP
+// login scoped to the org orgID and, for Customer Portal users, the portal portalID (the ID of the portal, starting
+// with "060"). portalID may be empty for Self-Service users. API access must be enabled for the portal users' profile.
+// Errors are returned like with LoginPassword.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_header_loginscopeheader.htm
+func (client *Client) LoginPortal(username, password, orgID, portalID string) error {
+	if orgID == "" {
+		return fmt.Errorf("portal login requires the organization ID")
+	}
+
+	scopeHeader := `
+                <urn:LoginScopeHeader>
+                    <urn:organizationId>` + html.EscapeString(orgID) + `</urn:organizationId>`
+	if portalID != "" {
+		scopeHeader += `
+                    <urn:portalId>` + html.EscapeString(portalID) + `</urn:portalId>`
+	}
+	scopeHeader += `
+                </urn:LoginScopeHeader>`
+
+	return client.guardedLogin(username, func() error {
+		return client.loginPassword(username, password, "", scopeHeader)
+	})
+}
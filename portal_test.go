@@ -0,0 +1,52 @@
+package simpleforce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_LoginPortal(t *testing.T) {
+	var body string
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com">
+				<soapenv:Body><loginResponse><result>
+					<serverUrl>https://example.my.salesforce.com/services/Soap/u/54.0/00D000000000001</serverUrl>
+					<sessionId>__PORTAL_SESSION__</sessionId>
+					<userId>005000000000002AAA</userId>
+					<userInfo><userName>portal@example.com</userName></userInfo>
+				</result></loginResponse></soapenv:Body>
+			</soapenv:Envelope>`))
+	})
+	client.SetSidLoc("", "")
+	client.baseURL = server.URL
+
+	if err := client.LoginPortal("portal@example.com", "secret", "00D000000000001", "060000000000001"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<urn:organizationId>00D000000000001</urn:organizationId>",
+		"<urn:portalId>060000000000001</urn:portalId>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("login request is missing %s", want)
+		}
+	}
+	if client.GetSid() != "__PORTAL_SESSION__" || client.user.name != "portal@example.com" {
+		t.Errorf("unexpected session %s", client.GetSid())
+	}
+
+	if err := client.LoginPortal("portal@example.com", "secret", "00D000000000001", ""); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(body, "portalId") || !strings.Contains(body, "organizationId") {
+		t.Error("unexpected scope header for a self-service login")
+	}
+	if err := client.LoginPortal("portal@example.com", "secret", "", ""); err == nil {
+		t.Error("expected an error without an org ID")
+	}
+}
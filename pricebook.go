@@ -0,0 +1,205 @@
+package simpleforce
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// productKeyPrefix is the key prefix of Product2 IDs.
+	productKeyPrefix = "01t"
+
+	// maxPricebookProducts is the number of products resolved by a single PricebookEntry query.
+	maxPricebookProducts = 200
+)
+
+// PricebookEntry is the price of a product in a price book.
+type PricebookEntry struct {
+	ID          string
+	Product2ID  string
+	ProductCode string
+	UnitPrice   float64
+}
+
+// LineItem is a product added to an opportunity with AddOpportunityLineItems. UnitPrice and TotalPrice are exclusive;
+// if neither is set, the list price of the product is used.
+type LineItem struct {
+	Product     string // Product2 ID or ProductCode
+	Quantity    float64
+	UnitPrice   *float64
+	TotalPrice  *float64
+	Discount    float64 // percent
+	ServiceDate time.Time
+	Description string
+	Fields      map[string]interface{} // any other fields
+}
+
+// StandardPricebookID returns the ID of the standard price book of the org.
+func (client *Client) StandardPricebookID() (string, error) {
+	result, err := client.Query("SELECT Id FROM Pricebook2 WHERE IsStandard = true LIMIT 1")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", fmt.Errorf("standard price book not found")
+	}
+	return result.Records[0].ID(), nil
+}
+
+// PricebookEntries resolves the active entries of the price book pricebookID for products, given by their Product2 ID
+// or ProductCode, in currency, an ISO code which must be empty unless multiple currencies are enabled. The entries are
+// returned by the product as given; products without an entry are missing from the result. Strings of 15 or 18
+// characters starting with "01t" are taken to be Product2 IDs.
+func (client *Client) PricebookEntries(pricebookID, currency string, products []string) (map[string]PricebookEntry, error) {
+	entries := make(map[string]PricebookEntry, len(products))
+	for start := 0; start < len(products); start += maxPricebookProducts {
+		end := start + maxPricebookProducts
+		if end > len(products) {
+			end = len(products)
+		}
+
+		var ids, codes []string
+		for _, product := range products[start:end] {
+			if isProductID(product) {
+				ids = append(ids, QuoteSOQL(product))
+			} else {
+				codes = append(codes, QuoteSOQL(product))
+			}
+		}
+		var conditions []string
+		if len(ids) > 0 {
+			conditions = append(conditions, "Product2Id IN ("+strings.Join(ids, ", ")+")")
+		}
+		if len(codes) > 0 {
+			conditions = append(conditions, "ProductCode IN ("+strings.Join(codes, ", ")+")")
+		}
+		soql := "SELECT Id, Product2Id, ProductCode, UnitPrice FROM PricebookEntry WHERE Pricebook2Id = " +
+			QuoteSOQL(pricebookID) + " AND IsActive = true AND (" + strings.Join(conditions, " OR ") + ")"
+		if currency != "" {
+			soql += " AND CurrencyIsoCode = " + QuoteSOQL(currency)
+		}
+
+		err := client.queryPages("query", soql, func(records []byte) error {
+			var page []struct {
+				ID          string  `json:"Id"`
+				Product2ID  string  `json:"Product2Id"`
+				ProductCode string  `json:"ProductCode"`
+				UnitPrice   float64 `json:"UnitPrice"`
+			}
+			err := client.unmarshalJSON(records, &page)
+			if err != nil {
+				return err
+			}
+			for _, record := range page {
+				entry := PricebookEntry(record)
+				for _, product := range products[start:end] {
+					if sameID(product, entry.Product2ID) || (!isProductID(product) && product == entry.ProductCode) {
+						entries[product] = entry
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// isProductID tells whether product looks like a Product2 ID rather than a ProductCode.
+func isProductID(product string) bool {
+	return (len(product) == 15 || len(product) == 18) && strings.HasPrefix(product, productKeyPrefix)
+}
+
+// AddOpportunityLineItems adds items to the opportunity opportunityID, resolving their price book entries from the
+// price book and currency of the opportunity. An opportunity without a price book is set to the standard price book
+// first. No item is created if the entry of any product cannot be resolved. The items are created in batches like
+// CreateAll.
+func (client *Client) AddOpportunityLineItems(opportunityID string, items []LineItem, opts ...BatchOption) (
+	[]SaveResult, error) {
+	for idx, item := range items {
+		if item.UnitPrice != nil && item.TotalPrice != nil {
+			return nil, fmt.Errorf("line item %d has both a unit and a total price", idx)
+		}
+	}
+
+	fields, err := client.QueryFields("Opportunity", FieldsAll)
+	if err != nil {
+		return nil, err
+	}
+	// CurrencyIsoCode only exists if multiple currencies are enabled.
+	multiCurrency := containsFold(fields, "CurrencyIsoCode")
+	soql := "SELECT Id, Pricebook2Id FROM Opportunity WHERE Id = " + QuoteSOQL(opportunityID)
+	if multiCurrency {
+		soql = "SELECT Id, Pricebook2Id, CurrencyIsoCode FROM Opportunity WHERE Id = " + QuoteSOQL(opportunityID)
+	}
+	result, err := client.Query(soql)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Records) == 0 {
+		return nil, fmt.Errorf("opportunity %s not found", opportunityID)
+	}
+	opportunity := result.Records[0]
+
+	pricebookID := opportunity.StringField("Pricebook2Id")
+	if pricebookID == "" {
+		pricebookID, err = client.StandardPricebookID()
+		if err != nil {
+			return nil, err
+		}
+		update := client.SObject("Opportunity").Set("Id", opportunityID).Set("Pricebook2Id", pricebookID)
+		_, err = client.UpdateAll([]*SObject{update})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	products := make([]string, 0, len(items))
+	for _, item := range items {
+		products = append(products, item.Product)
+	}
+	entries, err := client.PricebookEntries(pricebookID, opportunity.StringField("CurrencyIsoCode"), products)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*SObject, len(items))
+	var missing []string
+	for idx, item := range items {
+		entry, ok := entries[item.Product]
+		if !ok {
+			missing = append(missing, item.Product)
+			continue
+		}
+		record := client.SObject("OpportunityLineItem").
+			Set("OpportunityId", opportunityID).
+			Set("PricebookEntryId", entry.ID).
+			Set("Quantity", item.Quantity)
+		switch {
+		case item.TotalPrice != nil:
+			record.Set("TotalPrice", *item.TotalPrice)
+		case item.UnitPrice != nil:
+			record.Set("UnitPrice", *item.UnitPrice)
+		default:
+			record.Set("UnitPrice", entry.UnitPrice)
+		}
+		if item.Discount != 0 {
+			record.Set("Discount", item.Discount)
+		}
+		if !item.ServiceDate.IsZero() {
+			record.Set("ServiceDate", item.ServiceDate.Format(dateLayout))
+		}
+		setOptional(record, "Description", item.Description)
+		for field, value := range item.Fields {
+			record.Set(field, value)
+		}
+		records[idx] = record
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no active price book entry for %s in price book %s", strings.Join(missing, ", "), pricebookID)
+	}
+	return client.CreateAll(records, opts...)
+}
@@ -0,0 +1,78 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_AddOpportunityLineItems(t *testing.T) {
+	var sent []map[string]interface{}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Opportunity/describe"):
+			w.Write([]byte(`{"name": "Opportunity", "fields": [{"name": "Id"}, {"name": "Pricebook2Id"},
+				{"name": "CurrencyIsoCode"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/composite/sobjects"):
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body.Records...)
+			var results []map[string]interface{}
+			for range body.Records {
+				results = append(results, map[string]interface{}{"id": "00kNEW", "success": true, "errors": []string{}})
+			}
+			json.NewEncoder(w).Encode(results)
+		default:
+			q := r.URL.Query().Get("q")
+			switch {
+			case q == "SELECT Id, Pricebook2Id, CurrencyIsoCode FROM Opportunity WHERE Id = '006A'":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "006A", "Pricebook2Id": null,
+					"CurrencyIsoCode": "EUR"}]}`))
+			case q == "SELECT Id FROM Pricebook2 WHERE IsStandard = true LIMIT 1":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "01sSTD"}]}`))
+			case strings.HasPrefix(q, "SELECT Id, Product2Id, ProductCode, UnitPrice FROM PricebookEntry WHERE "+
+				"Pricebook2Id = '01sSTD' AND IsActive = true AND (Product2Id IN ('01t000000000001AAA') OR ProductCode IN ('GADGET'") &&
+				strings.HasSuffix(q, " AND CurrencyIsoCode = 'EUR'"):
+				w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+					{"Id": "01uA", "Product2Id": "01t000000000001AAA", "ProductCode": "WIDGET", "UnitPrice": 10},
+					{"Id": "01uB", "Product2Id": "01t000000000002AAA", "ProductCode": "GADGET", "UnitPrice": 25.5}]}`))
+			default:
+				t.Errorf("unexpected query %s", q)
+			}
+		}
+	})
+
+	price := 0.0
+	items := []LineItem{
+		{Product: "01t000000000001AAA", Quantity: 2},
+		{Product: "GADGET", Quantity: 1, UnitPrice: &price, Description: "Free sample"},
+	}
+	if _, err := client.AddOpportunityLineItems("006A", append(items, LineItem{Product: "GONE", Quantity: 1})); err == nil ||
+		!strings.Contains(err.Error(), "GONE") {
+		t.Errorf("expected an error for the missing product, got %v", err)
+	}
+	if len(sent) != 1 || sent[0]["Pricebook2Id"] != "01sSTD" {
+		t.Fatalf("expected only the price book to be set, got %v", sent)
+	}
+
+	sent = nil
+	results, err := client.AddOpportunityLineItems("006A", items)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("unexpected results %v, %v", results, err)
+	}
+	if item := sent[1]; item["PricebookEntryId"] != "01uA" || item["UnitPrice"] != 10.0 || item["Quantity"] != 2.0 ||
+		item["OpportunityId"] != "006A" {
+		t.Errorf("unexpected item %v", item)
+	}
+	if item := sent[2]; item["PricebookEntryId"] != "01uB" || item["UnitPrice"] != 0.0 || item["Description"] != "Free sample" {
+		t.Errorf("unexpected item %v", item)
+	}
+
+	total := 50.0
+	if _, err = client.AddOpportunityLineItems("006A", []LineItem{{Product: "GADGET", UnitPrice: &price, TotalPrice: &total}}); err == nil {
+		t.Error("expected an error for both prices")
+	}
+}
@@ -0,0 +1,82 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// QueryMetadata describes the result of a query without running it: the queried object and the columns of the result
+// with their types, so generic tools such as report builders can render results without describing the objects.
+type QueryMetadata struct {
+	EntityName string        `json:"entityName"`
+	KeyPrefix  string        `json:"keyPrefix"`
+	GroupBy    bool          `json:"groupBy"`
+	IDSelected bool          `json:"idSelected"`
+	Columns    []QueryColumn `json:"columnMetadata"`
+}
+
+// QueryColumn describes a column of a query result. Relationship fields, e.g. Account.Name, and subqueries are
+// columns named by the relationship with the selected fields as JoinColumns. Aggregate expressions are named like
+// in the result, by their alias or expr0, expr1, ...
+type QueryColumn struct {
+	ColumnName     string        `json:"columnName"`
+	DisplayName    string        `json:"displayName"`
+	ApexType       string        `json:"apexType"` // e.g. "String", "Double", "Date" or "Id"
+	ForeignKeyName string        `json:"foreignKeyName"`
+	Aggregate      bool          `json:"aggregate"`
+	BooleanType    bool          `json:"booleanType"`
+	NumberType     bool          `json:"numberType"`
+	TextType       bool          `json:"textType"`
+	Custom         bool          `json:"custom"`
+	Insertable     bool          `json:"insertable"`
+	Updatable      bool          `json:"updatable"`
+	JoinColumns    []QueryColumn `json:"joinColumns"`
+}
+
+// QueryMetadata returns the column metadata of the SOQL query soql, as reported by the query resource with the columns
+// parameter. The query is parsed and checked but not run, so invalid queries fail with the usual errors, e.g.
+// errcode.MalformedQuery.
+func (client *Client) QueryMetadata(soql string) (*QueryMetadata, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	resource := "query"
+	if client.useToolingAPI {
+		resource = "tooling/query"
+	}
+	params := url.Values{"q": {soql}, "columns": {"true"}}
+	u := fmt.Sprintf("%s/services/data/v%s/%s?%s",
+		client.servicesURL(), strings.TrimPrefix(client.apiVersion, "v"), resource, params.Encode())
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta QueryMetadata
+	err = client.unmarshalJSON(data, &meta)
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// FlatColumns returns the columns with relationship columns replaced by their join columns, named by their path, e.g.
+// "Account.Owner.Name", in query order. This matches the flattened columns of exports.
+func (meta *QueryMetadata) FlatColumns() []QueryColumn {
+	return flattenColumns("", meta.Columns, nil)
+}
+
+func flattenColumns(prefix string, columns []QueryColumn, flat []QueryColumn) []QueryColumn {
+	for _, column := range columns {
+		column.ColumnName = prefix + column.ColumnName
+		if len(column.JoinColumns) > 0 {
+			flat = flattenColumns(column.ColumnName+".", column.JoinColumns, flat)
+			continue
+		}
+		flat = append(flat, column)
+	}
+	return flat
+}
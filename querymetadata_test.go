@@ -0,0 +1,47 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_QueryMetadata(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v54.0/query" || r.URL.Query().Get("columns") != "true" ||
+			r.URL.Query().Get("q") != "SELECT Id, Account.Owner.Name, COUNT(Id) n FROM Contact" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"entityName": "Contact", "groupBy": false, "idSelected": true, "keyPrefix": "003",
+			"columnMetadata": [
+				{"columnName": "Id", "displayName": "Id", "apexType": "Id", "insertable": false, "joinColumns": []},
+				{"columnName": "Account", "displayName": "Account", "foreignKeyName": "AccountId", "joinColumns": [
+					{"columnName": "Owner", "displayName": "Owner", "joinColumns": [
+						{"columnName": "Name", "displayName": "Full Name", "apexType": "String", "textType": true}]}]},
+				{"columnName": "n", "displayName": "n", "apexType": "Integer", "aggregate": true, "numberType": true}
+			]}`))
+	})
+
+	meta, err := client.QueryMetadata("SELECT Id, Account.Owner.Name, COUNT(Id) n FROM Contact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.EntityName != "Contact" || !meta.IDSelected || meta.KeyPrefix != "003" || len(meta.Columns) != 3 ||
+		meta.Columns[1].ForeignKeyName != "AccountId" {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+
+	var names []string
+	for _, column := range meta.FlatColumns() {
+		names = append(names, column.ColumnName)
+	}
+	if strings.Join(names, "|") != "Id|Account.Owner.Name|n" {
+		t.Errorf("unexpected columns %v", names)
+	}
+	if flat := meta.FlatColumns(); !flat[1].TextType || flat[1].DisplayName != "Full Name" || !flat[2].Aggregate {
+		t.Errorf("unexpected columns %+v", flat)
+	}
+	if meta.Columns[1].ColumnName != "Account" {
+		t.Error("expected FlatColumns to leave the columns unchanged")
+	}
+}
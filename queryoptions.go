@@ -0,0 +1,147 @@
+package simpleforce
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+const (
+	// maxQueryBatchSize and minQueryBatchSize bound the batch size salesforce accepts in the Sforce-Query-Options header.
+	maxQueryBatchSize = 2000
+	minQueryBatchSize = 200
+)
+
+// QueryOptions configures how QueryEach runs a query and recovers from QUERY_TIMEOUT errors.
+type QueryOptions struct {
+	// BatchSize is the number of records requested per page, between 200 and 2000. Salesforce picks the batch size if
+	// it is 0.
+	BatchSize int
+
+	// MaxRetries is the number of times a request failing with QUERY_TIMEOUT is retried. Retries of the first request
+	// halve the batch size each time, down to 200; pages after the first are retried as is.
+	MaxRetries int
+
+	// SplitByID is the number of ID ranges (see IDRanges) a query is split into if its first request still times out
+	// after the retries. The ranges are queried one after another. Only queries without ORDER BY, GROUP BY, LIMIT or
+	// OFFSET clauses are split; 0 or 1 disables splitting.
+	SplitByID int
+}
+
+// QueryEach runs the SOQL query soql and calls fn with every record, paging through the results. If opts is not nil,
+// requests failing with QUERY_TIMEOUT are retried with progressively smaller batch sizes and by splitting the query into
+// ID ranges as configured, so large extractions recover from timeouts. Records are never passed to fn twice. If fn
+// returns an error, QueryEach stops and returns it.
+func (client *Client) QueryEach(soql string, opts *QueryOptions, fn func(*SObject) error) error {
+	if opts == nil {
+		opts = &QueryOptions{}
+	}
+	return client.queryEach(soql, *opts, opts.SplitByID > 1, fn)
+}
+
+// queryEach implements QueryEach, splitting the query into ID ranges only if split is true.
+func (client *Client) queryEach(soql string, opts QueryOptions, split bool, fn func(*SObject) error) error {
+	batchSize := opts.BatchSize
+	if batchSize > maxQueryBatchSize {
+		batchSize = maxQueryBatchSize
+	} else if batchSize > 0 && batchSize < minQueryBatchSize {
+		batchSize = minQueryBatchSize
+	}
+
+	result, err := client.query(soql, queryOptionsHeader(batchSize))
+	for retry := 0; isQueryTimeout(err) && retry < opts.MaxRetries; retry++ {
+		if batchSize == 0 {
+			batchSize = maxQueryBatchSize
+		}
+		if batchSize /= 2; batchSize < minQueryBatchSize {
+			batchSize = minQueryBatchSize
+		}
+		log.Println(logPrefix, "query timed out, retrying with batch size", batchSize)
+		result, err = client.query(soql, queryOptionsHeader(batchSize))
+	}
+	if isQueryTimeout(err) && split {
+		if queries, ok := client.splitQuery(soql, opts.SplitByID); ok {
+			log.Println(logPrefix, "query timed out, splitting it into", len(queries), "ID ranges")
+			for _, q := range queries {
+				if err := client.queryEach(q, opts, false, fn); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	for err == nil {
+		for idx := range result.Records {
+			if err := fn(&result.Records[idx]); err != nil {
+				return err
+			}
+		}
+		// External objects may report pages which are not done but empty.
+		if result.Done || result.NextRecordsURL == "" || len(result.Records) == 0 {
+			return nil
+		}
+
+		nextRecordsURL := result.NextRecordsURL
+		result, err = client.query(nextRecordsURL, queryOptionsHeader(batchSize))
+		for retry := 0; isQueryTimeout(err) && retry < opts.MaxRetries; retry++ {
+			log.Println(logPrefix, "query page timed out, retrying")
+			result, err = client.query(nextRecordsURL, queryOptionsHeader(batchSize))
+		}
+	}
+	return err
+}
+
+// splitQuery splits a query of the form "SELECT ... FROM Object [WHERE ...]" into n queries of ID ranges.
+func (client *Client) splitQuery(soql string, n int) ([]string, bool) {
+	trimmed := strings.TrimSpace(soql)
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return nil, false
+	}
+	rest := trimmed[len("SELECT"):]
+	fromIdx := indexTopLevelKeyword(rest, "FROM")
+	if fromIdx < 0 {
+		return nil, false
+	}
+	for _, keyword := range []string{"ORDER", "GROUP", "LIMIT", "OFFSET", "FOR", "WITH"} {
+		if indexTopLevelKeyword(rest[fromIdx:], keyword) >= 0 {
+			return nil, false
+		}
+	}
+
+	fields := strings.TrimSpace(rest[:fromIdx])
+	tail := strings.TrimSpace(rest[fromIdx+len("FROM"):])
+	objectType, where := tail, ""
+	if whereIdx := indexTopLevelKeyword(tail, "WHERE"); whereIdx >= 0 {
+		objectType, where = strings.TrimSpace(tail[:whereIdx]), strings.TrimSpace(tail[whereIdx+len("WHERE"):])
+	}
+	if objectType == "" || strings.ContainsAny(objectType, " \t\n") {
+		return nil, false
+	}
+
+	queries, err := client.SplitQueryByID(splitTopLevel(fields), objectType, where, n)
+	if err != nil {
+		log.Println(logPrefix, "failed to split query,", err)
+		return nil, false
+	}
+	return queries, true
+}
+
+// queryOptionsHeader returns the Sforce-Query-Options header requesting batchSize records per page, or nil if
+// batchSize is 0.
+func queryOptionsHeader(batchSize int) http.Header {
+	if batchSize == 0 {
+		return nil
+	}
+	return http.Header{"Sforce-Query-Options": []string{fmt.Sprintf("batchSize=%d", batchSize)}}
+}
+
+// isQueryTimeout reports whether err is a QUERY_TIMEOUT error.
+func isQueryTimeout(err error) bool {
+	return anyError(err, func(err error) bool {
+		return hasErrorCode(err, errcode.QueryTimeout)
+	})
+}
@@ -0,0 +1,92 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const queryTimeoutResponse = `[{"message": "Your query request was running for too long.", "errorCode": "QUERY_TIMEOUT"}]`
+
+func TestClient_QueryEachNarrowsBatchSize(t *testing.T) {
+	var batchSizes []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		options := r.Header.Get("Sforce-Query-Options")
+		batchSizes = append(batchSizes, options)
+		switch {
+		case options != "batchSize=500":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(queryTimeoutResponse))
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			w.Write([]byte(`{"totalSize": 2, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-1",
+				"records": [{"attributes": {"type": "Account"}, "Id": "001A"}]}`))
+		default:
+			w.Write([]byte(`{"totalSize": 2, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "001B"}]}`))
+		}
+	})
+
+	var ids []string
+	err := client.QueryEach("SELECT Id FROM Account", &QueryOptions{BatchSize: 2000, MaxRetries: 2}, func(obj *SObject) error {
+		ids = append(ids, obj.ID())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "001A,001B" ||
+		strings.Join(batchSizes, ",") != "batchSize=2000,batchSize=1000,batchSize=500,batchSize=500" {
+		t.Errorf("unexpected records %v, batch sizes %v", ids, batchSizes)
+	}
+
+	batchSizes = nil
+	err = client.QueryEach("SELECT Id FROM Account", &QueryOptions{BatchSize: 2000, MaxRetries: 1}, func(obj *SObject) error {
+		return nil
+	})
+	if !isQueryTimeout(err) || len(batchSizes) != 2 {
+		t.Errorf("expected timeout after 2 requests, got %v after %v", err, batchSizes)
+	}
+}
+
+func TestClient_QueryEachSplitsByID(t *testing.T) {
+	var queries []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+		switch {
+		case strings.HasSuffix(q, "ORDER BY Id ASC LIMIT 1"):
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "001000000000000AAA"}]}`))
+		case strings.HasSuffix(q, "ORDER BY Id DESC LIMIT 1"):
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "00100000000000zAAA"}]}`))
+		case strings.Contains(q, "Id < "):
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "001000000000001AAA"}]}`))
+		case strings.Contains(q, "Id >= "):
+			w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "00100000000000yAAA"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(queryTimeoutResponse))
+		}
+	})
+
+	var ids []string
+	err := client.QueryEach("SELECT Id, Name FROM Account WHERE Name != null", &QueryOptions{SplitByID: 2}, func(obj *SObject) error {
+		ids = append(ids, obj.ID())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "001000000000001AAA,00100000000000yAAA" {
+		t.Errorf("unexpected records %v", ids)
+	}
+	if queries[len(queries)-1] != "SELECT Id, Name FROM Account WHERE (Name != null) AND Id >= '00100000000000U'" {
+		t.Errorf("unexpected queries %v", queries)
+	}
+
+	// Ordered queries cannot be split.
+	err = client.QueryEach("SELECT Id FROM Account ORDER BY Name", &QueryOptions{SplitByID: 2}, func(obj *SObject) error {
+		return nil
+	})
+	if !isQueryTimeout(err) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
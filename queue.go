@@ -0,0 +1,190 @@
+package simpleforce
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Priority is the class of a request in the request queue of a client.
+type Priority int
+
+// Priority classes of requests.
+const (
+	// PriorityInteractive requests are latency-sensitive, e.g. lookups of single records, and sent before any waiting
+	// batch requests.
+	PriorityInteractive Priority = iota
+	// PriorityBatch requests belong to background jobs, e.g. extractions, and are limited so that they do not starve
+	// interactive requests.
+	PriorityBatch
+)
+
+// RequestQueue configures the request queue of a client, see SetRequestQueue.
+type RequestQueue struct {
+	// MaxInFlight is the maximum number of requests in flight at once.
+	MaxInFlight int
+
+	// MaxBatchInFlight is the maximum number of batch requests in flight at once, at most MaxInFlight. If not positive,
+	// one request slot is kept for interactive requests, unless MaxInFlight is 1.
+	MaxBatchInFlight int
+
+	// Classify returns the priority of a request, DefaultRequestPriority if nil.
+	Classify func(*http.Request) Priority
+}
+
+// requestQueue dispatches requests by priority within the limits of a RequestQueue.
+type requestQueue struct {
+	config RequestQueue
+
+	mu            sync.Mutex
+	inFlight      int
+	batchInFlight int
+	waiting       [2][]chan struct{} // by priority
+}
+
+// SetRequestQueue limits the number of requests the client has in flight at once, e.g. to stay below the concurrent
+// request limit of the org, queueing further requests so that waiting interactive requests are sent before batch
+// requests, and batch requests never take all slots. A request holds its slot until its response body is closed. A
+// nil queue, or one without a positive MaxInFlight, disables queueing.
+func (client *Client) SetRequestQueue(queue *RequestQueue) {
+	if queue == nil || queue.MaxInFlight <= 0 {
+		client.queue = nil
+		return
+	}
+	config := *queue
+	if config.MaxBatchInFlight <= 0 || config.MaxBatchInFlight > config.MaxInFlight {
+		config.MaxBatchInFlight = config.MaxInFlight - 1
+		if config.MaxBatchInFlight == 0 {
+			config.MaxBatchInFlight = 1
+		}
+	}
+	if config.Classify == nil {
+		config.Classify = DefaultRequestPriority
+	}
+	client.queue = &requestQueue{config: config}
+}
+
+// DefaultRequestPriority classifies requests of the Bulk APIs, of further pages of query results and of sObject
+// Collections as batch requests, and all others as interactive requests.
+func DefaultRequestPriority(req *http.Request) Priority {
+	path := req.URL.Path
+	if strings.Contains(path, "/services/async/") || strings.Contains(path, "/jobs/") ||
+		strings.Contains(path, "/composite/sobjects") {
+		return PriorityBatch
+	}
+	if cursor, ok := queryCursor(path); ok && cursor != "" {
+		return PriorityBatch
+	}
+	return PriorityInteractive
+}
+
+// queued sends req with send once the queue, if enabled, has a slot for it, and releases the slot when the response
+// body is closed.
+func (client *Client) queued(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	queue := client.queue
+	if queue == nil {
+		return send(req)
+	}
+	priority := queue.config.Classify(req)
+	err := queue.acquire(req, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := send(req)
+	if err != nil || resp == nil {
+		queue.release(priority)
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { queue.release(priority) }}
+	return resp, nil
+}
+
+// acquire waits for a slot for a request of priority, or until the context of req is done.
+func (queue *requestQueue) acquire(req *http.Request, priority Priority) error {
+	queue.mu.Lock()
+	if queue.available(priority) && len(queue.waiting[PriorityInteractive]) == 0 &&
+		(priority == PriorityInteractive || len(queue.waiting[PriorityBatch]) == 0) {
+		queue.take(priority)
+		queue.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	queue.waiting[priority] = append(queue.waiting[priority], ready)
+	queue.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-req.Context().Done():
+		queue.mu.Lock()
+		defer queue.mu.Unlock()
+		for idx, waiter := range queue.waiting[priority] {
+			if waiter == ready {
+				queue.waiting[priority] = append(queue.waiting[priority][:idx], queue.waiting[priority][idx+1:]...)
+				return req.Context().Err()
+			}
+		}
+		// The slot was granted concurrently, pass it on.
+		queue.free(priority)
+		queue.dispatch()
+		return req.Context().Err()
+	}
+}
+
+// release frees the slot of a request of priority and hands it to the next waiting request.
+func (queue *requestQueue) release(priority Priority) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.free(priority)
+	queue.dispatch()
+}
+
+// dispatch grants free slots to waiting requests, interactive ones first. The caller must hold mu.
+func (queue *requestQueue) dispatch() {
+	for _, priority := range []Priority{PriorityInteractive, PriorityBatch} {
+		for len(queue.waiting[priority]) > 0 && queue.available(priority) {
+			ready := queue.waiting[priority][0]
+			queue.waiting[priority] = queue.waiting[priority][1:]
+			queue.take(priority)
+			close(ready)
+		}
+	}
+}
+
+// available reports whether a request of priority may be sent. The caller must hold mu.
+func (queue *requestQueue) available(priority Priority) bool {
+	if queue.inFlight >= queue.config.MaxInFlight {
+		return false
+	}
+	return priority != PriorityBatch || queue.batchInFlight < queue.config.MaxBatchInFlight
+}
+
+// take and free count a request of priority in and out of flight. The caller must hold mu.
+func (queue *requestQueue) take(priority Priority) {
+	queue.inFlight++
+	if priority == PriorityBatch {
+		queue.batchInFlight++
+	}
+}
+
+func (queue *requestQueue) free(priority Priority) {
+	queue.inFlight--
+	if priority == PriorityBatch {
+		queue.batchInFlight--
+	}
+}
+
+// releasingBody is a response body calling release once when closed, e.g. to free the queue slot of its request.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (body *releasingBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.once.Do(body.release)
+	return err
+}
@@ -0,0 +1,123 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultRequestPriority(t *testing.T) {
+	for path, expected := range map[string]Priority{
+		"/services/data/v54.0/query":                          PriorityInteractive,
+		"/services/data/v54.0/sobjects/Account/001A":          PriorityInteractive,
+		"/services/data/v54.0/query/01gD0000002HU6KIAW-2000":  PriorityBatch,
+		"/services/data/v54.0/jobs/query/750A/results":        PriorityBatch,
+		"/services/async/54.0/job/750A/batch":                 PriorityBatch,
+		"/services/data/v54.0/composite/sobjects":             PriorityBatch,
+		"/services/data/v54.0/composite/sobjects/Account/Id/": PriorityBatch,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.my.salesforce.com"+path, nil)
+		if got := DefaultRequestPriority(req); got != expected {
+			t.Errorf("expected %d for %s, got %d", expected, path, got)
+		}
+	}
+}
+
+func TestRequestQueue_Priorities(t *testing.T) {
+	client := NewClient("https://example.my.salesforce.com", DefaultClientID, DefaultAPIVersion)
+	client.SetRequestQueue(&RequestQueue{MaxInFlight: 2})
+	queue := client.queue
+	req, _ := http.NewRequest(http.MethodGet, "https://example.my.salesforce.com/", nil)
+
+	// A batch request takes the only batch slot, an interactive one the other slot.
+	if err := queue.acquire(req, PriorityBatch); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	wait := func(name string, priority Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := queue.acquire(req, priority); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}()
+		// Let the request join the queue before the next one.
+		time.Sleep(10 * time.Millisecond)
+	}
+	wait("batch", PriorityBatch)
+	if err := queue.acquire(req, PriorityInteractive); err != nil {
+		t.Fatal(err)
+	}
+	wait("interactive", PriorityInteractive)
+
+	// The first release goes to the interactive request, which waited after the batch request.
+	queue.release(PriorityBatch)
+	time.Sleep(10 * time.Millisecond)
+	queue.release(PriorityInteractive)
+	queue.release(PriorityInteractive)
+	wg.Wait()
+	if len(order) != 2 || order[0] != "interactive" || order[1] != "batch" {
+		t.Errorf("unexpected order %v", order)
+	}
+	queue.release(PriorityBatch)
+	if queue.inFlight != 0 || queue.batchInFlight != 0 {
+		t.Errorf("expected no requests in flight, got %d/%d", queue.inFlight, queue.batchInFlight)
+	}
+
+	// Waiting ends with the context of the request.
+	queue.acquire(req, PriorityInteractive)
+	queue.acquire(req, PriorityInteractive)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := queue.acquire(req.WithContext(ctx), PriorityBatch); err != context.DeadlineExceeded {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+	if len(queue.waiting[PriorityBatch]) != 0 {
+		t.Error("expected the canceled request to leave the queue")
+	}
+}
+
+func TestClient_SetRequestQueue(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
+	client.SetRequestQueue(&RequestQueue{MaxInFlight: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Query("SELECT Id FROM Account"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+	if client.queue.inFlight != 0 {
+		t.Errorf("expected all slots to be released, got %d", client.queue.inFlight)
+	}
+}
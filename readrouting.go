@@ -0,0 +1,140 @@
+package simpleforce
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxRoutedCursors bounds the number of query cursors whose session is remembered; cursors of abandoned queries are
+// forgotten once it is reached.
+const maxRoutedCursors = 10000
+
+// queryPeekSize is the size of the beginning of query responses searched for the nextRecordsUrl, which salesforce
+// sends before the records.
+const queryPeekSize = 1024
+
+// nextRecordsURLPattern matches the nextRecordsUrl of a query response.
+var nextRecordsURLPattern = regexp.MustCompile(`"nextRecordsUrl"\s*:\s*"([^"]+)"`)
+
+// readRouter spreads queries across the sessions of a client and its read clients.
+type readRouter struct {
+	mu      sync.Mutex
+	readers []*Client
+	next    int
+	cursors map[string]*Client // reader running each query cursor
+}
+
+// SetReadSessions registers clients logged in as other users of the same org, e.g. further integration users, across
+// which the queries of the client are spread round-robin, together with the client itself, so that the per-user limits
+// of concurrent requests are shared. Only SOQL queries are routed: the further pages of a query are fetched with the
+// session which ran it, and DML and all other requests always use the session of the client. The users should see the
+// same records, as each query is run with the sharing of the user it is routed to. Calling it without clients disables
+// routing.
+func (client *Client) SetReadSessions(readers ...*Client) {
+	if len(readers) == 0 {
+		client.readRouter = nil
+		return
+	}
+	client.readRouter = &readRouter{readers: readers, cursors: make(map[string]*Client)}
+}
+
+// routeRead sends the query request req with the session of the next client in turn, or of the client which ran the
+// query for further pages, and returns that client, or nil if req is sent with the session of the client.
+func (client *Client) routeRead(req *http.Request) *Client {
+	router := client.readRouter
+	if router == nil || req.Method != http.MethodGet {
+		return nil
+	}
+	cursor, ok := queryCursor(req.URL.Path)
+	if !ok {
+		return nil
+	}
+
+	router.mu.Lock()
+	var reader *Client
+	if cursor != "" {
+		reader = router.cursors[cursor]
+	} else {
+		// The client itself takes the turn after the last reader.
+		if router.next < len(router.readers) {
+			reader = router.readers[router.next]
+		}
+		router.next = (router.next + 1) % (len(router.readers) + 1)
+	}
+	router.mu.Unlock()
+	if reader == nil || !reader.isLoggedIn() {
+		return nil
+	}
+
+	instance, err := url.Parse(reader.instanceURL)
+	if err != nil || instance.Host == "" {
+		return nil
+	}
+	req.URL.Scheme, req.URL.Host, req.Host = instance.Scheme, instance.Host, instance.Host
+	req.Header.Set("Authorization", "Bearer "+reader.sessionID)
+	return reader
+}
+
+// trackRead remembers the client reader as the one running the query cursor of resp, if the results have more pages,
+// and forgets the cursor of the page requested.
+func (client *Client) trackRead(reader *Client, req *http.Request, resp *http.Response) {
+	router := client.readRouter
+	if router == nil {
+		return
+	}
+	if cursor, _ := queryCursor(req.URL.Path); cursor != "" {
+		router.mu.Lock()
+		delete(router.cursors, cursor)
+		router.mu.Unlock()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	buffered := bufio.NewReaderSize(resp.Body, queryPeekSize)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{buffered, resp.Body}
+	head, _ := buffered.Peek(queryPeekSize)
+	match := nextRecordsURLPattern.FindSubmatch(head)
+	if match == nil {
+		return
+	}
+	next, _ := queryCursor(string(match[1]))
+	if next == "" {
+		return
+	}
+
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	if len(router.cursors) >= maxRoutedCursors {
+		router.cursors = make(map[string]*Client)
+	}
+	router.cursors[next] = reader
+}
+
+// queryCursor reports whether path is a query resource, and returns the ID of the cursor of its further pages, e.g.
+// "01gD0000002HU6KIAW" of ".../query/01gD0000002HU6KIAW-2000", or an empty string for the first page.
+func queryCursor(path string) (cursor string, ok bool) {
+	idx := strings.Index(path, "/services/data/v")
+	if idx < 0 {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(path[idx+len("/services/data/"):], "/"), "/")
+	if len(parts) < 2 || (parts[1] != "query" && parts[1] != "queryAll") {
+		return "", false
+	}
+	if len(parts) == 2 {
+		return "", true
+	}
+	if len(parts) > 3 {
+		return "", false
+	}
+	return strings.SplitN(parts[2], "-", 2)[0], true
+}
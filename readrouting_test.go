@@ -0,0 +1,81 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestQueryCursor(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/services/data/v54.0/query":                             "ok ",
+		"/services/data/v54.0/queryAll/":                         "ok ",
+		"/services/data/v54.0/query/01gD0000002HU6KIAW-2000":     "ok 01gD0000002HU6KIAW",
+		"/partners/services/data/v54.0/query/01gD0000002HU6KIAW": "ok 01gD0000002HU6KIAW",
+		"/services/data/v54.0/tooling/query":                     "no ",
+		"/services/data/v54.0/sobjects/Account":                  "no ",
+	} {
+		cursor, ok := queryCursor(path)
+		got := "no "
+		if ok {
+			got = "ok " + cursor
+		}
+		if got != expected {
+			t.Errorf("expected %q for %s, got %q", expected, path, got)
+		}
+	}
+}
+
+func TestClient_SetReadSessions(t *testing.T) {
+	var calls []string
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, name+" "+r.Method+" "+r.Header.Get("Authorization"))
+			switch {
+			case r.Method == http.MethodPost:
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": "001N", "success": true, "errors": []}`))
+			case strings.HasSuffix(r.URL.Path, "/query"):
+				w.Write([]byte(`{"totalSize": 2, "done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g` +
+					name + `-1", "records": [{"attributes": {"type": "Account"}, "Id": "001A"}]}`))
+			default:
+				w.Write([]byte(`{"totalSize": 2, "done": true, "records": [{"attributes": {"type": "Account"}, "Id": "001B"}]}`))
+			}
+		}
+	}
+	primary, _ := newStubClient(t, handler("primary"))
+	reader, _ := newStubClient(t, handler("reader"))
+	reader.SetSidLoc("__READER__", reader.instanceURL)
+	primary.SetReadSessions(reader)
+
+	for i := 0; i < 2; i++ {
+		count := 0
+		err := primary.QueryEach("SELECT Id FROM Account", nil, func(record *SObject) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 records, got %d", count)
+		}
+	}
+	if primary.SObject("Account").Set("Name", "New").Create() == nil {
+		t.Fatal("create failed")
+	}
+
+	expected := []string{
+		"reader GET Bearer __READER__",
+		"reader GET Bearer __READER__",
+		"primary GET Bearer __SESSION__",
+		"primary GET Bearer __SESSION__",
+		"primary POST Bearer __SESSION__",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if len(primary.readRouter.cursors) != 0 {
+		t.Errorf("expected finished cursors to be forgotten, got %v", primary.readRouter.cursors)
+	}
+}
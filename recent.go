@@ -0,0 +1,61 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RecentItems returns the records most recently viewed or modified by the logged in user, most recent first, with
+// their type, ID and name. At most limit records are returned; salesforce returns up to 200 if limit is not positive.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_recent_items.htm
+func (client *Client) RecentItems(limit int) ([]SObject, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("recent/")
+	if limit > 0 {
+		u += "?limit=" + strconv.Itoa(limit)
+	}
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []SObject
+	err = client.unmarshalJSON(data, &records)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range records {
+		records[idx].setClient(client)
+	}
+	return records, nil
+}
+
+// RecentlyViewed returns the records of objectType most recently viewed by the logged in user, most recent first, with
+// their ID and name, e.g. to offer them in a record picker.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_basic_info.htm
+func (client *Client) RecentlyViewed(objectType string) ([]SObject, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("sobjects/"+url.PathEscape(objectType)+"/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		RecentItems []SObject `json:"recentItems"`
+	}
+	err = client.unmarshalJSON(data, &info)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range info.RecentItems {
+		info.RecentItems[idx].setClient(client)
+	}
+	return info.RecentItems, nil
+}
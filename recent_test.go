@@ -0,0 +1,41 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_RecentItems(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/recent/"):
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[
+				{"attributes": {"type": "Account", "url": "/services/data/v54.0/sobjects/Account/001A"}, "Id": "001A", "Name": "Acme"},
+				{"attributes": {"type": "Contact", "url": "/services/data/v54.0/sobjects/Contact/003A"}, "Id": "003A", "Name": "Jane Doe"}
+			]`))
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Account/"):
+			w.Write([]byte(`{"objectDescribe": {"name": "Account"}, "recentItems": [
+				{"attributes": {"type": "Account", "url": "/services/data/v54.0/sobjects/Account/001A"}, "Id": "001A", "Name": "Acme"}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	items, err := client.RecentItems(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].Type() != "Contact" || items[1].StringField("Name") != "Jane Doe" || items[1].client() != client {
+		t.Errorf("unexpected items %v", items)
+	}
+
+	items, err = client.RecentlyViewed("Account")
+	if err != nil || len(items) != 1 || items[0].ID() != "001A" {
+		t.Errorf("unexpected items %v, %v", items, err)
+	}
+}
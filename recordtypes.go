@@ -0,0 +1,75 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRecordTypeNotFound is returned when a record type cannot be resolved.
+var ErrRecordTypeNotFound = errors.New("record type not found")
+
+// RecordTypeInfo describes a record type of an object as seen by the running user.
+type RecordTypeInfo struct {
+	RecordTypeID             string `json:"recordTypeId"`
+	Name                     string `json:"name"`
+	DeveloperName            string `json:"developerName"`
+	Active                   bool   `json:"active"`
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	Master                   bool   `json:"master"`
+}
+
+// RecordTypes lists the record types of objectType, including the master record type, from the describe metadata of
+// the object.
+func (client *Client) RecordTypes(objectType string) ([]RecordTypeInfo, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("sobjects/" + url.PathEscape(objectType) + "/describe")
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta struct {
+		RecordTypeInfos []RecordTypeInfo `json:"recordTypeInfos"`
+	}
+	err = client.unmarshalJSON(data, &meta)
+	if err != nil {
+		return nil, err
+	}
+	return meta.RecordTypeInfos, nil
+}
+
+// RecordTypeID resolves the developer name of a record type of objectType to its ID. ErrRecordTypeNotFound is
+// returned if the object has no such record type.
+func (client *Client) RecordTypeID(objectType, developerName string) (string, error) {
+	recordTypes, err := client.RecordTypes(objectType)
+	if err != nil {
+		return "", err
+	}
+	for _, recordType := range recordTypes {
+		if recordType.DeveloperName == developerName {
+			return recordType.RecordTypeID, nil
+		}
+	}
+	return "", errors.Wrapf(ErrRecordTypeNotFound, "%s.%s", objectType, developerName)
+}
+
+// DefaultRecordType returns the default record type of objectType for the running user. For objects without record
+// types, this is the master record type. ErrRecordTypeNotFound is returned if no default is reported.
+func (client *Client) DefaultRecordType(objectType string) (*RecordTypeInfo, error) {
+	recordTypes, err := client.RecordTypes(objectType)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range recordTypes {
+		if recordTypes[idx].DefaultRecordTypeMapping {
+			return &recordTypes[idx], nil
+		}
+	}
+	return nil, errors.Wrapf(ErrRecordTypeNotFound, "default of %s", objectType)
+}
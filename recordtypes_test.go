@@ -0,0 +1,41 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_RecordTypes(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/v"+DefaultAPIVersion+"/sobjects/Case/describe" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"name": "Case", "recordTypeInfos": [
+			{"recordTypeId": "012000000000001AAA", "name": "Support", "developerName": "Support", "active": true,
+				"available": true, "defaultRecordTypeMapping": false},
+			{"recordTypeId": "012000000000002AAA", "name": "Billing", "developerName": "Billing_Case", "active": true,
+				"available": true, "defaultRecordTypeMapping": true},
+			{"recordTypeId": "012000000000000AAA", "name": "Master", "developerName": "Master", "master": true}
+		]}`))
+	})
+
+	recordTypes, err := client.RecordTypes("Case")
+	if err != nil || len(recordTypes) != 3 || !recordTypes[2].Master {
+		t.Fatalf("unexpected record types %v, %v", recordTypes, err)
+	}
+
+	id, err := client.RecordTypeID("Case", "Billing_Case")
+	if err != nil || id != "012000000000002AAA" {
+		t.Errorf("unexpected ID %s, %v", id, err)
+	}
+	if _, err = client.RecordTypeID("Case", "Missing"); errors.Cause(err) != ErrRecordTypeNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	recordType, err := client.DefaultRecordType("Case")
+	if err != nil || recordType.DeveloperName != "Billing_Case" {
+		t.Errorf("unexpected default %v, %v", recordType, err)
+	}
+}
@@ -0,0 +1,73 @@
+// Package rediscache implements the simpleforce.Cache interface on Redis, so that the instances of a horizontally
+// scaled service share describe and query results. It is a separate module so that the Redis client is only pulled in
+// by programs which need it.
+package rediscache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/scottraio/simpleforce"
+)
+
+// defaultTimeout bounds every Redis command, if Cache.Timeout is not positive.
+const defaultTimeout = time.Second
+
+// Cache is a simpleforce.Cache storing values in Redis.
+type Cache struct {
+	Client redis.UniversalClient
+
+	// Prefix is prepended to all keys, e.g. to separate services sharing a Redis database.
+	Prefix string
+
+	// Timeout bounds every Redis command, one second if not positive.
+	Timeout time.Duration
+}
+
+var _ simpleforce.Cache = (*Cache)(nil)
+
+// New returns a Cache storing values through client, with keys prefixed by prefix.
+func New(client redis.UniversalClient, prefix string) *Cache {
+	return &Cache{Client: client, Prefix: prefix}
+}
+
+// Get implements simpleforce.Cache.
+func (cache *Cache) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := cache.context()
+	defer cancel()
+	value, err := cache.Client.Get(ctx, cache.Prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements simpleforce.Cache.
+func (cache *Cache) Set(key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	ctx, cancel := cache.context()
+	defer cancel()
+	return cache.Client.Set(ctx, cache.Prefix+key, value, ttl).Err()
+}
+
+// Delete implements simpleforce.Cache.
+func (cache *Cache) Delete(key string) error {
+	ctx, cancel := cache.context()
+	defer cancel()
+	return cache.Client.Del(ctx, cache.Prefix+key).Err()
+}
+
+// context returns the context of a Redis command.
+func (cache *Cache) context() (context.Context, context.CancelFunc) {
+	timeout := cache.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,75 @@
+package rediscache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/scottraio/simpleforce"
+)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "svc:"), server
+}
+
+func TestCache(t *testing.T) {
+	cache, server := newTestCache(t)
+
+	if _, ok, err := cache.Get("missing"); ok || err != nil {
+		t.Errorf("expected a miss, got %t, %v", ok, err)
+	}
+	if err := cache.Set("key", []byte("value"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !server.Exists("svc:key") {
+		t.Error("expected the key to be prefixed")
+	}
+	if value, ok, err := cache.Get("key"); !ok || err != nil || string(value) != "value" {
+		t.Errorf("unexpected value %q, %t, %v", value, ok, err)
+	}
+
+	server.FastForward(time.Minute)
+	if _, ok, _ := cache.Get("key"); ok {
+		t.Error("expected the key to expire")
+	}
+
+	cache.Set("forever", []byte("value"), 0)
+	if err := cache.Delete("forever"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := cache.Get("forever"); ok {
+		t.Error("expected the key to be deleted")
+	}
+}
+
+func TestCache_SharedDescribes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/userinfo" {
+			w.Write([]byte(`{"user_id": "005A"}`))
+			return
+		}
+		requests++
+		w.Write([]byte(`{"name": "Account", "fields": [{"name": "Id", "type": "id"}]}`))
+	}))
+	defer server.Close()
+	cache, _ := newTestCache(t)
+
+	for i := 0; i < 2; i++ {
+		client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+		client.SetSidLoc("__SESSION__", server.URL)
+		client.SetDescribeCache(cache, time.Hour)
+		if _, err := client.DescribeSObjects("Account"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected a single describe, got %d", requests)
+	}
+}
@@ -0,0 +1,193 @@
+package simpleforce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRelationshipNotFound is returned by Related and Parent for relationships the object does not have.
+var ErrRelationshipNotFound = errors.New("relationship not found")
+
+// relatedOptions are the options of Related and RelatedEach.
+type relatedOptions struct {
+	fields    []string
+	condition string
+	orderBy   string
+	pageSize  int
+}
+
+// RelatedOption is a functional option for Related and RelatedEach.
+type RelatedOption func(*relatedOptions)
+
+// WithRelatedFields selects fields of the child records, e.g. "Id", "LastName" or "Owner.Name". All fields of the child
+// object are selected by default.
+func WithRelatedFields(fields ...string) RelatedOption {
+	return func(opts *relatedOptions) {
+		opts.fields = fields
+	}
+}
+
+// WithRelatedCondition restricts the child records to those matching the SOQL condition, e.g. "IsActive__c = true".
+func WithRelatedCondition(condition string) RelatedOption {
+	return func(opts *relatedOptions) {
+		opts.condition = condition
+	}
+}
+
+// WithRelatedOrder sorts the child records by the ORDER BY clause orderBy, e.g. "CreatedDate DESC".
+func WithRelatedOrder(orderBy string) RelatedOption {
+	return func(opts *relatedOptions) {
+		opts.orderBy = orderBy
+	}
+}
+
+// WithRelatedPageSize sets the number of child records fetched per request, between 200 and 2000.
+func WithRelatedPageSize(size int) RelatedOption {
+	return func(opts *relatedOptions) {
+		opts.pageSize = size
+	}
+}
+
+// Related queries the child records of the relationship relationshipName, e.g. "Contacts" of an Account or
+// "Line_Items__r", from salesforce when called. The relationship is looked up in the describe metadata cached by the
+// client; the SObject needs its type, ID and client. Use RelatedEach to page through large numbers of child records.
+func (obj *SObject) Related(relationshipName string, opts ...RelatedOption) ([]*SObject, error) {
+	var records []*SObject
+	err := obj.RelatedEach(relationshipName, func(record *SObject) error {
+		records = append(records, record)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// RelatedEach queries the child records of the relationship relationshipName like Related and calls fn with every
+// record, page by page. If fn returns an error, RelatedEach stops and returns it.
+func (obj *SObject) RelatedEach(relationshipName string, fn func(*SObject) error, opts ...RelatedOption) error {
+	if err := obj.checkOnline(); err != nil {
+		return err
+	}
+	if obj.ID() == "" {
+		return ErrMissingID
+	}
+	options := &relatedOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	client := obj.client()
+	_, childType, field, err := client.childRelationship(obj.Type(), relationshipName)
+	if err != nil {
+		return err
+	}
+	soql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(options.fields, ", "), childType)
+	if len(options.fields) == 0 {
+		soql, err = client.SelectFields(childType, FieldsAll)
+		if err != nil {
+			return err
+		}
+	}
+	soql += fmt.Sprintf(" WHERE %s = %s", field, QuoteSOQL(obj.ID()))
+	if options.condition != "" {
+		soql += " AND (" + options.condition + ")"
+	}
+	if options.orderBy != "" {
+		soql += " ORDER BY " + options.orderBy
+	}
+	return client.QueryEach(soql, &QueryOptions{BatchSize: options.pageSize}, fn)
+}
+
+// Parent fetches the record referenced by the lookup or master-detail relationship relationshipName, e.g. "Account" of a
+// Contact or "Parent__r", by the ID in its reference field. All fields are retrieved unless fields are given. The type
+// of polymorphic relationships, e.g. "What", is told by the key prefix of the ID. nil is returned if the reference field
+// is null; ErrFieldNotFound if it is not present, e.g. because it was not queried.
+func (obj *SObject) Parent(relationshipName string, fields ...string) (*SObject, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
+	}
+	client := obj.client()
+	field, referenceTo, err := client.parentRelationship(obj.Type(), relationshipName)
+	if err != nil {
+		return nil, err
+	}
+	id, err := obj.StringFieldE(field)
+	if err != nil || id == "" {
+		return nil, err
+	}
+
+	parentType := ""
+	if len(referenceTo) == 1 {
+		parentType = referenceTo[0]
+	} else {
+		objects, err := client.ListSObjects()
+		if err != nil {
+			return nil, err
+		}
+		info := objects.ByKeyPrefix(id)
+		if info == nil {
+			return nil, errors.Errorf("no object with the key prefix of %s", id)
+		}
+		parentType = info.Name
+	}
+
+	if len(fields) == 0 {
+		return client.SObject(parentType).GetE(id)
+	}
+	result, err := client.Query(fmt.Sprintf("SELECT %s FROM %s WHERE Id = %s",
+		strings.Join(fields, ", "), parentType, QuoteSOQL(id)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.Errorf("%s %s not found", parentType, id)
+	}
+	return &result.Records[0], nil
+}
+
+// childRelationship returns the name as described, the child object and the reference field of its records of the
+// child relationship relationshipName of objectType.
+func (client *Client) childRelationship(objectType, relationshipName string) (name, childType, field string, err error) {
+	metas, err := client.DescribeSObjects(objectType)
+	if err != nil {
+		return "", "", "", err
+	}
+	relationships, _ := (*metas[objectType])["childRelationships"].([]interface{})
+	for _, raw := range relationships {
+		relationship, _ := raw.(map[string]interface{})
+		if name, _ = relationship["relationshipName"].(string); strings.EqualFold(name, relationshipName) {
+			childType, _ = relationship["childSObject"].(string)
+			field, _ = relationship["field"].(string)
+			return name, childType, field, nil
+		}
+	}
+	return "", "", "", errors.Wrapf(ErrRelationshipNotFound, "%s.%s", objectType, relationshipName)
+}
+
+// parentRelationship returns the reference field of the relationship relationshipName of objectType and the objects it
+// may reference.
+func (client *Client) parentRelationship(objectType, relationshipName string) (field string, referenceTo []string, err error) {
+	metas, err := client.DescribeSObjects(objectType)
+	if err != nil {
+		return "", nil, err
+	}
+	fields, _ := (*metas[objectType])["fields"].([]interface{})
+	for _, raw := range fields {
+		describe, _ := raw.(map[string]interface{})
+		if name, _ := describe["relationshipName"].(string); !strings.EqualFold(name, relationshipName) {
+			continue
+		}
+		field, _ = describe["name"].(string)
+		targets, _ := describe["referenceTo"].([]interface{})
+		for _, target := range targets {
+			if name, ok := target.(string); ok {
+				referenceTo = append(referenceTo, name)
+			}
+		}
+		return field, referenceTo, nil
+	}
+	return "", nil, errors.Wrapf(ErrRelationshipNotFound, "%s.%s", objectType, relationshipName)
+}
@@ -0,0 +1,104 @@
+package simpleforce
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+const relationshipsDescribe = `{"name": "Contact", "fields": [
+	{"name": "Id", "type": "id"},
+	{"name": "LastName", "type": "string"},
+	{"name": "AccountId", "type": "reference", "relationshipName": "Account", "referenceTo": ["Account"]},
+	{"name": "OwnerId", "type": "reference", "relationshipName": "Owner", "referenceTo": ["Group", "User"]}
+], "childRelationships": [
+	{"childSObject": "Case", "field": "ContactId", "relationshipName": "Cases"}
+]}`
+
+func newRelationshipsClient(t *testing.T) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/sobjects/Contact/describe":
+			w.Write([]byte(relationshipsDescribe))
+		case "/services/data/v54.0/sobjects/Case/describe":
+			w.Write([]byte(`{"name": "Case", "fields": [{"name": "Id"}, {"name": "Subject"}]}`))
+		case "/services/data/v54.0/sobjects":
+			w.Write([]byte(`{"sobjects": [{"name": "Group", "keyPrefix": "00G"}, {"name": "User", "keyPrefix": "005"}]}`))
+		case "/services/data/v54.0/sobjects/Account/001A":
+			w.Write([]byte(`{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme"}`))
+		case "/services/data/v54.0/sobjects/User/005A":
+			w.Write([]byte(`{"attributes": {"type": "User"}, "Id": "005A", "Name": "Jane Doe"}`))
+		case "/services/data/v54.0/query":
+			switch q := r.URL.Query().Get("q"); q {
+			case "SELECT Id, Subject FROM Case WHERE ContactId = '003A' AND (IsClosed = false) ORDER BY CreatedDate":
+				w.Write([]byte(`{"done": false, "nextRecordsUrl": "/services/data/v54.0/query/01gA-2000", "records": [
+					{"attributes": {"type": "Case"}, "Id": "500A", "Subject": "First"}]}`))
+			case "SELECT Subject FROM Case WHERE ContactId = '003A'":
+				w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "Case"}, "Subject": "First"}]}`))
+			case "SELECT Name FROM Account WHERE Id = '001A'":
+				w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "Account"}, "Name": "Acme"}]}`))
+			default:
+				t.Errorf("unexpected query %s", q)
+			}
+		case "/services/data/v54.0/query/01gA-2000":
+			w.Write([]byte(`{"done": true, "records": [{"attributes": {"type": "Case"}, "Id": "500B", "Subject": "Second"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+	return client
+}
+
+func TestSObject_Related(t *testing.T) {
+	client := newRelationshipsClient(t)
+	contact := client.SObject("Contact").Set("Id", "003A")
+
+	cases, err := contact.Related("Cases", WithRelatedCondition("IsClosed = false"), WithRelatedOrder("CreatedDate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 || cases[0].ID() != "500A" || cases[1].StringField("Subject") != "Second" {
+		t.Errorf("unexpected cases %v", cases)
+	}
+
+	cases, err = contact.Related("cases", WithRelatedFields("Subject"))
+	if err != nil || len(cases) != 1 {
+		t.Errorf("unexpected cases %v %v", cases, err)
+	}
+
+	if _, err = contact.Related("Opportunities"); !errors.Is(err, ErrRelationshipNotFound) {
+		t.Errorf("expected ErrRelationshipNotFound, got %v", err)
+	}
+	if _, err = client.SObject("Contact").Related("Cases"); !errors.Is(err, ErrMissingID) {
+		t.Errorf("expected ErrMissingID, got %v", err)
+	}
+}
+
+func TestSObject_Parent(t *testing.T) {
+	client := newRelationshipsClient(t)
+	contact := client.SObject("Contact").Set("Id", "003A").Set("AccountId", "001A").Set("OwnerId", "005A")
+
+	account, err := contact.Parent("Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Type() != "Account" || account.StringField("Name") != "Acme" {
+		t.Errorf("unexpected account %v", account)
+	}
+	if account, err = contact.Parent("Account", "Name"); err != nil || account.StringField("Name") != "Acme" {
+		t.Errorf("unexpected account %v %v", account, err)
+	}
+
+	owner, err := contact.Parent("Owner")
+	if err != nil || owner.Type() != "User" || owner.StringField("Name") != "Jane Doe" {
+		t.Errorf("unexpected owner %v %v", owner, err)
+	}
+
+	if account, err = client.SObject("Contact").Set("AccountId", nil).Parent("Account"); account != nil || err != nil {
+		t.Errorf("expected no account for a null reference, got %v %v", account, err)
+	}
+	if _, err = client.SObject("Contact").Parent("Account"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// replicationTimeLayout is the ISO 8601 layout expected by the get updated/deleted resources.
+const replicationTimeLayout = "2006-01-02T15:04:05+00:00"
+
+// UpdatedResult is returned by GetUpdated.
+type UpdatedResult struct {
+	IDs               []string
+	LatestDateCovered time.Time
+}
+
+// DeletedRecord identifies a record deleted within the range of a GetDeleted call.
+type DeletedRecord struct {
+	ID          string
+	DeletedDate time.Time
+}
+
+// DeletedResult is returned by GetDeleted.
+type DeletedResult struct {
+	DeletedRecords        []DeletedRecord
+	EarliestDateAvailable time.Time
+	LatestDateCovered     time.Time
+}
+
+// GetUpdated returns the IDs of records of type typeName updated between start and end, which must be within the last
+// 30 days.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_getupdated.htm
+func (client *Client) GetUpdated(typeName string, start, end time.Time) (*UpdatedResult, error) {
+	var raw struct {
+		IDs               []string `json:"ids"`
+		LatestDateCovered string   `json:"latestDateCovered"`
+	}
+	err := client.getReplication(typeName, "updated", start, end, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdatedResult{IDs: raw.IDs}
+	result.LatestDateCovered, err = parseOptionalTime(raw.LatestDateCovered)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetDeleted returns the records of type typeName deleted between start and end, which must be within the last 30
+// days.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_getdeleted.htm
+func (client *Client) GetDeleted(typeName string, start, end time.Time) (*DeletedResult, error) {
+	var raw struct {
+		DeletedRecords []struct {
+			ID          string `json:"id"`
+			DeletedDate string `json:"deletedDate"`
+		} `json:"deletedRecords"`
+		EarliestDateAvailable string `json:"earliestDateAvailable"`
+		LatestDateCovered     string `json:"latestDateCovered"`
+	}
+	err := client.getReplication(typeName, "deleted", start, end, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeletedResult{}
+	for _, record := range raw.DeletedRecords {
+		deletedDate, err := parseOptionalTime(record.DeletedDate)
+		if err != nil {
+			return nil, err
+		}
+		result.DeletedRecords = append(result.DeletedRecords, DeletedRecord{ID: record.ID, DeletedDate: deletedDate})
+	}
+	result.EarliestDateAvailable, err = parseOptionalTime(raw.EarliestDateAvailable)
+	if err != nil {
+		return nil, err
+	}
+	result.LatestDateCovered, err = parseOptionalTime(raw.LatestDateCovered)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (client *Client) getReplication(typeName, resource string, start, end time.Time, result interface{}) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	params := url.Values{}
+	params.Set("start", start.UTC().Format(replicationTimeLayout))
+	params.Set("end", end.UTC().Format(replicationTimeLayout))
+	u := client.makeURL("sobjects/" + typeName + "/" + resource + "/?" + params.Encode())
+
+	data, err := client.httpRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return client.unmarshalJSON(data, result)
+}
+
+// parseOptionalTime parses a salesforce datetime value, returning the zero time for empty values.
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return ParseDateTime(value)
+}
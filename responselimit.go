@@ -0,0 +1,62 @@
+package simpleforce
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ErrResponseTooLarge matches a ResponseTooLargeError.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ResponseTooLargeError is returned when a response exceeds the limit set with SetMaxResponseSize. The rest of the
+// response is discarded without being read into memory.
+type ResponseTooLargeError struct {
+	Limit int64
+	URL   string
+}
+
+// Error implements the error interface.
+func (err *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response of %s exceeds %d bytes", err.URL, err.Limit)
+}
+
+// Is reports whether target is ErrResponseTooLarge.
+func (err *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// SetMaxResponseSize limits the size of the responses the client reads into memory to n bytes, so that memory
+// constrained services fail with a *ResponseTooLargeError instead of running out of memory, e.g. when a query
+// accidentally selects large base64 encoded blob fields. Streamed downloads such as ApexLogBody or the blob download
+// helpers are not limited. 0, the default, disables the limit.
+func (client *Client) SetMaxResponseSize(n int64) {
+	client.maxBodySize = n
+}
+
+// readResponse reads the body of resp, enforcing the limit of SetMaxResponseSize.
+func (client *Client) readResponse(resp *http.Response) ([]byte, error) {
+	limit := client.maxBodySize
+	if limit <= 0 {
+		return ioutil.ReadAll(resp.Body)
+	}
+
+	tooLarge := &ResponseTooLargeError{Limit: limit}
+	if resp.Request != nil {
+		tooLarge.URL = resp.Request.URL.Redacted()
+	}
+	if resp.ContentLength > limit {
+		return nil, tooLarge
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, tooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,56 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_SetMaxResponseSize(t *testing.T) {
+	blob := `"` + strings.Repeat("A", 4096) + `"`
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Document"}, "Body": ` +
+			blob + `}]}`))
+	})
+
+	if _, err := client.Query("SELECT Body FROM Document"); err != nil {
+		t.Fatalf("unexpected error without limit, %v", err)
+	}
+
+	client.SetMaxResponseSize(1024)
+	_, err := client.Query("SELECT Body FROM Document")
+	var tooLarge *ResponseTooLargeError
+	if !errors.Is(err, ErrResponseTooLarge) || !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 ||
+		!strings.Contains(tooLarge.URL, "/query") {
+		t.Errorf("expected ResponseTooLargeError, got %v", err)
+	}
+
+	client.SetMaxResponseSize(1 << 20)
+	if _, err := client.Query("SELECT Body FROM Document"); err != nil {
+		t.Errorf("unexpected error within the limit, %v", err)
+	}
+}
+
+func TestClient_readResponseChunked(t *testing.T) {
+	client, server := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before the body is complete sends it chunked, without a Content-Length.
+		w.Write([]byte(strings.Repeat("x", 100)))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("x", 100)))
+	})
+	client.SetMaxResponseSize(150)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.ContentLength != -1 {
+		t.Fatalf("expected a chunked response, got length %d", resp.ContentLength)
+	}
+	if _, err := client.readResponse(resp); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package simpleforce
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// IsRetryable reports whether err is a transient failure which may succeed if the call is retried after a while: row
+// lock contention, server errors and unavailability, and network timeouts. Rate limiting is not considered retryable as
+// the limits are typically reset only after hours, use IsRateLimited to handle it separately. For a *BatchError,
+// IsRetryable reports whether any record failed for a retryable reason, use the RecordErrors to retry only those.
+func IsRetryable(err error) bool {
+	return anyError(err, func(err error) bool {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return true
+		}
+		if sfErr, ok := err.(SalesforceError); ok && sfErr.HttpCode >= http.StatusInternalServerError {
+			return true
+		}
+		return hasErrorCode(err, errcode.UnableToLockRow, errcode.ServerUnavailable, errcode.RequestRunningTooLong)
+	})
+}
+
+// IsRateLimited reports whether err was caused by exceeding the API request limits of the org.
+func IsRateLimited(err error) bool {
+	return anyError(err, func(err error) bool {
+		if sfErr, ok := err.(SalesforceError); ok && sfErr.HttpCode == http.StatusTooManyRequests {
+			return true
+		}
+		return hasErrorCode(err, errcode.RequestLimitExceeded, errcode.TooManyApexRequests)
+	})
+}
+
+// IsRowLockError reports whether err was caused by a record being locked by another transaction.
+func IsRowLockError(err error) bool {
+	return anyError(err, func(err error) bool {
+		return hasErrorCode(err, errcode.UnableToLockRow)
+	})
+}
+
+// IsSessionExpired reports whether err was caused by an expired or invalid session, in which case the client needs to
+// login again.
+func IsSessionExpired(err error) bool {
+	return anyError(err, func(err error) bool {
+		if sfErr, ok := err.(SalesforceError); ok && sfErr.HttpCode == http.StatusUnauthorized {
+			return true
+		}
+		return hasErrorCode(err, errcode.InvalidSessionID)
+	})
+}
+
+// hasErrorCode reports whether err is a SalesforceError, SaveError or *RecordError with one of the error codes.
+func hasErrorCode(err error, codes ...string) bool {
+	var found []string
+	switch e := err.(type) {
+	case SalesforceError:
+		found = append(found, e.ErrorCode)
+	case SaveError:
+		found = append(found, e.StatusCode)
+	case *RecordError:
+		for _, saveErr := range e.Errors {
+			found = append(found, saveErr.StatusCode)
+		}
+	}
+	for _, code := range found {
+		for _, expected := range codes {
+			if code == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anyError reports whether match returns true for err or any error it wraps, following Unwrap() error, Unwrap()
+// []error (as implemented by *BatchError) and Cause() error (as implemented by github.com/pkg/errors).
+func anyError(err error, match func(error) bool) bool {
+	for err != nil {
+		if match(err) {
+			return true
+		}
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range wrapper.Unwrap() {
+				if anyError(inner, match) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() error }:
+			err = wrapper.Unwrap()
+		case interface{ Cause() error }:
+			err = wrapper.Cause()
+		default:
+			return false
+		}
+	}
+	return false
+}
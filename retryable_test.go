@@ -0,0 +1,47 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestErrorClassification(t *testing.T) {
+	rowLock := &BatchError{Records: []*RecordError{
+		{Index: 0, Errors: []SaveError{{StatusCode: "DUPLICATE_VALUE"}}},
+		{Index: 3, Errors: []SaveError{{StatusCode: "UNABLE_TO_LOCK_ROW"}}},
+	}}
+	rateLimited := SalesforceError{HttpCode: 403, ErrorCode: "REQUEST_LIMIT_EXCEEDED"}
+	expired := SalesforceError{HttpCode: 401, ErrorCode: "INVALID_SESSION_ID"}
+
+	tests := []struct {
+		err                                      error
+		retryable, rateLimited, rowLock, expired bool
+	}{
+		{nil, false, false, false, false},
+		{ErrFailure, false, false, false, false},
+		{rowLock, true, false, true, false},
+		{errors.Wrap(rowLock, "sync failed"), true, false, true, false},
+		{rateLimited, false, true, false, false},
+		{fmt.Errorf("query: %w", expired), false, false, false, true},
+		{SalesforceError{HttpCode: 503, ErrorCode: "SERVER_UNAVAILABLE"}, true, false, false, false},
+		{SalesforceError{HttpCode: 400, ErrorCode: "MALFORMED_QUERY"}, false, false, false, false},
+		{&RecordError{Err: timeoutError{}}, true, false, false, false},
+	}
+	for idx, test := range tests {
+		if IsRetryable(test.err) != test.retryable || IsRateLimited(test.err) != test.rateLimited ||
+			IsRowLockError(test.err) != test.rowLock || IsSessionExpired(test.err) != test.expired {
+			t.Errorf("%d: unexpected classification of %v", idx, test.err)
+		}
+	}
+}
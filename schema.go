@@ -0,0 +1,166 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// SchemaDialect selects the flavor of the schemas generated by NewJSONSchema.
+type SchemaDialect int
+
+const (
+	// JSONSchemaDraft2020 generates JSON Schema (draft 2020-12) documents. Nullable fields allow the type "null".
+	JSONSchemaDraft2020 SchemaDialect = iota
+	// OpenAPI3 generates OpenAPI 3.0 component schemas. Nullable fields are marked as nullable.
+	OpenAPI3
+)
+
+const jsonSchemaDraft2020 = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema or OpenAPI schema of a record or field, generated from describe metadata. Marshal it to
+// JSON to use it with validators and code generators.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"` // a type name, or a list of them
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	MaxLength            int                    `json:"maxLength,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// schemaField is the part of the describe metadata of a field used to generate its schema.
+type schemaField struct {
+	Name               string `json:"name"`
+	Label              string `json:"label"`
+	InlineHelpText     string `json:"inlineHelpText"`
+	Type               string `json:"type"`
+	Length             int    `json:"length"`
+	Nillable           bool   `json:"nillable"`
+	Createable         bool   `json:"createable"`
+	Updateable         bool   `json:"updateable"`
+	DefaultedOnCreate  bool   `json:"defaultedOnCreate"`
+	RestrictedPicklist bool   `json:"restrictedPicklist"`
+	PicklistValues     []struct {
+		Value  string `json:"value"`
+		Active bool   `json:"active"`
+	} `json:"picklistValues"`
+}
+
+// schemaTypes maps the salesforce field types to schema types and formats. Unlisted types are strings.
+var schemaTypes = map[string]struct{ typ, format string }{
+	"boolean":  {"boolean", ""},
+	"int":      {"integer", ""},
+	"double":   {"number", ""},
+	"currency": {"number", ""},
+	"percent":  {"number", ""},
+	"date":     {"string", "date"},
+	"datetime": {"string", "date-time"},
+	"time":     {"string", "time"},
+	"email":    {"string", "email"},
+	"url":      {"string", "uri"},
+	"address":  {"object", ""},
+	"location": {"object", ""},
+	"anyType":  {"", ""},
+}
+
+// JSONSchema generates the schema of records of objectType from its describe metadata, see NewJSONSchema.
+func (client *Client) JSONSchema(objectType string, dialect SchemaDialect) (*JSONSchema, error) {
+	metas, err := client.DescribeSObjects(objectType)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONSchema(metas[objectType], dialect)
+}
+
+// NewJSONSchema generates the schema of records described by meta. Fields which must be set on creation, i.e. not
+// nillable and not defaulted, are required; fields which can neither be created nor updated are read-only. Restricted
+// picklists enumerate their active values.
+func NewJSONSchema(meta *SObjectMeta, dialect SchemaDialect) (*JSONSchema, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	var describe struct {
+		Name   string        `json:"name"`
+		Label  string        `json:"label"`
+		Fields []schemaField `json:"fields"`
+	}
+	err = json.Unmarshal(data, &describe)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &JSONSchema{
+		Title:      describe.Name,
+		Type:       "object",
+		Properties: make(map[string]*JSONSchema, len(describe.Fields)),
+	}
+	if describe.Label != describe.Name {
+		schema.Description = describe.Label
+	}
+	if dialect == JSONSchemaDraft2020 {
+		schema.Schema = jsonSchemaDraft2020
+	}
+	for _, field := range describe.Fields {
+		schema.Properties[field.Name] = newFieldSchema(field, dialect)
+		if !field.Nillable && field.Createable && !field.DefaultedOnCreate {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema, nil
+}
+
+// newFieldSchema generates the schema of a single field.
+func newFieldSchema(field schemaField, dialect SchemaDialect) *JSONSchema {
+	schema := &JSONSchema{
+		Title:       field.Label,
+		Description: field.InlineHelpText,
+		ReadOnly:    !field.Createable && !field.Updateable,
+	}
+
+	mapped, ok := schemaTypes[field.Type]
+	if !ok {
+		mapped.typ = "string"
+	}
+	schema.Format = mapped.format
+	if field.Type == "base64" {
+		if dialect == OpenAPI3 {
+			schema.Format = "byte"
+		} else {
+			schema.ContentEncoding = "base64"
+		}
+	} else if mapped.typ == "string" && mapped.format == "" && field.Length > 0 {
+		schema.MaxLength = field.Length
+	}
+
+	if field.RestrictedPicklist && field.Type == "picklist" {
+		for _, value := range field.PicklistValues {
+			if value.Active {
+				schema.Enum = append(schema.Enum, value.Value)
+			}
+		}
+		if field.Nillable && dialect == JSONSchemaDraft2020 {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+
+	switch {
+	case mapped.typ == "":
+		// Any type, including null.
+	case !field.Nillable:
+		schema.Type = mapped.typ
+	case dialect == OpenAPI3:
+		schema.Type, schema.Nullable = mapped.typ, true
+	default:
+		schema.Type = []string{mapped.typ, "null"}
+	}
+	return schema
+}
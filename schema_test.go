@@ -0,0 +1,60 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_JSONSchema(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Invoice__c", "label": "Invoice", "fields": [
+			{"name": "Id", "label": "Record ID", "type": "id", "length": 18, "nillable": false, "createable": false,
+				"updateable": false, "defaultedOnCreate": true},
+			{"name": "Name", "label": "Invoice Number", "type": "string", "length": 80, "nillable": false,
+				"createable": true, "updateable": true},
+			{"name": "Amount__c", "label": "Amount", "type": "currency", "nillable": true, "createable": true,
+				"updateable": true, "inlineHelpText": "Gross amount"},
+			{"name": "Status__c", "label": "Status", "type": "picklist", "length": 255, "nillable": true,
+				"createable": true, "updateable": true, "restrictedPicklist": true, "picklistValues": [
+				{"value": "Draft", "active": true}, {"value": "Void", "active": false}, {"value": "Sent", "active": true}
+			]},
+			{"name": "Issued__c", "label": "Issued", "type": "date", "nillable": true, "createable": true, "updateable": true}
+		]}`))
+	})
+
+	schema, err := client.JSONSchema("Invoice__c", JSONSchemaDraft2020)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(schema)
+	var doc map[string]interface{}
+	json.Unmarshal(data, &doc)
+	properties := doc["properties"].(map[string]interface{})
+
+	if doc["$schema"] != jsonSchemaDraft2020 || doc["title"] != "Invoice__c" || doc["description"] != "Invoice" ||
+		!reflect.DeepEqual(doc["required"], []interface{}{"Name"}) {
+		t.Errorf("unexpected schema %s", data)
+	}
+	expected := map[string]interface{}{
+		"Id":        map[string]interface{}{"title": "Record ID", "type": "string", "readOnly": true, "maxLength": 18.0},
+		"Name":      map[string]interface{}{"title": "Invoice Number", "type": "string", "maxLength": 80.0},
+		"Amount__c": map[string]interface{}{"title": "Amount", "description": "Gross amount", "type": []interface{}{"number", "null"}},
+		"Status__c": map[string]interface{}{"title": "Status", "type": []interface{}{"string", "null"}, "maxLength": 255.0,
+			"enum": []interface{}{"Draft", "Sent", nil}},
+		"Issued__c": map[string]interface{}{"title": "Issued", "type": []interface{}{"string", "null"}, "format": "date"},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("unexpected properties %v", properties)
+	}
+
+	schema, err = client.JSONSchema("Invoice__c", OpenAPI3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount := schema.Properties["Amount__c"]
+	if schema.Schema != "" || amount.Type != "number" || !amount.Nullable || len(schema.Properties["Status__c"].Enum) != 2 {
+		t.Errorf("unexpected OpenAPI schema %+v", amount)
+	}
+}
@@ -0,0 +1,218 @@
+package simpleforce
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSchemaWatchInterval is used by WatchSchema when no positive interval is given.
+const DefaultSchemaWatchInterval = 5 * time.Minute
+
+// maxSchemaWatchNames is the number of objects looked up in EntityDefinition by a single query.
+const maxSchemaWatchNames = 100
+
+// SchemaWatcher invalidates describes cached by DescribeSObjects when admins change the schema of the objects.
+// Salesforce does not publish change events for metadata, so the watcher polls the LastModifiedDate of the
+// EntityDefinition and of the FieldDefinitions of the cached objects, as changes of fields do not always modify the
+// EntityDefinition, and the number of fields, which drops when a field is deleted. A SchemaWatcher is created by
+// Client.WatchSchema and must be stopped with Stop.
+//
+// Rows without a LastModifiedDate, e.g. of most standard fields, are only counted. Changes of standard fields which
+// do not modify any date, e.g. of the values of their picklists, therefore go unnoticed. The first poll after an object
+// has been described records its state, so changes made between the describe and that poll go unnoticed too. Start the
+// watcher before describing to keep this window small.
+type SchemaWatcher struct {
+	client   *Client
+	interval time.Duration
+	onChange func(names []string)
+	onError  func(error)
+
+	mu       sync.Mutex
+	modified map[string]schemaStamp
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	task     int
+}
+
+// schemaStamp is the state of the schema of an object recorded by a poll.
+type schemaStamp struct {
+	modified time.Time // the latest LastModifiedDate of the object and its fields
+	fields   int
+}
+
+// SchemaWatchOption is a functional option for WatchSchema.
+type SchemaWatchOption func(*SchemaWatcher)
+
+// WithSchemaChangeHandler registers a callback invoked with the names of the objects whose cached describe has been
+// invalidated by a poll.
+func WithSchemaChangeHandler(handler func(names []string)) SchemaWatchOption {
+	return func(w *SchemaWatcher) {
+		w.onChange = handler
+	}
+}
+
+// WithSchemaWatchErrorHandler registers a callback invoked whenever a poll fails. Failures are only logged if no
+// handler is registered.
+func WithSchemaWatchErrorHandler(handler func(error)) SchemaWatchOption {
+	return func(w *SchemaWatcher) {
+		w.onError = handler
+	}
+}
+
+// WatchSchema starts a goroutine which checks the cached describes for schema changes every interval until Stop is
+// called on the returned SchemaWatcher. DefaultSchemaWatchInterval is used if interval is not positive.
+func (client *Client) WatchSchema(interval time.Duration, opts ...SchemaWatchOption) *SchemaWatcher {
+	if interval <= 0 {
+		interval = DefaultSchemaWatchInterval
+	}
+
+	w := &SchemaWatcher{
+		client:   client,
+		interval: interval,
+		modified: make(map[string]schemaStamp),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	go w.run()
+	w.task = client.startTask(w.Stop)
+	return w
+}
+
+// Stop terminates the watcher goroutine and waits for it to exit. It is safe to call Stop more than once. Close stops
+// the watchers of a client.
+func (w *SchemaWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		w.client.endTask(w.task)
+	})
+	<-w.done
+}
+
+// Check polls EntityDefinition and FieldDefinition once and drops the cached describes of the objects modified since
+// the previous poll, or deleted. It returns the names of the invalidated objects. Check is called by the watcher
+// goroutine and may also be called directly, e.g. before a schema sensitive job.
+func (w *SchemaWatcher) Check() ([]string, error) {
+	names := w.client.cachedDescribes()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	modified := make(map[string]schemaStamp, len(names))
+	record := func(name string, field bool, lastModified string) {
+		key := strings.ToLower(name)
+		stamp, found := modified[key]
+		if !found && field {
+			// Fields of objects missing in EntityDefinition, which are deleted.
+			return
+		}
+		if field {
+			stamp.fields++
+		}
+		// Rows without a valid date are kept, so the object is not taken for deleted.
+		if date, err := ParseDateTime(lastModified); err == nil && date.After(stamp.modified) {
+			stamp.modified = date
+		}
+		modified[key] = stamp
+	}
+	for start := 0; start < len(names); start += maxSchemaWatchNames {
+		end := start + maxSchemaWatchNames
+		if end > len(names) {
+			end = len(names)
+		}
+		quoted := make([]string, end-start)
+		for i, name := range names[start:end] {
+			quoted[i] = QuoteSOQL(name)
+		}
+		in := strings.Join(quoted, ", ")
+
+		soql := fmt.Sprintf("SELECT QualifiedApiName, LastModifiedDate FROM EntityDefinition WHERE QualifiedApiName IN (%s)", in)
+		err := w.client.QueryEach(soql, nil, func(entity *SObject) error {
+			record(entity.StringField("QualifiedApiName"), false, entity.StringField("LastModifiedDate"))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		soql = fmt.Sprintf("SELECT EntityDefinition.QualifiedApiName, LastModifiedDate FROM FieldDefinition "+
+			"WHERE EntityDefinition.QualifiedApiName IN (%s)", in)
+		err = w.client.QueryEach(soql, nil, func(field *SObject) error {
+			entity, _ := field.InterfaceField("EntityDefinition").(map[string]interface{})
+			name, _ := entity["QualifiedApiName"].(string)
+			record(name, true, field.StringField("LastModifiedDate"))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.mu.Lock()
+	var changed []string
+	for _, name := range names {
+		key := strings.ToLower(name)
+		previous, seen := w.modified[key]
+		current, found := modified[key]
+		switch {
+		case !found:
+			if seen {
+				changed = append(changed, name)
+				delete(w.modified, key)
+			}
+		case seen && (current.modified.After(previous.modified) || current.fields != previous.fields):
+			changed = append(changed, name)
+			w.modified[key] = current
+		case !seen:
+			w.modified[key] = current
+		}
+	}
+	w.mu.Unlock()
+
+	if len(changed) > 0 {
+		w.client.dropDescribes(changed)
+	}
+	return changed, nil
+}
+
+// run is the loop of the watcher goroutine.
+func (w *SchemaWatcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			changed, err := w.Check()
+			if err != nil {
+				if w.onError != nil {
+					w.onError(err)
+				} else {
+					log.Println(logPrefix, "schema watch failed,", err)
+				}
+				continue
+			}
+			if len(changed) == 0 {
+				continue
+			}
+			if w.onChange != nil {
+				w.onChange(changed)
+			} else {
+				log.Println(logPrefix, "schema changed, dropped cached describes of", strings.Join(changed, ", "))
+			}
+		}
+	}
+}
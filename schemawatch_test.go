@@ -0,0 +1,191 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchemaWatcher_Check(t *testing.T) {
+	var mu sync.Mutex
+	describes := map[string]int{}
+	modified := map[string]string{
+		"Account":    "2022-01-01T00:00:00.000+0000",
+		"Invoice__c": "2022-01-01T00:00:00.000+0000",
+	}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			name := strings.Split(r.URL.Path, "/")[5]
+			describes[name]++
+			fmt.Fprintf(w, `{"name": %q}`, name)
+			return
+		}
+		q := r.URL.Query().Get("q")
+		if strings.HasPrefix(q, "SELECT EntityDefinition.QualifiedApiName, LastModifiedDate FROM FieldDefinition ") {
+			w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+			return
+		}
+		if !strings.HasPrefix(q, "SELECT QualifiedApiName, LastModifiedDate FROM EntityDefinition WHERE QualifiedApiName IN (") {
+			t.Errorf("unexpected query %s", q)
+		}
+		var records []string
+		for name, date := range modified {
+			if strings.Contains(q, "'"+name+"'") {
+				records = append(records, fmt.Sprintf(`{"attributes": {"type": "EntityDefinition"},
+					"QualifiedApiName": %q, "LastModifiedDate": %q}`, name, date))
+			}
+		}
+		fmt.Fprintf(w, `{"totalSize": %d, "done": true, "records": [%s]}`, len(records), strings.Join(records, ","))
+	})
+
+	var notified [][]string
+	watcher := client.WatchSchema(time.Hour, WithSchemaChangeHandler(func(names []string) {
+		notified = append(notified, names)
+	}))
+	defer watcher.Stop()
+
+	if changed, err := watcher.Check(); err != nil || len(changed) != 0 {
+		t.Fatalf("expected nothing to check, got %v, %v", changed, err)
+	}
+	if _, err := client.DescribeSObjects("Account", "Invoice__c"); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := watcher.Check(); err != nil || len(changed) != 0 {
+		t.Fatalf("expected the first check to record the baseline, got %v, %v", changed, err)
+	}
+
+	mu.Lock()
+	modified["Account"] = "2022-02-01T00:00:00.000+0000"
+	delete(modified, "Invoice__c")
+	mu.Unlock()
+	changed, err := watcher.Check()
+	if err != nil || strings.Join(changed, ",") != "Account,Invoice__c" {
+		t.Fatalf("expected both objects to be invalidated, got %v, %v", changed, err)
+	}
+	if _, err := client.DescribeSObjects("Account", "Invoice__c"); err != nil {
+		t.Fatal(err)
+	}
+	if describes["Account"] != 2 || describes["Invoice__c"] != 2 {
+		t.Errorf("expected the invalidated objects to be described again, got %v", describes)
+	}
+
+	if changed, err := watcher.Check(); err != nil || len(changed) != 0 {
+		t.Errorf("expected no changes, got %v, %v", changed, err)
+	}
+	if len(notified) != 0 {
+		t.Errorf("unexpected notification %v", notified)
+	}
+}
+
+func TestSchemaWatcher_CheckFields(t *testing.T) {
+	var mu sync.Mutex
+	entity := `null`
+	fields := []string{`null`, `"2022-01-01T00:00:00.000+0000"`, `"2022-01-01T00:00:00.000+0000"`}
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			w.Write([]byte(`{"name": "Account"}`))
+			return
+		}
+		q := r.URL.Query().Get("q")
+		if strings.HasPrefix(q, "SELECT QualifiedApiName, LastModifiedDate FROM EntityDefinition ") {
+			fmt.Fprintf(w, `{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "EntityDefinition"},
+				"QualifiedApiName": "Account", "LastModifiedDate": %s}]}`, entity)
+			return
+		}
+		if q != "SELECT EntityDefinition.QualifiedApiName, LastModifiedDate FROM FieldDefinition "+
+			"WHERE EntityDefinition.QualifiedApiName IN ('Account')" {
+			t.Errorf("unexpected query %s", q)
+		}
+		var records []string
+		for _, date := range fields {
+			records = append(records, fmt.Sprintf(`{"attributes": {"type": "FieldDefinition"},
+				"EntityDefinition": {"QualifiedApiName": "Account"}, "LastModifiedDate": %s}`, date))
+		}
+		fmt.Fprintf(w, `{"totalSize": %d, "done": true, "records": [%s]}`, len(records), strings.Join(records, ","))
+	})
+	if _, err := client.DescribeSObjects("Account"); err != nil {
+		t.Fatal(err)
+	}
+	watcher := client.WatchSchema(time.Hour)
+	defer watcher.Stop()
+
+	if changed, err := watcher.Check(); err != nil || len(changed) != 0 {
+		t.Fatalf("expected the first check to record the baseline despite null dates, got %v, %v", changed, err)
+	}
+
+	for _, change := range []func(){
+		func() { fields[2] = `"2022-02-01T00:00:00.000+0000"` },
+		func() { fields = fields[:2] },
+		func() { entity = `"2022-03-01T00:00:00.000+0000"` },
+	} {
+		mu.Lock()
+		change()
+		mu.Unlock()
+		changed, err := watcher.Check()
+		if err != nil || len(changed) != 1 || changed[0] != "Account" {
+			t.Fatalf("expected Account to be invalidated, got %v, %v", changed, err)
+		}
+		if _, err := client.DescribeSObjects("Account"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if changed, err := watcher.Check(); err != nil || len(changed) != 0 {
+		t.Errorf("expected no changes, got %v, %v", changed, err)
+	}
+}
+
+func TestClient_WatchSchema(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	modified := "2022-01-01T00:00:00.000+0000"
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			w.Write([]byte(`{"name": "Account"}`))
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+		fmt.Fprintf(w, `{"totalSize": 1, "done": true, "records": [{"attributes": {"type": "EntityDefinition"},
+			"QualifiedApiName": "Account", "LastModifiedDate": %q}]}`, modified)
+	})
+	if _, err := client.DescribeSObjects("Account"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan []string, 10)
+	watcher := client.WatchSchema(5*time.Millisecond, WithSchemaChangeHandler(func(names []string) {
+		changes <- names
+	}))
+	defer watcher.Stop()
+
+	// Change the schema once the watcher has recorded the baseline.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		if polls > 0 || time.Now().After(deadline) {
+			modified = "2022-02-01T00:00:00.000+0000"
+			mu.Unlock()
+			break
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case names := <-changes:
+		if len(names) != 1 || names[0] != "Account" {
+			t.Errorf("unexpected change %v", names)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a schema change")
+	}
+	watcher.Stop()
+}
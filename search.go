@@ -0,0 +1,85 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SearchScope is an object in the search scope of the logged in user.
+type SearchScope struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Suggestions is the result of SearchSuggestions and SuggestTitleMatches.
+type Suggestions struct {
+	Records        []SObject `json:"autoSuggestResults"`
+	HasMoreResults bool      `json:"hasMoreResults"`
+}
+
+// SearchScopeOrder returns the objects in the search scope of the logged in user in the order global search in
+// salesforce lists them, including the objects the user pinned.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search_scope_order.htm
+func (client *Client) SearchScopeOrder() ([]SearchScope, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL("search/scopeOrder"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []SearchScope
+	err = client.unmarshalJSON(data, &scopes)
+	if err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+// SearchSuggestions returns up to limit records of objectType, salesforce's default of 5 if limit is not positive,
+// whose name matches query, the way the typeahead of global search does. query must have at least 2 characters, or 1
+// for Chinese, Japanese and Korean.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search_suggest_records.htm
+func (client *Client) SearchSuggestions(query, objectType string, limit int) (*Suggestions, error) {
+	params := url.Values{"q": {query}, "sobject": {objectType}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	return client.suggest("search/suggestions", params)
+}
+
+// SuggestTitleMatches returns the Knowledge articles in language, e.g. "en_US", whose titles match query, e.g. for a
+// help center typeahead. publishStatus is "Online", "Draft" or "Archived". query must have at least 3 characters.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search_suggest_title_matches.htm
+func (client *Client) SuggestTitleMatches(query, language, publishStatus string, limit int) (*Suggestions, error) {
+	params := url.Values{"q": {query}, "language": {language}, "publishStatus": {publishStatus}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	return client.suggest("search/suggestTitleMatches", params)
+}
+
+// suggest sends a suggestion request with params to the resource apiPath.
+func (client *Client) suggest(apiPath string, params url.Values) (*Suggestions, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest(http.MethodGet, client.makeURL(apiPath+"?"+params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Suggestions
+	err = client.unmarshalJSON(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range result.Records {
+		result.Records[idx].setClient(client)
+	}
+	return &result, nil
+}
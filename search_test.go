@@ -0,0 +1,51 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_Search(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/search/scopeOrder"):
+			w.Write([]byte(`[
+				{"type": "Account", "url": "/services/data/v54.0/sobjects/Account/describe"},
+				{"type": "Contact", "url": "/services/data/v54.0/sobjects/Contact/describe"}
+			]`))
+		case strings.HasSuffix(r.URL.Path, "/search/suggestions"):
+			if query.Get("q") != "acme co" || query.Get("sobject") != "Account" || query.Get("limit") != "3" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"autoSuggestResults": [
+				{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme Co"}
+			], "hasMoreResults": false}`))
+		case strings.HasSuffix(r.URL.Path, "/search/suggestTitleMatches"):
+			if query.Get("language") != "en_US" || query.Get("publishStatus") != "Online" || query.Has("limit") {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"autoSuggestResults": [
+				{"attributes": {"type": "KnowledgeArticleVersion"}, "Id": "ka0A", "Title": "Reset your password"}
+			], "hasMoreResults": true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	scopes, err := client.SearchScopeOrder()
+	if err != nil || len(scopes) != 2 || scopes[1].Type != "Contact" {
+		t.Errorf("unexpected scopes %v, %v", scopes, err)
+	}
+
+	suggestions, err := client.SearchSuggestions("acme co", "Account", 3)
+	if err != nil || len(suggestions.Records) != 1 || suggestions.Records[0].StringField("Name") != "Acme Co" {
+		t.Errorf("unexpected suggestions %+v, %v", suggestions, err)
+	}
+
+	suggestions, err = client.SuggestTitleMatches("reset pass", "en_US", "Online", 0)
+	if err != nil || !suggestions.HasMoreResults || suggestions.Records[0].StringField("Title") != "Reset your password" {
+		t.Errorf("unexpected suggestions %+v, %v", suggestions, err)
+	}
+}
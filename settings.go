@@ -0,0 +1,261 @@
+package simpleforce
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key prefixes of the owners of hierarchy custom setting records.
+const (
+	userKeyPrefix         = "005"
+	profileKeyPrefix      = "00e"
+	organizationKeyPrefix = "00D"
+)
+
+// ListSetting returns the record of the list custom setting settingType, e.g. "Feature_Flags__c", with the given name,
+// or nil if there is none.
+func (client *Client) ListSetting(settingType, name string) (*SObject, error) {
+	records, err := client.querySettings(settingType, "Name = "+QuoteSOQL(name))
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// HierarchySetting returns the values of the hierarchy custom setting settingType which apply to the user userID, the
+// logged in user if empty, like getInstance in Apex: fields which are not set on the record of the user are taken from
+// the record of its profile, then from the org default. The Id and SetupOwnerId of the most specific record are
+// returned. nil is returned if no record applies.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.apexcode.meta/apexcode/apex_methods_system_custom_settings.htm
+func (client *Client) HierarchySetting(settingType, userID string) (*SObject, error) {
+	if userID == "" {
+		userID = client.user.id
+	}
+	users, err := client.Query("SELECT ProfileId FROM User WHERE Id = " + QuoteSOQL(userID))
+	if err != nil {
+		return nil, err
+	}
+	if len(users.Records) == 0 {
+		return nil, fmt.Errorf("user %s not found", userID)
+	}
+	orgID, err := client.currentOrgID()
+	if err != nil {
+		return nil, err
+	}
+	owners := []string{QuoteSOQL(userID), QuoteSOQL(users.Records[0].StringField("ProfileId")), QuoteSOQL(orgID)}
+	records, err := client.querySettings(settingType, "SetupOwnerId IN ("+strings.Join(owners, ", ")+")")
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	// Merge the records from the least to the most specific.
+	levels := map[string]int{organizationKeyPrefix: 0, profileKeyPrefix: 1, userKeyPrefix: 2}
+	sort.SliceStable(records, func(i, j int) bool {
+		return levels[settingOwnerPrefix(records[i])] < levels[settingOwnerPrefix(records[j])]
+	})
+	merged := &SObject{}
+	for _, record := range records {
+		for key, value := range *record {
+			if key != sobjectClientKey && key != sobjectAttributesKey && value != nil {
+				(*merged)[key] = value
+			}
+		}
+	}
+	merged.setType(settingType)
+	merged.setClient(client)
+	return merged, nil
+}
+
+// settingOwnerPrefix returns the key prefix of the SetupOwnerId of a hierarchy custom setting record.
+func settingOwnerPrefix(record *SObject) string {
+	owner := record.StringField("SetupOwnerId")
+	if len(owner) < 3 {
+		return ""
+	}
+	return owner[:3]
+}
+
+// SaveListSetting sets values on the record of the list custom setting settingType with the given name, creating it if
+// it does not exist. Fields not in values are left unchanged. A *BatchError is returned if the record is rejected.
+func (client *Client) SaveListSetting(settingType, name string, values map[string]interface{}) error {
+	return client.saveSetting(settingType, "Name = "+QuoteSOQL(name), "Name", name, values)
+}
+
+// SaveHierarchySetting sets values on the record of the hierarchy custom setting settingType owned by ownerID, a user
+// or profile ID, or the org default if empty, creating it if it does not exist. Fields not in values are left
+// unchanged. A *BatchError is returned if the record is rejected.
+func (client *Client) SaveHierarchySetting(settingType, ownerID string, values map[string]interface{}) error {
+	if ownerID == "" {
+		orgID, err := client.currentOrgID()
+		if err != nil {
+			return err
+		}
+		ownerID = orgID
+	}
+	return client.saveSetting(settingType, "SetupOwnerId = "+QuoteSOQL(ownerID), "SetupOwnerId", ownerID, values)
+}
+
+// currentOrgID returns the ID of the org, querying it if it is not known from the login.
+func (client *Client) currentOrgID() (string, error) {
+	if client.organizationID != "" {
+		return client.organizationID, nil
+	}
+	result, err := client.Query("SELECT Id FROM Organization")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", fmt.Errorf("organization not found")
+	}
+	return result.Records[0].ID(), nil
+}
+
+// saveSetting updates the custom setting record of settingType matching condition with values, or creates it with
+// keyField set to key if there is none.
+func (client *Client) saveSetting(settingType, condition, keyField, key string, values map[string]interface{}) error {
+	result, err := client.Query(fmt.Sprintf("SELECT Id FROM %s WHERE %s LIMIT 1", settingType, condition))
+	if err != nil {
+		return err
+	}
+
+	record := client.SObject(settingType)
+	for field, value := range values {
+		record.Set(field, value)
+	}
+	if len(result.Records) > 0 {
+		record.setID(result.Records[0].ID())
+		_, err = client.UpdateAll([]*SObject{record})
+		return err
+	}
+	record.Set(keyField, key)
+	_, err = client.CreateAll([]*SObject{record})
+	return err
+}
+
+// querySettings returns all fields of the records of the custom setting or custom metadata type objectType matching
+// condition, or all records if empty.
+func (client *Client) querySettings(objectType, condition string) ([]*SObject, error) {
+	soql, err := client.SelectFields(objectType, FieldsAll)
+	if err != nil {
+		return nil, err
+	}
+	if condition != "" {
+		soql += " WHERE " + condition
+	}
+
+	var records []*SObject
+	err = client.QueryEach(soql, nil, func(record *SObject) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CustomMetadataRecords returns all records of the custom metadata type typeName, e.g. "Feature_Flag__mdt", with all
+// their fields.
+func (client *Client) CustomMetadataRecords(typeName string) ([]*SObject, error) {
+	return client.querySettings(typeName, "")
+}
+
+// CustomMetadataRecord returns the record of the custom metadata type typeName with the given developer name, or nil if
+// there is none.
+func (client *Client) CustomMetadataRecord(typeName, developerName string) (*SObject, error) {
+	records, err := client.querySettings(typeName, "DeveloperName = "+QuoteSOQL(developerName))
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// SaveCustomMetadata creates or replaces the record of the custom metadata type typeName, e.g. "Feature_Flag__mdt",
+// with the given developer name. Custom metadata records cannot be written with the REST API, so the record is saved
+// with UpsertMetadata. The record is replaced, so fields not in values are cleared. label defaults to developerName.
+func (client *Client) SaveCustomMetadata(typeName, developerName, label string, values map[string]interface{}) error {
+	if label == "" {
+		label = developerName
+	}
+	record := &CustomMetadata{
+		FullName: strings.TrimSuffix(typeName, "__mdt") + "." + developerName,
+		Label:    label,
+	}
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		record.Values = append(record.Values, CustomMetadataValue{Field: field, Value: values[field]})
+	}
+
+	_, err := client.UpsertMetadata(record)
+	return err
+}
+
+// CustomMetadata is a record of a custom metadata type, e.g. with FullName "Feature_Flag.New_Checkout" for the record
+// New_Checkout of Feature_Flag__mdt.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_meta.meta/api_meta/meta_custommetadata.htm
+type CustomMetadata struct {
+	FullName  string                `xml:"fullName"`
+	Label     string                `xml:"label"`
+	Protected bool                  `xml:"protected"`
+	Values    []CustomMetadataValue `xml:"values"`
+}
+
+// MetadataType implements Metadata.
+func (*CustomMetadata) MetadataType() string { return "CustomMetadata" }
+
+// CustomMetadataValue is the value of a field of a CustomMetadata record. Value may be a string, bool, number,
+// time.Time or nil to clear the field.
+type CustomMetadataValue struct {
+	Field string
+	Value interface{}
+}
+
+// MarshalXML implements xml.Marshaler, typing the value with xsi:type as the Metadata API requires.
+func (value CustomMetadataValue) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	var xsdType, text string
+	switch v := value.Value.(type) {
+	case nil:
+	case string:
+		xsdType, text = "xsd:string", v
+	case bool:
+		xsdType, text = "xsd:boolean", strconv.FormatBool(v)
+	case int:
+		xsdType, text = "xsd:double", strconv.Itoa(v)
+	case int64:
+		xsdType, text = "xsd:double", strconv.FormatInt(v, 10)
+	case float64:
+		xsdType, text = "xsd:double", strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		xsdType, text = "xsd:dateTime", v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Errorf("unsupported value %T of custom metadata field %s", value.Value, value.Field)
+	}
+
+	valueStart := xml.StartElement{Name: xml.Name{Local: "value"}}
+	if xsdType == "" {
+		valueStart.Attr = []xml.Attr{{Name: xml.Name{Local: "xsi:nil"}, Value: "true"}}
+	} else {
+		valueStart.Attr = []xml.Attr{
+			{Name: xml.Name{Local: "xmlns:xsd"}, Value: "http://www.w3.org/2001/XMLSchema"},
+			{Name: xml.Name{Local: "xsi:type"}, Value: xsdType},
+		}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := encoder.EncodeElement(value.Field, xml.StartElement{Name: xml.Name{Local: "field"}}); err != nil {
+		return err
+	}
+	if err := encoder.EncodeElement(text, valueStart); err != nil {
+		return err
+	}
+	return encoder.EncodeToken(start.End())
+}
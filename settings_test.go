@@ -0,0 +1,128 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newSettingsClient returns a client whose stub server describes Flags__c and answers queries of it with the records of
+// the given owners, e.g. {"00D000000000001AAA": `"Enabled__c": true`}.
+func newSettingsClient(t *testing.T, records map[string]string, saved *[]map[string]interface{}) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Flags__c/describe"):
+			w.Write([]byte(`{"name": "Flags__c", "fields": [{"name": "Id"}, {"name": "Name"}, {"name": "SetupOwnerId"},
+				{"name": "Enabled__c"}, {"name": "Limit__c"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/composite/sobjects"):
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			*saved = append(*saved, body.Records...)
+			w.Write([]byte(`[{"id": "a00NEW", "success": true, "errors": []}]`))
+		default:
+			q := r.URL.Query().Get("q")
+			switch {
+			case q == "SELECT ProfileId FROM User WHERE Id = '005USER'":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"ProfileId": "00ePROFILE"}]}`))
+				return
+			case q == "SELECT Id FROM Organization":
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "00DORG"}]}`))
+				return
+			}
+			var found []string
+			for owner, fields := range records {
+				if strings.Contains(q, "'"+owner+"'") {
+					found = append(found, fmt.Sprintf(`{"attributes": {"type": "Flags__c"}, "Id": "a00%s",
+						"SetupOwnerId": %q, %s}`, owner, owner, fields))
+				}
+			}
+			fmt.Fprintf(w, `{"totalSize": %d, "done": true, "records": [%s]}`, len(found), strings.Join(found, ","))
+		}
+	})
+	return client
+}
+
+func TestClient_HierarchySetting(t *testing.T) {
+	client := newSettingsClient(t, map[string]string{
+		"005USER":    `"Enabled__c": null, "Limit__c": 5`,
+		"00ePROFILE": `"Enabled__c": false, "Limit__c": 10`,
+		"00DORG":     `"Enabled__c": true, "Limit__c": 20`,
+	}, nil)
+
+	setting, err := client.HierarchySetting("Flags__c", "005USER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setting.Type() != "Flags__c" || setting.ID() != "a00005USER" || setting.InterfaceField("Enabled__c") != false ||
+		setting.InterfaceField("Limit__c") != 5.0 {
+		t.Errorf("unexpected setting %v", *setting)
+	}
+
+	client = newSettingsClient(t, map[string]string{"00DORG": `"Enabled__c": true, "Limit__c": 20`}, nil)
+	setting, err = client.HierarchySetting("Flags__c", "005USER")
+	if err != nil || setting == nil || setting.InterfaceField("Enabled__c") != true {
+		t.Errorf("expected the org default, got %v, %v", setting, err)
+	}
+
+	client = newSettingsClient(t, nil, nil)
+	if setting, err = client.HierarchySetting("Flags__c", "005USER"); setting != nil || err != nil {
+		t.Errorf("expected no setting, got %v, %v", setting, err)
+	}
+}
+
+func TestClient_SaveHierarchySetting(t *testing.T) {
+	var saved []map[string]interface{}
+	client := newSettingsClient(t, map[string]string{"00ePROFILE": `"Enabled__c": false`}, &saved)
+
+	if err := client.SaveHierarchySetting("Flags__c", "00ePROFILE", map[string]interface{}{"Enabled__c": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SaveHierarchySetting("Flags__c", "", map[string]interface{}{"Limit__c": 20}); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 || saved[0]["Id"] != "a0000ePROFILE" || saved[0]["Enabled__c"] != true ||
+		saved[1]["Id"] != nil || saved[1]["SetupOwnerId"] != "00DORG" || saved[1]["Limit__c"] != 20.0 {
+		t.Errorf("unexpected records %v", saved)
+	}
+}
+
+func TestClient_SaveCustomMetadata(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `<upsertMetadata xmlns="http://soap.sforce.com/2006/04/metadata"><metadata xsi:type="CustomMetadata">` +
+			"<fullName>Feature_Flag.New_Checkout</fullName><label>New Checkout</label><protected>false</protected>" +
+			`<values><field>Enabled__c</field><value xmlns:xsd="http://www.w3.org/2001/XMLSchema" xsi:type="xsd:boolean">true</value></values>` +
+			`<values><field>Note__c</field><value xsi:nil="true"></value></values>` +
+			`<values><field>Rollout__c</field><value xmlns:xsd="http://www.w3.org/2001/XMLSchema" xsi:type="xsd:double">0.25</value></values>` +
+			"</metadata></upsertMetadata>"
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("%s missing in %s", expected, body)
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata">
+    <soapenv:Body>
+        <upsertMetadataResponse>
+            <result><created>true</created><fullName>Feature_Flag.New_Checkout</fullName><success>true</success></result>
+        </upsertMetadataResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`))
+	})
+
+	err := client.SaveCustomMetadata("Feature_Flag__mdt", "New_Checkout", "New Checkout", map[string]interface{}{
+		"Enabled__c": true,
+		"Rollout__c": 0.25,
+		"Note__c":    nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.SaveCustomMetadata("Feature_Flag__mdt", "Bad", "", map[string]interface{}{"List__c": []string{"a"}})
+	if err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
@@ -0,0 +1,57 @@
+package simpleforce
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_SetupAuditTrail(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/v54.0/query":
+			expected := "SELECT " + setupAuditFields + " FROM SetupAuditTrail WHERE CreatedDate >= 2022-05-01T00:00:00Z" +
+				" AND CreatedDate < 2022-05-02T00:00:00Z ORDER BY CreatedDate, Id"
+			if q := r.URL.Query().Get("q"); q != expected {
+				t.Errorf("unexpected query %s", q)
+			}
+			w.Write([]byte(`{"done": false, "nextRecordsUrl": "/services/data/v54.0/query/01g-1", "records": [
+				{"attributes": {"type": "SetupAuditTrail"}, "Id": "0YmA", "Action": "changedUserEmail",
+					"Section": "Manage Users", "Display": "Changed email for user jdoe", "DelegateUser": null,
+					"CreatedById": "005A", "CreatedBy": {"attributes": {"type": "User"}, "Username": "admin@example.com"},
+					"CreatedDate": "2022-05-01T10:00:00.000+0000"}]}`))
+		case "/services/data/v54.0/query/01g-1":
+			w.Write([]byte(`{"done": true, "records": [
+				{"attributes": {"type": "SetupAuditTrail"}, "Id": "0YmB", "Action": "PermSetAssign",
+					"Section": "Manage Users", "DelegateUser": "support@example.com", "CreatedById": "005B",
+					"CreatedBy": null, "CreatedDate": "2022-05-01T11:00:00.000+0000"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	since := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	var entries []SetupAuditEntry
+	err := client.SetupAuditTrail(since, since.AddDate(0, 0, 1), func(entry SetupAuditEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].CreatedByUsername != "admin@example.com" || entries[0].Section != "Manage Users" ||
+		entries[1].DelegateUser != "support@example.com" || entries[1].Action != "PermSetAssign" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = client.SetupAuditTrail(since, since.AddDate(0, 0, 1), func(entry SetupAuditEntry) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("expected to stop after the first entry, got %v after %d", err, count)
+	}
+}
@@ -0,0 +1,283 @@
+// Package sfsync incrementally replicates salesforce records into a user-provided Sink.
+//
+// For every configured object, an Engine keeps a high-water mark on SystemModstamp to pull created and updated
+// records with SOQL queries, and uses the get deleted resource to detect deletions. Checkpoints are persisted through a
+// CheckpointStore after every page of records, so an interrupted sync resumes where it stopped. Records at the
+// high-water mark are pulled again on resume, so sinks must handle repeated upserts of the same record.
+package sfsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scottraio/simpleforce"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+const (
+	modstampField  = "SystemModstamp"
+	soqlTimeLayout = "2006-01-02T15:04:05Z"
+
+	// minDeletedRange is the minimum range covered by a get deleted call.
+	minDeletedRange = time.Minute
+	// deletedRetention is how far back the get deleted resource reports deletions.
+	deletedRetention = 30 * 24 * time.Hour
+)
+
+// ResyncRequiredError is returned by Sync if the deletions of Object since its checkpoint can no longer be detected,
+// because the get deleted resource only reports the last 30 days, e.g. after a long outage. Records deleted in the
+// meantime may still be in the sink. Resync makes the next Sync pull all records of the object again; the sink should
+// then drop the records which were not pulled.
+type ResyncRequiredError struct {
+	Object         string
+	DeletedThrough time.Time
+}
+
+// Error implements the error interface.
+func (err *ResyncRequiredError) Error() string {
+	return fmt.Sprintf("deletions of %s since %s are no longer available, a full resync is required",
+		err.Object, err.DeletedThrough.Format(time.RFC3339))
+}
+
+// Object configures the replication of a salesforce object. Id and SystemModstamp are always selected in addition to
+// Fields.
+type Object struct {
+	Name   string
+	Fields []string
+}
+
+// Sink receives the changes pulled by an Engine.
+type Sink interface {
+	Upsert(object string, record *simpleforce.SObject) error
+	Delete(object string, id string) error
+}
+
+// Checkpoint is the replication state of an object.
+type Checkpoint struct {
+	Modstamp       time.Time `json:"modstamp"`
+	DeletedThrough time.Time `json:"deletedThrough"`
+}
+
+// CheckpointStore persists Checkpoints. Load returns the zero Checkpoint for objects never synced.
+type CheckpointStore interface {
+	Load(object string) (Checkpoint, error)
+	Save(object string, checkpoint Checkpoint) error
+}
+
+// Engine replicates Objects from Client into Sink.
+type Engine struct {
+	Client  *simpleforce.Client
+	Objects []Object
+	Sink    Sink
+	Store   CheckpointStore
+}
+
+// Sync runs a single incremental sync of all objects.
+func (engine *Engine) Sync() error {
+	for _, object := range engine.Objects {
+		err := engine.syncObject(object)
+		if err != nil {
+			return fmt.Errorf("sync %s: %w", object.Name, err)
+		}
+	}
+	return nil
+}
+
+// Resync resets the checkpoint of object, so the next Sync pulls all its records again and tracks deletions from then
+// on, e.g. after a ResyncRequiredError.
+func (engine *Engine) Resync(object string) error {
+	return engine.Store.Save(object, Checkpoint{})
+}
+
+// Run calls Sync every interval until stop is closed or Sync fails.
+func (engine *Engine) Run(interval time.Duration, stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := engine.Sync()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (engine *Engine) syncObject(object Object) error {
+	checkpoint, err := engine.Store.Load(object.Name)
+	if err != nil {
+		return err
+	}
+	// Deletions are tracked from the start of the first sync, as any record deleted before is never pulled.
+	started := time.Now().UTC()
+
+	q := changesQuery(object, checkpoint.Modstamp)
+	for {
+		result, err := engine.Client.Query(q)
+		if err != nil {
+			return err
+		}
+		for idx := range result.Records {
+			record := &result.Records[idx]
+			err = engine.Sink.Upsert(object.Name, record)
+			if err != nil {
+				return err
+			}
+			modstamp, err := simpleforce.ParseDateTime(record.StringField(modstampField))
+			if err == nil && modstamp.After(checkpoint.Modstamp) {
+				checkpoint.Modstamp = modstamp
+			}
+		}
+		err = engine.Store.Save(object.Name, checkpoint)
+		if err != nil {
+			return err
+		}
+
+		if result.Done || result.NextRecordsURL == "" {
+			break
+		}
+		q = result.NextRecordsURL
+	}
+
+	if checkpoint.DeletedThrough.IsZero() {
+		checkpoint.DeletedThrough = started
+	} else if started.Sub(checkpoint.DeletedThrough) >= deletedRetention {
+		return &ResyncRequiredError{Object: object.Name, DeletedThrough: checkpoint.DeletedThrough}
+	} else if started.Sub(checkpoint.DeletedThrough) >= minDeletedRange {
+		deleted, err := engine.Client.GetDeleted(object.Name, checkpoint.DeletedThrough, started)
+		var sfErr simpleforce.SalesforceError
+		if errors.As(err, &sfErr) && sfErr.ErrorCode == errcode.InvalidReplicationDate {
+			return &ResyncRequiredError{Object: object.Name, DeletedThrough: checkpoint.DeletedThrough}
+		}
+		if err != nil {
+			return err
+		}
+		for _, record := range deleted.DeletedRecords {
+			err = engine.Sink.Delete(object.Name, record.ID)
+			if err != nil {
+				return err
+			}
+		}
+		checkpoint.DeletedThrough = started
+		if !deleted.LatestDateCovered.IsZero() {
+			checkpoint.DeletedThrough = deleted.LatestDateCovered
+		}
+	}
+	return engine.Store.Save(object.Name, checkpoint)
+}
+
+// changesQuery builds the SOQL query pulling the records of object modified at or after since.
+func changesQuery(object Object, since time.Time) string {
+	fields := []string{"Id", modstampField}
+	for _, field := range object.Fields {
+		if !strings.EqualFold(field, "Id") && !strings.EqualFold(field, modstampField) {
+			fields = append(fields, field)
+		}
+	}
+
+	q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), object.Name)
+	if !since.IsZero() {
+		// SOQL datetime literals have a precision of seconds; truncating re-pulls records at the high-water mark.
+		q += fmt.Sprintf(" WHERE %s >= %s", modstampField, since.UTC().Truncate(time.Second).Format(soqlTimeLayout))
+	}
+	return q + fmt.Sprintf(" ORDER BY %s ASC", modstampField)
+}
+
+// MemoryCheckpointStore keeps Checkpoints in memory. The zero value is ready to use.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// Load implements CheckpointStore.
+func (store *MemoryCheckpointStore) Load(object string) (Checkpoint, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.checkpoints[object], nil
+}
+
+// Save implements CheckpointStore.
+func (store *MemoryCheckpointStore) Save(object string, checkpoint Checkpoint) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.checkpoints == nil {
+		store.checkpoints = make(map[string]Checkpoint)
+	}
+	store.checkpoints[object] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore persists the Checkpoints of all objects in a JSON file at Path, which is replaced atomically on
+// every Save.
+type FileCheckpointStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Load implements CheckpointStore.
+func (store *FileCheckpointStore) Load(object string) (Checkpoint, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	checkpoints, err := store.read()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	return checkpoints[object], nil
+}
+
+// Save implements CheckpointStore.
+func (store *FileCheckpointStore) Save(object string, checkpoint Checkpoint) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	checkpoints, err := store.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[object] = checkpoint
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(store.Path), filepath.Base(store.Path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), store.Path)
+}
+
+func (store *FileCheckpointStore) read() (map[string]Checkpoint, error) {
+	checkpoints := make(map[string]Checkpoint)
+	data, err := ioutil.ReadFile(store.Path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &checkpoints)
+	if err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,132 @@
+package sfsync
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottraio/simpleforce"
+)
+
+type recordingSink struct {
+	upserts []string
+	deletes []string
+}
+
+func (sink *recordingSink) Upsert(object string, record *simpleforce.SObject) error {
+	sink.upserts = append(sink.upserts, object+":"+record.ID())
+	return nil
+}
+
+func (sink *recordingSink) Delete(object string, id string) error {
+	sink.deletes = append(sink.deletes, object+":"+id)
+	return nil
+}
+
+func TestEngine_Sync(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			queries = append(queries, r.URL.Query().Get("q"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalSize": 2,
+				"done":      true,
+				"records": []map[string]interface{}{
+					{"Id": "001A", "SystemModstamp": "2022-05-01T10:00:00.000+0000", "Name": "Acme"},
+					{"Id": "001B", "SystemModstamp": "2022-05-01T11:30:00.000+0000", "Name": "Globex"},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Account/deleted/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"deletedRecords":    []map[string]interface{}{{"id": "001C", "deletedDate": "2022-05-01T12:00:00.000+0000"}},
+				"latestDateCovered": "2022-05-01T12:00:00.000+0000",
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	sink := &recordingSink{}
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoints.json")}
+	engine := &Engine{
+		Client:  client,
+		Objects: []Object{{Name: "Account", Fields: []string{"Id", "Name"}}},
+		Sink:    sink,
+		Store:   store,
+	}
+
+	// Initial sync: full pull, deletions tracked from now on.
+	if err := engine.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if queries[0] != "SELECT Id, SystemModstamp, Name FROM Account ORDER BY SystemModstamp ASC" {
+		t.Errorf("unexpected query %s", queries[0])
+	}
+	checkpoint, _ := store.Load("Account")
+	if !checkpoint.Modstamp.Equal(time.Date(2022, 5, 1, 11, 30, 0, 0, time.UTC)) || checkpoint.DeletedThrough.IsZero() {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+
+	// Incremental sync from the high-water mark, with deletions.
+	checkpoint.DeletedThrough = checkpoint.DeletedThrough.Add(-time.Hour)
+	store.Save("Account", checkpoint)
+	if err := engine.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if queries[1] != "SELECT Id, SystemModstamp, Name FROM Account WHERE SystemModstamp >= 2022-05-01T11:30:00Z ORDER BY SystemModstamp ASC" {
+		t.Errorf("unexpected query %s", queries[1])
+	}
+	if len(sink.upserts) != 4 || len(sink.deletes) != 1 || sink.deletes[0] != "Account:001C" {
+		t.Errorf("unexpected changes %v %v", sink.upserts, sink.deletes)
+	}
+	checkpoint, _ = store.Load("Account")
+	if !checkpoint.DeletedThrough.Equal(time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+}
+
+func TestEngine_SyncResyncRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"totalSize": 0, "done": true, "records": []interface{}{}})
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Account/deleted/"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`[{"errorCode": "INVALID_REPLICATION_DATE", "message": "startDate before org replication enabled date"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	store := &MemoryCheckpointStore{}
+	engine := &Engine{Client: client, Objects: []Object{{Name: "Account"}}, Sink: &recordingSink{}, Store: store}
+
+	for _, age := range []time.Duration{31 * 24 * time.Hour, time.Hour} {
+		deletedThrough := time.Now().UTC().Add(-age)
+		store.Save("Account", Checkpoint{DeletedThrough: deletedThrough})
+		var resyncErr *ResyncRequiredError
+		if err := engine.Sync(); !errors.As(err, &resyncErr) || resyncErr.Object != "Account" ||
+			!resyncErr.DeletedThrough.Equal(deletedThrough) {
+			t.Errorf("expected a ResyncRequiredError, got %v", err)
+		}
+	}
+
+	if err := engine.Resync("Account"); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint, _ := store.Load("Account"); !checkpoint.DeletedThrough.IsZero() || !checkpoint.Modstamp.IsZero() {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+}
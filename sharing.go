@@ -0,0 +1,146 @@
+package simpleforce
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Access levels of share records.
+const (
+	ShareRead = "Read"
+	ShareEdit = "Edit"
+)
+
+// shareRowCauseManual is the row cause of records shared with ShareRecord.
+const shareRowCauseManual = "Manual"
+
+// maxRecordAccessIDs is the number of records checked by a single UserRecordAccess query.
+const maxRecordAccessIDs = 200
+
+// RecordAccess is the access of a user to a record.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.object_reference.meta/object_reference/sforce_api_objects_userrecordaccess.htm
+type RecordAccess struct {
+	RecordID          string `json:"RecordId"`
+	HasReadAccess     bool   `json:"HasReadAccess"`
+	HasEditAccess     bool   `json:"HasEditAccess"`
+	HasDeleteAccess   bool   `json:"HasDeleteAccess"`
+	HasTransferAccess bool   `json:"HasTransferAccess"`
+	HasAllAccess      bool   `json:"HasAllAccess"`
+	MaxAccessLevel    string `json:"MaxAccessLevel"` // e.g. "None", "Read", "Edit" or "All"
+}
+
+// RecordShare is a share record granting a user or group access to a record.
+type RecordShare struct {
+	ID            string
+	UserOrGroupID string
+	AccessLevel   string
+	RowCause      string // e.g. "Owner", "Manual" or "Rule"
+}
+
+// RecordAccess returns the access of the user userID, the logged in user if empty, to the records recordIDs, keyed by
+// the record IDs as returned by salesforce, with 18 characters. Checking access before an operation avoids partial
+// failures with INSUFFICIENT_ACCESS_OR_READONLY errors.
+func (client *Client) RecordAccess(userID string, recordIDs []string) (map[string]RecordAccess, error) {
+	if userID == "" {
+		userID = client.user.id
+	}
+
+	access := make(map[string]RecordAccess, len(recordIDs))
+	for start := 0; start < len(recordIDs); start += maxRecordAccessIDs {
+		end := start + maxRecordAccessIDs
+		if end > len(recordIDs) {
+			end = len(recordIDs)
+		}
+		quoted := make([]string, end-start)
+		for idx, id := range recordIDs[start:end] {
+			quoted[idx] = QuoteSOQL(id)
+		}
+		soql := "SELECT RecordId, HasReadAccess, HasEditAccess, HasDeleteAccess, HasTransferAccess, HasAllAccess, " +
+			"MaxAccessLevel FROM UserRecordAccess WHERE UserId = " + QuoteSOQL(userID) +
+			" AND RecordId IN (" + strings.Join(quoted, ", ") + ")"
+		err := client.queryPages("query", soql, func(records []byte) error {
+			var page []RecordAccess
+			err := client.unmarshalJSON(records, &page)
+			for _, record := range page {
+				access[record.RecordID] = record
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return access, nil
+}
+
+// shareObject returns the share object of objectType, e.g. "AccountShare" or "Invoice__Share", and the names of its
+// fields holding the shared record and the access level.
+func shareObject(objectType string) (shareType, parentField, accessField string) {
+	if strings.HasSuffix(objectType, "__c") {
+		return strings.TrimSuffix(objectType, "__c") + "__Share", "ParentId", "AccessLevel"
+	}
+	return objectType + "Share", objectType + "Id", objectType + "AccessLevel"
+}
+
+// RecordShares lists the share records of the record recordID of objectType, e.g. "Account" or "Invoice__c".
+func (client *Client) RecordShares(objectType, recordID string) ([]RecordShare, error) {
+	shareType, parentField, accessField := shareObject(objectType)
+	return client.queryShares(shareType, accessField, parentField+" = "+QuoteSOQL(recordID))
+}
+
+// queryShares returns the share records of shareType matching condition.
+func (client *Client) queryShares(shareType, accessField, condition string) ([]RecordShare, error) {
+	soql := fmt.Sprintf("SELECT Id, UserOrGroupId, %s, RowCause FROM %s WHERE %s", accessField, shareType, condition)
+	var shares []RecordShare
+	err := client.QueryEach(soql, nil, func(record *SObject) error {
+		shares = append(shares, RecordShare{
+			ID:            record.ID(),
+			UserOrGroupID: record.StringField("UserOrGroupId"),
+			AccessLevel:   record.StringField(accessField),
+			RowCause:      record.StringField("RowCause"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// ShareRecord manually shares the record recordID of objectType with the user or group userOrGroupID, granting
+// accessLevel, ShareRead or ShareEdit, and returns the ID of the share record. Sharing an account grants no access to
+// its opportunities and cases. The object must have a private or read-only sharing model, and the logged in user must
+// be allowed to share the record.
+func (client *Client) ShareRecord(objectType, recordID, userOrGroupID, accessLevel string) (string, error) {
+	shareType, parentField, accessField := shareObject(objectType)
+	share := client.SObject(shareType).
+		Set(parentField, recordID).
+		Set("UserOrGroupId", userOrGroupID).
+		Set(accessField, accessLevel).
+		Set("RowCause", shareRowCauseManual)
+	if objectType == "Account" {
+		share.Set("OpportunityAccessLevel", "None").Set("CaseAccessLevel", "None")
+	}
+	_, err := client.CreateAll([]*SObject{share})
+	if err != nil {
+		return "", err
+	}
+	return share.ID(), nil
+}
+
+// UnshareRecord deletes the manual shares of the record recordID of objectType with the user or group userOrGroupID.
+// Access granted by ownership, sharing rules or the role hierarchy is not affected.
+func (client *Client) UnshareRecord(objectType, recordID, userOrGroupID string) error {
+	shareType, parentField, accessField := shareObject(objectType)
+	shares, err := client.queryShares(shareType, accessField, parentField+" = "+QuoteSOQL(recordID)+
+		" AND UserOrGroupId = "+QuoteSOQL(userOrGroupID)+" AND RowCause = "+QuoteSOQL(shareRowCauseManual))
+	if err != nil || len(shares) == 0 {
+		return err
+	}
+	ids := make([]string, len(shares))
+	for idx, share := range shares {
+		ids[idx] = share.ID
+	}
+	_, err = client.DeleteAll(ids)
+	return err
+}
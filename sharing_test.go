@@ -0,0 +1,95 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_RecordAccess(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "SELECT RecordId, HasReadAccess, HasEditAccess, HasDeleteAccess, HasTransferAccess, HasAllAccess, " +
+			"MaxAccessLevel FROM UserRecordAccess WHERE UserId = '005ME' AND RecordId IN ('001A', '001B')"
+		if q := r.URL.Query().Get("q"); q != expected {
+			t.Errorf("unexpected query %s", q)
+		}
+		w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+			{"attributes": {"type": "UserRecordAccess"}, "RecordId": "001A", "HasReadAccess": true,
+				"HasEditAccess": true, "MaxAccessLevel": "Edit"},
+			{"attributes": {"type": "UserRecordAccess"}, "RecordId": "001B", "HasReadAccess": true,
+				"HasEditAccess": false, "MaxAccessLevel": "Read"}]}`))
+	})
+	client.user.id = "005ME"
+
+	access, err := client.RecordAccess("", []string{"001A", "001B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !access["001A"].HasEditAccess || access["001B"].HasEditAccess || access["001B"].MaxAccessLevel != "Read" {
+		t.Errorf("unexpected access %+v", access)
+	}
+}
+
+func TestShareObject(t *testing.T) {
+	for objectType, expected := range map[string]string{
+		"Account":        "AccountShare AccountId AccountAccessLevel",
+		"Opportunity":    "OpportunityShare OpportunityId OpportunityAccessLevel",
+		"ns__Invoice__c": "ns__Invoice__Share ParentId AccessLevel",
+	} {
+		shareType, parentField, accessField := shareObject(objectType)
+		if got := shareType + " " + parentField + " " + accessField; got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, objectType, got)
+		}
+	}
+}
+
+func TestClient_ShareRecord(t *testing.T) {
+	var sent []map[string]interface{}
+	var deleted string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Query().Get("ids")
+			w.Write([]byte(`[{"id": "00rA", "success": true, "errors": []}]`))
+		case r.Method == http.MethodPost:
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body.Records...)
+			w.Write([]byte(`[{"id": "00rNEW", "success": true, "errors": []}]`))
+		default:
+			q := r.URL.Query().Get("q")
+			if !strings.HasPrefix(q, "SELECT Id, UserOrGroupId, AccountAccessLevel, RowCause FROM AccountShare WHERE AccountId = '001A'") {
+				t.Errorf("unexpected query %s", q)
+			}
+			if strings.HasSuffix(q, " AND UserOrGroupId = '005OTHER' AND RowCause = 'Manual'") {
+				w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"Id": "00rA", "UserOrGroupId": "005OTHER",
+					"AccountAccessLevel": "Edit", "RowCause": "Manual"}]}`))
+				return
+			}
+			w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+				{"Id": "00rO", "UserOrGroupId": "005ME", "AccountAccessLevel": "All", "RowCause": "Owner"},
+				{"Id": "00rA", "UserOrGroupId": "005OTHER", "AccountAccessLevel": "Edit", "RowCause": "Manual"}]}`))
+		}
+	})
+
+	id, err := client.ShareRecord("Account", "001A", "005OTHER", ShareEdit)
+	if err != nil || id != "00rNEW" {
+		t.Fatalf("unexpected result %s, %v", id, err)
+	}
+	if share := sent[0]; share["AccountId"] != "001A" || share["UserOrGroupId"] != "005OTHER" ||
+		share["AccountAccessLevel"] != ShareEdit || share["OpportunityAccessLevel"] != "None" || share["RowCause"] != "Manual" {
+		t.Errorf("unexpected share %v", share)
+	}
+
+	shares, err := client.RecordShares("Account", "001A")
+	if err != nil || len(shares) != 2 || shares[0].RowCause != "Owner" || shares[1].AccessLevel != ShareEdit {
+		t.Errorf("unexpected shares %+v, %v", shares, err)
+	}
+
+	if err = client.UnshareRecord("Account", "001A", "005OTHER"); err != nil || deleted != "00rA" {
+		t.Errorf("expected the manual share to be deleted, got %s, %v", deleted, err)
+	}
+}
@@ -0,0 +1,132 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrClientClosed is returned for requests made with a client after Close was called.
+var ErrClientClosed = errors.New("client closed")
+
+// lifecycle tracks the requests in flight and the background goroutines of a client for Close.
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight int
+	drained  chan struct{} // closed once no requests are in flight after Close
+	nextTask int
+	tasks    map[int]func()
+}
+
+// Close shuts the client down, e.g. when a service embedding it terminates: it stops the keep-alive pings, schema
+// watchers and streaming subscribers started with the client, makes further requests fail with ErrClientClosed, waits
+// for the requests in flight to complete, i.e. for their response bodies to be closed, and closes the idle connections
+// of the HTTP client. If ctx is done before, Close returns its error without aborting the remaining requests. Close does
+// not wait for calls of Subscriber.Run to return. Calling Close more than once waits for the requests again.
+func (client *Client) Close(ctx context.Context) error {
+	state := &client.lifecycle
+	state.mu.Lock()
+	state.closed = true
+	tasks := state.tasks
+	state.tasks = nil
+	if state.drained == nil {
+		state.drained = make(chan struct{})
+		if state.inFlight == 0 {
+			close(state.drained)
+		}
+	}
+	drained := state.drained
+	state.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		var wg sync.WaitGroup
+		for _, stop := range tasks {
+			wg.Add(1)
+			go func(stop func()) {
+				defer wg.Done()
+				stop()
+			}(stop)
+		}
+		wg.Wait()
+	}()
+
+	var err error
+	for _, done := range []chan struct{}{stopped, drained} {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = errors.Wrap(ctx.Err(), "waiting for requests in flight")
+		}
+		if err != nil {
+			break
+		}
+	}
+	if client.httpClient != nil {
+		client.httpClient.CloseIdleConnections()
+	}
+	return err
+}
+
+// startTask registers stop to be called by Close to stop a background goroutine, and returns an ID for endTask. If the
+// client is already closed, stop is called at once.
+func (client *Client) startTask(stop func()) int {
+	state := &client.lifecycle
+	state.mu.Lock()
+	if state.closed {
+		state.mu.Unlock()
+		stop()
+		return 0
+	}
+	if state.tasks == nil {
+		state.tasks = make(map[int]func())
+	}
+	state.nextTask++
+	id := state.nextTask
+	state.tasks[id] = stop
+	state.mu.Unlock()
+	return id
+}
+
+// endTask unregisters the background goroutine id once it is stopped otherwise.
+func (client *Client) endTask(id int) {
+	state := &client.lifecycle
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	delete(state.tasks, id)
+}
+
+// tracked sends req with send unless the client is closed, counting it in flight until the response body is closed.
+func (client *Client) tracked(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	state := &client.lifecycle
+	state.mu.Lock()
+	if state.closed {
+		state.mu.Unlock()
+		return nil, ErrClientClosed
+	}
+	state.inFlight++
+	state.mu.Unlock()
+
+	resp, err := send(req)
+	if err != nil || resp == nil {
+		client.untrack()
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: client.untrack}
+	return resp, nil
+}
+
+// untrack counts a request out of flight.
+func (client *Client) untrack() {
+	state := &client.lifecycle
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.inFlight--
+	if state.inFlight == 0 && state.drained != nil {
+		close(state.drained)
+	}
+}
@@ -0,0 +1,59 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_Close(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/data/v54.0/query" {
+			close(received)
+			<-release
+		}
+		w.Write([]byte(`{"done": true, "records": []}`))
+	})
+	ka := client.StartKeepAlive(time.Hour)
+	sub, err := client.NewSubscriber()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queried := make(chan error)
+	go func() {
+		_, err := client.Query("SELECT Id FROM Account")
+		queried <- err
+	}()
+	<-received
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err = client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Close to time out with a request in flight, got %v", err)
+	}
+	select {
+	case <-ka.done:
+	default:
+		t.Error("expected the keep-alive to be stopped")
+	}
+	if sub.ctx.Err() == nil {
+		t.Error("expected the subscriber to be stopped")
+	}
+	if _, err = client.Query("SELECT Id FROM Contact"); err == nil || !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+
+	close(release)
+	if err = <-queried; err != nil {
+		t.Errorf("expected the request in flight to complete, got %v", err)
+	}
+	if err = client.Close(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
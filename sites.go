@@ -0,0 +1,51 @@
+package simpleforce
+
+import (
+	"net/url"
+	"strings"
+)
+
+// SetSitePrefix sets the path prefix of the Experience Cloud site the client is authenticated against, e.g.
+// "/partners", so that API requests are sent to "<instance>/partners/services/data/...". LoginPassword sets the prefix
+// from the server URL returned by the login; use SetSitePrefix after SetSidLoc. An empty prefix addresses the instance
+// directly.
+func (client *Client) SetSitePrefix(prefix string) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	client.sitePrefix = prefix
+}
+
+// SitePrefix returns the path prefix of the Experience Cloud site of the client, or an empty string if there is none.
+func (client *Client) SitePrefix() string {
+	return client.sitePrefix
+}
+
+// servicesURL returns the URL the services paths, e.g. "/services/data", are relative to: the instance URL followed by
+// the site prefix, if any.
+func (client *Client) servicesURL() string {
+	return strings.TrimRight(client.instanceURL, "/") + client.sitePrefix
+}
+
+// resolveURL returns the absolute URL of path, e.g. a nextRecordsUrl, which may or may not include the site prefix.
+func (client *Client) resolveURL(path string) string {
+	if client.sitePrefix != "" && !strings.HasPrefix(path, client.sitePrefix+"/") {
+		return client.servicesURL() + path
+	}
+	return strings.TrimRight(client.instanceURL, "/") + path
+}
+
+// sitePrefixFromServerURL returns the site prefix of a login server URL, e.g. "/partners" of
+// "https://acme.my.site.com/partners/services/Soap/u/54.0/00D000000000001", or an empty string if there is none.
+func sitePrefixFromServerURL(serverURL string) string {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+	idx := strings.Index(parsed.Path, "/services/")
+	if idx <= 0 {
+		return ""
+	}
+	return strings.TrimRight(parsed.Path[:idx], "/")
+}
@@ -0,0 +1,53 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_SitePrefix(t *testing.T) {
+	var paths []string
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			w.Write([]byte(`{"totalSize": 2, "done": false, "nextRecordsUrl": "/partners/services/data/v54.0/query/01g-1",
+				"records": [{"Id": "001A"}]}`))
+			return
+		}
+		w.Write([]byte(`{"totalSize": 2, "done": true, "records": [{"Id": "001B"}]}`))
+	})
+	client.SetSitePrefix("partners/")
+	if client.SitePrefix() != "/partners" {
+		t.Errorf("unexpected prefix %s", client.SitePrefix())
+	}
+
+	result, err := client.Query("SELECT Id FROM Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Query(result.NextRecordsURL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.QueryMore("/services/data/v54.0/query/01g-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "/partners/services/data/v54.0/query,/partners/services/data/v54.0/query/01g-1," +
+		"/partners/services/data/v54.0/query/01g-2"
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("unexpected paths %v", paths)
+	}
+}
+
+func TestSitePrefixFromServerURL(t *testing.T) {
+	for serverURL, expected := range map[string]string{
+		"https://acme.my.site.com/partners/services/Soap/u/54.0/00D000000000001": "/partners",
+		"https://example.my.salesforce.com/services/Soap/u/54.0/00D000000000001": "",
+		"::": "",
+	} {
+		if prefix := sitePrefixFromServerURL(serverURL); prefix != expected {
+			t.Errorf("unexpected prefix %q of %s", prefix, serverURL)
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -15,6 +17,9 @@ const (
 	sobjectAttributesKey          = "attributes" // points to the attributes structure which should be common to all SObjects.
 	sobjectIDKey                  = "Id"
 	sobjectExternalIDFieldNameKey = "ExternalIDField"
+
+	// salesforceTimeLayout is the layout of datetime values in REST API responses, e.g. "2022-05-01T10:00:00.000+0000".
+	salesforceTimeLayout = "2006-01-02T15:04:05.000-0700"
 )
 
 var (
@@ -39,6 +44,24 @@ var (
 	}
 )
 
+var (
+	// ErrNilSObject is returned by the error-returning methods of SObject when called on a nil SObject, e.g. the result
+	// of a failed Get.
+	ErrNilSObject = errors.New("nil sobject")
+
+	// ErrMissingClient is returned for online operations on an SObject not associated with a client, see Client.Attach.
+	ErrMissingClient = errors.New("sobject has no client")
+
+	// ErrMissingType is returned for online operations on an SObject without a type.
+	ErrMissingType = errors.New("sobject has no type")
+
+	// ErrMissingID is returned for operations on an SObject which require an ID it does not have.
+	ErrMissingID = errors.New("sobject has no id")
+
+	// ErrFieldNotFound is returned by the error-returning field accessors of SObject for fields that are not present.
+	ErrFieldNotFound = errors.New("field not found")
+)
+
 // SObject describes an instance of SObject.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/resources_sobject_basic_info.htm
 type SObject map[string]interface{}
@@ -56,22 +79,27 @@ type SObjectAttributes struct {
 // Describe queries the metadata of an SObject using the "describe" API.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/resources_sobject_describe.htm
 func (obj *SObject) Describe() *SObjectMeta {
-	if obj.Type() == "" || obj.client() == nil {
-		// Sanity check.
-		return nil
+	meta, _ := obj.DescribeE()
+	return meta
+}
+
+// DescribeE is Describe returning an error instead of nil for failures.
+func (obj *SObject) DescribeE() (*SObjectMeta, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
 	}
 	url := obj.client().makeURL("sobjects/" + obj.Type() + "/describe")
 	data, err := obj.client().httpRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	var meta SObjectMeta
-	err = json.Unmarshal(data, &meta)
+	err = obj.client().unmarshalJSON(data, &meta)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	return &meta
+	return &meta, nil
 }
 
 // Get retrieves all the data fields of an SObject. If id is provided, the SObject with the provided external ID will
@@ -80,34 +108,40 @@ func (obj *SObject) Describe() *SObjectMeta {
 // If query is successful, the SObject is updated in-place and exact same address is returned; otherwise, nil is
 // returned if failed.
 func (obj *SObject) Get(id ...string) *SObject {
-	if obj.Type() == "" || obj.client() == nil {
-		// Sanity check.
+	obj, err := obj.GetE(id...)
+	if err != nil {
+		log.Println(logPrefix, "get failed,", err)
 		return nil
 	}
+	return obj
+}
+
+// GetE is Get returning an error instead of nil for failures, e.g. ErrMissingID if there is no ID to retrieve.
+func (obj *SObject) GetE(id ...string) (*SObject, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
+	}
 
 	oid := obj.ID()
 	if len(id) > 0 {
 		oid = id[0]
 	}
 	if oid == "" {
-		log.Println(logPrefix, "object id not found.")
-		return nil
+		return nil, ErrMissingID
 	}
 
 	url := obj.client().makeURL("sobjects/" + obj.Type() + "/" + oid)
 	data, err := obj.client().httpRequest(http.MethodGet, url, nil)
 	if err != nil {
-		log.Println(logPrefix, "http request failed,", err)
-		return nil
+		return nil, err
 	}
 
 	err = json.Unmarshal(data, obj)
 	if err != nil {
-		log.Println(logPrefix, "json decode failed,", err)
-		return nil
+		return nil, errors.Wrap(err, "decoding record")
 	}
 
-	return obj
+	return obj, nil
 }
 
 // Create posts the JSON representation of the SObject to salesforce to create the entry.
@@ -115,49 +149,69 @@ func (obj *SObject) Get(id ...string) *SObject {
 // returned for failures.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/dome_sobject_create.htm
 func (obj *SObject) Create() *SObject {
-	if obj.Type() == "" || obj.client() == nil {
-		// Sanity check.
+	obj, err := obj.CreateE()
+	if err != nil {
+		log.Println(logPrefix, "create failed,", err)
 		return nil
 	}
+	return obj
+}
+
+// CreateE is Create returning an error instead of nil for failures, e.g. the errors reported by salesforce.
+func (obj *SObject) CreateE() (*SObject, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
+	}
 
 	// Make a copy of the incoming SObject, but skip certain metadata fields as they're not understood by salesforce.
 	reqObj := obj.makeCopy()
-	reqData, err := json.Marshal(reqObj)
+	reqData, err := obj.client().marshalJSON(reqObj)
 	if err != nil {
-		log.Println(logPrefix, "failed to convert sobject to json,", err)
-		return nil
+		return nil, errors.Wrap(err, "encoding record")
 	}
 
 	url := obj.client().makeURL("sobjects/" + obj.Type() + "/")
 	respData, err := obj.client().httpRequest(http.MethodPost, url, bytes.NewReader(reqData))
 	if err != nil {
-		log.Println(logPrefix, "failed to process http request,", err)
-		return nil
+		return nil, err
 	}
 
 	err = obj.setIDFromResponseData(respData)
 	if err != nil {
-		log.Println(logPrefix, "failed to parse response,", err)
-		return nil
+		return nil, err
 	}
 
-	return obj
+	return obj, nil
 }
 
 // Update updates SObject in place. Upon successful, same SObject is returned for chained access.
 // ID is required.
 func (obj *SObject) Update() *SObject {
-	if obj.Type() == "" || obj.client() == nil || obj.ID() == "" {
-		// Sanity check.
+	obj, err := obj.UpdateE()
+	if err != nil {
+		log.Println(logPrefix, "update failed,", err)
 		return nil
 	}
+	return obj
+}
+
+// UpdateE is Update returning an error instead of nil for failures, e.g. ErrMissingID if the SObject has no ID.
+func (obj *SObject) UpdateE() (*SObject, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
+	}
+	if obj.ID() == "" {
+		return nil, ErrMissingID
+	}
+	if err := checkBigObjectWrite(obj.Type(), "update"); err != nil {
+		return nil, err
+	}
 
 	// Make a copy of the incoming SObject, but skip certain metadata fields as they're not understood by salesforce.
 	reqObj := obj.makeCopy()
-	reqData, err := json.Marshal(reqObj)
+	reqData, err := obj.client().marshalJSON(reqObj)
 	if err != nil {
-		log.Println(logPrefix, "failed to convert sobject to json,", err)
-		return nil
+		return nil, errors.Wrap(err, "encoding record")
 	}
 
 	queryBase := "sobjects/"
@@ -165,34 +219,42 @@ func (obj *SObject) Update() *SObject {
 		queryBase = "tooling/sobjects/"
 	}
 	url := obj.client().makeURL(queryBase + obj.Type() + "/" + obj.ID())
-	respData, err := obj.client().httpRequest(http.MethodPatch, url, bytes.NewReader(reqData))
+	_, err = obj.client().httpRequest(http.MethodPatch, url, bytes.NewReader(reqData))
 	if err != nil {
-		log.Println(logPrefix, "failed to process http request,", err)
-		return nil
+		return nil, err
 	}
-	log.Println(string(respData))
 
-	return obj
+	return obj, nil
 }
 
 // Upsert creates SObject or updates existing SObject in place. Upon successful upsert, same SObject is returned for chained access.
 // ID, ExternalIDField and Type are required. ID is the value of the external ID in this case.
 func (obj *SObject) Upsert() *SObject {
-	log.Println(logPrefix, "ExternalID:", obj.ExternalID())
-	log.Println(logPrefix, "ExternalIDField:", obj.ExternalIDFieldName())
-	if obj.Type() == "" || obj.client() == nil || obj.ExternalIDFieldName() == "" ||
-		obj.ExternalID() == "" {
-		// Sanity check.
-		log.Println(logPrefix, "required fields are missing")
+	obj, err := obj.UpsertE()
+	if err != nil {
+		log.Println(logPrefix, "upsert failed,", err)
 		return nil
 	}
+	return obj
+}
+
+// UpsertE is Upsert returning an error instead of nil for failures, e.g. ErrMissingID if the external ID is not set.
+func (obj *SObject) UpsertE() (*SObject, error) {
+	if err := obj.checkOnline(); err != nil {
+		return nil, err
+	}
+	if obj.ExternalIDFieldName() == "" || obj.ExternalID() == "" {
+		return nil, errors.Wrap(ErrMissingID, "external id")
+	}
+	if err := checkBigObjectWrite(obj.Type(), "upsert"); err != nil {
+		return nil, err
+	}
 
 	// Make a copy of the incoming SObject, but skip certain metadata fields as they're not understood by salesforce.
 	reqObj := obj.makeCopy()
-	reqData, err := json.Marshal(reqObj)
+	reqData, err := obj.client().marshalJSON(reqObj)
 	if err != nil {
-		log.Println(logPrefix, "failed to convert sobject to json,", err)
-		return nil
+		return nil, errors.Wrap(err, "encoding record")
 	}
 
 	queryBase := "sobjects/"
@@ -203,8 +265,7 @@ func (obj *SObject) Upsert() *SObject {
 		makeURL(queryBase + obj.Type() + "/" + obj.ExternalIDFieldName() + "/" + obj.ExternalID())
 	respData, err := obj.client().httpRequest(http.MethodPatch, url, bytes.NewReader(reqData))
 	if err != nil {
-		log.Println(logPrefix, "failed to process http request,", err)
-		return nil
+		return nil, err
 	}
 
 	// Upsert returns with 201 and id in response if a new record is created. If a record is updated, it returns
@@ -212,12 +273,11 @@ func (obj *SObject) Upsert() *SObject {
 	if len(respData) > 0 {
 		err = obj.setIDFromResponseData(respData)
 		if err != nil {
-			log.Println(logPrefix, "failed to parse response,", err)
-			return nil
+			return nil, err
 		}
 	}
 
-	return obj
+	return obj, nil
 }
 
 // Delete deletes an SObject record identified by external ID. nil is returned if the operation completes successfully;
@@ -227,6 +287,9 @@ func (obj *SObject) Delete(id ...string) error {
 		// Sanity check
 		return ErrFailure
 	}
+	if err := checkBigObjectWrite(obj.Type(), "delete"); err != nil {
+		return err
+	}
 
 	oid := obj.ID()
 	if id != nil {
@@ -246,6 +309,19 @@ func (obj *SObject) Delete(id ...string) error {
 	return nil
 }
 
+// checkOnline returns an error if obj cannot be used for API calls.
+func (obj *SObject) checkOnline() error {
+	switch {
+	case obj == nil:
+		return ErrNilSObject
+	case obj.client() == nil:
+		return ErrMissingClient
+	case obj.Type() == "":
+		return ErrMissingType
+	}
+	return nil
+}
+
 // Type returns the type, or sometimes referred to as name, of an SObject.
 func (obj *SObject) Type() string {
 	attributes := obj.AttributesField()
@@ -281,6 +357,47 @@ func (obj *SObject) StringField(key string) string {
 	}
 }
 
+// StringFieldE accesses a field in the SObject as string like StringField, but returns ErrFieldNotFound if the field is
+// not present and an error if it is not a string. Null values are returned as empty strings.
+func (obj *SObject) StringFieldE(key string) (string, error) {
+	value, err := obj.FieldE(key)
+	if err != nil || value == nil {
+		return "", err
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("field %s is a %T, not a string", key, value)
+	}
+	return str, nil
+}
+
+// FieldE accesses a field in the SObject as raw interface like InterfaceField, but returns ErrFieldNotFound if the
+// field is not present, e.g. because it was not queried.
+func (obj *SObject) FieldE(key string) (interface{}, error) {
+	if obj == nil {
+		return nil, ErrNilSObject
+	}
+	value, present := (*obj)[key]
+	if !present {
+		return nil, errors.Wrap(ErrFieldNotFound, key)
+	}
+	return value, nil
+}
+
+// NumberField accesses a numeric field in the SObject as a json.Number, which can be converted with its Int64 and
+// Float64 methods or parsed by a decimal package. The value is exact if the client decodes with SetUseNumber. An empty
+// json.Number is returned if the field is not numeric.
+func (obj *SObject) NumberField(key string) json.Number {
+	switch value := obj.InterfaceField(key).(type) {
+	case json.Number:
+		return value
+	case float64:
+		return json.Number(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return ""
+	}
+}
+
 // SObjectField accesses a field in the SObject as another SObject. This is only applicable if the field is an external
 // ID to another object. The typeName of the SObject must be provided. <nil> is returned if the field is empty.
 func (obj *SObject) SObjectField(typeName, key string) *SObject {
@@ -331,11 +448,26 @@ func (obj *SObject) SObjectField(typeName, key string) *SObject {
 	return object
 }
 
-// InterfaceField accesses a field in the SObject as raw interface. This allows access to any type of fields.
+// InterfaceField accesses a field in the SObject as raw interface. This allows access to any type of fields. All
+// accessors return zero values for a nil SObject.
 func (obj *SObject) InterfaceField(key string) interface{} {
+	if obj == nil {
+		return nil
+	}
 	return (*obj)[key]
 }
 
+// Value accesses a field in the SObject and tells whether it is present at all and whether it is null. A field that
+// was not queried is not present, while a field that was queried but is empty in salesforce is present and null, so
+// sync logic can tell whether it is safe to treat a missing value as cleared.
+func (obj *SObject) Value(key string) (value interface{}, present bool, null bool) {
+	if obj == nil {
+		return nil, false, false
+	}
+	value, present = (*obj)[key]
+	return value, present, present && value == nil
+}
+
 // AttributesField returns a read-only copy of the attributes field of an SObject.
 func (obj *SObject) AttributesField() *SObjectAttributes {
 	attributes := obj.InterfaceField(sobjectAttributesKey)
@@ -349,12 +481,8 @@ func (obj *SObject) AttributesField() *SObjectAttributes {
 		// Can't convert attributes to concrete type; decode interface.
 		mapper := attributes.(map[string]interface{})
 		attrs := &SObjectAttributes{}
-		if mapper["type"] != nil {
-			attrs.Type = mapper["type"].(string)
-		}
-		if mapper["url"] != nil {
-			attrs.URL = mapper["url"].(string)
-		}
+		attrs.Type, _ = mapper["type"].(string)
+		attrs.URL, _ = mapper["url"].(string)
 		return attrs
 	default:
 		return nil
@@ -368,6 +496,16 @@ func (obj *SObject) Set(key string, value interface{}) *SObject {
 	return obj
 }
 
+// SetLookupByExternalID sets the lookup relationship relationshipName, e.g. "Account" or "Parent__r", to reference the
+// related record by one of its external ID fields. Salesforce resolves the reference when the SObject is created,
+// updated or upserted:
+//
+//	obj.SetLookupByExternalID("Account", "External_Id__c", "ABC-1")
+//	// sends {"Account": {"External_Id__c": "ABC-1"}}
+func (obj *SObject) SetLookupByExternalID(relationshipName, externalIDField string, value interface{}) *SObject {
+	return obj.Set(relationshipName, map[string]interface{}{externalIDField: value})
+}
+
 // client returns the associated Client with the SObject.
 func (obj *SObject) client() *Client {
 	client := obj.InterfaceField(sobjectClientKey)
@@ -430,7 +568,7 @@ func (obj *SObject) setIDFromResponseData(respData []byte) error {
 		ID      string `json:"id"`
 		Success bool   `json:"success"`
 	}
-	err := json.Unmarshal(respData, &respVal)
+	err := obj.client().unmarshalJSON(respData, &respVal)
 	if err != nil {
 		log.Println(logPrefix, "failed to process response data,", err)
 		return err
@@ -444,3 +582,64 @@ func (obj *SObject) setIDFromResponseData(respData []byte) error {
 	obj.setID(respVal.ID)
 	return nil
 }
+
+// MarshalJSON implements json.Marshaler. The client reference is left out and the attributes are encoded the way
+// salesforce returns them, so an SObject round-trips with its type, URL, nested sobjects and subquery results. Use
+// Client.Attach to use a decoded SObject for online operations.
+func (obj SObject) MarshalJSON() ([]byte, error) {
+	if obj == nil {
+		return []byte("null"), nil
+	}
+
+	fields := make(map[string]interface{}, len(obj))
+	for key, val := range obj {
+		if key == sobjectClientKey {
+			continue
+		}
+		fields[key] = val
+	}
+	if attrs := obj.AttributesField(); attrs != nil {
+		attributes := map[string]string{"type": attrs.Type}
+		if attrs.URL != "" {
+			attributes["url"] = attrs.URL
+		}
+		fields[sobjectAttributesKey] = attributes
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Decoded fields are merged into the SObject, keeping its client. Numbers
+// are decoded as json.Number if the client of the SObject is set up with SetUseNumber.
+func (obj *SObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if client := obj.client(); client != nil && client.useNumber {
+		decoder.UseNumber()
+	}
+
+	var fields map[string]interface{}
+	err := decoder.Decode(&fields)
+	if err != nil {
+		return err
+	}
+	if fields == nil {
+		return nil
+	}
+
+	if *obj == nil {
+		*obj = make(SObject, len(fields))
+	}
+	for key, val := range fields {
+		(*obj)[key] = val
+	}
+	return nil
+}
+
+// ParseDateTime parses a salesforce datetime value in either the REST API format, e.g. "2022-05-01T10:00:00.000+0000",
+// or the ISO 8601 format used by the streaming API.
+func ParseDateTime(value string) (time.Time, error) {
+	t, err := time.Parse(salesforceTimeLayout, value)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -1,11 +1,13 @@
 package simpleforce
 
 import (
+	"encoding/json"
 	"log"
+	"net/http"
+	"strings"
 	"testing"
-	"time"
 
-	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 func TestSObject_AttributesField(t *testing.T) {
@@ -51,6 +53,21 @@ func TestSObject_InterfaceField(t *testing.T) {
 	}
 }
 
+func TestSObject_Value(t *testing.T) {
+	obj := &SObject{"Subject": "hello", "Description": nil}
+
+	value, present, null := obj.Value("Subject")
+	if value != "hello" || !present || null {
+		t.Fail()
+	}
+	if value, present, null = obj.Value("Description"); value != nil || !present || !null {
+		t.Fail()
+	}
+	if value, present, null = obj.Value("Status"); value != nil || present || null {
+		t.Fail()
+	}
+}
+
 func TestSObject_SObjectField(t *testing.T) {
 	obj := &SObject{
 		sobjectAttributesKey: SObjectAttributes{Type: "CaseComment"},
@@ -76,243 +93,124 @@ func TestSObject_SObjectField(t *testing.T) {
 	}
 }
 
-func TestSObject_Describe(t *testing.T) {
-	client := requireClient(t, true)
-	meta := client.SObject("Case").Describe()
-	if meta == nil {
-		t.FailNow()
-	} else {
-		if (*meta)["name"].(string) != "Case" {
-			t.Fail()
-		}
+func TestSObject_JSONRoundTrip(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	obj := client.SObject("Account").Set("Name", "Acme").Set("Description", nil)
+	(*obj)["Owner"] = map[string]interface{}{
+		"attributes": map[string]interface{}{"type": "User", "url": "/services/data/v54.0/sobjects/User/005A"},
+		"Name":       "Jane",
+	}
+	(*obj)["Contacts"] = map[string]interface{}{
+		"totalSize": 1.0,
+		"done":      true,
+		"records":   []interface{}{map[string]interface{}{"attributes": map[string]interface{}{"type": "Contact"}, "LastName": "Doe"}},
 	}
-}
-
-func TestSObject_Get(t *testing.T) {
-	client := requireClient(t, true)
 
-	// Search for a valid Case ID first.
-	queryResult, err := client.Query("SELECT Id,OwnerId,Subject FROM CASE")
-	if err != nil || queryResult == nil {
-		log.Println(logPrefix, "query failed,", err)
-		t.FailNow()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if queryResult.TotalSize < 1 {
-		t.FailNow()
+	if strings.Contains(string(data), sobjectClientKey) {
+		t.Errorf("client leaked into %s", data)
 	}
-	oid := queryResult.Records[0].ID()
-	ownerID := queryResult.Records[0].StringField("OwnerId")
 
-	// Positive
-	obj := client.SObject("Case").Get(oid)
-	if obj.ID() != oid || obj.StringField("OwnerId") != ownerID {
-		t.Fail()
+	var decoded SObject
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// Positive 2
-	obj = client.SObject("Case")
-	if obj.StringField("OwnerId") != "" {
-		t.Fail()
+	if decoded.Type() != "Account" || decoded.StringField("Name") != "Acme" || decoded.client() != nil {
+		t.Errorf("unexpected object %v", decoded)
 	}
-	obj.setID(oid)
-	obj.Get()
-	if obj.ID() != oid || obj.StringField("OwnerId") != ownerID {
+	if _, present, null := decoded.Value("Description"); !present || !null {
 		t.Fail()
 	}
-
-	// Negative 1
-	obj = client.SObject("Case").Get("non-exist-id")
-	if obj != nil {
-		t.Fail()
+	owner := decoded.SObjectField("User", "Owner")
+	if owner == nil || owner.ID() != "005A" || owner.StringField("Name") != "Jane" {
+		t.Errorf("unexpected owner %v", owner)
+	}
+	again, err := json.Marshal(decoded)
+	if err != nil || string(again) != string(data) {
+		t.Errorf("round trip mismatch:\n%s\n%s", data, again)
 	}
 
-	// Negative 2
-	obj = &SObject{}
-	if obj.Get() != nil {
+	if client.Attach(&decoded).client() != client {
 		t.Fail()
 	}
 }
 
-func TestSObject_Create(t *testing.T) {
-	client := requireClient(t, true)
+func TestSObject_UnmarshalJSONUseNumber(t *testing.T) {
+	client := NewClient(DefaultURL, DefaultClientID, DefaultAPIVersion)
+	client.SetUseNumber(true)
+	obj := client.SObject("Opportunity").Set("Name", "Big deal")
 
-	// Positive
-	case1 := client.SObject("Case")
-	case1Result := case1.Set("Subject", "Case created by simpleforce on "+time.Now().Format("2006/01/02 03:04:05")).
-		Set("Comments", "This case is created by simpleforce").
-		Create()
-	if case1Result == nil || case1Result.ID() == "" || case1Result.Type() != case1.Type() {
-		t.Fail()
-	} else {
-		log.Println(logPrefix, "Case created,", case1Result.Get().StringField("CaseNumber"))
+	err := json.Unmarshal([]byte(`{"Amount": 12345678901234567.89}`), obj)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// Positive 2
-	caseComment1 := client.SObject("CaseComment")
-	caseComment1Result := caseComment1.Set("ParentId", case1Result.ID()).
-		Set("CommentBody", "This comment is created by simpleforce & used for testing").
-		Set("IsPublished", true).
-		Create()
-	if caseComment1Result.Get().SObjectField("Case", "ParentId").ID() != case1Result.ID() {
-		t.Fail()
-	} else {
-		log.Println(logPrefix, "CaseComment created,", caseComment1Result.ID())
-	}
-
-	// Negative: object without type.
-	obj := client.SObject()
-	if obj.Create() != nil {
-		t.Fail()
+	if obj.NumberField("Amount") != "12345678901234567.89" || obj.StringField("Name") != "Big deal" || obj.client() != client {
+		t.Errorf("unexpected object %v", obj)
 	}
+}
 
-	// Negative: object without client.
-	obj = &SObject{}
-	if obj.Create() != nil {
-		t.Fail()
-	}
+func TestSObject_SetLookupByExternalID(t *testing.T) {
+	obj := (&SObject{}).SetLookupByExternalID("Account", "External_Id__c", "ABC-1")
 
-	// Negative: Invalid type
-	obj = client.SObject("__SOME_INVALID_TYPE__")
-	if obj.Create() != nil {
-		t.Fail()
+	data, err := json.Marshal(obj.makeCopy())
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// Negative: Invalid field
-	obj = client.SObject("Case").Set("__SOME_INVALID_FIELD__", "")
-	if obj.Create() != nil {
-		t.Fail()
+	if string(data) != `{"Account":{"External_Id__c":"ABC-1"}}` {
+		t.Errorf("unexpected request body %s", data)
 	}
 }
 
-func TestSObject_Update(t *testing.T) {
-	client := requireClient(t, true)
+func TestSObject_ErrorVariants(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/services/data/v54.0/sobjects/Account/001A":
+			w.Write([]byte(`{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme", "Description": null}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/services/data/v54.0/sobjects/Account/":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`[{"message": "Required fields are missing: [Name]", "errorCode": "REQUIRED_FIELD_MISSING"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
 
-	// Positive
-	if client.SObject("Case").
-		Set("Subject", "Case created by simpleforce on "+time.Now().Format("2006/01/02 03:04:05")).
-		Create().
-		Set("Subject", "Case subject updated by simpleforce").
-		Update().
-		Get().
-		StringField("Subject") != "Case subject updated by simpleforce" {
-		t.Fail()
+	obj, err := client.SObject("Account").GetE("001A")
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestSObject_Upsert(t *testing.T) {
-	client := requireClient(t, true)
-
-	// Positive create new object through upsert
-	case1 := client.SObject("Case")
-	case1Result := case1.Set("Subject", "Case created by simpleforce on "+time.Now().Format("2006/01/02 03:04:05")).
-		Set("Comments", "This case is created by simpleforce").
-		Set("customExtIdField__c", uuid.NewString()).
-		Set("ExternalIDField", "customExtIdField__c").
-		Upsert()
-	if case1Result == nil || case1Result.ID() == "" || case1Result.Type() != case1.Type() {
-		t.Fail()
-	} else {
-		log.Println(logPrefix, "Case created,", case1Result.Get().StringField("CaseNumber"))
+	if name, err := obj.StringFieldE("Name"); err != nil || name != "Acme" {
+		t.Errorf("unexpected name %q %v", name, err)
 	}
-
-	// Positive update existing object through upsert
-	case2 := client.SObject("Case").
-		Set("Subject", "Case created by simpleforce on "+time.Now().Format("2006/01/02 03:04:05")).
-		Set("customExtIdField__c", uuid.NewString())
-	case2Result := case2.Create()
-	case2.
-		Set("Subject", "Case subject updated by simpleforce").
-		Set("ExternalIDField", "customExtIdField__c").
-		Upsert()
-	if case2Result.Get().StringField("Subject") != "Case subject updated by simpleforce" {
-		t.Fail()
-	} else {
-		log.Println(logPrefix, "Case updated,", case2Result.Get().StringField("CaseNumber"))
+	if description, err := obj.StringFieldE("Description"); err != nil || description != "" {
+		t.Errorf("unexpected description %q %v", description, err)
 	}
-
-	// Negative: object without type.
-	obj := client.SObject()
-	if obj.Upsert() != nil {
-		t.Fail()
+	if _, err = obj.StringFieldE("Industry"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound, got %v", err)
 	}
 
-	// Negative: object without client.
-	obj = &SObject{}
-	if obj.Upsert() != nil {
-		t.Fail()
+	if _, err = client.SObject("Account").CreateE(); err == nil || !strings.Contains(err.Error(), "REQUIRED_FIELD_MISSING") {
+		t.Errorf("expected the error of salesforce, got %v", err)
 	}
-
-	// Negative: Invalid type
-	obj = client.SObject("__SOME_INVALID_TYPE__").
-		Set("ExternalIDField", "customExtIdField__c").
-		Set("customExtIdField__c", uuid.NewString())
-	if obj.Upsert() != nil {
-		t.Fail()
+	if _, err = client.SObject("Account").UpdateE(); !errors.Is(err, ErrMissingID) {
+		t.Errorf("expected ErrMissingID, got %v", err)
 	}
-
-	// Negative: Invalid field
-	obj = client.SObject("Case").
-		Set("ExternalIDField", "customExtIdField__c").
-		Set("customExtIdField__c", uuid.NewString()).
-		Set("__SOME_INVALID_FIELD__", "")
-	if obj.Upsert() != nil {
-		t.Fail()
+	if _, err = (&SObject{}).GetE("001A"); !errors.Is(err, ErrMissingClient) {
+		t.Errorf("expected ErrMissingClient, got %v", err)
 	}
-
-	// Negative: Missing ext ID
-	obj = client.SObject("Case").
-		Set("ExternalIDField", "customExtIdField__c")
-	if obj.Upsert() != nil {
-		t.Fail()
+	if _, err = client.SObject().UpsertE(); !errors.Is(err, ErrMissingType) {
+		t.Errorf("expected ErrMissingType, got %v", err)
 	}
-}
-
-func TestSObject_Delete(t *testing.T) {
-	client := requireClient(t, true)
 
-	// Positive: create a case first then delete it and verify if it is gone.
-	case1 := client.SObject("Case").
-		Set("Subject", "Case created by simpleforce on "+time.Now().Format("2006/01/02 03:04:05")).
-		Create().
-		Get()
-	if case1 == nil || case1.ID() == "" {
-		t.Fatal()
-	}
-	caseID := case1.ID()
-	if case1.Delete() != nil {
-		t.Fail()
+	// Chained calls on the nil result of a failed call do not panic.
+	var missing *SObject
+	if missing.StringField("Name") != "" || missing.Type() != "" || missing.SObjectField("User", "OwnerId") != nil {
+		t.Error("expected zero values")
 	}
-	case1 = client.SObject("Case").Get(caseID)
-	if case1 != nil {
-		t.Fail()
+	if _, err = missing.UpdateE(); !errors.Is(err, ErrNilSObject) {
+		t.Errorf("expected ErrNilSObject, got %v", err)
 	}
 }
-
-// TestSObject_GetUpdate validates updating of existing records.
-func TestSObject_GetUpdate(t *testing.T) {
-	client := requireClient(t, true)
-
-	// Create a new case first.
-	case1 := client.SObject("Case").
-		Set("Subject", "Original").
-		Create().
-		Get()
-
-	// Query the case by ID, then update the Subject.
-	case2 := client.SObject("Case").
-		Get(case1.ID()).
-		Set("Subject", "Updated").
-		Update().
-		Get()
-
-	// Query the case by ID again and check if the Subject has been updated.
-	case3 := client.SObject("Case").
-		Get(case2.ID())
-
-	if case3.StringField("Subject") != "Updated" {
-		t.Fail()
-	}
-
-	user1 := client.SObject("User").Create()
-	log.Println(user1.ID())
-}
@@ -0,0 +1,288 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ReplayLatest subscribes to new events only.
+	ReplayLatest int64 = -1
+	// ReplayAll subscribes to all events retained by salesforce, as well as new events.
+	ReplayAll int64 = -2
+
+	streamingRetryInterval = 5 * time.Second
+)
+
+// StreamingMessage is an event received through a Subscriber. Raw is the complete CometD message, suitable for
+// EventRegistry.Decode.
+type StreamingMessage struct {
+	Channel  string
+	ReplayID int64
+	Data     json.RawMessage
+	Raw      json.RawMessage
+}
+
+type bayeuxMessage struct {
+	Channel      string          `json:"channel"`
+	ClientID     string          `json:"clientId"`
+	Successful   bool            `json:"successful"`
+	Error        string          `json:"error"`
+	Subscription string          `json:"subscription"`
+	Data         json.RawMessage `json:"data"`
+	Advice       *struct {
+		Reconnect string `json:"reconnect"`
+		Interval  int    `json:"interval"`
+	} `json:"advice"`
+}
+
+// Subscriber receives platform events, change data capture events and PushTopic events through the Streaming API
+// (CometD long polling). Subscriptions are resumed from the replay ID of the last handled event whenever the
+// connection has to be re-established.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_streaming.meta/api_streaming/intro_stream.htm
+type Subscriber struct {
+	client     *Client
+	httpClient *http.Client
+	endpoint   string
+
+	mu       sync.Mutex
+	clientID string
+	channels map[string]int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	task   int
+}
+
+// NewSubscriber creates a Subscriber using the session of the client.
+func (client *Client) NewSubscriber() (*Subscriber, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	// CometD relies on cookies set during the handshake, so a dedicated http.Client with a cookie jar is used.
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Jar: jar}
+	if client.httpClient != nil {
+		httpClient.Transport = client.httpClient.Transport
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Subscriber{
+		client:     client,
+		httpClient: httpClient,
+		endpoint:   fmt.Sprintf("%s/cometd/%s", client.servicesURL(), client.apiVersion),
+		channels:   make(map[string]int64),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	s.task = client.startTask(cancel)
+	return s, nil
+}
+
+// Subscribe adds a channel, e.g. "/event/Order_Shipped__e" or "/data/AccountChangeEvent", starting after replayID.
+// Use ReplayLatest or ReplayAll to start without a stored replay ID. Subscriptions must be added before Run.
+func (s *Subscriber) Subscribe(channel string, replayID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[channel] = replayID
+}
+
+// ReplayID returns the replay ID of the last event handled successfully on channel.
+func (s *Subscriber) ReplayID(channel string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.channels[channel]
+}
+
+// Stop makes Run return, aborting any pending long polling request. Close stops the subscribers of a client.
+func (s *Subscriber) Stop() {
+	s.cancel()
+	s.client.endTask(s.task)
+}
+
+// Run connects to salesforce and passes each event to handler until Stop is called, in which case nil is returned,
+// or handler returns an error, which is then returned. The replay ID of a channel only advances once handler succeeds,
+// so events are delivered at least once across reconnects.
+func (s *Subscriber) Run(handler func(*StreamingMessage) error) error {
+	for {
+		err := s.handshake()
+		if err == nil {
+			err = s.connectLoop(handler)
+		}
+		if s.ctx.Err() != nil {
+			return nil
+		}
+		if _, ok := err.(handlerError); ok {
+			return err.(handlerError).err
+		}
+		if err != nil {
+			log.Println(logPrefix, "streaming connection failed, retrying,", err)
+			select {
+			case <-s.ctx.Done():
+				return nil
+			case <-time.After(streamingRetryInterval):
+			}
+		}
+	}
+}
+
+// handlerError marks errors returned by the handler passed to Run.
+type handlerError struct {
+	err error
+}
+
+func (err handlerError) Error() string {
+	return err.err.Error()
+}
+
+// handshake negotiates a new CometD client ID and subscribes to all channels.
+func (s *Subscriber) handshake() error {
+	resp, err := s.send(map[string]interface{}{
+		"channel":                  "/meta/handshake",
+		"version":                  "1.0",
+		"supportedConnectionTypes": []string{"long-polling"},
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp) == 0 || !resp[0].Successful {
+		return fmt.Errorf("handshake failed: %v", resp)
+	}
+
+	s.mu.Lock()
+	s.clientID = resp[0].ClientID
+	var subscriptions []interface{}
+	for channel, replayID := range s.channels {
+		subscriptions = append(subscriptions, map[string]interface{}{
+			"channel":      "/meta/subscribe",
+			"clientId":     s.clientID,
+			"subscription": channel,
+			"ext":          map[string]interface{}{"replay": map[string]int64{channel: replayID}},
+		})
+	}
+	s.mu.Unlock()
+
+	resp, err = s.send(subscriptions...)
+	if err != nil {
+		return err
+	}
+	for _, msg := range resp {
+		if msg.Channel == "/meta/subscribe" && !msg.Successful {
+			return fmt.Errorf("failed to subscribe to %s: %s", msg.Subscription, msg.Error)
+		}
+	}
+	return nil
+}
+
+// connectLoop long polls for events until the connection needs to be re-established.
+func (s *Subscriber) connectLoop(handler func(*StreamingMessage) error) error {
+	for {
+		resp, err := s.send(map[string]interface{}{
+			"channel":        "/meta/connect",
+			"clientId":       s.clientID,
+			"connectionType": "long-polling",
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range resp {
+			if msg.Channel == "/meta/connect" {
+				if !msg.Successful {
+					return fmt.Errorf("connect failed: %s", msg.Error)
+				}
+				if msg.Advice != nil && msg.Advice.Reconnect == "handshake" {
+					return errors.New("server requested handshake")
+				}
+				continue
+			}
+			if len(msg.Data) == 0 {
+				continue
+			}
+
+			event, err := newStreamingMessage(msg)
+			if err != nil {
+				return handlerError{err}
+			}
+			err = handler(event)
+			if err != nil {
+				return handlerError{err}
+			}
+			s.mu.Lock()
+			if _, ok := s.channels[event.Channel]; ok {
+				s.channels[event.Channel] = event.ReplayID
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func newStreamingMessage(msg bayeuxMessage) (*StreamingMessage, error) {
+	var data struct {
+		Event struct {
+			ReplayID int64 `json:"replayId"`
+		} `json:"event"`
+	}
+	err := json.Unmarshal(msg.Data, &data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(map[string]interface{}{"channel": msg.Channel, "data": msg.Data})
+	if err != nil {
+		return nil, err
+	}
+	return &StreamingMessage{
+		Channel:  msg.Channel,
+		ReplayID: data.Event.ReplayID,
+		Data:     msg.Data,
+		Raw:      raw,
+	}, nil
+}
+
+// send posts CometD messages to the streaming endpoint.
+func (s *Subscriber) send(msgs ...interface{}) ([]bayeuxMessage, error) {
+	reqData, err := s.client.marshalJSON(msgs)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.endpoint, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", s.client.sessionID))
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respData, err := s.client.readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, ParseSalesforceError(resp.StatusCode, respData)
+	}
+
+	var result []bayeuxMessage
+	err = s.client.unmarshalJSON(respData, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newCometDServer serves a fake streaming endpoint which delivers events once, then keeps long polls open.
+func newCometDServer(t *testing.T, events ...string) (*httptest.Server, *sync.Map) {
+	subscriptions := &sync.Map{}
+	var mu sync.Mutex
+	pending := events
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cometd/"+DefaultAPIVersion {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var msgs []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&msgs)
+
+		var resp []interface{}
+		for _, msg := range msgs {
+			switch msg["channel"] {
+			case "/meta/handshake":
+				resp = append(resp, map[string]interface{}{"channel": "/meta/handshake", "clientId": "__CLIENT__", "successful": true})
+			case "/meta/subscribe":
+				subscriptions.Store(msg["subscription"], msg["ext"])
+				resp = append(resp, map[string]interface{}{"channel": "/meta/subscribe", "subscription": msg["subscription"], "successful": true})
+			case "/meta/connect":
+				mu.Lock()
+				batch := pending
+				pending = nil
+				mu.Unlock()
+				if batch == nil {
+					select {
+					case <-r.Context().Done():
+						return
+					case <-time.After(20 * time.Millisecond):
+					}
+				}
+				for _, event := range batch {
+					resp = append(resp, json.RawMessage(event))
+				}
+				resp = append(resp, map[string]interface{}{"channel": "/meta/connect", "successful": true})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return server, subscriptions
+}
+
+func TestSubscriber_Run(t *testing.T) {
+	server, subscriptions := newCometDServer(t,
+		`{"channel": "/event/Order_Shipped__e", "data": {"event": {"replayId": 7}, "payload": {"Order_Number__c": "ORD-1"}}}`,
+		`{"channel": "/event/Order_Shipped__e", "data": {"event": {"replayId": 8}, "payload": {"Order_Number__c": "ORD-2"}}}`,
+	)
+	defer server.Close()
+
+	client := NewClient(server.URL, DefaultClientID, DefaultAPIVersion)
+	client.SetSidLoc("__SESSION__", server.URL)
+	subscriber, err := client.NewSubscriber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriber.Subscribe("/event/Order_Shipped__e", ReplayAll)
+
+	registry := NewEventRegistry()
+	registry.Register("Order_Shipped__e", orderShipped{})
+	var received []string
+	err = subscriber.Run(func(msg *StreamingMessage) error {
+		event, err := registry.Decode(msg.Raw)
+		if err != nil {
+			return err
+		}
+		received = append(received, event.Payload.(*orderShipped).OrderNumber)
+		if len(received) == 2 {
+			subscriber.Stop()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 2 || received[0] != "ORD-1" || received[1] != "ORD-2" {
+		t.Errorf("unexpected events %v", received)
+	}
+	if subscriber.ReplayID("/event/Order_Shipped__e") != 8 {
+		t.Errorf("unexpected replay ID %d", subscriber.ReplayID("/event/Order_Shipped__e"))
+	}
+	if _, ok := subscriptions.Load("/event/Order_Shipped__e"); !ok {
+		t.Error("channel not subscribed")
+	}
+}
@@ -0,0 +1,365 @@
+package testserver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// query is a parsed SOQL query. Only the subset of SOQL needed for tests is supported: a field list (with parent
+// relationship fields and COUNT()), a WHERE clause of conditions joined by AND, ORDER BY on a single field and LIMIT.
+type query struct {
+	fields  []string
+	count   bool
+	object  string
+	where   []condition
+	orderBy string
+	desc    bool
+	limit   int
+}
+
+// condition compares a field with one or more literal values.
+type condition struct {
+	field    string
+	operator string
+	values   []interface{}
+}
+
+// parseQuery parses the supported subset of SOQL.
+func parseQuery(soql string) (*query, error) {
+	tokens, err := tokenize(soql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q := &query{limit: -1}
+
+	if !p.accept("SELECT") {
+		return nil, fmt.Errorf("expected SELECT")
+	}
+	for {
+		field := p.next()
+		if strings.EqualFold(field, "COUNT") && p.accept("(") {
+			if !p.accept(")") {
+				return nil, fmt.Errorf("only COUNT() is supported")
+			}
+			q.count = true
+		} else if field == "" || field == "," {
+			return nil, fmt.Errorf("expected field")
+		} else {
+			q.fields = append(q.fields, field)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if !p.accept("FROM") {
+		return nil, fmt.Errorf("expected FROM")
+	}
+	q.object = p.next()
+
+	if p.accept("WHERE") {
+		for {
+			cond, err := p.condition()
+			if err != nil {
+				return nil, err
+			}
+			q.where = append(q.where, cond)
+			if !p.accept("AND") {
+				break
+			}
+		}
+	}
+	if p.accept("ORDER") {
+		if !p.accept("BY") {
+			return nil, fmt.Errorf("expected BY")
+		}
+		q.orderBy = p.next()
+		if p.accept("DESC") {
+			q.desc = true
+		} else {
+			p.accept("ASC")
+		}
+	}
+	if p.accept("LIMIT") {
+		q.limit, err = strconv.Atoi(p.next())
+		if err != nil {
+			return nil, fmt.Errorf("invalid LIMIT")
+		}
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %s", p.tokens[p.pos])
+	}
+	return q, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *parser) accept(token string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], token) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) condition() (condition, error) {
+	cond := condition{field: p.next()}
+	switch {
+	case p.accept("="), p.accept("!="), p.accept("<"), p.accept(">"), p.accept("<="), p.accept(">="), p.accept("LIKE"):
+		cond.operator = strings.ToUpper(p.tokens[p.pos-1])
+		value, err := literal(p.next())
+		if err != nil {
+			return cond, err
+		}
+		cond.values = []interface{}{value}
+	case p.accept("IN"), p.accept("NOT"):
+		cond.operator = "IN"
+		if strings.EqualFold(p.tokens[p.pos-1], "NOT") {
+			if !p.accept("IN") {
+				return cond, fmt.Errorf("expected IN")
+			}
+			cond.operator = "NOT IN"
+		}
+		if !p.accept("(") {
+			return cond, fmt.Errorf("expected (")
+		}
+		for {
+			value, err := literal(p.next())
+			if err != nil {
+				return cond, err
+			}
+			cond.values = append(cond.values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if !p.accept(")") {
+			return cond, fmt.Errorf("expected )")
+		}
+	default:
+		return cond, fmt.Errorf("unsupported operator after %s", cond.field)
+	}
+	return cond, nil
+}
+
+// literal converts a literal token into a value comparable with record fields.
+func literal(token string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(token, "'"):
+		return token[1 : len(token)-1], nil
+	case strings.EqualFold(token, "null"):
+		return nil, nil
+	case strings.EqualFold(token, "true"):
+		return true, nil
+	case strings.EqualFold(token, "false"):
+		return false, nil
+	}
+	number, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		// Date literals and others are compared as strings.
+		return token, nil
+	}
+	return number, nil
+}
+
+// tokenize splits SOQL into identifiers, string literals and punctuation.
+func tokenize(soql string) ([]string, error) {
+	var tokens []string
+	runes := []rune(soql)
+	for idx := 0; idx < len(runes); {
+		c := runes[idx]
+		switch {
+		case unicode.IsSpace(c):
+			idx++
+		case c == '\'':
+			end := idx + 1
+			var value strings.Builder
+			for ; end < len(runes) && runes[end] != '\''; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				value.WriteRune(runes[end])
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, "'"+value.String()+"'")
+			idx = end + 1
+		case strings.ContainsRune("(),", c):
+			tokens = append(tokens, string(c))
+			idx++
+		case strings.ContainsRune("=!<>", c):
+			end := idx + 1
+			if end < len(runes) && runes[end] == '=' {
+				end++
+			}
+			tokens = append(tokens, string(runes[idx:end]))
+			idx = end
+		default:
+			end := idx
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("(),=!<>'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[idx:end]))
+			idx = end
+		}
+	}
+	return tokens, nil
+}
+
+// matches reports whether a record satisfies all conditions of the query.
+func (q *query) matches(lookup func(field string) interface{}) bool {
+	for _, cond := range q.where {
+		value := lookup(cond.field)
+		switch cond.operator {
+		case "=":
+			if !equal(value, cond.values[0]) {
+				return false
+			}
+		case "!=":
+			if equal(value, cond.values[0]) {
+				return false
+			}
+		case "<", ">", "<=", ">=":
+			cmp, ok := compare(value, cond.values[0])
+			if !ok {
+				return false
+			}
+			if (cond.operator == "<" && cmp >= 0) || (cond.operator == ">" && cmp <= 0) ||
+				(cond.operator == "<=" && cmp > 0) || (cond.operator == ">=" && cmp < 0) {
+				return false
+			}
+		case "LIKE":
+			s, _ := value.(string)
+			pattern, _ := cond.values[0].(string)
+			if !like(s, pattern) {
+				return false
+			}
+		case "IN", "NOT IN":
+			found := false
+			for _, candidate := range cond.values {
+				if equal(value, candidate) {
+					found = true
+				}
+			}
+			if found != (cond.operator == "IN") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sortRecords orders records by the ORDER BY field of the query. Null values sort first.
+func (q *query) sortRecords(records []map[string]interface{}, lookup func(record map[string]interface{}, field string) interface{}) {
+	if q.orderBy == "" {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		cmp, _ := compare(lookup(records[i], q.orderBy), lookup(records[j], q.orderBy))
+		if q.desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func equal(a, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			// String comparisons in SOQL are case-insensitive.
+			return strings.EqualFold(sa, sb)
+		}
+	}
+	cmp, ok := compare(a, b)
+	return ok && cmp == 0
+}
+
+// compare orders two values of the same kind; nil is less than any other value.
+func compare(a, b interface{}) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, true
+	case a == nil:
+		return -1, true
+	case b == nil:
+		return 1, true
+	}
+	switch va := a.(type) {
+	case string:
+		vb, ok := b.(string)
+		return strings.Compare(strings.ToLower(va), strings.ToLower(vb)), ok
+	case float64:
+		vb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case va < vb:
+			return -1, true
+		case va > vb:
+			return 1, true
+		}
+		return 0, true
+	case int:
+		return compare(float64(va), b)
+	case bool:
+		vb, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if va == vb {
+			return 0, true
+		}
+		if !va {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// like matches s against a SOQL LIKE pattern, where % matches any sequence and _ a single character.
+func like(s, pattern string) bool {
+	s, pattern = strings.ToLower(s), strings.ToLower(pattern)
+	if pattern == "" {
+		return s == ""
+	}
+	switch pattern[0] {
+	case '%':
+		for idx := 0; idx <= len(s); idx++ {
+			if like(s[idx:], pattern[1:]) {
+				return true
+			}
+		}
+		return false
+	case '_':
+		return s != "" && like(s[1:], pattern[1:])
+	default:
+		return s != "" && s[0] == pattern[0] && like(s[1:], pattern[1:])
+	}
+}
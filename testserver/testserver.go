@@ -0,0 +1,686 @@
+// Package testserver provides an in-memory mock of the Salesforce REST API for hermetic integration tests.
+//
+// A Server implements the SOAP login used by Client.LoginPassword, SOQL queries against seeded records (see Seed for
+// the supported subset), query pagination, record CRUD and upsert by external ID, sObject Collections, a basic
+// describe derived from the seeded records, and error injection:
+//
+//	server := testserver.New()
+//	defer server.Close()
+//	server.Seed("Account", map[string]interface{}{"Name": "Acme"})
+//	client := server.Client()
+//	result, err := client.Query("SELECT Id, Name FROM Account WHERE Name = 'Acme'")
+package testserver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/scottraio/simpleforce"
+)
+
+const (
+	// DefaultSessionID is the session ID issued by the Server and accepted on every request.
+	DefaultSessionID = "00D000000000001!MOCKSESSION"
+	// OrganizationID is the ID of the mocked org.
+	OrganizationID = "00D000000000001AAA"
+	// UserID is the ID of the user logged in to the mocked org.
+	UserID = "005000000000001AAA"
+
+	defaultPageSize = 2000
+)
+
+// keyPrefixes maps well-known objects to the key prefix of their IDs. Other objects use "a00".
+var keyPrefixes = map[string]string{
+	"Account":        "001",
+	"Contact":        "003",
+	"Opportunity":    "006",
+	"Lead":           "00Q",
+	"Case":           "500",
+	"User":           "005",
+	"Task":           "00T",
+	"Event":          "00U",
+	"ContentVersion": "068",
+	"Attachment":     "00P",
+}
+
+// Server is an in-memory Salesforce REST API served over an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password, if set, are required by the SOAP login. Any credentials are accepted otherwise.
+	Username string
+	Password string
+	// PageSize is the number of records per query page. Defaults to 2000.
+	PageSize int
+
+	mu       sync.Mutex
+	records  map[string]map[string]map[string]interface{}
+	sequence int
+	cursors  map[string][]map[string]interface{}
+	failures []*injectedError
+	requests []string
+}
+
+type injectedError struct {
+	method     string
+	pathPrefix string
+	statusCode int
+	errorCode  string
+	message    string
+	remaining  int
+}
+
+// New starts a Server.
+func New() *Server {
+	server := &Server{
+		records: make(map[string]map[string]map[string]interface{}),
+		cursors: make(map[string][]map[string]interface{}),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// Client returns a client logged in to the Server.
+func (server *Server) Client() *simpleforce.Client {
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	client.SetSidLoc(DefaultSessionID, server.URL)
+	return client
+}
+
+// Seed stores records of objectType and returns their IDs. Records without an Id field are assigned a new ID.
+//
+// Queries support SELECT with plain and parent relationship fields (resolved through the <Relationship>Id or
+// <Relationship>__c lookup field) and COUNT(), a WHERE clause of conditions joined by AND with the operators =, !=,
+// <, >, <=, >=, LIKE, IN and NOT IN, ORDER BY on a single field and LIMIT.
+func (server *Server) Seed(objectType string, records ...map[string]interface{}) []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, server.insert(objectType, record))
+	}
+	return ids
+}
+
+// Record returns a copy of the stored record, or nil if there is none.
+func (server *Server) Record(objectType, id string) map[string]interface{} {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	record := server.records[objectType][id]
+	if record == nil {
+		return nil
+	}
+	return copyRecord(record)
+}
+
+// Records returns copies of all stored records of objectType, ordered by ID.
+func (server *Server) Records(objectType string) []map[string]interface{} {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	var records []map[string]interface{}
+	for _, record := range server.sortedRecords(objectType) {
+		records = append(records, copyRecord(record))
+	}
+	return records
+}
+
+// InjectError makes the next count requests with method (any method if empty) and a path starting with pathPrefix
+// fail with the given status and a standard REST API error body.
+func (server *Server) InjectError(method, pathPrefix string, count, statusCode int, errorCode, message string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.failures = append(server.failures, &injectedError{
+		method:     method,
+		pathPrefix: pathPrefix,
+		statusCode: statusCode,
+		errorCode:  errorCode,
+		message:    message,
+		remaining:  count,
+	})
+}
+
+// Requests returns the method and path of every request received, e.g. "GET /services/data/v54.0/query".
+func (server *Server) Requests() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]string{}, server.requests...)
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+
+	for _, failure := range server.failures {
+		if failure.remaining > 0 && (failure.method == "" || failure.method == r.Method) &&
+			strings.HasPrefix(r.URL.Path, failure.pathPrefix) {
+			failure.remaining--
+			writeError(w, failure.statusCode, failure.errorCode, failure.message)
+			return
+		}
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/services/Soap/u/") {
+		server.login(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+DefaultSessionID {
+		writeError(w, http.StatusUnauthorized, "INVALID_SESSION_ID", "Session expired or invalid")
+		return
+	}
+
+	// Strip "/services/data/vXX.X/" and the Tooling API prefix, which share the same store.
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "services" || parts[1] != "data" || !strings.HasPrefix(parts[2], "v") {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource does not exist")
+		return
+	}
+	parts = parts[3:]
+	if len(parts) > 0 && parts[0] == "tooling" {
+		parts = parts[1:]
+	}
+
+	switch {
+	case len(parts) == 0:
+		writeJSON(w, http.StatusOK, map[string]string{})
+	case parts[0] == "query" && len(parts) == 1:
+		server.query(w, r.URL.Query().Get("q"))
+	case parts[0] == "query" && len(parts) == 2:
+		server.queryMore(w, parts[1])
+	case parts[0] == "composite" && len(parts) == 2 && parts[1] == "sobjects":
+		server.collections(w, r)
+	case parts[0] == "composite" && len(parts) == 4 && parts[1] == "sobjects" && r.Method == http.MethodPatch:
+		server.upsertCollection(w, r, parts[2], parts[3])
+	case parts[0] == "sobjects" && len(parts) == 3 && parts[2] == "describe":
+		server.describe(w, parts[1])
+	case parts[0] == "sobjects" && len(parts) == 2 && r.Method == http.MethodPost:
+		server.create(w, r, parts[1])
+	case parts[0] == "sobjects" && len(parts) == 3:
+		server.record(w, r, parts[1], parts[2])
+	case parts[0] == "sobjects" && len(parts) == 4 && r.Method == http.MethodPatch:
+		server.upsert(w, r, parts[1], parts[2], parts[3])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource does not exist")
+	}
+}
+
+// login implements the SOAP login call.
+func (server *Server) login(w http.ResponseWriter, r *http.Request) {
+	var envelope struct {
+		Username string `xml:"Body>login>username"`
+		Password string `xml:"Body>login>password"`
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	err := xml.Unmarshal(body, &envelope)
+	if err != nil || (server.Username != "" && (envelope.Username != server.Username || envelope.Password != server.Password)) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:sf="urn:fault.partner.soap.sforce.com">
+    <soapenv:Body>
+        <soapenv:Fault>
+            <faultcode>sf:INVALID_LOGIN</faultcode>
+            <faultstring>INVALID_LOGIN: Invalid username, password, security token; or user locked out.</faultstring>
+            <detail>
+                <sf:LoginFault xsi:type="sf:LoginFault" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+                    <sf:exceptionCode>INVALID_LOGIN</sf:exceptionCode>
+                    <sf:exceptionMessage>Invalid username, password, security token; or user locked out.</sf:exceptionMessage>
+                </sf:LoginFault>
+            </detail>
+        </soapenv:Fault>
+    </soapenv:Body>
+</soapenv:Envelope>`)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com">
+    <soapenv:Body>
+        <loginResponse>
+            <result>
+                <serverUrl>%s/services/Soap/u/%s/%s</serverUrl>
+                <sessionId>%s</sessionId>
+                <userId>%s</userId>
+                <userInfo>
+                    <organizationId>%s</organizationId>
+                    <userEmail>%s</userEmail>
+                    <userFullName>Test User</userFullName>
+                    <userName>%s</userName>
+                </userInfo>
+            </result>
+        </loginResponse>
+    </soapenv:Body>
+</soapenv:Envelope>`, server.URL, simpleforce.DefaultAPIVersion, OrganizationID[:15], DefaultSessionID, UserID,
+		OrganizationID, xmlEscape(envelope.Username), xmlEscape(envelope.Username))
+}
+
+func (server *Server) query(w http.ResponseWriter, soql string) {
+	q, err := parseQuery(soql)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MALFORMED_QUERY", err.Error())
+		return
+	}
+	if _, ok := server.records[q.object]; !ok && keyPrefixes[q.object] == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_TYPE", fmt.Sprintf("sObject type '%s' is not supported.", q.object))
+		return
+	}
+
+	var matched []map[string]interface{}
+	for _, record := range server.sortedRecords(q.object) {
+		lookup := func(field string) interface{} { return server.lookup(record, field) }
+		if q.matches(lookup) {
+			matched = append(matched, record)
+		}
+	}
+	q.sortRecords(matched, server.lookup)
+	if q.limit >= 0 && len(matched) > q.limit {
+		matched = matched[:q.limit]
+	}
+
+	if q.count && len(q.fields) == 0 {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"totalSize": len(matched), "done": true, "records": []interface{}{}})
+		return
+	}
+
+	results := make([]map[string]interface{}, 0, len(matched))
+	for _, record := range matched {
+		results = append(results, server.project(q.object, record, q.fields))
+	}
+	server.writePage(w, results, len(results))
+}
+
+func (server *Server) queryMore(w http.ResponseWriter, cursor string) {
+	results, ok := server.cursors[cursor]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_QUERY_LOCATOR", "invalid query locator")
+		return
+	}
+	delete(server.cursors, cursor)
+	total, _ := strconv.Atoi(cursor[strings.LastIndex(cursor, "-")+1:])
+	server.writePage(w, results, total)
+}
+
+// writePage writes the first page of results and keeps the rest behind a cursor.
+func (server *Server) writePage(w http.ResponseWriter, results []map[string]interface{}, total int) {
+	pageSize := server.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	response := map[string]interface{}{"totalSize": total, "done": true, "records": results}
+	if len(results) > pageSize {
+		server.sequence++
+		cursor := fmt.Sprintf("01g%012d-%d", server.sequence, total)
+		server.cursors[cursor] = results[pageSize:]
+		response["records"] = results[:pageSize]
+		response["done"] = false
+		response["nextRecordsUrl"] = fmt.Sprintf("/services/data/v%s/query/%s", simpleforce.DefaultAPIVersion, cursor)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// project returns the selected fields of a record in the shape of a query result.
+func (server *Server) project(objectType string, record map[string]interface{}, fields []string) map[string]interface{} {
+	result := map[string]interface{}{"attributes": server.attributes(objectType, record)}
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		target := result
+		current := record
+		for idx, key := range path {
+			if idx == len(path)-1 {
+				name, value := fieldFold(current, key)
+				target[name] = value
+				break
+			}
+			parent, parentType := server.parent(current, key)
+			if parent == nil {
+				target[key] = nil
+				break
+			}
+			nested, ok := target[key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{"attributes": server.attributes(parentType, parent)}
+				target[key] = nested
+			}
+			target, current = nested, parent
+		}
+	}
+	return result
+}
+
+// lookup resolves a possibly relationship-qualified field of a record.
+func (server *Server) lookup(record map[string]interface{}, field string) interface{} {
+	path := strings.Split(field, ".")
+	current := record
+	for _, key := range path[:len(path)-1] {
+		current, _ = server.parent(current, key)
+		if current == nil {
+			return nil
+		}
+	}
+	_, value := fieldFold(current, path[len(path)-1])
+	return value
+}
+
+// parent resolves a parent relationship through its lookup field.
+func (server *Server) parent(record map[string]interface{}, relationship string) (map[string]interface{}, string) {
+	lookupField := relationship + "Id"
+	if strings.HasSuffix(relationship, "__r") {
+		lookupField = strings.TrimSuffix(relationship, "__r") + "__c"
+	}
+	_, value := fieldFold(record, lookupField)
+	id, _ := value.(string)
+	if id == "" {
+		return nil, ""
+	}
+	for objectType, records := range server.records {
+		if parent, ok := records[id]; ok {
+			return parent, objectType
+		}
+	}
+	return nil, ""
+}
+
+func (server *Server) attributes(objectType string, record map[string]interface{}) map[string]string {
+	id, _ := record["Id"].(string)
+	return map[string]string{
+		"type": objectType,
+		"url":  fmt.Sprintf("/services/data/v%s/sobjects/%s/%s", simpleforce.DefaultAPIVersion, objectType, id),
+	}
+}
+
+func (server *Server) describe(w http.ResponseWriter, objectType string) {
+	records, ok := server.records[objectType]
+	if !ok && keyPrefixes[objectType] == "" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource does not exist")
+		return
+	}
+
+	names := map[string]interface{}{"Id": "id"}
+	for _, record := range records {
+		for key, value := range record {
+			if _, ok := names[key]; ok && value == nil {
+				continue
+			}
+			switch value.(type) {
+			case bool:
+				names[key] = "boolean"
+			case float64, int:
+				names[key] = "double"
+			default:
+				names[key] = "string"
+			}
+		}
+	}
+	var fields []map[string]interface{}
+	for _, name := range sortedKeys(names) {
+		fields = append(fields, map[string]interface{}{"name": name, "type": names[name]})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":      objectType,
+		"keyPrefix": keyPrefix(objectType),
+		"fields":    fields,
+	})
+}
+
+func (server *Server) create(w http.ResponseWriter, r *http.Request, objectType string) {
+	fields, ok := decodeFields(w, r)
+	if !ok {
+		return
+	}
+	id := server.insert(objectType, fields)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"id": id, "success": true, "errors": []interface{}{}})
+}
+
+func (server *Server) record(w http.ResponseWriter, r *http.Request, objectType, id string) {
+	record, ok := server.records[objectType][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource does not exist")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		result := copyRecord(record)
+		result["attributes"] = server.attributes(objectType, record)
+		writeJSON(w, http.StatusOK, result)
+	case http.MethodPatch:
+		fields, ok := decodeFields(w, r)
+		if !ok {
+			return
+		}
+		for key, value := range fields {
+			record[key] = value
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(server.records[objectType], id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "HTTP Method '"+r.Method+"' not allowed")
+	}
+}
+
+func (server *Server) upsert(w http.ResponseWriter, r *http.Request, objectType, field, value string) {
+	fields, ok := decodeFields(w, r)
+	if !ok {
+		return
+	}
+	id, created := server.upsertRecord(objectType, field, value, fields)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, map[string]interface{}{"id": id, "success": true, "created": created, "errors": []interface{}{}})
+}
+
+// upsertRecord updates the record of objectType whose field matches value, or inserts one. Callers must hold the lock.
+func (server *Server) upsertRecord(objectType, field, value string, fields map[string]interface{}) (string, bool) {
+	for id, record := range server.records[objectType] {
+		if _, current := fieldFold(record, field); current == value {
+			for key, val := range fields {
+				record[key] = val
+			}
+			return id, false
+		}
+	}
+	fields[field] = value
+	return server.insert(objectType, fields), true
+}
+
+// upsertCollection implements the sObject Collections upsert resource.
+func (server *Server) upsertCollection(w http.ResponseWriter, r *http.Request, objectType, field string) {
+	var body struct {
+		AllOrNone bool                     `json:"allOrNone"`
+		Records   []map[string]interface{} `json:"records"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "JSON_PARSER_ERROR", err.Error())
+		return
+	}
+
+	var results []simpleforce.SaveResult
+	for _, record := range body.Records {
+		delete(record, "attributes")
+		value := fmt.Sprint(record[field])
+		if record[field] == nil || value == "" {
+			results = append(results, failedResult("", "MISSING_ARGUMENT", field+" not specified"))
+			continue
+		}
+		id, created := server.upsertRecord(objectType, field, value, record)
+		results = append(results, simpleforce.SaveResult{ID: id, Success: true, Created: created})
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// collections implements the sObject Collections create, update and delete resources.
+func (server *Server) collections(w http.ResponseWriter, r *http.Request) {
+	var results []simpleforce.SaveResult
+	if r.Method == http.MethodDelete {
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			results = append(results, server.deleteByID(id))
+		}
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	var body struct {
+		AllOrNone bool                     `json:"allOrNone"`
+		Records   []map[string]interface{} `json:"records"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "JSON_PARSER_ERROR", err.Error())
+		return
+	}
+	for _, record := range body.Records {
+		attributes, _ := record["attributes"].(map[string]interface{})
+		objectType, _ := attributes["type"].(string)
+		delete(record, "attributes")
+		if objectType == "" {
+			results = append(results, failedResult("", "INVALID_TYPE", "missing attributes.type"))
+			continue
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			results = append(results, simpleforce.SaveResult{ID: server.insert(objectType, record), Success: true})
+		case http.MethodPatch:
+			id, _ := record["Id"].(string)
+			stored, ok := server.records[objectType][id]
+			if !ok {
+				results = append(results, failedResult(id, "ENTITY_IS_DELETED", "entity is deleted"))
+				continue
+			}
+			for key, value := range record {
+				stored[key] = value
+			}
+			results = append(results, simpleforce.SaveResult{ID: id, Success: true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "HTTP Method '"+r.Method+"' not allowed")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (server *Server) deleteByID(id string) simpleforce.SaveResult {
+	for _, records := range server.records {
+		if _, ok := records[id]; ok {
+			delete(records, id)
+			return simpleforce.SaveResult{ID: id, Success: true}
+		}
+	}
+	return failedResult(id, "ENTITY_IS_DELETED", "entity is deleted")
+}
+
+// insert stores a record and returns its ID. Callers must hold the lock.
+func (server *Server) insert(objectType string, fields map[string]interface{}) string {
+	record := copyRecord(fields)
+	id, _ := record["Id"].(string)
+	if id == "" {
+		server.sequence++
+		id = fmt.Sprintf("%s%012dAAA", keyPrefix(objectType), server.sequence)
+		record["Id"] = id
+	}
+	if server.records[objectType] == nil {
+		server.records[objectType] = make(map[string]map[string]interface{})
+	}
+	server.records[objectType][id] = record
+	return id
+}
+
+func (server *Server) sortedRecords(objectType string) []map[string]interface{} {
+	records := server.records[objectType]
+	ids := make([]string, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	sorted := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, records[id])
+	}
+	return sorted
+}
+
+func keyPrefix(objectType string) string {
+	if prefix, ok := keyPrefixes[objectType]; ok {
+		return prefix
+	}
+	return "a00"
+}
+
+// fieldFold looks up a field case-insensitively and returns it under its stored name.
+func fieldFold(record map[string]interface{}, field string) (string, interface{}) {
+	if value, ok := record[field]; ok {
+		return field, value
+	}
+	for key, value := range record {
+		if strings.EqualFold(key, field) {
+			return key, value
+		}
+	}
+	return field, nil
+}
+
+func copyRecord(record map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(record))
+	for key, value := range record {
+		copied[key] = value
+	}
+	return copied
+}
+
+func sortedKeys(mapper map[string]interface{}) []string {
+	keys := make([]string, 0, len(mapper))
+	for key := range mapper {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func decodeFields(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var fields map[string]interface{}
+	err := json.NewDecoder(r.Body).Decode(&fields)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "JSON_PARSER_ERROR", err.Error())
+		return nil, false
+	}
+	delete(fields, "attributes")
+	return fields, true
+}
+
+func failedResult(id, statusCode, message string) simpleforce.SaveResult {
+	return simpleforce.SaveResult{ID: id, Errors: []simpleforce.SaveError{{StatusCode: statusCode, Message: message}}}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	writeJSON(w, statusCode, []map[string]string{{"errorCode": errorCode, "message": message}})
+}
+
+func xmlEscape(s string) string {
+	var buf strings.Builder
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
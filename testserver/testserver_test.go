@@ -0,0 +1,158 @@
+package testserver
+
+import (
+	"testing"
+
+	"github.com/scottraio/simpleforce"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+func TestServer_Login(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.Username, server.Password = "user@example.com", "secret"
+
+	client := simpleforce.NewClient(server.URL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	if err := client.LoginPassword("user@example.com", "secret", ""); err != nil {
+		t.Fatal(err)
+	}
+	if client.GetSid() != DefaultSessionID || client.GetLoc() != server.URL {
+		t.Errorf("unexpected session %s %s", client.GetSid(), client.GetLoc())
+	}
+
+	err := client.LoginPassword("user@example.com", "wrong", "")
+	if loginErr, ok := err.(*simpleforce.LoginError); !ok || loginErr.Code != errcode.InvalidLogin {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestServer_Query(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.PageSize = 2
+	accountIDs := server.Seed("Account",
+		map[string]interface{}{"Name": "Acme", "NumberOfEmployees": 100},
+		map[string]interface{}{"Name": "Globex", "NumberOfEmployees": 5},
+	)
+	server.Seed("Contact",
+		map[string]interface{}{"LastName": "Smith", "AccountId": accountIDs[0]},
+		map[string]interface{}{"LastName": "Jones", "AccountId": accountIDs[1]},
+		map[string]interface{}{"LastName": "Brown", "AccountId": accountIDs[0]},
+	)
+	client := server.Client()
+
+	result, err := client.Query("SELECT Id, LastName, Account.Name FROM Contact WHERE Account.NumberOfEmployees > 10 ORDER BY LastName")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalSize != 2 || len(result.Records) != 2 || result.Records[0].StringField("LastName") != "Brown" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	account := result.Records[0].SObjectField("Account", "Account")
+	if account == nil || account.StringField("Name") != "Acme" || account.ID() != accountIDs[0] {
+		t.Errorf("unexpected parent %v", account)
+	}
+
+	result, err = client.Query("SELECT Id FROM Contact WHERE LastName IN ('smith', 'Jones', 'Brown')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Done || len(result.Records) != 2 || result.NextRecordsURL == "" {
+		t.Fatalf("expected a second page, got %+v", result)
+	}
+	result, err = client.QueryMore(result.NextRecordsURL)
+	if err != nil || !result.Done || len(result.Records) != 1 || result.TotalSize != 3 {
+		t.Fatalf("unexpected second page %+v, %v", result, err)
+	}
+
+	result, err = client.Query("SELECT COUNT() FROM Account WHERE Name LIKE 'Ac%'")
+	if err != nil || result.TotalSize != 1 {
+		t.Errorf("unexpected count %+v, %v", result, err)
+	}
+
+	if _, err = client.Query("SELECT FROM"); err == nil {
+		t.Error("expected MALFORMED_QUERY")
+	}
+}
+
+func TestServer_CRUD(t *testing.T) {
+	server := New()
+	defer server.Close()
+	client := server.Client()
+
+	obj := client.SObject("Case").Set("Subject", "Printer on fire").Create()
+	if obj == nil || obj.ID() == "" {
+		t.Fatal("create failed")
+	}
+	id := obj.ID()
+
+	if client.SObject("Case").Set("Id", id).Set("Subject", "Printer fixed").Update() == nil {
+		t.Fatal("update failed")
+	}
+	if got := client.SObject("Case").Get(id); got == nil || got.StringField("Subject") != "Printer fixed" {
+		t.Errorf("unexpected record %v", got)
+	}
+
+	upserted := client.SObject("Case").Set("ExternalIDField", "Ext__c").Set("Ext__c", "X-1").Set("Subject", "New").Upsert()
+	if upserted == nil || server.Record("Case", upserted.ID())["Ext__c"] != "X-1" {
+		t.Errorf("unexpected upsert %v", upserted)
+	}
+
+	if err := client.SObject("Case").Set("Id", id).Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if client.SObject("Case").Get(id) != nil || server.Record("Case", id) != nil {
+		t.Error("record not deleted")
+	}
+
+	server.InjectError("POST", "/services/data/", 1, 400, "REQUIRED_FIELD_MISSING", "Required fields are missing")
+	if client.SObject("Case").Create() != nil {
+		t.Error("expected injected failure")
+	}
+	if client.SObject("Case").Create() == nil {
+		t.Error("injected failure should only apply once")
+	}
+}
+
+func TestServer_Collections(t *testing.T) {
+	server := New()
+	defer server.Close()
+	client := server.Client()
+
+	records := []*simpleforce.SObject{
+		client.SObject("Account").Set("Name", "A"),
+		client.SObject("Account").Set("Name", "B"),
+	}
+	if _, err := client.CreateAll(records); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.Records("Account")) != 2 {
+		t.Fatal("records not created")
+	}
+
+	results, err := client.DeleteAll([]string{records[0].ID(), "001000000000999AAA"})
+	if _, ok := err.(*simpleforce.BatchError); !ok || !results[0].Success || results[1].Success {
+		t.Errorf("unexpected results %+v, %v", results, err)
+	}
+}
+
+func TestServer_UpsertCollection(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.Seed("Account", map[string]interface{}{"Name": "Old", "External_Id__c": "A-1"})
+	client := server.Client()
+
+	results, err := client.UpsertAll("Account", "External_Id__c", []*simpleforce.SObject{
+		client.SObject("Account").Set("External_Id__c", "A-1").Set("Name", "Updated"),
+		client.SObject("Account").Set("External_Id__c", "A-2").Set("Name", "Created"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Created || !results[1].Created || len(server.Records("Account")) != 2 {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if server.Record("Account", results[0].ID)["Name"] != "Updated" {
+		t.Error("record not updated")
+	}
+}
@@ -0,0 +1,145 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThrottleCurve returns the delay before a request for the fraction of the daily API requests of the org used so far,
+// usually between 0 and 1.
+type ThrottleCurve func(usage float64) time.Duration
+
+// LinearThrottle returns a ThrottleCurve which does not delay requests until start of the daily API requests are used,
+// e.g. 0.8, and then increases the delay linearly up to maxDelay when the limit is reached.
+func LinearThrottle(start float64, maxDelay time.Duration) ThrottleCurve {
+	return func(usage float64) time.Duration {
+		if usage <= start {
+			return 0
+		}
+		if usage >= 1 || start >= 1 {
+			return maxDelay
+		}
+		return time.Duration(float64(maxDelay) * (usage - start) / (1 - start))
+	}
+}
+
+// apiThrottle delays requests by the API usage last reported by salesforce.
+type apiThrottle struct {
+	curve ThrottleCurve
+
+	mu   sync.Mutex
+	used int
+	max  int
+}
+
+// SetAPIUsageThrottle delays every request by curve applied to the API usage of the org, which salesforce reports in
+// the Sforce-Limit-Info header of REST API responses, so that batch jobs slow down as the daily limit approaches
+// instead of exhausting it for interactive users. Requests are not delayed until the usage was reported once. A nil
+// curve disables throttling. The throttle may be changed while requests are made with the client.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_api_usage.htm
+func (client *Client) SetAPIUsageThrottle(curve ThrottleCurve) {
+	var throttle *apiThrottle
+	if curve != nil {
+		throttle = &apiThrottle{curve: curve}
+	}
+	client.throttle.Store(throttle)
+}
+
+// currentThrottle returns the throttle set with SetAPIUsageThrottle, or nil.
+func (client *Client) currentThrottle() *apiThrottle {
+	throttle, _ := client.throttle.Load().(*apiThrottle)
+	return throttle
+}
+
+// APIUsage returns the number of API requests made by the org in the last 24 hours and the daily limit, as last
+// reported by salesforce. Usage is tracked while throttling is enabled with SetAPIUsageThrottle; zeros are returned
+// otherwise or before the first response.
+func (client *Client) APIUsage() (used, max int) {
+	throttle := client.currentThrottle()
+	if throttle == nil {
+		return 0, 0
+	}
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	return throttle.used, throttle.max
+}
+
+// roundTrip sends req with the HTTP client of the client, delaying it if throttling is enabled.
+func (client *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	throttle := client.currentThrottle()
+	if throttle == nil {
+		return client.httpClient.Do(req)
+	}
+	err := throttle.wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.httpClient.Do(req)
+	if resp != nil {
+		throttle.observe(resp.Header.Get("Sforce-Limit-Info"))
+	}
+	return resp, err
+}
+
+// wait sleeps for the delay of the current usage, or until ctx is done.
+func (throttle *apiThrottle) wait(ctx context.Context) error {
+	throttle.mu.Lock()
+	used, max := throttle.used, throttle.max
+	throttle.mu.Unlock()
+	if max <= 0 {
+		return nil
+	}
+	delay := throttle.curve(float64(used) / float64(max))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// observe records the usage of a Sforce-Limit-Info header, e.g. "api-usage=25/15000".
+func (throttle *apiThrottle) observe(limitInfo string) {
+	used, max, ok := parseAPIUsage(limitInfo)
+	if !ok {
+		return
+	}
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	throttle.used, throttle.max = used, max
+}
+
+// parseAPIUsage parses the api-usage entry of a Sforce-Limit-Info header, which may also list per-app usage, e.g.
+// "api-usage=25/15000, per-app-api-usage=17/250(appName=sample-app)".
+func parseAPIUsage(limitInfo string) (used, max int, ok bool) {
+	for _, entry := range strings.Split(limitInfo, ",") {
+		value := strings.TrimPrefix(strings.TrimSpace(entry), "api-usage=")
+		if value == strings.TrimSpace(entry) {
+			continue
+		}
+		parts := strings.SplitN(value, "/", 2)
+		if len(parts) != 2 {
+			return 0, 0, false
+		}
+		used, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return 0, 0, false
+		}
+		max, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, 0, false
+		}
+		return used, max, true
+	}
+	return 0, 0, false
+}
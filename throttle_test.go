@@ -0,0 +1,75 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseAPIUsage(t *testing.T) {
+	for limitInfo, expected := range map[string][2]int{
+		"api-usage=25/15000": {25, 15000},
+		"per-app-api-usage=17/250(appName=sample-app), api-usage=18/5000": {18, 5000},
+		"":                 {0, 0},
+		"api-usage=bogus":  {0, 0},
+		"api-usage=1/many": {0, 0},
+	} {
+		used, max, ok := parseAPIUsage(limitInfo)
+		if used != expected[0] || max != expected[1] || ok != (expected[1] > 0) {
+			t.Errorf("unexpected usage %d/%d (%t) of %q", used, max, ok, limitInfo)
+		}
+	}
+}
+
+func TestLinearThrottle(t *testing.T) {
+	curve := LinearThrottle(0.8, 10*time.Second)
+	for usage, expected := range map[float64]time.Duration{
+		0.5: 0,
+		0.8: 0,
+		0.9: 5 * time.Second,
+		1:   10 * time.Second,
+		1.2: 10 * time.Second,
+	} {
+		if delay := curve(usage); delay < expected-time.Millisecond || delay > expected+time.Millisecond {
+			t.Errorf("expected %s at %v, got %s", expected, usage, delay)
+		}
+	}
+}
+
+func TestClient_SetAPIUsageThrottle(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Sforce-Limit-Info", "api-usage=900/1000")
+		w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	})
+	var usages []float64
+	client.SetAPIUsageThrottle(func(usage float64) time.Duration {
+		usages = append(usages, usage)
+		return time.Millisecond
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Query("SELECT Id FROM Account"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(usages) != 1 || usages[0] != 0.9 {
+		t.Errorf("expected the second request to be throttled at 0.9, got %v", usages)
+	}
+	if used, max := client.APIUsage(); used != 900 || max != 1000 {
+		t.Errorf("unexpected usage %d/%d", used, max)
+	}
+
+	client.SetAPIUsageThrottle(LinearThrottle(0, time.Hour))
+	client.currentThrottle().observe("api-usage=1000/1000")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.currentThrottle().wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+
+	client.SetAPIUsageThrottle(nil)
+	if used, max := client.APIUsage(); used != 0 || max != 0 {
+		t.Errorf("expected no usage without throttling, got %d/%d", used, max)
+	}
+}
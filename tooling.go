@@ -1,6 +1,7 @@
 package simpleforce
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -28,6 +29,67 @@ func (client *Client) UnTooling() {
 	client.useToolingAPI = false
 }
 
+// toolingQuery runs an SOQL query against the Tooling API without switching the client to it, returning only the first
+// page of results.
+func (client *Client) toolingQuery(soql string) (*QueryResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	data, err := client.httpRequest("GET", client.makeURL("tooling/query?q="+url.QueryEscape(soql)), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.decodeQueryResult(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for idx := range result.Records {
+		result.Records[idx].setClient(client)
+	}
+	return result, nil
+}
+
+// toolingQueryPages runs an SOQL query against the Tooling API without switching the client to it, calling page with
+// the raw JSON records array of every page of results.
+func (client *Client) toolingQueryPages(soql string, page func(records []byte) error) error {
+	return client.queryPages("tooling/query", soql, page)
+}
+
+// queryPages runs an SOQL query against the query resource path, calling page with the raw JSON records array of every
+// page of results.
+func (client *Client) queryPages(path, soql string, page func(records []byte) error) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+
+	u := client.makeURL(path + "?q=" + url.QueryEscape(soql))
+	for u != "" {
+		data, err := client.httpRequest("GET", u, nil)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Records        json.RawMessage `json:"records"`
+			NextRecordsURL string          `json:"nextRecordsUrl"`
+		}
+		err = client.unmarshalJSON(data, &result)
+		if err != nil {
+			return err
+		}
+		err = page(result.Records)
+		if err != nil {
+			return err
+		}
+
+		u = ""
+		if result.NextRecordsURL != "" {
+			u = client.resolveURL(result.NextRecordsURL)
+		}
+	}
+	return nil
+}
+
 // ExecuteAnonymous executes a body of Apex code
 func (client *Client) ExecuteAnonymous(apexBody string) (*ExecuteAnonymousResult, error) {
 	if !client.isLoggedIn() {
@@ -36,7 +98,7 @@ func (client *Client) ExecuteAnonymous(apexBody string) (*ExecuteAnonymousResult
 
 	// Create the endpoint
 	formatString := "%s/services/data/v%s/tooling/executeAnonymous/?anonymousBody=%s"
-	baseURL := client.instanceURL
+	baseURL := client.servicesURL()
 	endpoint := fmt.Sprintf(formatString, baseURL, client.apiVersion, url.QueryEscape(apexBody))
 
 	data, err := client.httpRequest("GET", endpoint, nil)
@@ -46,7 +108,7 @@ func (client *Client) ExecuteAnonymous(apexBody string) (*ExecuteAnonymousResult
 	}
 
 	var result ExecuteAnonymousResult
-	err = json.Unmarshal(data, &result)
+	err = client.unmarshalJSON(data, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,236 @@
+package simpleforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxCompositeSubrequests is the maximum number of subrequests of a single composite request.
+const maxCompositeSubrequests = 25
+
+// ErrTransactionRolledBack matches a TransactionError.
+var ErrTransactionRolledBack = errors.New("transaction rolled back")
+
+// Transaction accumulates DML operations which are committed together with a single composite request with allOrNone
+// set, so either all of them are applied or, if any fails, none. A Transaction is created with Client.Transaction. The
+// ID of a record created earlier in the transaction can be used as a field value of a later operation through the
+// reference returned by Create, e.g. to create an account and its contacts together. Big objects are not
+// transactional and cannot be written in a Transaction.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_composite_composite.htm
+type Transaction struct {
+	client      *Client
+	subrequests []compositeSubrequest
+	records     []*SObject
+	err         error
+}
+
+// TransactionResult is the outcome of an operation of a committed Transaction. ID is the ID of the record created or
+// upserted, Created tells whether an upsert inserted the record.
+type TransactionResult struct {
+	ReferenceID    string
+	HTTPStatusCode int
+	ID             string
+	Created        bool
+}
+
+// TransactionError reports a rolled back Transaction. Index and ReferenceID identify the operation which failed and
+// Errors tells why; all other operations were rolled back as well. A TransactionError matches ErrTransactionRolledBack
+// with errors.Is.
+type TransactionError struct {
+	Index       int
+	ReferenceID string
+	Errors      []SaveError
+}
+
+// Error implements the error interface.
+func (err *TransactionError) Error() string {
+	msgs := make([]string, 0, len(err.Errors))
+	for _, saveErr := range err.Errors {
+		msgs = append(msgs, saveErr.Error())
+	}
+	return fmt.Sprintf("transaction rolled back, operation %d (%s) failed: %s",
+		err.Index, err.ReferenceID, strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is ErrTransactionRolledBack.
+func (err *TransactionError) Is(target error) bool {
+	return target == ErrTransactionRolledBack
+}
+
+type compositeSubrequest struct {
+	Method      string                 `json:"method"`
+	URL         string                 `json:"url"`
+	ReferenceID string                 `json:"referenceId"`
+	Body        map[string]interface{} `json:"body,omitempty"`
+}
+
+// Transaction starts a new Transaction.
+func (client *Client) Transaction() *Transaction {
+	return &Transaction{client: client}
+}
+
+// Create adds the creation of obj to the transaction and returns a reference to the ID of the new record, which can be
+// set as the value of lookup fields of records created or updated later in the transaction. The ID is set on obj when
+// the transaction is committed.
+func (tx *Transaction) Create(obj *SObject) string {
+	ref := tx.add(obj, "create", obj.Type(), http.MethodPost, "sobjects/"+url.PathEscape(obj.Type()), obj.makeCopy())
+	return "@{" + ref + ".id}"
+}
+
+// Update adds the update of obj, which must have its ID set, to the transaction.
+func (tx *Transaction) Update(obj *SObject) {
+	if obj.ID() == "" && tx.err == nil {
+		tx.err = fmt.Errorf("update of %s without ID", obj.Type())
+	}
+	apiPath := "sobjects/" + url.PathEscape(obj.Type()) + "/" + url.PathEscape(obj.ID())
+	tx.add(obj, "update", obj.Type(), http.MethodPatch, apiPath, obj.makeCopy())
+}
+
+// Upsert adds the upsert of obj by its external ID, see SObject.Upsert, to the transaction. The ID is set on obj when
+// the transaction is committed.
+func (tx *Transaction) Upsert(obj *SObject) {
+	if (obj.ExternalIDFieldName() == "" || obj.ExternalID() == "") && tx.err == nil {
+		tx.err = fmt.Errorf("upsert of %s without external ID", obj.Type())
+	}
+	apiPath := "sobjects/" + url.PathEscape(obj.Type()) + "/" + url.PathEscape(obj.ExternalIDFieldName()) + "/" +
+		url.PathEscape(obj.ExternalID())
+	tx.add(obj, "upsert", obj.Type(), http.MethodPatch, apiPath, obj.makeCopy())
+}
+
+// Delete adds the deletion of the record of objectType with id to the transaction.
+func (tx *Transaction) Delete(objectType, id string) {
+	apiPath := "sobjects/" + url.PathEscape(objectType) + "/" + url.PathEscape(id)
+	tx.add(nil, "delete", objectType, http.MethodDelete, apiPath, nil)
+}
+
+// Len returns the number of operations in the transaction.
+func (tx *Transaction) Len() int {
+	return len(tx.subrequests)
+}
+
+// add appends an operation on a record of objectType, obj if not nil, and returns its reference ID.
+func (tx *Transaction) add(
+	obj *SObject,
+	operation, objectType, method, apiPath string,
+	body map[string]interface{},
+) string {
+	ref := fmt.Sprintf("op%d", len(tx.subrequests))
+	if err := checkBigObjectWrite(objectType, operation); err != nil && tx.err == nil {
+		tx.err = err
+	}
+	tx.subrequests = append(tx.subrequests, compositeSubrequest{
+		Method:      method,
+		URL:         fmt.Sprintf("%s/services/data/v%s/%s", tx.client.sitePrefix, tx.apiVersion(), apiPath),
+		ReferenceID: ref,
+		Body:        body,
+	})
+	tx.records = append(tx.records, obj)
+	return ref
+}
+
+func (tx *Transaction) apiVersion() string {
+	return strings.TrimPrefix(tx.client.apiVersion, "v")
+}
+
+// Commit sends the operations of the transaction with a single composite request. The results are aligned with the
+// operations. If any operation fails, salesforce rolls back all of them and a *TransactionError is returned for the
+// failed operation. A transaction holds at most 25 operations.
+func (tx *Transaction) Commit() ([]TransactionResult, error) {
+	if tx.err != nil {
+		return nil, tx.err
+	}
+	if !tx.client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	if len(tx.subrequests) == 0 {
+		return nil, nil
+	}
+	if len(tx.subrequests) > maxCompositeSubrequests {
+		return nil, fmt.Errorf("transaction of %d operations exceeds the limit of %d",
+			len(tx.subrequests), maxCompositeSubrequests)
+	}
+
+	reqData, err := tx.client.marshalJSON(map[string]interface{}{
+		"allOrNone":        true,
+		"compositeRequest": tx.subrequests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/services/data/v%s/composite", tx.client.servicesURL(), tx.apiVersion())
+	data, err := tx.client.httpRequest(http.MethodPost, u, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		CompositeResponse []struct {
+			Body           json.RawMessage `json:"body"`
+			HTTPStatusCode int             `json:"httpStatusCode"`
+			ReferenceID    string          `json:"referenceId"`
+		} `json:"compositeResponse"`
+	}
+	err = tx.client.unmarshalJSON(data, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.CompositeResponse) != len(tx.subrequests) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(tx.subrequests), len(response.CompositeResponse))
+	}
+
+	results := make([]TransactionResult, 0, len(tx.subrequests))
+	var rolledBack, halted *TransactionError
+	for idx, sub := range response.CompositeResponse {
+		result := TransactionResult{ReferenceID: sub.ReferenceID, HTTPStatusCode: sub.HTTPStatusCode}
+		if sub.HTTPStatusCode < 200 || sub.HTTPStatusCode > 299 {
+			var errs []struct {
+				ErrorCode string   `json:"errorCode"`
+				Message   string   `json:"message"`
+				Fields    []string `json:"fields"`
+			}
+			tx.client.unmarshalJSON(sub.Body, &errs)
+			saveErrs := make([]SaveError, 0, len(errs))
+			isHalted := false
+			for _, e := range errs {
+				isHalted = isHalted || e.ErrorCode == "PROCESSING_HALTED"
+				saveErrs = append(saveErrs, SaveError{StatusCode: e.ErrorCode, Message: e.Message, Fields: e.Fields})
+			}
+			failure := &TransactionError{Index: idx, ReferenceID: sub.ReferenceID, Errors: saveErrs}
+			// Operations halted because of the failure of another one do not explain the rollback.
+			if isHalted && halted == nil {
+				halted = failure
+			} else if !isHalted && rolledBack == nil {
+				rolledBack = failure
+			}
+		} else if len(sub.Body) > 0 {
+			var body struct {
+				ID      string `json:"id"`
+				Created bool   `json:"created"`
+			}
+			if tx.client.unmarshalJSON(sub.Body, &body) == nil {
+				result.ID = body.ID
+				result.Created = body.Created || sub.HTTPStatusCode == http.StatusCreated
+			}
+		}
+		results = append(results, result)
+	}
+	if rolledBack == nil {
+		rolledBack = halted
+	}
+	if rolledBack != nil {
+		return results, rolledBack
+	}
+
+	for idx, result := range results {
+		if obj := tx.records[idx]; obj != nil && result.ID != "" {
+			obj.setID(result.ID)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,109 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/v54.0/composite" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			AllOrNone        bool                  `json:"allOrNone"`
+			CompositeRequest []compositeSubrequest `json:"compositeRequest"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		subs := body.CompositeRequest
+		if !body.AllOrNone || len(subs) != 4 || subs[0].URL != "/services/data/v54.0/sobjects/Account" ||
+			subs[1].Body["AccountId"] != "@{op0.id}" || subs[2].URL != "/services/data/v54.0/sobjects/Contact/Ext__c/E1" ||
+			subs[3].Method != http.MethodDelete || subs[3].Body != nil {
+			t.Errorf("unexpected subrequests %+v", body)
+		}
+		w.Write([]byte(`{"compositeResponse": [
+			{"body": {"id": "001A", "success": true, "errors": []}, "httpStatusCode": 201, "referenceId": "op0"},
+			{"body": {"id": "003A", "success": true, "errors": []}, "httpStatusCode": 201, "referenceId": "op1"},
+			{"body": {"id": "003B", "success": true, "created": false}, "httpStatusCode": 200, "referenceId": "op2"},
+			{"body": null, "httpStatusCode": 204, "referenceId": "op3"}]}`))
+	})
+
+	tx := client.Transaction()
+	account := client.SObject("Account").Set("Name", "Acme")
+	ref := tx.Create(account)
+	contact := client.SObject("Contact").Set("LastName", "Smith").Set("AccountId", ref)
+	tx.Create(contact)
+	upserted := client.SObject("Contact").Set("LastName", "Jones").Set("Ext__c", "E1")
+	upserted.Set(sobjectExternalIDFieldNameKey, "Ext__c")
+	tx.Upsert(upserted)
+	tx.Delete("Case", "500A")
+
+	results, err := tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 || !results[0].Created || results[2].Created || results[3].HTTPStatusCode != 204 {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if account.ID() != "001A" || contact.ID() != "003A" || upserted.ID() != "003B" {
+		t.Errorf("expected IDs to be set, got %s %s %s", account.ID(), contact.ID(), upserted.ID())
+	}
+}
+
+func TestTransaction_CommitRolledBack(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"compositeResponse": [
+			{"body": [{"errorCode": "PROCESSING_HALTED", "message": "The transaction was rolled back since another operation in the same transaction failed."}],
+				"httpStatusCode": 400, "referenceId": "op0"},
+			{"body": [{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [LastName]", "fields": ["LastName"]}],
+				"httpStatusCode": 400, "referenceId": "op1"}]}`))
+	})
+
+	tx := client.Transaction()
+	account := client.SObject("Account").Set("Name", "Acme")
+	tx.Create(account)
+	tx.Create(client.SObject("Contact"))
+
+	results, err := tx.Commit()
+	var txErr *TransactionError
+	if !errors.Is(err, ErrTransactionRolledBack) || !errors.As(err, &txErr) {
+		t.Fatalf("expected TransactionError, got %v", err)
+	}
+	if txErr.Index != 1 || txErr.ReferenceID != "op1" || txErr.Errors[0].StatusCode != "REQUIRED_FIELD_MISSING" ||
+		txErr.Errors[0].Fields[0] != "LastName" {
+		t.Errorf("unexpected error %+v", txErr)
+	}
+	if len(results) != 2 || account.ID() != "" {
+		t.Errorf("expected no IDs to be set, got %v, %s", results, account.ID())
+	}
+}
+
+func TestTransaction_CommitInvalid(t *testing.T) {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+
+	tx := client.Transaction()
+	tx.Update(client.SObject("Account").Set("Name", "No ID"))
+	if _, err := tx.Commit(); err == nil {
+		t.Error("expected an error for an update without ID")
+	}
+
+	tx = client.Transaction()
+	for i := 0; i <= maxCompositeSubrequests; i++ {
+		tx.Delete("Account", "001A")
+	}
+	if _, err := tx.Commit(); err == nil || tx.Len() != 26 {
+		t.Error("expected an error for too many operations")
+	}
+
+	// Big objects cannot take part in transactions, not even for inserts.
+	tx = client.Transaction()
+	tx.Create(client.SObject("Customer_Interaction__b").Set("Name", "x"))
+	if _, err := tx.Commit(); !errors.Is(err, ErrBigObjectUnsupported) {
+		t.Errorf("expected ErrBigObjectUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package simpleforce
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Truncation reports a string value shortened by WithFieldTruncation. Index is the position of the record in the
+// input, Value the original value and Length the maximum length of the field in characters.
+type Truncation struct {
+	Index  int
+	Type   string
+	Field  string
+	Length int
+	Value  string
+}
+
+// truncateRecords returns records with string values longer than their fields allow cut to the field length, like
+// salesforce does for SOAP requests with the AllowFieldTruncationHeader. Records with truncated values are copied, the
+// others are returned as is. onTruncate, if not nil, is called for every truncated value in input order.
+func (client *Client) truncateRecords(
+	objectType string,
+	records []*SObject,
+	onTruncate func(Truncation),
+) ([]*SObject, error) {
+	recordTypes, metas, err := client.describeRecords(objectType, records)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := make([]*SObject, len(records))
+	copy(truncated, records)
+	for idx, record := range records {
+		fields, _ := indexFields(metas[recordTypes[idx]])
+		payload := record.makeCopy()
+		keys := make([]string, 0, len(payload))
+		for key := range payload {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var copied SObject
+		for _, key := range keys {
+			s, ok := payload[key].(string)
+			field := fields[strings.ToLower(key)]
+			if !ok || field == nil {
+				continue
+			}
+			fieldType, _ := field["type"].(string)
+			length, _ := field["length"].(float64)
+			if !textFieldTypes[fieldType] || length <= 0 || utf8.RuneCountInString(s) <= int(length) {
+				continue
+			}
+
+			if copied == nil {
+				copied = make(SObject, len(*record))
+				for k, v := range *record {
+					copied[k] = v
+				}
+				truncated[idx] = &copied
+			}
+			copied[key] = string([]rune(s)[:int(length)])
+			if onTruncate != nil {
+				onTruncate(Truncation{Index: idx, Type: recordTypes[idx], Field: key, Length: int(length), Value: s})
+			}
+		}
+	}
+	return truncated, nil
+}
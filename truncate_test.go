@@ -0,0 +1,31 @@
+package simpleforce
+
+import (
+	"testing"
+)
+
+func TestClient_CreateAllWithFieldTruncation(t *testing.T) {
+	var sent []interface{}
+	client := newValidateClient(t, &sent)
+
+	long := client.SObject("Account").Set("Name", "Ünïcödé Corporation").Set("Description", "short")
+	short := client.SObject("Account").Set("Name", "Acme")
+	var truncations []Truncation
+	results, err := client.CreateAll([]*SObject{short, long}, WithFieldTruncation(func(truncation Truncation) {
+		truncations = append(truncations, truncation)
+	}), WithValidation(true))
+	if err != nil || !results[1].Success {
+		t.Fatalf("unexpected results %v, %v", results, err)
+	}
+
+	if name := sent[1].(map[string]interface{})["Name"]; name != "Ünïcödé Co" {
+		t.Errorf("expected the name to be cut to 10 characters, got %q", name)
+	}
+	if len(truncations) != 1 || truncations[0].Index != 1 || truncations[0].Field != "Name" ||
+		truncations[0].Length != 10 || truncations[0].Value != "Ünïcödé Corporation" || truncations[0].Type != "Account" {
+		t.Errorf("unexpected truncations %v", truncations)
+	}
+	if long.StringField("Name") != "Ünïcödé Corporation" {
+		t.Error("expected the record passed in to be left unchanged")
+	}
+}
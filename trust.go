@@ -0,0 +1,153 @@
+package simpleforce
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// statusAPIURL is the base URL of the Salesforce Trust status API.
+var statusAPIURL = "https://api.status.salesforce.com/v1"
+
+// instanceNameRegexp matches the names of salesforce instances, e.g. "NA123", "CS42" or "USA2S".
+var instanceNameRegexp = regexp.MustCompile(`(?i)^[a-z]+\d+[a-z]?$`)
+
+// InstanceStatus is the status of a salesforce instance as published on status.salesforce.com.
+// Ref: https://api.status.salesforce.com/v1/docs/
+type InstanceStatus struct {
+	Key            string                `json:"key"`
+	Location       string                `json:"location"`
+	Environment    string                `json:"environment"`
+	ReleaseVersion string                `json:"releaseVersion"`
+	Status         string                `json:"status"` // e.g. "OK", "MAJOR_INCIDENT_CORE" or "MAINTENANCE_CORE"
+	IsActive       bool                  `json:"isActive"`
+	Incidents      []InstanceIncident    `json:"Incidents"`
+	Maintenances   []InstanceMaintenance `json:"Maintenances"`
+}
+
+// OK tells whether the instance has no incident or maintenance in progress.
+func (status *InstanceStatus) OK() bool {
+	return status.Status == "OK"
+}
+
+// InstanceIncident is an incident affecting an instance.
+type InstanceIncident struct {
+	ID              int              `json:"id"`
+	IsCore          bool             `json:"isCore"`
+	AffectsAll      bool             `json:"affectsAll"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+	IncidentImpacts []IncidentImpact `json:"IncidentImpacts"`
+	IncidentEvents  []IncidentEvent  `json:"IncidentEvents"`
+}
+
+// IncidentImpact is the impact of an incident, e.g. a "performanceDegradation" of "minor" severity. EndTime is nil
+// while the impact lasts.
+type IncidentImpact struct {
+	Type      string     `json:"type"`
+	Severity  string     `json:"severity"`
+	StartTime time.Time  `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+}
+
+// IncidentEvent is an update posted on an incident.
+type IncidentEvent struct {
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// InstanceMaintenance is a planned maintenance of an instance.
+type InstanceMaintenance struct {
+	ID               int       `json:"id"`
+	Name             string    `json:"name"`
+	IsCore           bool      `json:"isCore"`
+	PlannedStartTime time.Time `json:"plannedStartTime"`
+	PlannedEndTime   time.Time `json:"plannedEndTime"`
+	Message          struct {
+		EventStatus     string `json:"eventStatus"`
+		MaintenanceType string `json:"maintenanceType"`
+		Availability    string `json:"availability"`
+	} `json:"message"`
+}
+
+// InstanceName returns the name of the salesforce instance of the org, e.g. "NA123". It is derived from the instance
+// URL where possible; URLs of My Domains without the instance name, e.g. "https://acme.my.salesforce.com", require a
+// query of the Organization.
+func (client *Client) InstanceName() (string, error) {
+	if name := instanceNameFromURL(client.instanceURL); name != "" {
+		return name, nil
+	}
+
+	result, err := client.Query("SELECT InstanceName FROM Organization")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", fmt.Errorf("organization not found")
+	}
+	return result.Records[0].StringField("InstanceName"), nil
+}
+
+// instanceNameFromURL returns the instance name encoded in the host of instanceURL, e.g. "NA123" for
+// "https://na123.salesforce.com" or "https://acme--dev.cs42.my.salesforce.com", or an empty string.
+func instanceNameFromURL(instanceURL string) string {
+	u, err := url.Parse(instanceURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	var label string
+	switch {
+	case strings.HasSuffix(host, ".my.salesforce.com"):
+		// The instance follows the My Domain name in legacy URLs.
+		labels := strings.Split(strings.TrimSuffix(host, ".my.salesforce.com"), ".")
+		if len(labels) == 2 {
+			label = labels[1]
+		}
+	case strings.HasSuffix(host, ".salesforce.com"):
+		label = strings.TrimSuffix(host, ".salesforce.com")
+	}
+	if !instanceNameRegexp.MatchString(label) {
+		return ""
+	}
+	return strings.ToUpper(label)
+}
+
+// InstanceStatus fetches the status of the instance of the org from status.salesforce.com, e.g. to tell an incident or
+// maintenance of salesforce from a bug of the client when errors spike. The Trust API does not require the session.
+func (client *Client) InstanceStatus() (*InstanceStatus, error) {
+	name, err := client.InstanceName()
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s/instances/%s/status?childProducts=false", statusAPIURL, url.PathEscape(name))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	// Send the request directly so that it is not audited or redirected like requests to the org.
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := client.readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status of instance %s: %s", name, resp.Status)
+	}
+	var status InstanceStatus
+	err = client.unmarshalJSON(data, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
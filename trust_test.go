@@ -0,0 +1,61 @@
+package simpleforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstanceNameFromURL(t *testing.T) {
+	for instanceURL, expected := range map[string]string{
+		"https://na123.salesforce.com":                "NA123",
+		"https://cs42.salesforce.com/":                "CS42",
+		"https://acme--dev.cs42.my.salesforce.com":    "CS42",
+		"https://acme.my.salesforce.com":              "",
+		"https://acme2.my.salesforce.com":             "",
+		"https://login.salesforce.com":                "",
+		"https://acme.lightning.force.com":            "",
+		"https://acme--dev.sandbox.my.salesforce.com": "",
+		"https://usa2s.salesforce.com":                "USA2S",
+		"http://127.0.0.1:8080":                       "",
+	} {
+		if name := instanceNameFromURL(instanceURL); name != expected {
+			t.Errorf("expected %q for %s, got %q", expected, instanceURL, name)
+		}
+	}
+}
+
+func TestClient_InstanceStatus(t *testing.T) {
+	status := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/instances/NA123/status" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"key": "NA123", "location": "NA", "environment": "production",
+			"releaseVersion": "Summer '22 Patch 10", "status": "MINOR_INCIDENT_CORE", "isActive": true,
+			"Incidents": [{"id": 1234, "isCore": true, "affectsAll": false,
+				"createdAt": "2022-07-01T10:00:00.000Z", "updatedAt": "2022-07-01T10:30:00.000Z",
+				"IncidentImpacts": [{"type": "performanceDegradation", "severity": "minor",
+					"startTime": "2022-07-01T09:45:00.000Z", "endTime": null}],
+				"IncidentEvents": [{"message": "Investigating", "createdAt": "2022-07-01T10:00:00.000Z"}]}],
+			"Maintenances": []}`))
+	}))
+	defer status.Close()
+	defer func(u string) { statusAPIURL = u }(statusAPIURL)
+	statusAPIURL = status.URL + "/v1"
+
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); q != "SELECT InstanceName FROM Organization" {
+			t.Errorf("unexpected query %s", q)
+		}
+		w.Write([]byte(`{"totalSize": 1, "done": true, "records": [{"InstanceName": "NA123"}]}`))
+	})
+
+	result, err := client.InstanceStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.OK() || result.Key != "NA123" || len(result.Incidents) != 1 ||
+		result.Incidents[0].IncidentImpacts[0].EndTime != nil || result.Incidents[0].IncidentEvents[0].Message != "Investigating" {
+		t.Errorf("unexpected status %+v", result)
+	}
+}
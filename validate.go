@@ -0,0 +1,270 @@
+package simpleforce
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+// textFieldTypes are the describe field types whose values are limited to the length of the field.
+var textFieldTypes = map[string]bool{
+	"string":          true,
+	"textarea":        true,
+	"email":           true,
+	"phone":           true,
+	"url":             true,
+	"picklist":        true,
+	"multipicklist":   true,
+	"combobox":        true,
+	"encryptedstring": true,
+}
+
+// validateRecords checks records against the cached describe metadata of their objects before they are sent with
+// operation, "create", "update" or "upsert", and returns the errors found by record index. Unknown fields, fields which
+// are not createable or updateable, missing required fields and too long strings are reported with the status codes
+// salesforce would use. objectType overrides the types of the records if not empty, e.g. for upserts.
+func (client *Client) validateRecords(operation, objectType string, records []*SObject) (map[int][]SaveError, error) {
+	return client.checkRecords(objectType, records, func(recordType string, meta *SObjectMeta, record *SObject) []SaveError {
+		return validateRecord(operation, recordType, meta, record)
+	})
+}
+
+// checkRecords describes the objects of records and returns the errors reported by check by record index. objectType
+// overrides the types of the records if not empty.
+func (client *Client) checkRecords(
+	objectType string,
+	records []*SObject,
+	check func(recordType string, meta *SObjectMeta, record *SObject) []SaveError,
+) (map[int][]SaveError, error) {
+	recordTypes, metas, err := client.describeRecords(objectType, records)
+	if err != nil {
+		return nil, err
+	}
+
+	invalid := map[int][]SaveError{}
+	for idx, record := range records {
+		if errs := check(recordTypes[idx], metas[recordTypes[idx]], record); len(errs) > 0 {
+			invalid[idx] = errs
+		}
+	}
+	return invalid, nil
+}
+
+// describeRecords returns the type of each record, objectType if not empty, and the describe metadata of the types.
+func (client *Client) describeRecords(objectType string, records []*SObject) ([]string, map[string]*SObjectMeta, error) {
+	recordTypes := make([]string, 0, len(records))
+	var types []string
+	seen := map[string]bool{}
+	for _, record := range records {
+		recordType := objectType
+		if recordType == "" {
+			recordType = record.Type()
+		}
+		if recordType == "" {
+			return nil, nil, fmt.Errorf("record without type")
+		}
+		recordTypes = append(recordTypes, recordType)
+		if !seen[recordType] {
+			seen[recordType] = true
+			types = append(types, recordType)
+		}
+	}
+	metas, err := client.DescribeSObjects(types...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return recordTypes, metas, nil
+}
+
+// validateRecord checks a single record against the describe metadata of its object.
+func validateRecord(operation, objectType string, meta *SObjectMeta, record *SObject) []SaveError {
+	fields, relationships := indexFields(meta)
+	rawFields, _ := (*meta)["fields"].([]interface{})
+
+	payload := record.makeCopy()
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		if key != sobjectAttributesKey && key != sobjectIDKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var errs []SaveError
+	var unknown, readOnly []string
+	present := map[string]bool{}
+	for _, key := range keys {
+		value := payload[key]
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			if _, isParent := value.(map[string]interface{}); isParent && relationships[strings.ToLower(key)] {
+				// The parent is referenced by an external ID, which salesforce resolves.
+				continue
+			}
+			unknown = append(unknown, key)
+			continue
+		}
+		present[strings.ToLower(key)] = value != nil
+
+		if !fieldWritable(operation, field) {
+			readOnly = append(readOnly, key)
+		}
+
+		fieldType, _ := field["type"].(string)
+		length, _ := field["length"].(float64)
+		if s, isString := value.(string); isString && textFieldTypes[fieldType] && length > 0 &&
+			utf8.RuneCountInString(s) > int(length) {
+			errs = append(errs, SaveError{
+				StatusCode: errcode.StringTooLong,
+				Message:    fmt.Sprintf("%s: data value too large: %s (max length=%d)", key, s, int(length)),
+				Fields:     []string{key},
+			})
+		}
+	}
+
+	if operation == "update" && record.ID() == "" {
+		errs = append(errs, SaveError{StatusCode: errcode.MissingArgument, Message: "Id not specified in an update call"})
+	}
+	if len(unknown) > 0 {
+		errs = append(errs, SaveError{
+			StatusCode: errcode.InvalidField,
+			Message:    fmt.Sprintf("No such column '%s' on sobject of type %s", strings.Join(unknown, "', '"), objectType),
+			Fields:     unknown,
+		})
+	}
+	if len(readOnly) > 0 {
+		errs = append(errs, SaveError{
+			StatusCode: errcode.InvalidFieldForInsertUpdate,
+			Message:    fmt.Sprintf("Unable to create/update fields: %s", strings.Join(readOnly, ", ")),
+			Fields:     readOnly,
+		})
+	}
+	if operation == "create" {
+		var missing []string
+		for _, rawField := range rawFields {
+			field, _ := rawField.(map[string]interface{})
+			name, _ := field["name"].(string)
+			if requiredOnCreate(field) && !present[strings.ToLower(name)] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, SaveError{
+				StatusCode: errcode.RequiredFieldMissing,
+				Message:    fmt.Sprintf("Required fields are missing: [%s]", strings.Join(missing, ", ")),
+				Fields:     missing,
+			})
+		}
+	}
+	return errs
+}
+
+// indexFields indexes the fields of an object by lower case name, and the relationship names of its reference fields.
+func indexFields(meta *SObjectMeta) (fields map[string]map[string]interface{}, relationships map[string]bool) {
+	fields = map[string]map[string]interface{}{}
+	relationships = map[string]bool{}
+	rawFields, _ := (*meta)["fields"].([]interface{})
+	for _, rawField := range rawFields {
+		field, ok := rawField.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		fields[strings.ToLower(name)] = field
+		if relationship, _ := field["relationshipName"].(string); relationship != "" {
+			relationships[strings.ToLower(relationship)] = true
+		}
+	}
+	return fields, relationships
+}
+
+// fieldWritable reports whether a field may be set by operation, "create", "update" or "upsert".
+func fieldWritable(operation string, field map[string]interface{}) bool {
+	createable, _ := field["createable"].(bool)
+	updateable, _ := field["updateable"].(bool)
+	switch operation {
+	case "create":
+		return createable
+	case "update":
+		return updateable
+	default:
+		return createable || updateable
+	}
+}
+
+// requiredOnCreate reports whether a value must be given for a field when a record is created.
+func requiredOnCreate(field map[string]interface{}) bool {
+	createable, _ := field["createable"].(bool)
+	nillable, _ := field["nillable"].(bool)
+	defaulted, _ := field["defaultedOnCreate"].(bool)
+	fieldType, _ := field["type"].(string)
+	return createable && !nillable && !defaulted && fieldType != "boolean"
+}
+
+// saveValidated saves the records which passed validation, aligning the results with records. Invalid records fail
+// with their validation errors and are not sent. With allOrNone, nothing is sent if any record is invalid; with dry
+// run, nothing is sent at all and the valid records are reported as successful.
+func (client *Client) saveValidated(
+	records []*SObject,
+	invalid map[int][]SaveError,
+	save func([]*SObject, bool) ([]SaveResult, error),
+	options *batchOptions,
+) ([]SaveResult, error) {
+	results := make([]SaveResult, len(records))
+	var failures []*RecordError
+	var valid []int
+	for idx, record := range records {
+		if errs, ok := invalid[idx]; ok {
+			results[idx] = SaveResult{ID: record.ID(), Errors: errs}
+			failures = append(failures, &RecordError{Index: idx, ID: record.ID(), Errors: errs})
+			continue
+		}
+		valid = append(valid, idx)
+	}
+
+	switch {
+	case options.dryRun:
+		for _, idx := range valid {
+			results[idx] = SaveResult{ID: records[idx].ID(), Success: true}
+		}
+	case options.allOrNone && len(failures) > 0:
+		rolledBack := []SaveError{{
+			StatusCode: errcode.AllOrNoneOperationRolledBack,
+			Message:    "Record rolled back because not all records were valid and the request was using AllOrNone header",
+		}}
+		for _, idx := range valid {
+			results[idx] = SaveResult{ID: records[idx].ID(), Errors: rolledBack}
+			failures = append(failures, &RecordError{Index: idx, ID: records[idx].ID(), Errors: rolledBack})
+		}
+	default:
+		pending := make([]*SObject, 0, len(valid))
+		for _, idx := range valid {
+			pending = append(pending, records[idx])
+		}
+		pendingResults, pendingFailures, _ := saveChunks(len(pending), 0,
+			func(idx int) string { return pending[idx].ID() },
+			func(start, end int) ([]SaveResult, error) {
+				return save(pending[start:end], options.allOrNone)
+			},
+			options,
+			func(end int) {
+				if options.progress != nil {
+					options.progress(end, len(pending))
+				}
+			},
+		)
+		for idx, saveResult := range pendingResults {
+			results[valid[idx]] = saveResult
+		}
+		for _, failure := range pendingFailures {
+			failure.Index = valid[failure.Index]
+			failures = append(failures, failure)
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+	return results, newBatchError(failures)
+}
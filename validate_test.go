@@ -0,0 +1,99 @@
+package simpleforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scottraio/simpleforce/errcode"
+)
+
+const validateDescribe = `{"name": "Account", "fields": [
+	{"name": "Id", "type": "id", "createable": false, "updateable": false, "nillable": false, "defaultedOnCreate": true},
+	{"name": "Name", "type": "string", "length": 10, "createable": true, "updateable": true, "nillable": false},
+	{"name": "Description", "type": "textarea", "length": 32000, "createable": true, "updateable": true, "nillable": true},
+	{"name": "IsDeleted", "type": "boolean", "createable": false, "updateable": false, "nillable": false},
+	{"name": "Score__c", "type": "double", "calculated": true, "createable": false, "updateable": false, "nillable": true},
+	{"name": "CreatedDate", "type": "datetime", "createable": false, "updateable": false, "nillable": false,
+		"defaultedOnCreate": true},
+	{"name": "ParentId", "type": "reference", "relationshipName": "Parent", "createable": true, "updateable": true,
+		"nillable": true}
+]}`
+
+func newValidateClient(t *testing.T, sent *[]interface{}) *Client {
+	client, _ := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/describe") {
+			w.Write([]byte(validateDescribe))
+			return
+		}
+		var body struct {
+			Records []interface{} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		*sent = append(*sent, body.Records...)
+		var results []SaveResult
+		for range body.Records {
+			results = append(results, SaveResult{ID: "001NEW", Success: true})
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+	return client
+}
+
+func TestClient_CreateAllWithValidation(t *testing.T) {
+	var sent []interface{}
+	client := newValidateClient(t, &sent)
+
+	records := []*SObject{
+		client.SObject("Account").Set("Name", "Acme"),
+		client.SObject("Account").Set("Name", "Much too long").Set("Bogus__c", 1).Set("Score__c", 2),
+		client.SObject("Account").Set("Description", "no name"),
+		client.SObject("Account").Set("Name", "Child").Set("Parent", map[string]interface{}{"Ext__c": "1"}),
+	}
+	results, err := client.CreateAll(records, WithValidation(true))
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Records) != 2 {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	if len(sent) != 2 || !results[0].Success || !results[3].Success || records[3].ID() != "001NEW" {
+		t.Errorf("expected valid records to be sent, got %v, %v", sent, results)
+	}
+
+	var codes []string
+	for _, saveErr := range results[1].Errors {
+		codes = append(codes, saveErr.StatusCode)
+	}
+	expected := []string{errcode.StringTooLong, errcode.InvalidField, errcode.InvalidFieldForInsertUpdate}
+	if strings.Join(codes, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected errors %v", results[1].Errors)
+	}
+	if results[2].Errors[0].StatusCode != errcode.RequiredFieldMissing || results[2].Errors[0].Fields[0] != "Name" {
+		t.Errorf("unexpected errors %v", results[2].Errors)
+	}
+	if indexes := batchErr.Indexes(); len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 2 {
+		t.Errorf("unexpected indexes %v", indexes)
+	}
+
+	sent = nil
+	results, err = client.CreateAll(records, WithValidation(true), WithAllOrNone(true))
+	if err == nil || len(sent) != 0 || results[0].Errors[0].StatusCode != errcode.AllOrNoneOperationRolledBack {
+		t.Errorf("expected nothing to be sent with all or none, got %v, %v", sent, results)
+	}
+}
+
+func TestClient_UpdateAllDryRun(t *testing.T) {
+	var sent []interface{}
+	client := newValidateClient(t, &sent)
+
+	valid := client.SObject("Account").Set("Id", "001A").Set("Name", "Acme")
+	results, err := client.UpdateAll([]*SObject{valid, client.SObject("Account").Set("Name", "No ID")}, WithDryRun(true))
+	if err == nil || len(sent) != 0 {
+		t.Fatalf("expected a dry run with errors, got %v, %v", sent, err)
+	}
+	if !results[0].Success || results[1].Errors[0].StatusCode != errcode.MissingArgument {
+		t.Errorf("unexpected results %v", results)
+	}
+}